@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// PluginsOptions is a struct to support plugins command
+type PluginsOptions struct {
+	OutputOpts output.OutputOptions
+}
+
+// NewPluginsOptions returns initialized Options
+func NewPluginsOptions() *PluginsOptions {
+	return &PluginsOptions{
+		OutputOpts: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewPluginsCmd() *cobra.Command {
+	o := NewPluginsOptions()
+	pluginsCmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "List the agent's executor plugins.",
+		Args:  cobra.NoArgs,
+		Run:   o.runPlugins,
+	}
+	pluginsCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOpts))
+	return pluginsCmd
+}
+
+var pluginColumns = []output.TableColumn[*models.ExecutorPluginInfo]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Name"},
+		Value:        func(p *models.ExecutorPluginInfo) string { return p.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Version"},
+		Value:        func(p *models.ExecutorPluginInfo) string { return p.Version },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "State"},
+		Value:        func(p *models.ExecutorPluginInfo) string { return string(p.State) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Restarts"},
+		Value:        func(p *models.ExecutorPluginInfo) string { return strconv.Itoa(p.Restarts) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Path", WidthMax: 40, WidthMaxEnforcer: text.WrapText},
+		Value:        func(p *models.ExecutorPluginInfo) string { return p.Path },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Error", WidthMax: 40, WidthMaxEnforcer: text.WrapText},
+		Value:        func(p *models.ExecutorPluginInfo) string { return p.Error },
+	},
+}
+
+// Run executes plugins command
+func (o *PluginsOptions) runPlugins(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	response, err := util.GetAPIClientV2().Agent().Plugins(ctx, &apimodels.GetAgentPluginsRequest{})
+	if err != nil {
+		util.Fatal(cmd, fmt.Errorf("could not get server plugins: %w", err), 1)
+	}
+
+	if err = output.Output(cmd, pluginColumns, o.OutputOpts, response.Plugins); err != nil {
+		util.Fatal(cmd, fmt.Errorf("failed to output: %w", err), 1)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unit || !integration
+
+package agent_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	cmdtesting "github.com/bacalhau-project/bacalhau/cmd/testing"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+func TestPluginsSuite(t *testing.T) {
+	suite.Run(t, new(PluginsSuite))
+}
+
+type PluginsSuite struct {
+	cmdtesting.BaseSuite
+}
+
+func (s *PluginsSuite) TestPluginsJSONOutput() {
+	_, out, err := s.ExecuteTestCobraCommand("agent", "plugins", "--output", string(output.JSONFormat))
+	s.Require().NoError(err, "Could not request plugins with json output.")
+
+	var plugins []*models.ExecutorPluginInfo
+	err = marshaller.JSONUnmarshalWithMax([]byte(out), &plugins)
+	s.Require().NoError(err, "Could not unmarshall the output into json - %+v", err)
+	s.Require().Empty(plugins)
+}
+
+func (s *PluginsSuite) TestPluginsTableOutput() {
+	_, out, err := s.ExecuteTestCobraCommand("agent", "plugins")
+	s.Require().NoError(err, "Could not request plugins.")
+	s.Require().Contains(out, "NAME")
+}
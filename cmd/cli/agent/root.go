@@ -14,6 +14,7 @@ func NewCmd() *cobra.Command {
 	}
 	cmd.AddCommand(NewAliveCmd())
 	cmd.AddCommand(NewNodeCmd())
+	cmd.AddCommand(NewPluginsCmd())
 	cmd.AddCommand(NewVersionCmd())
 	return cmd
 }
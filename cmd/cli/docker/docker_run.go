@@ -51,7 +51,10 @@ var (
 // DockerRunOptions declares the arguments accepted by the `docker run` command
 type DockerRunOptions struct {
 	Entrypoint       []string
-	WorkingDirectory string // Working directory for docker
+	WorkingDirectory string   // Working directory for docker
+	RegistrySecret   string   // Secret holding the credentials to pull the image
+	SecurityProfile  string   // Security profile of the compute node the container runs with
+	Capabilities     []string // Linux capabilities added to the container
 
 	SpecSettings       *cliflags.SpecFlagSettings            // Setting for top level job spec fields.
 	ResourceSettings   *cliflags.ResourceUsageSettings       // Settings for the jobs resource requirements.
@@ -122,6 +125,23 @@ func newDockerRunCmd() *cobra.Command { //nolint:funlen
 		`Override the default ENTRYPOINT of the image`,
 	)
 
+	dockerRunCmd.PersistentFlags().StringVar(
+		&opts.RegistrySecret, "registry-secret", opts.RegistrySecret,
+		`Name of the secret holding the credentials to pull the image from a private registry. `+
+			`The secret is resolved by the compute node in the job's namespace, so the credentials are never stored with the job.`,
+	)
+
+	dockerRunCmd.PersistentFlags().StringVar(
+		&opts.SecurityProfile, "security-profile", opts.SecurityProfile,
+		`Name of the security profile of the compute node the container runs with. `+
+			`The node's default profile is used if not set, and nodes without the profile will not run the job.`,
+	)
+
+	dockerRunCmd.PersistentFlags().StringSliceVar(
+		&opts.Capabilities, "cap-add", opts.Capabilities,
+		`Linux capabilities to add to the container, e.g. NET_ADMIN. They must be allowed by the security profile.`,
+	)
+
 	dockerRunCmd.PersistentFlags().AddFlagSet(cliflags.SpecFlags(opts.SpecSettings))
 	dockerRunCmd.PersistentFlags().AddFlagSet(cliflags.DealFlags(opts.DealSettings))
 	dockerRunCmd.PersistentFlags().AddFlagSet(cliflags.NewDownloadFlags(opts.DownloadSettings))
@@ -232,6 +252,16 @@ func CreateJob(ctx context.Context, image string, parameters []string, opts *Doc
 		return nil, err
 	}
 
+	if opts.RegistrySecret != "" {
+		spec.EngineSpec.Params[model.EngineKeyRegistrySecretDocker] = opts.RegistrySecret
+	}
+	if opts.SecurityProfile != "" {
+		spec.EngineSpec.Params[model.EngineKeySecurityProfileDocker] = opts.SecurityProfile
+	}
+	if len(opts.Capabilities) > 0 {
+		spec.EngineSpec.Params[model.EngineKeyCapabilitiesDocker] = opts.Capabilities
+	}
+
 	return &model.Job{
 		APIVersion: model.APIVersionLatest().String(),
 		Spec:       spec,
@@ -63,6 +63,7 @@ func NewCmd() *cobra.Command {
 	}
 
 	getCmd.PersistentFlags().AddFlagSet(cliflags.NewDownloadFlags(OG.DownloadSettings))
+	getCmd.PersistentFlags().AddFlagSet(cliflags.NewPublisherSelectionFlags(OG.DownloadSettings))
 
 	if err := configflags.RegisterFlags(getCmd, getFlags); err != nil {
 		util.Fatal(getCmd, err, 1)
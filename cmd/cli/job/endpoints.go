@@ -0,0 +1,123 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	endpointsShort = `List the endpoints of a job's published ports.`
+
+	endpointsLong = templates.LongDesc(i18n.T(`
+		List the addresses the published ports of the running executions of a job can be reached on.
+
+		Given a service name in the form _<port>._<protocol>.<job> instead of a job id, only the endpoints
+		of that port are listed, where job is the name or id of the job.
+`))
+
+	endpointsExample = templates.Examples(i18n.T(`
+		# All endpoints of a given job.
+		bacalhau job endpoints j-e3f8c209-d683-4a41-b840-f09b88d087b9
+
+		# The endpoints of the http port of the job named web.
+		bacalhau job endpoints _http._tcp.web
+`))
+)
+
+// EndpointsOptions is a struct to support endpoints command
+type EndpointsOptions struct {
+	output.OutputOptions
+	Namespace string
+}
+
+// NewEndpointsOptions returns initialized Options
+func NewEndpointsOptions() *EndpointsOptions {
+	return &EndpointsOptions{
+		OutputOptions: output.OutputOptions{Format: output.TableFormat},
+	}
+}
+
+func NewEndpointsCmd() *cobra.Command {
+	o := NewEndpointsOptions()
+	endpointsCmd := &cobra.Command{
+		Use:     "endpoints [id|service]",
+		Short:   endpointsShort,
+		Long:    endpointsLong,
+		Example: endpointsExample,
+		Args:    cobra.ExactArgs(1),
+		Run:     o.run,
+	}
+
+	endpointsCmd.Flags().StringVar(&o.Namespace, "namespace", o.Namespace,
+		"The namespace of the job when looking up a service. Defaults to the default namespace.")
+	endpointsCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return endpointsCmd
+}
+
+var endpointColumns = []output.TableColumn[*models.ServiceEndpoint]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Execution ID", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.ServiceEndpoint) string { return idgen.ShortID(e.ExecutionID) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Node ID", WidthMax: 10, WidthMaxEnforcer: text.WrapText},
+		Value:        func(e *models.ServiceEndpoint) string { return idgen.ShortID(e.NodeID) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Port"},
+		Value:        func(e *models.ServiceEndpoint) string { return e.Name },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Protocol"},
+		Value:        func(e *models.ServiceEndpoint) string { return e.Protocol },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Target"},
+		Value:        func(e *models.ServiceEndpoint) string { return strconv.Itoa(e.Target) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "Address"},
+		Value:        func(e *models.ServiceEndpoint) string { return e.Address() },
+	},
+}
+
+func (o *EndpointsOptions) run(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	var endpoints []*models.ServiceEndpoint
+	if strings.HasPrefix(args[0], "_") {
+		response, err := util.GetAPIClientV2().Jobs().LookupService(ctx, &apimodels.LookupServiceRequest{
+			Name:      args[0],
+			Namespace: o.Namespace,
+		})
+		if err != nil {
+			util.Fatal(cmd, err, 1)
+		}
+		endpoints = response.Endpoints
+	} else {
+		response, err := util.GetAPIClientV2().Jobs().Endpoints(ctx, &apimodels.GetJobEndpointsRequest{
+			JobID: args[0],
+		})
+		if err != nil {
+			util.Fatal(cmd, err, 1)
+		}
+		endpoints = response.Endpoints
+	}
+
+	if err := output.Output(cmd, endpointColumns, o.OutputOptions, endpoints); err != nil {
+		util.Fatal(cmd, fmt.Errorf("failed to output: %w", err), 1)
+	}
+}
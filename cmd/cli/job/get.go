@@ -0,0 +1,115 @@
+package job
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/configflags"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	getLong = templates.LongDesc(i18n.T(`
+		Get the results of the job, including stdout and stderr.
+
+		When the job's results were published by more than one publisher, results from
+		all publishers are downloaded unless --publisher selects one of them.
+
+		Jobs that publish snapshots of their results while running, such as service and
+		daemon jobs, can have their snapshots downloaded with --snapshot. Snapshots are
+		applied in order up to the given version, or up to the latest one.
+`))
+
+	//nolint:lll // Documentation
+	getExample = templates.Examples(i18n.T(`
+		# Get the results of a job.
+		bacalhau job get j-51225160-807e-48b8-88c9-28311c7899e1
+
+		# Get the results of a job, with a short ID.
+		bacalhau job get j-51225160
+
+		# Get only the results the job published to S3.
+		bacalhau job get j-51225160 --publisher s3
+
+		# Get the latest snapshot of the results of a running job.
+		bacalhau job get j-51225160 --snapshot latest
+
+		# Get the results of a running job as of its third snapshot.
+		bacalhau job get j-51225160 --snapshot 3
+`))
+)
+
+type GetOptions struct {
+	DownloadSettings *cliflags.DownloaderSettings
+	Snapshot         string
+}
+
+func NewGetOptions() *GetOptions {
+	return &GetOptions{
+		DownloadSettings: cliflags.NewDefaultDownloaderSettings(),
+	}
+}
+
+func NewGetCmd() *cobra.Command {
+	o := NewGetOptions()
+
+	getFlags := map[string][]configflags.Definition{
+		"ipfs": configflags.IPFSFlags,
+	}
+
+	getCmd := &cobra.Command{
+		Use:     "get [id]",
+		Short:   "Get the results of a job",
+		Long:    getLong,
+		Example: getExample,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: configflags.PreRun(getFlags),
+		RunE:    o.run,
+	}
+
+	getCmd.PersistentFlags().AddFlagSet(cliflags.NewDownloadFlags(o.DownloadSettings))
+	getCmd.PersistentFlags().AddFlagSet(cliflags.NewPublisherSelectionFlags(o.DownloadSettings))
+	getCmd.PersistentFlags().StringVar(&o.Snapshot, "snapshot", o.Snapshot,
+		`Download the snapshots of a running job up to the given version, or "latest".`)
+
+	if err := configflags.RegisterFlags(getCmd, getFlags); err != nil {
+		util.Fatal(getCmd, err, 1)
+	}
+
+	return getCmd
+}
+
+func (o *GetOptions) run(cmd *cobra.Command, cmdArgs []string) error {
+	jobID := cmdArgs[0]
+
+	// Split the jobID on / to see if the request is for a single file or for the
+	// entire jobid.
+	parts := strings.SplitN(jobID, "/", 2)
+	if len(parts) == 2 {
+		jobID, o.DownloadSettings.SingleFile = parts[0], parts[1]
+	}
+
+	switch o.Snapshot {
+	case "":
+	case "latest":
+		o.DownloadSettings.Snapshot = models.LatestSnapshot
+	default:
+		version, err := strconv.Atoi(o.Snapshot)
+		if err != nil || version < 1 {
+			return fmt.Errorf("invalid snapshot %q: must be a positive version or \"latest\"", o.Snapshot)
+		}
+		o.DownloadSettings.Snapshot = version
+	}
+
+	if err := util.DownloadResultsHandler(cmd.Context(), cmd, jobID, o.DownloadSettings); err != nil {
+		return fmt.Errorf("error downloading job: %w", err)
+	}
+	return nil
+}
@@ -34,7 +34,10 @@ var (
 		bacalhau job list
 
 		# List jobs and output as json
-		bacalhau job list --output json --pretty`))
+		bacalhau job list --output json --pretty
+
+		# List only the jobs you submitted
+		bacalhau job list --mine`))
 
 	// defaultLabelFilter is the default label filter for the list command when
 	// no other labels are specified.
@@ -46,6 +49,8 @@ type ListOptions struct {
 	output.OutputOptions
 	cliflags.ListOptions
 	Labels string
+	Owner  string
+	Mine   bool
 }
 
 // NewListOptions returns initialized Options
@@ -74,6 +79,12 @@ func NewListCmd() *cobra.Command {
 	listCmd.Flags().StringVar(&o.Labels, "labels", o.Labels,
 		"Filter nodes by labels. See https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/ for more information.")
 
+	listCmd.Flags().StringVar(&o.Owner, "owner", o.Owner,
+		"Only list jobs submitted by the given owner.")
+	listCmd.Flags().BoolVar(&o.Mine, "mine", o.Mine,
+		"Only list jobs submitted by you. Requires an authenticated session.")
+	listCmd.MarkFlagsMutuallyExclusive("owner", "mine")
+
 	listCmd.Flags().AddFlagSet(cliflags.ListFlags(&o.ListOptions))
 	listCmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
 	return listCmd
@@ -111,6 +122,10 @@ var listColumns = []output.TableColumn[*models.Job]{
 		ColumnConfig: table.ColumnConfig{Name: "state", WidthMax: 20, WidthMaxEnforcer: text.WrapText},
 		Value:        func(j *models.Job) string { return j.State.StateType.String() },
 	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "owner", WidthMax: 20, WidthMaxEnforcer: text.WrapText},
+		Value:        func(j *models.Job) string { return j.Owner },
+	},
 }
 
 func (o *ListOptions) run(cmd *cobra.Command, _ []string) {
@@ -126,6 +141,8 @@ func (o *ListOptions) run(cmd *cobra.Command, _ []string) {
 	}
 	response, err := util.GetAPIClientV2().Jobs().List(ctx, &apimodels.ListJobsRequest{
 		Labels: labelRequirements,
+		Owner:  o.Owner,
+		Mine:   o.Mine,
 		BaseListRequest: apimodels.BaseListRequest{
 			Limit:     o.Limit,
 			NextToken: o.NextToken,
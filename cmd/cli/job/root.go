@@ -14,6 +14,8 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewDescribeCmd())
+	cmd.AddCommand(NewEndpointsCmd())
+	cmd.AddCommand(NewGetCmd())
 	cmd.AddCommand(NewExecutionCmd())
 	cmd.AddCommand(NewHistoryCmd())
 	cmd.AddCommand(NewListCmd())
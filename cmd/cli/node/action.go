@@ -0,0 +1,72 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const defaultDrainTimeout = time.Hour
+
+// NodeActionCmd performs an action, such as approving a node, on a node.
+type NodeActionCmd struct {
+	action       apimodels.NodeAction
+	drainTimeout time.Duration
+}
+
+func NewActionCmd(action apimodels.NodeAction, short, example string) *cobra.Command {
+	o := &NodeActionCmd{action: action}
+	return &cobra.Command{
+		Use:     fmt.Sprintf("%s [id]", action),
+		Short:   short,
+		Example: example,
+		Args:    cobra.ExactArgs(1),
+		Run:     o.run,
+	}
+}
+
+func NewDrainCmd() *cobra.Command {
+	o := &NodeActionCmd{action: apimodels.NodeActionDrain, drainTimeout: defaultDrainTimeout}
+	cmd := &cobra.Command{
+		Use: "drain [id]",
+		Short: "Cordon a node and move its work elsewhere. Service and daemon executions are rescheduled " +
+			"straight away, while batch and ops executions are given until the timeout to complete.",
+		Example: "bacalhau node drain QmXaXu9N --timeout 30m",
+		Args:    cobra.ExactArgs(1),
+		Run:     o.run,
+	}
+	cmd.Flags().DurationVar(&o.drainTimeout, "timeout", o.drainTimeout,
+		"How long batch and ops executions are given to complete before they are stopped and rescheduled.")
+	return cmd
+}
+
+func (o *NodeActionCmd) run(cmd *cobra.Command, args []string) {
+	ctx := cmd.Context()
+	nodeID := args[0]
+
+	response, err := util.GetAPIClientV2().Nodes().Put(ctx, &apimodels.PutNodeRequest{
+		NodeID:       nodeID,
+		Action:       o.action,
+		DrainTimeout: o.drainTimeout,
+	})
+	if err != nil {
+		util.Fatal(cmd, fmt.Errorf("could not %s node %s: %w", o.action, nodeID, err), 1)
+	}
+
+	cmd.Printf("Node %s is now %s\n", response.Node.ID(), o.state(response.Node))
+}
+
+// state returns the state of the node that the action changes.
+func (o *NodeActionCmd) state(node *models.NodeInfo) fmt.Stringer {
+	switch o.action {
+	case apimodels.NodeActionCordon, apimodels.NodeActionUncordon, apimodels.NodeActionDrain:
+		return node.Scheduling
+	default:
+		return node.Membership
+	}
+}
@@ -24,6 +24,37 @@ var alwaysColumns = []output.TableColumn[*models.NodeInfo]{
 		ColumnConfig: table.ColumnConfig{Name: "type"},
 		Value:        func(ni *models.NodeInfo) string { return ni.NodeType.String() },
 	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "membership"},
+		Value: func(ni *models.NodeInfo) string {
+			if ni.Membership == 0 {
+				return ""
+			}
+			return ni.Membership.String()
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "scheduling"},
+		Value:        func(ni *models.NodeInfo) string { return ni.Scheduling.String() },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "liveness"},
+		Value: func(ni *models.NodeInfo) string {
+			if ni.Liveness == 0 {
+				return ""
+			}
+			return ni.Liveness.String()
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "last seen"},
+		Value: func(ni *models.NodeInfo) string {
+			if ni.LastSeen.IsZero() {
+				return ""
+			}
+			return output.Elapsed(ni.LastSeen)
+		},
+	},
 }
 
 var toggleColumns = map[string][]output.TableColumn[*models.NodeInfo]{
@@ -0,0 +1,178 @@
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/c2h5oh/datasize"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/cmd/util/flags/cliflags"
+	"github.com/bacalhau-project/bacalhau/cmd/util/output"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+var (
+	imagesLong = templates.LongDesc(i18n.T(`
+		Manage the docker images cached on a compute node, so that jobs using
+		them start without waiting for the image to be pulled. These commands
+		talk to the compute node directly, so --api-host and --api-port must
+		point at the compute node rather than at the orchestrator.
+`))
+
+	imagesExample = templates.Examples(i18n.T(`
+		# Pre-pull an image, and pin it so that it is never pruned
+		bacalhau node images pull ubuntu:22.04 --pin --api-host compute-1.example.com
+
+		# List the cached images, most recently used first
+		bacalhau node images list --api-host compute-1.example.com
+
+		# Remove the least recently used images until they use at most 10Gb
+		bacalhau node images prune --disk-budget 10Gb --api-host compute-1.example.com
+`))
+)
+
+var imageColumns = []output.TableColumn[models.CachedImage]{
+	{
+		ColumnConfig: table.ColumnConfig{Name: "image"},
+		Value:        func(image models.CachedImage) string { return image.Image },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "id"},
+		Value:        func(image models.CachedImage) string { return shortImageID(image.ID) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "size"},
+		Value: func(image models.CachedImage) string {
+			return datasize.ByteSize(image.Size).HR()
+		},
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "pinned"},
+		Value:        func(image models.CachedImage) string { return fmt.Sprint(image.Pinned) },
+	},
+	{
+		ColumnConfig: table.ColumnConfig{Name: "last used"},
+		Value:        func(image models.CachedImage) string { return output.Elapsed(image.LastUsed) },
+	},
+}
+
+func NewImagesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "images",
+		Short:   "Commands to manage the docker images cached on a compute node.",
+		Long:    imagesLong,
+		Example: imagesExample,
+	}
+	cmd.AddCommand(NewImagesListCmd())
+	cmd.AddCommand(NewImagesPullCmd())
+	cmd.AddCommand(NewImagesPruneCmd())
+	return cmd
+}
+
+func NewImagesListCmd() *cobra.Command {
+	o := output.OutputOptions{Format: output.TableFormat}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the docker images cached on a compute node.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			response, err := util.GetAPIClientV2().Compute().ListImages(cmd.Context(), &apimodels.ListImagesRequest{})
+			if err != nil {
+				util.Fatal(cmd, fmt.Errorf("could not list images: %w", err), 1)
+			}
+			outputImages(cmd, o, response.Images)
+		},
+	}
+	cmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o))
+	return cmd
+}
+
+// ImagesPullOptions is a struct to support the images pull command
+type ImagesPullOptions struct {
+	output.OutputOptions
+	Pin bool
+}
+
+func NewImagesPullCmd() *cobra.Command {
+	o := &ImagesPullOptions{OutputOptions: output.OutputOptions{Format: output.TableFormat}}
+	cmd := &cobra.Command{
+		Use:   "pull <image>...",
+		Short: "Pull docker images into the image cache of a compute node.",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   o.run,
+	}
+	cmd.Flags().BoolVar(&o.Pin, "pin", o.Pin, "Never prune the pulled images.")
+	cmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return cmd
+}
+
+func (o *ImagesPullOptions) run(cmd *cobra.Command, args []string) {
+	images := make([]models.CachedImage, 0, len(args))
+	for _, image := range args {
+		response, err := util.GetAPIClientV2().Compute().PullImage(cmd.Context(), &apimodels.PullImageRequest{
+			Image: image,
+			Pin:   o.Pin,
+		})
+		if err != nil {
+			util.Fatal(cmd, fmt.Errorf("could not pull image %s: %w", image, err), 1)
+		}
+		images = append(images, response.Image)
+	}
+	outputImages(cmd, o.OutputOptions, images)
+}
+
+// ImagesPruneOptions is a struct to support the images prune command
+type ImagesPruneOptions struct {
+	output.OutputOptions
+	DiskBudget string
+}
+
+func NewImagesPruneCmd() *cobra.Command {
+	o := &ImagesPruneOptions{OutputOptions: output.OutputOptions{Format: output.TableFormat}}
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the least recently used docker images that are not pinned from a compute node.",
+		Long: templates.LongDesc(i18n.T(`
+			Remove the least recently used docker images that are not pinned until the
+			cached images fit the disk budget. Images used by running containers are
+			skipped. The removed images are printed.
+`)),
+		Args: cobra.NoArgs,
+		Run:  o.run,
+	}
+	cmd.Flags().StringVar(&o.DiskBudget, "disk-budget", o.DiskBudget,
+		"Disk space the cached images can use, e.g. 10Gb. Defaults to the node's configured budget.")
+	cmd.Flags().AddFlagSet(cliflags.OutputFormatFlags(&o.OutputOptions))
+	return cmd
+}
+
+func (o *ImagesPruneOptions) run(cmd *cobra.Command, _ []string) {
+	response, err := util.GetAPIClientV2().Compute().PruneImages(cmd.Context(), &apimodels.PruneImagesRequest{
+		DiskBudget: o.DiskBudget,
+	})
+	if err != nil {
+		util.Fatal(cmd, fmt.Errorf("could not prune images: %w", err), 1)
+	}
+	outputImages(cmd, o.OutputOptions, response.Removed)
+}
+
+func outputImages(cmd *cobra.Command, o output.OutputOptions, images []models.CachedImage) {
+	if err := output.Output(cmd, imageColumns, o, images); err != nil {
+		util.Fatal(cmd, fmt.Errorf("failed to output: %w", err), 1)
+	}
+}
+
+// shortImageID returns the ID docker shows for an image, without its digest algorithm.
+func shortImageID(id string) string {
+	id = id[strings.Index(id, ":")+1:]
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -2,6 +2,7 @@ package node
 
 import (
 	"github.com/bacalhau-project/bacalhau/cmd/util/hook"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/spf13/cobra"
 )
 
@@ -15,5 +16,23 @@ func NewCmd() *cobra.Command {
 
 	cmd.AddCommand(NewDescribeCmd())
 	cmd.AddCommand(NewListCmd())
+	cmd.AddCommand(NewTokenCmd())
+	cmd.AddCommand(NewImagesCmd())
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionApprove,
+		"Approve a pending node so that it can receive work.",
+		"bacalhau node approve QmXaXu9N5GNetatsvwnTfQqNtSeKAD6uCmarbh3LMRYAcF"))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionReject,
+		"Reject a pending node so that it cannot receive work.",
+		"bacalhau node reject QmXaXu9N"))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionRevoke,
+		"Revoke the membership of an approved node so that it receives no more work.",
+		"bacalhau node revoke QmXaXu9N"))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionCordon,
+		"Cordon a node so that it receives no new work. Executions already running on the node are not affected.",
+		"bacalhau node cordon QmXaXu9N"))
+	cmd.AddCommand(NewActionCmd(apimodels.NodeActionUncordon,
+		"Uncordon a cordoned, draining or drained node so that it can receive work again.",
+		"bacalhau node uncordon QmXaXu9N"))
+	cmd.AddCommand(NewDrainCmd())
 	return cmd
 }
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kubectl/pkg/util/i18n"
+
+	"github.com/bacalhau-project/bacalhau/cmd/util"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/util/templates"
+)
+
+const defaultJoinTokenTTL = time.Hour
+
+var (
+	tokenCreateLong = templates.LongDesc(i18n.T(`
+		Create a single-use token that admits a compute node to an orchestrator
+		that requires manual node approval. Pass the token to the compute node
+		using the --join-token flag. The token is only shown once.
+`))
+
+	tokenCreateExample = templates.Examples(i18n.T(`
+		# Create a token that is valid for one hour
+		bacalhau node token create
+
+		# Create a token that is valid for ten minutes, printing only the token
+		bacalhau node token create --ttl 10m --quiet
+`))
+)
+
+func NewTokenCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Commands to manage the join tokens used to admit compute nodes.",
+	}
+	cmd.AddCommand(NewTokenCreateCmd())
+	return cmd
+}
+
+// TokenCreateOptions is a struct to support the token create command
+type TokenCreateOptions struct {
+	TTL   time.Duration
+	Quiet bool
+}
+
+func NewTokenCreateCmd() *cobra.Command {
+	o := &TokenCreateOptions{TTL: defaultJoinTokenTTL}
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Create a single-use join token for a compute node.",
+		Long:    tokenCreateLong,
+		Example: tokenCreateExample,
+		Args:    cobra.NoArgs,
+		Run:     o.run,
+	}
+	cmd.Flags().DurationVar(&o.TTL, "ttl", o.TTL, "How long the token can be used for.")
+	cmd.Flags().BoolVar(&o.Quiet, "quiet", o.Quiet, "Only print the token.")
+	return cmd
+}
+
+func (o *TokenCreateOptions) run(cmd *cobra.Command, _ []string) {
+	ctx := cmd.Context()
+	if o.TTL <= 0 {
+		util.Fatal(cmd, fmt.Errorf("token TTL must be greater than zero"), 1)
+	}
+
+	response, err := util.GetAPIClientV2().Nodes().CreateJoinToken(ctx, &apimodels.CreateJoinTokenRequest{
+		TTL: o.TTL,
+	})
+	if err != nil {
+		util.Fatal(cmd, fmt.Errorf("could not create join token: %w", err), 1)
+	}
+
+	if o.Quiet {
+		cmd.Println(response.Token)
+		return
+	}
+	cmd.Printf("Join token %s (expires %s):\n\n%s\n", response.TokenID, response.ExpiresAt.Local().Format(time.RFC1123), response.Token)
+}
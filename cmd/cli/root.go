@@ -75,6 +75,18 @@ func NewRootCmd() *cobra.Command {
 				}
 			}
 
+			// Likewise, a client certificate must come with a key and both files must exist.
+			clientCert, _ := config.Get[string](types.NodeClientAPIClientTLSClientCert)
+			clientKey, _ := config.Get[string](types.NodeClientAPIClientTLSClientKey)
+			if (clientCert == "") != (clientKey == "") {
+				util.Fatal(cmd, fmt.Errorf("a client certificate and key must be provided together"), 1)
+			}
+			for _, file := range []string{clientCert, clientKey} {
+				if _, err := os.Stat(file); file != "" && os.IsNotExist(err) {
+					util.Fatal(cmd, fmt.Errorf("client certificate file '%s' does not exist", file), 1)
+				}
+			}
+
 			ctx := cmd.Context()
 
 			logger.ConfigureLogging(util.LoggingMode)
@@ -114,6 +114,7 @@ func NewCmd() *cobra.Command {
 		"web-ui":                configflags.WebUIFlags,
 		"node-info-store":       configflags.NodeInfoStoreFlags,
 		"translations":          configflags.JobTranslationFlags,
+		"node-admission":        configflags.NodeAdmissionFlags,
 		"docker-cache-manifest": configflags.DockerManifestCacheFlags,
 	}
 
@@ -275,6 +276,10 @@ func serve(cmd *cobra.Command) error {
 		cert, key := config.GetRequesterCertificateSettings()
 		nodeConfig.RequesterTLSCertificateFile = cert
 		nodeConfig.RequesterTLSKeyFile = key
+
+		clientCACert, requireClientCert := config.GetRequesterClientCertificateSettings()
+		nodeConfig.RequesterTLSClientCAFile = clientCACert
+		nodeConfig.RequesterRequireClientCert = requireClientCert
 	}
 
 	// Create node
@@ -43,6 +43,7 @@ func GetComputeConfig() (node.ComputeConfig, error) {
 		JobResourceLimits:                     *jobResources,
 		DefaultJobResourceLimits:              *defaultResources,
 		IgnorePhysicalResourceLimits:          cfg.Capacity.IgnorePhysicalResourceLimits,
+		GPUSharing:                            cfg.Capacity.GPUSharing,
 		JobNegotiationTimeout:                 time.Duration(cfg.JobTimeouts.JobNegotiationTimeout),
 		MinJobExecutionTimeout:                time.Duration(cfg.JobTimeouts.MinJobExecutionTimeout),
 		MaxJobExecutionTimeout:                time.Duration(cfg.JobTimeouts.MaxJobExecutionTimeout),
@@ -58,6 +59,10 @@ func GetComputeConfig() (node.ComputeConfig, error) {
 		LogRunningExecutionsInterval: time.Duration(cfg.Logging.LogRunningExecutionsInterval),
 		LogStreamBufferSize:          cfg.LogStreamConfig.ChannelBufferSize,
 		LocalPublisher:               cfg.LocalPublisher,
+		ImageCache:                   cfg.ImageCache,
+		Ports:                        cfg.Ports,
+		ScratchVolumes:               cfg.ScratchVolumes,
+		ContainerRuntime:             cfg.ContainerRuntime,
 	})
 }
 
@@ -94,7 +99,8 @@ func GetRequesterConfig() (node.RequesterConfig, error) {
 		S3PreSignedURLDisabled:         cfg.StorageProvider.S3.PreSignedURLDisabled,
 		TranslationEnabled:             cfg.TranslationEnabled,
 
-		DefaultPublisher: cfg.DefaultPublisher,
+		DefaultPublisher:   cfg.DefaultPublisher,
+		ManualNodeApproval: cfg.ManualNodeApproval,
 	})
 }
 
@@ -213,6 +219,10 @@ func getNetworkConfig(nodeID string) (node.NetworkConfig, error) {
 		AdvertisedAddress:        networkCfg.AdvertisedAddress,
 		Orchestrators:            networkCfg.Orchestrators,
 		AuthSecret:               networkCfg.AuthSecret,
+		JoinToken:                networkCfg.JoinToken,
+		TLSCACertFile:            networkCfg.TLS.CACert,
+		TLSCertFile:              networkCfg.TLS.Certificate,
+		TLSKeyFile:               networkCfg.TLS.Key,
 		ClusterName:              networkCfg.Cluster.Name,
 		ClusterPort:              networkCfg.Cluster.Port,
 		ClusterAdvertisedAddress: networkCfg.Cluster.AdvertisedAddress,
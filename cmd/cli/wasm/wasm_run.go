@@ -48,6 +48,7 @@ type WasmRunOptions struct {
 	// parameters and entry modules are arguments
 	ImportModules []model.StorageSpec
 	Entrypoint    string
+	Fuel          uint64 // Most fuel the job can use
 
 	SpecSettings       *cliflags.SpecFlagSettings            // Setting for top level job spec fields.
 	ResourceSettings   *cliflags.ResourceUsageSettings       // Settings for the jobs resource requirements.
@@ -123,6 +124,11 @@ func newRunCmd() *cobra.Command {
 		`The name of the WASM function in the entry module to call. This should be a zero-parameter zero-result function that
 		will execute the job.`,
 	)
+	wasmRunCmd.PersistentFlags().Uint64Var(
+		&opts.Fuel, "fuel", opts.Fuel,
+		`The most fuel the job can use, where a unit of fuel is roughly a WASM instruction. The job fails if it runs out. `+
+			`Defaults to the maximum of the compute node, which may be unlimited.`,
+	)
 
 	wasmRunCmd.PersistentFlags().AddFlagSet(cliflags.SpecFlags(opts.SpecSettings))
 	wasmRunCmd.PersistentFlags().AddFlagSet(cliflags.DealFlags(opts.DealSettings))
@@ -223,6 +229,10 @@ func CreateJob(ctx context.Context, cmdArgs []string, opts *WasmRunOptions) (*mo
 		return nil, err
 	}
 
+	if opts.Fuel > 0 {
+		spec.EngineSpec.Params[model.EngineKeyFuelWasm] = opts.Fuel
+	}
+
 	// Publisher is now optional
 	p := opts.SpecSettings.Publisher.Value()
 	if p != nil {
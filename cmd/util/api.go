@@ -41,6 +41,7 @@ func GetAPIClientV2() *clientv2.Client {
 	opts := []clientv2.OptionFn{
 		clientv2.WithCACertificate(tlsConfig.CACert),
 		clientv2.WithInsecureTLS(tlsConfig.Insecure),
+		clientv2.WithClientCertificate(tlsConfig.ClientCert, tlsConfig.ClientKey),
 		clientv2.WithTLS(tlsConfig.UseTLS),
 		clientv2.WithHeaders(headers),
 	}
@@ -7,8 +7,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 
@@ -26,11 +28,16 @@ func DownloadResultsHandler(
 	cmd.PrintErrf("Fetching results of job '%s'...\n", jobID)
 	cm := GetCleanupManager(ctx)
 	response, err := GetAPIClientV2().Jobs().Results(ctx, &apimodels.ListJobResultsRequest{
-		JobID: jobID,
+		JobID:     jobID,
+		Publisher: downloadSettings.Publisher,
+		Snapshot:  downloadSettings.Snapshot,
 	})
 	if err != nil {
 		Fatal(cmd, fmt.Errorf("could not get results for job %s: %w", jobID, err), 1)
 	}
+	if downloadSettings.Snapshot != 0 {
+		return downloadSnapshots(ctx, cmd, jobID, response.Snapshots, downloadSettings)
+	}
 
 	if len(response.Results) == 0 {
 		// No results doesn't mean error, so we should print out a message and return nil
@@ -67,6 +74,8 @@ func DownloadResultsHandler(
 	err = downloader.DownloadResults(
 		ctx,
 		response.Results,
+		response.Manifests,
+		nodeKeys(ctx, response.Manifests...),
 		downloaderProvider,
 		(*downloader.DownloaderSettings)(processedDownloadSettings),
 	)
@@ -80,6 +89,90 @@ func DownloadResultsHandler(
 
 	return nil
 }
+
+// downloadSnapshots downloads each snapshot in order of their versions into
+// the output directory, so that later snapshots overwrite the files they
+// changed and delete the files they removed.
+func downloadSnapshots(
+	ctx context.Context,
+	cmd *cobra.Command,
+	jobID string,
+	snapshots []*models.ResultSnapshot,
+	downloadSettings *cliflags.DownloaderSettings,
+) error {
+	if len(snapshots) == 0 {
+		cmd.Println("No snapshots found")
+		return nil
+	}
+
+	downloaderProvider := util.NewStandardDownloaders(GetCleanupManager(ctx))
+	for _, snapshot := range snapshots {
+		for _, published := range snapshot.Results {
+			if !downloaderProvider.Has(ctx, published.Result.Type) {
+				return fmt.Errorf("no supported downloader found for snapshot %d published by %s",
+					snapshot.Version, published.Publisher)
+			}
+		}
+	}
+
+	processedDownloadSettings, err := processDownloadSettings(downloadSettings, jobID)
+	if err != nil {
+		return err
+	}
+	outputDir := processedDownloadSettings.OutputDir
+
+	for _, snapshot := range snapshots {
+		cmd.PrintErrf("Downloading snapshot %d of execution '%s'...\n", snapshot.Version, idgen.ShortID(snapshot.ExecutionID))
+		for _, published := range snapshot.Results {
+			err = downloader.DownloadResults(
+				ctx,
+				[]*models.SpecConfig{published.Result},
+				[]*models.ResultManifestSignature{snapshot.Manifest},
+				nodeKeys(ctx, snapshot.Manifest),
+				downloaderProvider,
+				(*downloader.DownloaderSettings)(processedDownloadSettings),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		for _, removed := range snapshot.Removed {
+			if !models.IsLocalResultPath(removed) {
+				return fmt.Errorf("snapshot %d removes invalid path %q", snapshot.Version, removed)
+			}
+			if err = os.RemoveAll(filepath.Join(outputDir, filepath.FromSlash(removed))); err != nil {
+				return err
+			}
+		}
+	}
+
+	cmd.Printf("Snapshots of job '%s' have been written to...\n", jobID)
+	cmd.Printf("%s\n", outputDir)
+	return nil
+}
+
+// nodeKeys looks up the public keys the orchestrator pinned for the nodes that
+// signed the manifests. Nodes that cannot be looked up are left out, and their
+// results only verify if their node ID is derived from the signing key.
+func nodeKeys(ctx context.Context, manifests ...*models.ResultManifestSignature) map[string]string {
+	keys := make(map[string]string)
+	for _, manifest := range manifests {
+		if manifest == nil {
+			continue
+		}
+		if _, ok := keys[manifest.NodeID]; ok {
+			continue
+		}
+		response, err := GetAPIClientV2().Nodes().Get(ctx, &apimodels.GetNodeRequest{NodeID: manifest.NodeID})
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("NodeID", manifest.NodeID).Msg("Failed to look up node key")
+			continue
+		}
+		keys[manifest.NodeID] = response.Node.PublicKey
+	}
+	return keys
+}
+
 func processDownloadSettings(settings *cliflags.DownloaderSettings, jobID string) (*cliflags.DownloaderSettings, error) {
 	if settings.OutputDir == "" {
 		dir, err := ensureDefaultDownloadLocation(jobID)
@@ -21,15 +21,29 @@ type DownloaderSettings struct {
 	OutputDir  string
 	SingleFile string
 	Raw        bool
+	NoVerify   bool
+	Publisher  string
+	Snapshot   int
 }
 
 func NewDownloadFlags(settings *DownloaderSettings) *pflag.FlagSet {
 	flags := pflag.NewFlagSet("Download flags", pflag.ContinueOnError)
 	flags.BoolVar(&settings.Raw, "raw",
 		settings.Raw, "Download raw result CIDs instead of merging multiple CIDs into a single result")
+	flags.BoolVar(&settings.NoVerify, "no-verify",
+		settings.NoVerify, "Download results without verifying them against the manifests signed by the compute nodes.")
 	flags.DurationVar(&settings.Timeout, "download-timeout-secs",
 		settings.Timeout, "Timeout duration for IPFS downloads.")
 	flags.StringVar(&settings.OutputDir, "output-dir",
 		settings.OutputDir, "Directory to write the output to.")
 	return flags
 }
+
+// NewPublisherSelectionFlags returns the flags for choosing which publisher's
+// results to download, for commands that only download results.
+func NewPublisherSelectionFlags(settings *DownloaderSettings) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("Publisher selection flags", pflag.ContinueOnError)
+	flags.StringVar(&settings.Publisher, "publisher",
+		settings.Publisher, "Only download the results published by the given publisher type, e.g. s3 or local.")
+	return flags
+}
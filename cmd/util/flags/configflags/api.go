@@ -41,6 +41,20 @@ Ignored if BACALHAU_API_PORT environment variable is set.`,
 		Description:          `Enables TLS but does not verify certificates`,
 		EnvironmentVariables: []string{"BACALHAU_API_INSECURE"},
 	},
+	{
+		FlagName:             "client-cert",
+		DefaultValue:         Default.Node.ClientAPI.ClientTLS.ClientCert,
+		ConfigPath:           types.NodeClientAPIClientTLSClientCert,
+		Description:          `The location of a client certificate file to present when the server requires mutual TLS`,
+		EnvironmentVariables: []string{"BACALHAU_API_CLIENT_CERT"},
+	},
+	{
+		FlagName:             "client-key",
+		DefaultValue:         Default.Node.ClientAPI.ClientTLS.ClientKey,
+		ConfigPath:           types.NodeClientAPIClientTLSClientKey,
+		Description:          `The location of the key file matching the client certificate`,
+		EnvironmentVariables: []string{"BACALHAU_API_CLIENT_KEY"},
+	},
 }
 
 var ServerAPIFlags = []Definition{
@@ -83,4 +97,19 @@ Using this option results in the API serving over HTTPS`,
 		Description:          `Specifies a TLS key file matching the certificate to be used by the requester node`,
 		EnvironmentVariables: []string{"BACALHAU_TLS_KEY"},
 	},
+	{
+		FlagName:     "tls-client-cacert",
+		DefaultValue: Default.Node.ServerAPI.TLS.ClientCACert,
+		ConfigPath:   types.NodeServerAPITLSClientCACert,
+		Description: `Specifies a CA certificate file used to verify client certificates, enabling
+mutual TLS on the requester API`,
+		EnvironmentVariables: []string{"BACALHAU_TLS_CLIENT_CACERT"},
+	},
+	{
+		FlagName:             "tls-require-client-cert",
+		DefaultValue:         Default.Node.ServerAPI.TLS.RequireClientCert,
+		ConfigPath:           types.NodeServerAPITLSRequireClientCert,
+		Description:          `Rejects API clients that do not present a certificate signed by the client CA`,
+		EnvironmentVariables: []string{"BACALHAU_TLS_REQUIRE_CLIENT_CERT"},
+	},
 }
@@ -46,4 +46,10 @@ var CapacityFlags = []Definition{
 		DefaultValue: Default.Node.Compute.Capacity.JobResourceLimits.GPU,
 		Description:  `Job GPU limit to run all jobs (e.g. 1, 2, or 8).`,
 	},
+	{
+		FlagName:     "gpu-sharing",
+		ConfigPath:   types.NodeComputeCapacityGPUSharing,
+		DefaultValue: Default.Node.Compute.Capacity.GPUSharing,
+		Description:  `Number of jobs that can time-slice each GPU, for jobs that allow their GPUs to be shared.`,
+	},
 }
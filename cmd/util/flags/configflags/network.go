@@ -21,6 +21,14 @@ var NetworkFlags = []Definition{
 		DefaultValue: Default.Node.Network.Orchestrators,
 		Description:  `Comma-separated list of orchestrators to connect to. Applies to compute nodes.`,
 	},
+	{
+		FlagName:     "join-token",
+		ConfigPath:   types.NodeNetworkJoinToken,
+		DefaultValue: Default.Node.Network.JoinToken,
+		Description: `Single-use token presented to the orchestrator to be admitted without manual approval. ` +
+			`Applies to compute nodes.`,
+		EnvironmentVariables: []string{"BACALHAU_JOIN_TOKEN"},
+	},
 	{
 		FlagName:     "advertised-address",
 		ConfigPath:   types.NodeNetworkAdvertisedAddress,
@@ -51,4 +59,22 @@ var NetworkFlags = []Definition{
 		DefaultValue: Default.Node.Network.Cluster.Peers,
 		Description:  `Comma-separated list of other orchestrators to connect to to form a cluster.`,
 	},
+	{
+		FlagName:     "network-tls-cacert",
+		ConfigPath:   types.NodeNetworkTLSCACert,
+		DefaultValue: Default.Node.Network.TLS.CACert,
+		Description:  `CA certificate file used to verify other nodes, enabling mutual TLS between orchestrator and compute nodes.`,
+	},
+	{
+		FlagName:     "network-tls-cert",
+		ConfigPath:   types.NodeNetworkTLSCertificate,
+		DefaultValue: Default.Node.Network.TLS.Certificate,
+		Description:  `Certificate file presented to other nodes when mutual TLS is enabled.`,
+	},
+	{
+		FlagName:     "network-tls-key",
+		ConfigPath:   types.NodeNetworkTLSKey,
+		DefaultValue: Default.Node.Network.TLS.Key,
+		Description:  `Key file matching the certificate presented to other nodes.`,
+	},
 }
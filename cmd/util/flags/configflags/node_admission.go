@@ -0,0 +1,13 @@
+package configflags
+
+import "github.com/bacalhau-project/bacalhau/pkg/config/types"
+
+var NodeAdmissionFlags = []Definition{
+	{
+		FlagName:     "manual-node-approval",
+		ConfigPath:   types.NodeRequesterManualNodeApproval,
+		DefaultValue: Default.Node.Requester.ManualNodeApproval,
+		Description: `Require compute nodes to present a join token, or to be approved with 'bacalhau node approve', ` +
+			`before they are sent work. Applies to orchestrator nodes.`,
+	},
+}
@@ -10,6 +10,7 @@ var NodeInfoStoreFlags = []Definition{
 		ConfigPath:   types.NodeNodeInfoStoreTTL,
 		DefaultValue: Default.Node.NodeInfoStoreTTL,
 		Description: `Sets the duration for which node information is retained in the node info store after which it
-is automatically removed from the store.`,
+is automatically removed from the store. Requester nodes keep nodes in their registry, and instead mark nodes that
+have not been seen for this long as disconnected.`,
 	},
 }
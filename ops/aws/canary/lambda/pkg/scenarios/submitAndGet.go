@@ -64,7 +64,12 @@ func SubmitAndGet(ctx context.Context) error {
 		return err
 	}
 
-	err = downloader.DownloadResults(ctx, results.Results, downloaderProvider, downloadSettings)
+	nodeKeys, err := getNodeKeys(ctx, results.Manifests)
+	if err != nil {
+		return err
+	}
+
+	err = downloader.DownloadResults(ctx, results.Results, results.Manifests, nodeKeys, downloaderProvider, downloadSettings)
 	if err != nil {
 		return err
 	}
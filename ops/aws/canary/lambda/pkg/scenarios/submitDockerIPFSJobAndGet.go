@@ -77,7 +77,12 @@ func SubmitDockerIPFSJobAndGet(ctx context.Context) error {
 		return err
 	}
 
-	err = downloader.DownloadResults(ctx, results.Results, downloaderProvider, downloadSettings)
+	nodeKeys, err := getNodeKeys(ctx, results.Manifests)
+	if err != nil {
+		return fmt.Errorf("getting node keys: %s", err)
+	}
+
+	err = downloader.DownloadResults(ctx, results.Results, results.Manifests, nodeKeys, downloaderProvider, downloadSettings)
 	if err != nil {
 		return fmt.Errorf("downloading job: %s", err)
 	}
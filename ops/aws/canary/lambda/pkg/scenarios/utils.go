@@ -15,6 +15,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/downloader"
 	"github.com/bacalhau-project/bacalhau/pkg/job"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/client"
 	clientv2 "github.com/bacalhau-project/bacalhau/pkg/publicapi/client/v2"
 )
@@ -152,6 +154,26 @@ func getClientV2() *clientv2.Client {
 	return clientv2.New(fmt.Sprintf("http://%s:%d", host, port))
 }
 
+// getNodeKeys looks up the public keys the orchestrator pinned for the nodes
+// that signed the manifests, so that their results can be verified.
+func getNodeKeys(ctx context.Context, manifests []*models.ResultManifestSignature) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, manifest := range manifests {
+		if manifest == nil {
+			continue
+		}
+		if _, ok := keys[manifest.NodeID]; ok {
+			continue
+		}
+		response, err := getClientV2().Nodes().Get(ctx, &apimodels.GetNodeRequest{NodeID: manifest.NodeID})
+		if err != nil {
+			return nil, fmt.Errorf("looking up key of node %s: %w", manifest.NodeID, err)
+		}
+		keys[manifest.NodeID] = response.Node.PublicKey
+	}
+	return keys, nil
+}
+
 func getNodeSelectors() ([]model.LabelSelectorRequirement, error) {
 	nodeSelectors := os.Getenv("BACALHAU_NODE_SELECTORS")
 	if nodeSelectors != "" {
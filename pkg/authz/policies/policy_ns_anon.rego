@@ -4,6 +4,7 @@ import rego.v1
 default allow = false
 
 job_endpoint := ["api", "v1", "orchestrator", "jobs"]
+node_endpoint := ["api", "v1", "orchestrator", "nodes"]
 
 # https://developer.mozilla.org/en-US/docs/Glossary/Safe/HTTP
 http_safe_methods := ["GET", "HEAD", "OPTIONS"]
@@ -30,6 +31,15 @@ allow if {
     namespace_readable(job_namespace_perms)
 }
 
+# Allow managing nodes, such as approving them or creating join tokens, if the
+# access token has write access to all namespaces
+allow if {
+    array.slice(input.http.path, 0, 4) == node_endpoint
+    input.http.method in http_unsafe_methods
+
+    namespace_writable(token_namespaces["*"])
+}
+
 # Allow reading all other endpoints, inclduing by users who don't have a token
 allow if {
     input.http.path != job_endpoint
@@ -78,14 +88,34 @@ job_namespace := ns if {
     ns := jobRequest["namespace"]
 }
 
-# The list of namespaces from the verified access token
-token_namespaces := ns if {
+# The claims from the verified access token
+token_claims := claims if {
     authHeader := input.http.headers["Authorization"][0]
     startswith(authHeader, "Bearer ")
     accessToken := trim_prefix(authHeader, "Bearer ")
 
-    [valid, header, claims] := io.jwt.decode_verify(accessToken, input.constraints)
-    ns := claims["ns"]
+    [valid, _, claims] := io.jwt.decode_verify(accessToken, input.constraints)
+    valid
+}
+
+# The list of namespaces from the verified access token
+token_namespaces := token_claims["ns"]
+
+# The identity of the caller, recorded as the owner of any submitted jobs. An
+# access token takes precedence over a verified client certificate.
+principal := token_claims["sub"]
+
+principal := input.certificate.common_name if {
+    not token_claims["sub"]
+    input.certificate.common_name != ""
+}
+
+# Whether the caller is the recorded owner of the job the request refers to,
+# for use in rules that restrict access to a job to the principal that
+# submitted it
+is_job_owner if {
+    input.job.owner != ""
+    input.job.owner == principal
 }
 
 namespace_readable(namespace)     if { bits.and(namespace, 1) != 0 }
@@ -0,0 +1,9 @@
+package bacalhau.authz
+import rego.v1
+
+default allow = false
+
+allow if {
+    input.job.id == "j-owned"
+    input.job.owner == "test-user"
+}
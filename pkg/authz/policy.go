@@ -2,6 +2,7 @@ package authz
 
 import (
 	"bytes"
+	"context"
 	"crypto/rsa"
 	"embed"
 	"encoding/json"
@@ -23,12 +24,24 @@ import (
 // `policy_test_allow.rego` for a minimal example.
 const AuthzAllowRule = "bacalhau.authz.allow"
 
+// The name of the rule that returns the identity of the authenticated caller.
+// This rule is optional: if the policy does not define it, or it is undefined
+// for a given request, the request is treated as anonymous. The principal is
+// recorded as the owner of any jobs submitted by the caller.
+const AuthzPrincipalRule = "bacalhau.authz.principal"
+
+// JobOwnerLookup returns the principal recorded as the owner of a job, or an
+// empty string if the job is unknown or was submitted anonymously.
+type JobOwnerLookup func(ctx context.Context, jobID string) (string, error)
+
 type policyAuthorizer struct {
 	policy *policy.Policy
 	keyset string
 	nodeID string
+	owners JobOwnerLookup
 
-	allowQuery policy.Query[authzData, bool]
+	allowQuery     policy.Query[authzData, bool]
+	principalQuery policy.Query[authzData, string]
 }
 
 type httpData struct {
@@ -46,9 +59,59 @@ type tokenData struct {
 	Audience string `json:"aud"`
 }
 
+// certificateData describes a client certificate that was verified against
+// the server's client CA during the TLS handshake.
+type certificateData struct {
+	CommonName         string   `json:"common_name"`
+	Organization       []string `json:"organization"`
+	OrganizationalUnit []string `json:"organizational_unit"`
+	DNSNames           []string `json:"dns_names"`
+	EmailAddresses     []string `json:"email_addresses"`
+	URIs               []string `json:"uris"`
+	Issuer             string   `json:"issuer"`
+	SerialNumber       string   `json:"serial_number"`
+}
+
+// jobData describes the existing job that a request refers to.
+type jobData struct {
+	ID    string `json:"id"`
+	Owner string `json:"owner"`
+}
+
 type authzData struct {
-	HTTP        httpData  `json:"http"`
-	Constraints tokenData `json:"constraints"`
+	HTTP        httpData         `json:"http"`
+	Constraints tokenData        `json:"constraints"`
+	Certificate *certificateData `json:"certificate,omitempty"`
+	Job         *jobData         `json:"job,omitempty"`
+}
+
+// requestedJobID returns the ID of the job addressed by a request to one of
+// the job endpoints, or an empty string if the request is not about a job.
+func requestedJobID(path []string) string {
+	if len(path) < 5 || path[0] != "api" || path[1] != "v1" || path[2] != "orchestrator" || path[3] != "jobs" {
+		return ""
+	}
+	return path[4]
+}
+
+// verifiedClientCertificate returns details of the client certificate if the
+// request was made over mutual TLS and the certificate chain was verified.
+func verifiedClientCertificate(req *http.Request) *certificateData {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	cert := req.TLS.VerifiedChains[0][0]
+	return &certificateData{
+		CommonName:         cert.Subject.CommonName,
+		Organization:       cert.Subject.Organization,
+		OrganizationalUnit: cert.Subject.OrganizationalUnit,
+		DNSNames:           cert.DNSNames,
+		EmailAddresses:     cert.EmailAddresses,
+		URIs:               lo.Map(cert.URIs, func(u *url.URL, _ int) string { return u.String() }),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.String(),
+	}
 }
 
 //go:embed policies/*.rego
@@ -56,11 +119,15 @@ var policies embed.FS
 
 // PolicyAuthorizer can authorize users by calling out to an external Rego
 // policy containing logic to make decisions about who should be authorized.
-func NewPolicyAuthorizer(authzPolicy *policy.Policy, key *rsa.PublicKey, nodeID string) Authorizer {
+// If owners is not nil, it is used to expose the owner of the job that a
+// request refers to, so that policies can compare it with the principal.
+func NewPolicyAuthorizer(authzPolicy *policy.Policy, key *rsa.PublicKey, nodeID string, owners JobOwnerLookup) Authorizer {
 	p := &policyAuthorizer{
-		policy:     authzPolicy,
-		nodeID:     nodeID,
-		allowQuery: policy.AddQuery[authzData, bool](authzPolicy, AuthzAllowRule),
+		policy:         authzPolicy,
+		nodeID:         nodeID,
+		owners:         owners,
+		allowQuery:     policy.AddQuery[authzData, bool](authzPolicy, AuthzAllowRule),
+		principalQuery: policy.AddQuery[authzData, string](authzPolicy, AuthzPrincipalRule),
 	}
 
 	if key != nil {
@@ -114,10 +181,31 @@ func (authorizer *policyAuthorizer) Authorize(req *http.Request) (Authorization,
 			Issuer:   authorizer.nodeID,
 			Audience: authorizer.nodeID,
 		},
+		// Details of a client certificate verified by the server, so that
+		// policies can map certificates to principals and permissions
+		Certificate: verifiedClientCertificate(req),
+	}
+
+	// The recorded owner of the job the request refers to, so that policies
+	// can restrict access to a job to the principal that submitted it
+	if jobID := requestedJobID(in.HTTP.Path); jobID != "" && authorizer.owners != nil {
+		owner, err := authorizer.owners(req.Context(), jobID)
+		if err != nil {
+			return Authorization{}, err
+		}
+		in.Job = &jobData{ID: jobID, Owner: owner}
 	}
 
 	approved, err := authorizer.allowQuery(req.Context(), in)
-	return Authorization{Approved: approved}, err
+	if err != nil || !approved {
+		return Authorization{Approved: approved}, err
+	}
+
+	principal, err := authorizer.principalQuery(req.Context(), in)
+	if err != nil && !errors.Is(err, policy.ErrNoResult) {
+		return Authorization{}, err
+	}
+	return Authorization{Approved: approved, Principal: principal}, nil
 }
 
 // AlwaysAllowPolicy is a policy that will always permit access, irrespective of
@@ -126,4 +214,4 @@ var AlwaysAllowPolicy = lo.Must(policy.FromFS(policies, "policies/policy_test_al
 
 // AlwaysAllow is an authorizer that will always permit access, irrespective of
 // the passed in data, which is useful for testing.
-var AlwaysAllow = NewPolicyAuthorizer(AlwaysAllowPolicy, nil, "")
+var AlwaysAllow = NewPolicyAuthorizer(AlwaysAllowPolicy, nil, "", nil)
@@ -4,9 +4,14 @@ package authz
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"net/http"
 	"testing"
 
@@ -30,6 +35,7 @@ func getJWTWithNamespace(t *testing.T, signingKey crypto.PrivateKey, namespace s
 	token, err := jwt.NewWithClaims(jwt.GetSigningMethod(jwt.SigningMethodRS256.Name), jwt.MapClaims{
 		"aud": []string{"test-node"},
 		"iss": "test-node",
+		"sub": "test-user",
 		"ns":  map[string]uint8{namespace: perms},
 	}).SignedString(signingKey)
 	require.NoError(t, err)
@@ -73,6 +79,14 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 			"other", "other", "test", NamespaceNoPermission, http.MethodGet, "/api/v1/orchestrator/nodes", sameKey, require.True},
 		{"deny writing other APIs",
 			"other", "other", "test", NamespaceNoPermission, http.MethodDelete, "/api/v1/orchestrator/nodes", sameKey, require.False},
+		{"allow managing nodes with write access to all namespaces",
+			"", "", "*", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/nodes/QmNode", sameKey, require.True},
+		{"allow creating join tokens with write access to all namespaces",
+			"", "", "*", NamespaceWritable, http.MethodPost, "/api/v1/orchestrator/nodes/tokens", sameKey, require.True},
+		{"deny managing nodes with write access to one namespace",
+			"", "", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/nodes/QmNode", sameKey, require.False},
+		{"deny managing nodes with read access to all namespaces",
+			"", "", "*", NamespaceReadable, http.MethodPut, "/api/v1/orchestrator/nodes/QmNode", sameKey, require.False},
 		{"deny signed by wrong key",
 			"test", "test", "test", NamespaceWritable, http.MethodPut, "/api/v1/orchestrator/jobs", newKey, require.False},
 	}
@@ -82,7 +96,7 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 
 	policy, err := policy.FromFS(policies, "policies/policy_ns_anon.rego")
 	require.NoError(t, err)
-	authorizer := NewPolicyAuthorizer(policy, &key.PublicKey, "test-node")
+	authorizer := NewPolicyAuthorizer(policy, &key.PublicKey, "test-node", nil)
 
 	for _, testcase := range cases {
 		t.Run(testcase.name, func(t *testing.T) {
@@ -112,3 +126,104 @@ func TestAppliesAnonymousNamespacePolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestAnonymousNamespacePolicyResolvesPrincipal(t *testing.T) {
+	logger.ConfigureTestLogging(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	policy, err := policy.FromFS(policies, "policies/policy_ns_anon.rego")
+	require.NoError(t, err)
+	authorizer := NewPolicyAuthorizer(policy, &key.PublicKey, "test-node", nil)
+
+	body, err := yaml.Marshal(&models.Job{Namespace: "test"})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPut, "/api/v1/orchestrator/jobs", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.Header.Add("Authorization", "Bearer "+getJWTWithNamespace(t, key, "test", NamespaceWritable))
+
+	result, err := authorizer.Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Approved)
+	require.Equal(t, "test-user", result.Principal)
+
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/orchestrator/nodes", nil)
+	require.NoError(t, err)
+
+	result, err = authorizer.Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Approved)
+	require.Empty(t, result.Principal)
+}
+
+func TestAnonymousNamespacePolicyResolvesCertificatePrincipal(t *testing.T) {
+	logger.ConfigureTestLogging(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	policy, err := policy.FromFS(policies, "policies/policy_ns_anon.rego")
+	require.NoError(t, err)
+	authorizer := NewPolicyAuthorizer(policy, &key.PublicKey, "test-node", nil)
+
+	clientCert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "cert-user"},
+		SerialNumber: big.NewInt(1),
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/orchestrator/nodes", nil)
+	require.NoError(t, err)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}
+
+	result, err := authorizer.Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Approved)
+	require.Equal(t, "cert-user", result.Principal)
+
+	// An access token takes precedence over the client certificate
+	body, err := yaml.Marshal(&models.Job{Namespace: "test"})
+	require.NoError(t, err)
+	request, err = http.NewRequest(http.MethodPut, "/api/v1/orchestrator/jobs", bytes.NewReader(body))
+	require.NoError(t, err)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}
+	request.Header.Add("Authorization", "Bearer "+getJWTWithNamespace(t, key, "test", NamespaceWritable))
+
+	result, err = authorizer.Authorize(request)
+	require.NoError(t, err)
+	require.True(t, result.Approved)
+	require.Equal(t, "test-user", result.Principal)
+}
+
+func TestAnonymousNamespacePolicyComparesJobOwnerWithPrincipal(t *testing.T) {
+	logger.ConfigureTestLogging(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authzPolicy, err := policy.FromFS(policies, "policies/policy_ns_anon.rego")
+	require.NoError(t, err)
+	isJobOwner := policy.AddQuery[authzData, bool](authzPolicy, "bacalhau.authz.is_job_owner")
+	keyset := NewPolicyAuthorizer(authzPolicy, &key.PublicKey, "test-node", nil).(*policyAuthorizer).keyset
+
+	token := getJWTWithNamespace(t, key, "test", NamespaceReadable)
+	input := func(owner string) authzData {
+		return authzData{
+			HTTP: httpData{
+				Method:  http.MethodGet,
+				Path:    []string{"api", "v1", "orchestrator", "jobs", "j-1"},
+				Headers: http.Header{"Authorization": []string{"Bearer " + token}},
+			},
+			Constraints: tokenData{Keyset: keyset, Issuer: "test-node", Audience: "test-node"},
+			Job:         &jobData{ID: "j-1", Owner: owner},
+		}
+	}
+
+	owned, err := isJobOwner(context.Background(), input("test-user"))
+	require.NoError(t, err)
+	require.True(t, owned)
+
+	_, err = isJobOwner(context.Background(), input("other-user"))
+	require.ErrorIs(t, err, policy.ErrNoResult)
+}
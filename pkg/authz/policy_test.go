@@ -3,6 +3,8 @@
 package authz
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"testing"
 
@@ -17,7 +19,7 @@ func TestAllowsWhenPolicySaysAllow(t *testing.T) {
 	policy, err := policy.FromFS(policies, "policies/policy_test_allow.rego")
 	require.NoError(t, err)
 
-	authorizer := NewPolicyAuthorizer(policy, nil, "")
+	authorizer := NewPolicyAuthorizer(policy, nil, "", nil)
 	require.NotNil(t, authorizer)
 
 	result, err := authorizer.Authorize(request)
@@ -32,7 +34,7 @@ func TestDeniesWhenPolicySaysDeny(t *testing.T) {
 	policy, err := policy.FromFS(policies, "policies/policy_test_deny.rego")
 	require.NoError(t, err)
 
-	authorizer := NewPolicyAuthorizer(policy, nil, "")
+	authorizer := NewPolicyAuthorizer(policy, nil, "", nil)
 	require.NotNil(t, authorizer)
 
 	result, err := authorizer.Authorize(request)
@@ -44,7 +46,7 @@ func TestPolicyEvaluatedAgainstHTTPRequest(t *testing.T) {
 	policy, err := policy.FromFS(policies, "policies/policy_test_http.rego")
 	require.NoError(t, err)
 
-	authorizer := NewPolicyAuthorizer(policy, nil, "")
+	authorizer := NewPolicyAuthorizer(policy, nil, "", nil)
 	require.NotNil(t, authorizer)
 
 	goodRequest, err := http.NewRequest(http.MethodGet, "/api/v1/hello", nil)
@@ -61,3 +63,43 @@ func TestPolicyEvaluatedAgainstHTTPRequest(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, badResult.Approved)
 }
+
+func TestPolicyEvaluatedAgainstJobOwner(t *testing.T) {
+	policy, err := policy.FromFS(policies, "policies/policy_test_owner.rego")
+	require.NoError(t, err)
+
+	owners := func(ctx context.Context, jobID string) (string, error) {
+		if jobID == "j-owned" {
+			return "test-user", nil
+		}
+		return "", nil
+	}
+	authorizer := NewPolicyAuthorizer(policy, nil, "", owners)
+
+	for path, approved := range map[string]bool{
+		"/api/v1/orchestrator/jobs/j-owned":         true,
+		"/api/v1/orchestrator/jobs/j-owned/history": true,
+		"/api/v1/orchestrator/jobs/j-other":         false,
+		"/api/v1/orchestrator/jobs":                 false,
+	} {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		result, err := authorizer.Authorize(request)
+		require.NoError(t, err)
+		require.Equal(t, approved, result.Approved, path)
+	}
+}
+
+func TestJobOwnerLookupErrorIsReturned(t *testing.T) {
+	owners := func(ctx context.Context, jobID string) (string, error) {
+		return "", errors.New("store unavailable")
+	}
+	authorizer := NewPolicyAuthorizer(AlwaysAllowPolicy, nil, "", owners)
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/orchestrator/jobs/j-owned", nil)
+	require.NoError(t, err)
+
+	_, err = authorizer.Authorize(request)
+	require.Error(t, err)
+}
@@ -7,6 +7,9 @@ import (
 type Authorization struct {
 	Approved bool   `json:"approved"`
 	Reason   string `json:"reason"`
+	// Principal is the identity of the authenticated caller, if the policy
+	// was able to establish one. It is empty for anonymous requests.
+	Principal string `json:"principal,omitempty"`
 }
 
 type Authorizer interface {
@@ -0,0 +1,129 @@
+package azure
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+)
+
+const (
+	EnvConnectionString = "AZURE_STORAGE_CONNECTION_STRING"
+	EnvAccount          = "AZURE_STORAGE_ACCOUNT"
+	EnvAccountKey       = "AZURE_STORAGE_KEY"
+	EnvSASToken         = "AZURE_STORAGE_SAS_TOKEN"
+)
+
+type ClientProviderParams struct {
+	// ConnectionString is used for sources and publishers that do not set
+	// an account or endpoint.
+	ConnectionString string
+	// Account is the default storage account, and the account AccountKey
+	// belongs to.
+	Account    string
+	AccountKey string
+	// SASToken is appended to the service URL of accounts without a key.
+	SASToken string
+	// AllowedEndpoints are the custom service URLs that are sent the
+	// credentials. Other custom endpoints are accessed anonymously.
+	AllowedEndpoints []string
+}
+
+// DefaultClientProviderParams reads the Azure storage credentials from the
+// environment, and the endpoints trusted with them from the configuration.
+func DefaultClientProviderParams() ClientProviderParams {
+	return ClientProviderParams{
+		ConnectionString: os.Getenv(EnvConnectionString),
+		Account:          os.Getenv(EnvAccount),
+		AccountKey:       os.Getenv(EnvAccountKey),
+		SASToken:         strings.TrimPrefix(os.Getenv(EnvSASToken), "?"),
+		AllowedEndpoints: config.GetObjectStorageConfig().AzureEndpoints,
+	}
+}
+
+type ClientProvider struct {
+	params    ClientProviderParams
+	clients   map[string]*azblob.Client
+	clientsMu sync.Mutex
+}
+
+func NewClientProvider(params ClientProviderParams) *ClientProvider {
+	return &ClientProvider{
+		params:  params,
+		clients: make(map[string]*azblob.Client),
+	}
+}
+
+// IsInstalled returns true if Azure storage credentials are configured.
+func (p *ClientProvider) IsInstalled() bool {
+	return p.params.ConnectionString != "" ||
+		(p.params.Account != "" && p.params.AccountKey != "") ||
+		p.params.SASToken != ""
+}
+
+// GetClient returns a client for the given storage account and endpoint,
+// either of which may be empty to use the configured defaults.
+func (p *ClientProvider) GetClient(account, endpoint string) (*azblob.Client, error) {
+	clientIdentifier := account + "-" + endpoint
+	p.clientsMu.Lock()
+	defer p.clientsMu.Unlock()
+	if client, ok := p.clients[clientIdentifier]; ok {
+		return client, nil
+	}
+
+	client, err := p.newClient(account, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[clientIdentifier] = client
+	return client, nil
+}
+
+func (p *ClientProvider) newClient(account, endpoint string) (*azblob.Client, error) {
+	if account == "" && endpoint == "" && p.params.ConnectionString != "" {
+		return azblob.NewClientFromConnectionString(p.params.ConnectionString, nil)
+	}
+	if account == "" {
+		account = p.params.Account
+	}
+	serviceURL := endpoint
+	if serviceURL == "" {
+		if account == "" {
+			return nil, fmt.Errorf("azure storage account not specified and %s is not set", EnvAccount)
+		}
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	}
+
+	// the credentials are only sent to Azure and to the endpoints the
+	// operator trusts, never to an endpoint chosen by a job
+	if p.isAllowedEndpoint(endpoint) {
+		if account == p.params.Account && p.params.AccountKey != "" {
+			credential, err := azblob.NewSharedKeyCredential(account, p.params.AccountKey)
+			if err != nil {
+				return nil, err
+			}
+			return azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+		}
+		if p.params.SASToken != "" && (p.params.Account == "" || account == p.params.Account) {
+			serviceURL = serviceURL + "?" + p.params.SASToken
+		}
+	}
+	// anonymous access only works for public containers
+	return azblob.NewClientWithNoCredential(serviceURL, nil)
+}
+
+// isAllowedEndpoint returns true for the default endpoint of accounts, and
+// for the custom endpoints the credentials can be sent to.
+func (p *ClientProvider) isAllowedEndpoint(endpoint string) bool {
+	if endpoint == "" {
+		return true
+	}
+	return slices.ContainsFunc(p.params.AllowedEndpoints, func(allowed string) bool {
+		return strings.TrimSuffix(allowed, "/") == strings.TrimSuffix(endpoint, "/")
+	})
+}
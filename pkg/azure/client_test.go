@@ -0,0 +1,50 @@
+//go:build unit || !integration
+
+package azure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientProviderTestSuite struct {
+	suite.Suite
+	provider *ClientProvider
+}
+
+func TestClientProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientProviderTestSuite))
+}
+
+func (s *ClientProviderTestSuite) SetupTest() {
+	s.provider = NewClientProvider(ClientProviderParams{
+		Account:          "account",
+		SASToken:         "sig=secret",
+		AllowedEndpoints: []string{"https://trusted.example.com/"},
+	})
+}
+
+func (s *ClientProviderTestSuite) TestDefaultEndpointIsSentToken() {
+	client, err := s.provider.GetClient("", "")
+	s.Require().NoError(err)
+	s.Contains(client.URL(), "sig=secret")
+}
+
+func (s *ClientProviderTestSuite) TestAllowedEndpointIsSentToken() {
+	client, err := s.provider.GetClient("", "https://trusted.example.com")
+	s.Require().NoError(err)
+	s.Contains(client.URL(), "sig=secret")
+}
+
+func (s *ClientProviderTestSuite) TestCustomEndpointIsAnonymous() {
+	client, err := s.provider.GetClient("", "https://attacker.example.com/")
+	s.Require().NoError(err)
+	s.NotContains(client.URL(), "sig=secret")
+}
+
+func (s *ClientProviderTestSuite) TestOtherAccountIsAnonymous() {
+	client, err := s.provider.GetClient("other", "")
+	s.Require().NoError(err)
+	s.NotContains(client.URL(), "sig=secret")
+}
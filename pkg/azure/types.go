@@ -0,0 +1,91 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type SourceSpec struct {
+	// Account is the storage account name. Defaults to the account of the
+	// node's credentials if empty.
+	Account   string
+	Container string
+	Key       string
+	Filter    string
+	// Endpoint overrides the blob service URL, e.g. for Azurite
+	Endpoint string
+	ETag     string
+}
+
+func (c SourceSpec) Validate() error {
+	if c.Container == "" {
+		return errors.New("invalid azure storage params: container cannot be empty")
+	}
+	return nil
+}
+
+func (c SourceSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+type PublisherSpec struct {
+	Account   string `json:"Account"`
+	Container string `json:"Container"`
+	Key       string `json:"Key"`
+	Endpoint  string `json:"Endpoint"`
+}
+
+func (c PublisherSpec) Validate() error {
+	if c.Container == "" {
+		return fmt.Errorf("invalid azure params. container cannot be empty")
+	}
+	if c.Key == "" {
+		return fmt.Errorf("invalid azure params. key cannot be empty")
+	}
+	return nil
+}
+
+func (c PublisherSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+func DecodeSourceSpec(spec *models.SpecConfig) (SourceSpec, error) {
+	if !spec.IsType(models.StorageSourceAzure) {
+		return SourceSpec{}, errors.New(
+			"invalid storage source type. expected " + models.StorageSourceAzure + ", but received: " + spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return SourceSpec{}, errors.New("invalid storage source params. cannot be nil")
+	}
+
+	var c SourceSpec
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+func DecodePublisherSpec(spec *models.SpecConfig) (PublisherSpec, error) {
+	if !spec.IsType(models.PublisherAzure) {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher type. expected %s, but received: %s",
+			models.PublisherAzure, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher params. cannot be nil")
+	}
+
+	var c PublisherSpec
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
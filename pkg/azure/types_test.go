@@ -0,0 +1,77 @@
+//go:build unit || !integration
+
+package azure
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ParamsTestSuite struct {
+	suite.Suite
+}
+
+func TestParamsTestSuite(t *testing.T) {
+	suite.Run(t, new(ParamsTestSuite))
+}
+
+func (s *ParamsTestSuite) TestDecodePublisherLowerCase() {
+	decoded, err := DecodePublisherSpec(&models.SpecConfig{
+		Type: models.PublisherAzure,
+		Params: map[string]interface{}{
+			"account":   "myaccount",
+			"container": "results",
+			"key":       "{jobID}.tar.gz",
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(PublisherSpec{Account: "myaccount", Container: "results", Key: "{jobID}.tar.gz"}, decoded)
+}
+
+func (s *ParamsTestSuite) TestDecodePublisherInvalid() {
+	_, err := DecodePublisherSpec(&models.SpecConfig{
+		Type:   models.PublisherAzure,
+		Params: map[string]interface{}{"container": "results"},
+	})
+	s.Require().Error(err)
+
+	_, err = DecodePublisherSpec(&models.SpecConfig{
+		Type:   models.PublisherGCS,
+		Params: PublisherSpec{Container: "results", Key: "key"}.ToMap(),
+	})
+	s.Require().Error(err)
+}
+
+func (s *ParamsTestSuite) TestDecodeSourceJSON() {
+	expected := SourceSpec{
+		Account:   "myaccount",
+		Container: "inputs",
+		Key:       "data/",
+		Filter:    ".*\\.csv",
+		Endpoint:  "http://127.0.0.1:10000/devstoreaccount1",
+		ETag:      "0x8DB",
+	}
+	bytes, err := json.Marshal(&models.SpecConfig{
+		Type:   models.StorageSourceAzure,
+		Params: expected.ToMap(),
+	})
+	s.Require().NoError(err)
+
+	var spec models.SpecConfig
+	s.Require().NoError(json.Unmarshal(bytes, &spec))
+	decoded, err := DecodeSourceSpec(&spec)
+	s.Require().NoError(err)
+	s.Equal(expected, decoded)
+}
+
+func (s *ParamsTestSuite) TestDecodeSourceMissingContainer() {
+	_, err := DecodeSourceSpec(&models.SpecConfig{
+		Type:   models.StorageSourceAzure,
+		Params: SourceSpec{Key: "data/"}.ToMap(),
+	})
+	s.Require().Error(err)
+}
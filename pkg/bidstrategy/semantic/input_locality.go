@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
 )
 
@@ -44,6 +45,7 @@ func (s *InputLocalityStrategy) ShouldBid(
 
 	inputSources := request.Job.Task().InputSources
 	foundInputs := 0
+	ctx = secrets.ContextWithNamespace(ctx, request.Job.Namespace)
 	for _, input := range inputSources {
 		// see if the storage engine reports that we have the resource locally
 		strg, err := s.storages.Get(ctx, input.Source.Type)
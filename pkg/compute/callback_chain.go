@@ -29,6 +29,30 @@ func (c ChainedCallback) OnRunComplete(ctx context.Context, result RunResult) {
 	}
 }
 
+func (c ChainedCallback) OnSnapshotComplete(ctx context.Context, result SnapshotResult) {
+	for _, callback := range c.callbacks {
+		callback.OnSnapshotComplete(ctx, result)
+	}
+}
+
+func (c ChainedCallback) OnHealthUpdate(ctx context.Context, result HealthResult) {
+	for _, callback := range c.callbacks {
+		callback.OnHealthUpdate(ctx, result)
+	}
+}
+
+func (c ChainedCallback) OnEndpointsAllocated(ctx context.Context, result EndpointsResult) {
+	for _, callback := range c.callbacks {
+		callback.OnEndpointsAllocated(ctx, result)
+	}
+}
+
+func (c ChainedCallback) OnCheckpointComplete(ctx context.Context, result CheckpointResult) {
+	for _, callback := range c.callbacks {
+		callback.OnCheckpointComplete(ctx, result)
+	}
+}
+
 func (c ChainedCallback) OnCancelComplete(ctx context.Context, result CancelResult) {
 	for _, callback := range c.callbacks {
 		callback.OnCancelComplete(ctx, result)
@@ -3,10 +3,14 @@ package compute
 import "context"
 
 type CallbackMock struct {
-	OnBidCompleteHandler    func(ctx context.Context, result BidResult)
-	OnCancelCompleteHandler func(ctx context.Context, result CancelResult)
-	OnComputeFailureHandler func(ctx context.Context, err ComputeError)
-	OnRunCompleteHandler    func(ctx context.Context, result RunResult)
+	OnBidCompleteHandler        func(ctx context.Context, result BidResult)
+	OnCancelCompleteHandler     func(ctx context.Context, result CancelResult)
+	OnCheckpointCompleteHandler func(ctx context.Context, result CheckpointResult)
+	OnComputeFailureHandler     func(ctx context.Context, err ComputeError)
+	OnEndpointsAllocatedHandler func(ctx context.Context, result EndpointsResult)
+	OnHealthUpdateHandler       func(ctx context.Context, result HealthResult)
+	OnRunCompleteHandler        func(ctx context.Context, result RunResult)
+	OnSnapshotCompleteHandler   func(ctx context.Context, result SnapshotResult)
 }
 
 // OnBidComplete implements Callback
@@ -37,4 +41,32 @@ func (c CallbackMock) OnRunComplete(ctx context.Context, result RunResult) {
 	}
 }
 
+// OnSnapshotComplete implements Callback
+func (c CallbackMock) OnSnapshotComplete(ctx context.Context, result SnapshotResult) {
+	if c.OnSnapshotCompleteHandler != nil {
+		c.OnSnapshotCompleteHandler(ctx, result)
+	}
+}
+
+// OnCheckpointComplete implements Callback
+func (c CallbackMock) OnCheckpointComplete(ctx context.Context, result CheckpointResult) {
+	if c.OnCheckpointCompleteHandler != nil {
+		c.OnCheckpointCompleteHandler(ctx, result)
+	}
+}
+
+// OnHealthUpdate implements Callback
+func (c CallbackMock) OnHealthUpdate(ctx context.Context, result HealthResult) {
+	if c.OnHealthUpdateHandler != nil {
+		c.OnHealthUpdateHandler(ctx, result)
+	}
+}
+
+// OnEndpointsAllocated implements Callback
+func (c CallbackMock) OnEndpointsAllocated(ctx context.Context, result EndpointsResult) {
+	if c.OnEndpointsAllocatedHandler != nil {
+		c.OnEndpointsAllocatedHandler(ctx, result)
+	}
+}
+
 var _ Callback = CallbackMock{}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
 )
@@ -27,6 +28,7 @@ func (c *DiskUsageCalculator) Calculate(ctx context.Context, job models.Job, par
 	requirements := &models.Resources{}
 
 	var totalDiskRequirements uint64 = 0
+	ctx = secrets.ContextWithNamespace(ctx, job.Namespace)
 	for _, input := range job.Task().InputSources {
 		strg, err := c.storages.Get(ctx, input.Source.Type)
 		if err != nil {
@@ -93,3 +93,24 @@ func TestParsingIntelGPUsWithMany(t *testing.T) {
 		require.Equal(t, uint64(5068), gpu.Memory)
 	}
 }
+
+func TestSelectingIntelGPUs(t *testing.T) {
+	provider := getTestProvider(oneListOutput, infoOutput)
+	output, err := provider.GetAvailableCapacity(context.Background())
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		selector models.GPUSelectorConfig
+		matches  bool
+	}{
+		{selector: models.GPUSelectorConfig{Vendor: "intel"}, matches: true},
+		{selector: models.GPUSelectorConfig{Vendor: "nvidia"}, matches: false},
+		{selector: models.GPUSelectorConfig{Model: "device 56C1"}, matches: true},
+		{selector: models.GPUSelectorConfig{MinMemory: "4GiB"}, matches: true},
+		{selector: models.GPUSelectorConfig{MinMemory: "5GiB"}, matches: false},
+	} {
+		selector, err := test.selector.ToGPUSelector()
+		require.NoError(t, err)
+		require.Equal(t, test.matches, selector.Matches(output.GPUs[0]), test.selector)
+	}
+}
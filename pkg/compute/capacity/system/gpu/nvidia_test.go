@@ -56,3 +56,25 @@ func TestParsingNvidiaGPUsWithNone(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, gpus, 0)
 }
+
+func TestSelectingNvidiaGPUs(t *testing.T) {
+	output := strings.Join([]string{
+		"0, Tesla T4, 15360",
+		"1, NVIDIA A100-SXM4-80GB, 81920",
+	}, "\n")
+
+	resources, err := parseNvidiaCliOutput(strings.NewReader(output))
+	require.NoError(t, err)
+
+	selector, err := (&models.GPUSelectorConfig{Vendor: "NVIDIA", MinMemory: "40GiB"}).ToGPUSelector()
+	require.NoError(t, err)
+	gpus := selector.Filter(resources.GPUs)
+	require.Len(t, gpus, 1)
+	require.Equal(t, uint64(1), gpus[0].Index)
+
+	selector, err = (&models.GPUSelectorConfig{Model: "t4"}).ToGPUSelector()
+	require.NoError(t, err)
+	gpus = selector.Filter(resources.GPUs)
+	require.Len(t, gpus, 1)
+	require.Equal(t, uint64(0), gpus[0].Index)
+}
@@ -10,18 +10,46 @@ import (
 
 type LocalTrackerParams struct {
 	MaxCapacity models.Resources
+	// GPUSharing is how many tasks can share a GPU by time-slicing it. GPUs
+	// are only shared with tasks whose GPU selector allows it, and are not
+	// shared at all if this is less than two.
+	GPUSharing uint64
 }
 
 // LocalTracker keeps track of the current resource usage of the local node in-memory.
+// When the node's GPUs are known, they are allocated per device so that each
+// execution is assigned specific GPUs.
 type LocalTracker struct {
 	maxCapacity  models.Resources
 	usedCapacity models.Resources
-	mu           sync.Mutex
+	gpuSharing   uint64
+	// gpus is the allocation of each of the node's GPUs, in the order of maxCapacity.GPUs
+	gpus []gpuAllocation
+	mu   sync.Mutex
+}
+
+// gpuAllocation is the usage of a single GPU device.
+type gpuAllocation struct {
+	gpu models.GPU
+	// exclusive is true when the GPU is allocated to a single task
+	exclusive bool
+	// shared is the number of tasks time-slicing the GPU
+	shared uint64
+}
+
+func (a *gpuAllocation) isFree() bool {
+	return !a.exclusive && a.shared == 0
 }
 
 func NewLocalTracker(params LocalTrackerParams) *LocalTracker {
+	gpus := make([]gpuAllocation, len(params.MaxCapacity.GPUs))
+	for i, gpu := range params.MaxCapacity.GPUs {
+		gpus[i] = gpuAllocation{gpu: gpu}
+	}
 	return &LocalTracker{
 		maxCapacity: params.MaxCapacity,
+		gpuSharing:  params.GPUSharing,
+		gpus:        gpus,
 	}
 }
 
@@ -34,26 +62,129 @@ func (t *LocalTracker) AddIfHasCapacity(ctx context.Context, usage models.Resour
 	defer t.mu.Unlock()
 
 	newUsedCapacity := t.usedCapacity.Add(usage)
+	if len(t.gpus) > 0 {
+		// GPUs are accounted for per device below, as shared GPUs can be
+		// allocated to more than one task
+		newUsedCapacity.GPU = 0
+	}
 	if !newUsedCapacity.LessThanEq(t.maxCapacity) {
 		return nil
 	}
 
-	// Allocate any GPUs that have been asked for but not chosen
-	unspecifiedGPUs := math.Max(usage.GPU-uint64(len(usage.GPUs)), 0)
-	availableGPUs := t.maxCapacity.Sub(t.usedCapacity).GPUs
-	if unspecifiedGPUs > uint64(len(availableGPUs)) {
+	if len(t.gpus) == 0 {
+		// Allocate any GPUs that have been asked for but not chosen
+		unspecifiedGPUs := math.Max(usage.GPU-uint64(len(usage.GPUs)), 0)
+		availableGPUs := t.maxCapacity.Sub(t.usedCapacity).GPUs
+		if unspecifiedGPUs > uint64(len(availableGPUs)) {
+			return nil
+		}
+		usage.GPUs = append(usage.GPUs, availableGPUs[:unspecifiedGPUs]...)
+		t.usedCapacity = *t.usedCapacity.Add(usage)
+		return &usage
+	}
+
+	devices := t.selectGPUs(usage)
+	if devices == nil {
 		return nil
 	}
-	usage.GPUs = append(usage.GPUs, availableGPUs[:unspecifiedGPUs]...)
+	shared := t.isShared(usage)
+	usage.GPUs = nil
+	for _, device := range devices {
+		if shared {
+			device.shared++
+		} else {
+			device.exclusive = true
+		}
+		usage.GPUs = append(usage.GPUs, device.gpu)
+	}
 
-	t.usedCapacity = *t.usedCapacity.Add(usage)
+	used := usage
+	used.GPU, used.GPUs = 0, nil
+	t.usedCapacity = *t.usedCapacity.Add(used)
+	t.usedCapacity.GPUSelector = nil
 	return &usage
 }
 
+// isShared returns true if the usage's GPUs can be shared with other tasks.
+func (t *LocalTracker) isShared(usage models.Resources) bool {
+	return t.gpuSharing > 1 && usage.GPUSelector != nil && usage.GPUSelector.Shared
+}
+
+// canAllocate returns true if the device can be allocated to the usage.
+func (t *LocalTracker) canAllocate(device *gpuAllocation, usage models.Resources) bool {
+	if !usage.GPUSelector.Matches(device.gpu) {
+		return false
+	}
+	if t.isShared(usage) {
+		return !device.exclusive && device.shared < t.gpuSharing
+	}
+	return device.isFree()
+}
+
+// selectGPUs returns the devices to allocate to the usage, or nil if there
+// aren't enough devices that match its GPU selector. GPUs that were already
+// chosen for the usage are kept if they are still available. Shared usage
+// is packed onto GPUs that are already shared, to keep GPUs free for tasks
+// that need them exclusively.
+func (t *LocalTracker) selectGPUs(usage models.Resources) []*gpuAllocation {
+	if usage.GPU == 0 {
+		return []*gpuAllocation{}
+	}
+	selected := make([]*gpuAllocation, 0, usage.GPU)
+	isSelected := make(map[int]bool)
+	selectWhere := func(predicate func(*gpuAllocation) bool) {
+		for i := range t.gpus {
+			if uint64(len(selected)) == usage.GPU {
+				return
+			}
+			device := &t.gpus[i]
+			if !isSelected[i] && t.canAllocate(device, usage) && predicate(device) {
+				selected = append(selected, device)
+				isSelected[i] = true
+			}
+		}
+	}
+
+	selectWhere(func(device *gpuAllocation) bool {
+		for _, chosen := range usage.GPUs {
+			if sameGPU(chosen, device.gpu) {
+				return true
+			}
+		}
+		return false
+	})
+	if t.isShared(usage) {
+		selectWhere(func(device *gpuAllocation) bool { return device.shared > 0 })
+	}
+	selectWhere(func(*gpuAllocation) bool { return true })
+
+	if uint64(len(selected)) < usage.GPU {
+		return nil
+	}
+	return selected
+}
+
+func sameGPU(a, b models.GPU) bool {
+	return a.Vendor == b.Vendor && a.Index == b.Index
+}
+
+// GetAvailableCapacity returns the capacity that isn't in use. GPUs that are
+// shared are still available if they can be shared with more tasks.
 func (t *LocalTracker) GetAvailableCapacity(ctx context.Context) models.Resources {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	return *t.maxCapacity.Sub(t.usedCapacity)
+	available := *t.maxCapacity.Sub(t.usedCapacity)
+	if len(t.gpus) > 0 {
+		available.GPUs = nil
+		for i := range t.gpus {
+			device := &t.gpus[i]
+			if device.isFree() || (t.gpuSharing > 1 && !device.exclusive && device.shared < t.gpuSharing) {
+				available.GPUs = append(available.GPUs, device.gpu)
+			}
+		}
+		available.GPU = uint64(len(available.GPUs))
+	}
+	return available
 }
 
 func (t *LocalTracker) GetMaxCapacity(ctx context.Context) models.Resources {
@@ -63,6 +194,25 @@ func (t *LocalTracker) GetMaxCapacity(ctx context.Context) models.Resources {
 func (t *LocalTracker) Remove(ctx context.Context, usage models.Resources) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
+	if len(t.gpus) == 0 {
+		t.usedCapacity = *t.usedCapacity.Sub(usage)
+		return
+	}
+
+	for _, gpu := range usage.GPUs {
+		for i := range t.gpus {
+			device := &t.gpus[i]
+			if !sameGPU(gpu, device.gpu) {
+				continue
+			}
+			if device.exclusive {
+				device.exclusive = false
+			} else if device.shared > 0 {
+				device.shared--
+			}
+		}
+	}
+	usage.GPU, usage.GPUs = 0, nil
 	t.usedCapacity = *t.usedCapacity.Sub(usage)
 }
 
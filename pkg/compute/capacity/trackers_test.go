@@ -46,3 +46,101 @@ func TestDoesntAllocateGPUs(t *testing.T) {
 	require.Len(t, avail.GPUs, 2)
 	require.Equal(t, avail, tracker.maxCapacity)
 }
+
+func newGPUTracker(sharing uint64) *LocalTracker {
+	return NewLocalTracker(LocalTrackerParams{
+		MaxCapacity: models.Resources{
+			GPU: 3,
+			GPUs: []models.GPU{
+				{Index: 0, Name: "Tesla T4", Vendor: models.GPUVendorNvidia, Memory: 15360},
+				{Index: 1, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+				{Index: 0, Name: "Radeon Pro VII", Vendor: models.GPUVendorAMDATI, Memory: 16384},
+			},
+		},
+		GPUSharing: sharing,
+	})
+}
+
+func TestAllocatesSelectedGPUs(t *testing.T) {
+	ctx := context.Background()
+	tracker := newGPUTracker(0)
+
+	added := tracker.AddIfHasCapacity(ctx, models.Resources{
+		GPU:         1,
+		GPUSelector: &models.GPUSelector{Vendor: models.GPUVendorNvidia, MinMemory: 40000},
+	})
+	require.NotNil(t, added)
+	require.Equal(t, []models.GPU{tracker.maxCapacity.GPUs[1]}, added.GPUs)
+
+	// the only matching GPU is in use
+	require.Nil(t, tracker.AddIfHasCapacity(ctx, models.Resources{
+		GPU:         1,
+		GPUSelector: &models.GPUSelector{Model: "a100"},
+	}))
+
+	added = tracker.AddIfHasCapacity(ctx, models.Resources{
+		GPU:         1,
+		GPUSelector: &models.GPUSelector{Vendor: models.GPUVendorAMDATI},
+	})
+	require.NotNil(t, added)
+	require.Equal(t, []models.GPU{tracker.maxCapacity.GPUs[2]}, added.GPUs)
+
+	avail := tracker.GetAvailableCapacity(ctx)
+	require.Equal(t, uint64(1), avail.GPU)
+	require.Equal(t, []models.GPU{tracker.maxCapacity.GPUs[0]}, avail.GPUs)
+
+	tracker.Remove(ctx, *added)
+	avail = tracker.GetAvailableCapacity(ctx)
+	require.Equal(t, uint64(2), avail.GPU)
+}
+
+func TestKeepsChosenGPUs(t *testing.T) {
+	ctx := context.Background()
+	tracker := newGPUTracker(0)
+
+	chosen := tracker.maxCapacity.GPUs[2]
+	added := tracker.AddIfHasCapacity(ctx, models.Resources{GPU: 1, GPUs: []models.GPU{chosen}})
+	require.NotNil(t, added)
+	require.Equal(t, []models.GPU{chosen}, added.GPUs)
+
+	// a GPU chosen by another tracker is replaced if it is in use
+	added = tracker.AddIfHasCapacity(ctx, models.Resources{GPU: 1, GPUs: []models.GPU{chosen}})
+	require.NotNil(t, added)
+	require.Equal(t, []models.GPU{tracker.maxCapacity.GPUs[0]}, added.GPUs)
+}
+
+func TestSharesGPUs(t *testing.T) {
+	ctx := context.Background()
+	tracker := newGPUTracker(2)
+	shared := models.Resources{GPU: 1, GPUSelector: &models.GPUSelector{Model: "T4", Shared: true}}
+
+	first := tracker.AddIfHasCapacity(ctx, shared)
+	require.NotNil(t, first)
+	second := tracker.AddIfHasCapacity(ctx, shared)
+	require.NotNil(t, second)
+	require.Equal(t, first.GPUs, second.GPUs)
+
+	// the GPU is shared by as many tasks as allowed
+	require.Nil(t, tracker.AddIfHasCapacity(ctx, shared))
+	require.Len(t, tracker.GetAvailableCapacity(ctx).GPUs, 2)
+
+	// shared GPUs aren't allocated to tasks that need them exclusively
+	require.Nil(t, tracker.AddIfHasCapacity(ctx, models.Resources{GPU: 1, GPUSelector: &models.GPUSelector{Model: "T4"}}))
+
+	tracker.Remove(ctx, *first)
+	avail := tracker.GetAvailableCapacity(ctx)
+	require.Len(t, avail.GPUs, 3)
+	require.Nil(t, tracker.AddIfHasCapacity(ctx, models.Resources{GPU: 1, GPUSelector: &models.GPUSelector{Model: "T4"}}))
+
+	tracker.Remove(ctx, *second)
+	require.NotNil(t, tracker.AddIfHasCapacity(ctx, models.Resources{GPU: 1, GPUSelector: &models.GPUSelector{Model: "T4"}}))
+}
+
+func TestDoesntShareGPUsByDefault(t *testing.T) {
+	ctx := context.Background()
+	tracker := newGPUTracker(0)
+	shared := models.Resources{GPU: 1, GPUSelector: &models.GPUSelector{Model: "T4", Shared: true}}
+
+	require.NotNil(t, tracker.AddIfHasCapacity(ctx, shared))
+	require.Nil(t, tracker.AddIfHasCapacity(ctx, shared))
+}
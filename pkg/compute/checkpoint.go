@@ -0,0 +1,225 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/filecopy"
+)
+
+// checkpointer publishes the checkpoint path of a running execution whenever
+// its files changed since the previous checkpoint. Unlike snapshots, every
+// checkpoint holds all the files of the checkpoint path, so that the latest
+// checkpoint is enough to restore an execution.
+type checkpointer struct {
+	executor *BaseExecutor
+	state    store.LocalExecutionState
+	config   *models.CheckpointConfig
+	// dir is the directory on the node that is mounted at the checkpoint path
+	dir     string
+	version int
+	// files holds the state of the files in the previous checkpoint, keyed by
+	// their path relative to dir
+	files map[string]snapshotFile
+}
+
+func newCheckpointer(e *BaseExecutor, state store.LocalExecutionState, resultsDir string) *checkpointer {
+	return &checkpointer{
+		executor: e,
+		state:    state,
+		config:   state.Execution.Job.Task().Checkpoint,
+		dir:      filepath.Join(resultsDir, models.CheckpointResultPathName),
+		files:    make(map[string]snapshotFile),
+	}
+}
+
+// run takes checkpoints on the configured interval until the context is canceled.
+func (c *checkpointer) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := c.checkpoint(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("version", c.version+1).Msg("failed to publish checkpoint")
+		}
+	}
+}
+
+// checkpoint publishes the checkpoint path if any of its files changed since
+// the previous checkpoint.
+func (c *checkpointer) checkpoint(ctx context.Context) error {
+	execution := c.state.Execution
+	current, changed, err := c.scan()
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	version := c.version + 1
+	checkpointDir, err := c.executor.resultsPath.PrepareCheckpointDir(execution.ID, version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(checkpointDir); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("failed to remove checkpoint folder at %s", checkpointDir)
+		}
+	}()
+	// the job can write to the checkpoint path, so links in it must not be
+	// followed to files outside of it
+	if err = filecopy.CopyDirNoFollow(c.dir, checkpointDir); err != nil {
+		return fmt.Errorf("error copying checkpoint: %w", err)
+	}
+
+	checkpoint := &models.Checkpoint{
+		Version:     version,
+		ExecutionID: execution.ID,
+		CreateTime:  time.Now().UTC().UnixNano(),
+	}
+	publishers := execution.Job.Task().AllPublishers()
+	for i, config := range publishers {
+		publishers[i] = versionedPublisherConfig(config, version, publisher.CheckpointKey)
+	}
+	if checkpoint.Results, err = c.executor.publish(ctx, execution, publishers, checkpointDir); err != nil {
+		return err
+	}
+
+	c.version = version
+	c.files = current
+	log.Ctx(ctx).Debug().Int("version", version).Int("files", len(current)).Msg("published checkpoint")
+
+	c.executor.callback.OnCheckpointComplete(ctx, CheckpointResult{
+		ExecutionMetadata: NewExecutionMetadata(execution),
+		RoutingMetadata: RoutingMetadata{
+			SourcePeerID: c.executor.ID,
+			TargetPeerID: c.state.RequesterNodeID,
+		},
+		Checkpoint: checkpoint,
+	})
+	return nil
+}
+
+// scan lists the files of the checkpoint path, and returns their state and
+// whether any file was added, changed or removed since the previous checkpoint.
+func (c *checkpointer) scan() (map[string]snapshotFile, bool, error) {
+	current := make(map[string]snapshotFile)
+	changed := false
+	err := filepath.WalkDir(c.dir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.dir, file)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		state := snapshotFile{size: info.Size(), modTime: info.ModTime(), published: name}
+		current[name] = state
+		if previous, ok := c.files[name]; !ok || previous.size != state.size || !previous.modTime.Equal(state.modTime) {
+			changed = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error scanning checkpoint path: %w", err)
+	}
+	return current, changed || len(current) != len(c.files), nil
+}
+
+// startCheckpoints publishes checkpoints of a running execution if the task
+// enables them. The returned function stops taking checkpoints and waits for
+// any checkpoint in progress to be published.
+func (e *BaseExecutor) startCheckpoints(ctx context.Context, state store.LocalExecutionState) func() {
+	if !state.Execution.Job.Task().Checkpoint.Enabled() {
+		return func() {}
+	}
+	resultsDir, err := e.resultsPath.EnsureResultsDir(state.Execution.ID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to find results folder. checkpoints will not be published")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		newCheckpointer(e, state, resultsDir).run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// prepareCheckpointDir creates the directory that is mounted at the task's
+// checkpoint path, and restores the checkpoint of a previous execution into
+// it if the execution has one.
+func prepareCheckpointDir(
+	ctx context.Context,
+	strgprovider storage.StorageProvider,
+	storageDirectory string,
+	execution *models.Execution,
+	resultsDir string,
+) error {
+	dir := filepath.Join(resultsDir, models.CheckpointResultPathName)
+	if err := os.MkdirAll(dir, StorageDirectoryPerms); err != nil {
+		return fmt.Errorf("error creating checkpoint dir %s: %w", dir, err)
+	}
+	if execution.CheckpointInput == nil {
+		return nil
+	}
+
+	volumes, cleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, execution.CheckpointInput)
+	if err != nil {
+		return fmt.Errorf("error downloading checkpoint: %w", err)
+	}
+	defer func() {
+		if err := cleanup(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to clean up downloaded checkpoint")
+		}
+	}()
+
+	for _, volume := range volumes {
+		source := volume.Volume.Source
+		info, err := os.Stat(source)
+		if err != nil {
+			return fmt.Errorf("error restoring checkpoint: %w", err)
+		}
+		if info.IsDir() {
+			err = filecopy.CopyDir(source, dir)
+		} else {
+			err = filecopy.CopyFile(source, filepath.Join(dir, filepath.Base(source)))
+		}
+		if err != nil {
+			return fmt.Errorf("error restoring checkpoint: %w", err)
+		}
+	}
+	log.Ctx(ctx).Info().Str("source", execution.CheckpointInput.Source.Type).Msg("restored checkpoint")
+	return nil
+}
@@ -0,0 +1,172 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/inline"
+)
+
+type CheckpointerSuite struct {
+	suite.Suite
+	resultsDir   string
+	checkpointer *checkpointer
+	// published holds the files of each published checkpoint
+	published [][]string
+	// checkpoints holds the checkpoints reported to the callback
+	checkpoints []*models.Checkpoint
+}
+
+func TestCheckpointerSuite(t *testing.T) {
+	suite.Run(t, new(CheckpointerSuite))
+}
+
+func (s *CheckpointerSuite) SetupTest() {
+	s.published = nil
+	s.checkpoints = nil
+	results := ResultsPath{ResultsDir: s.T().TempDir()}
+	var err error
+	s.resultsDir, err = results.PrepareResultsDir("e-123")
+	s.Require().NoError(err)
+
+	publishers := provider.NewMappedProvider(map[string]publisher.Publisher{
+		models.PublisherNoop: noop.NewNoopPublisherWithConfig(noop.PublisherConfig{
+			ExternalHooks: noop.PublisherExternalHooks{
+				PublishResult: func(ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+					s.published = append(s.published, s.listFiles(resultPath))
+					return *models.NewSpecConfig(models.PublisherNoop).
+						WithParam("Key", execution.Job.Task().Publisher.Params["Key"]), nil
+				},
+			},
+		}),
+	})
+	executor := NewBaseExecutor(BaseExecutorParams{
+		ID: "node-1",
+		Callback: CallbackMock{
+			OnCheckpointCompleteHandler: func(ctx context.Context, result CheckpointResult) {
+				s.checkpoints = append(s.checkpoints, result.Checkpoint)
+			},
+		},
+		ResultsPath: results,
+		Publishers:  publishers,
+	})
+
+	task := &models.Task{
+		Name:       "task",
+		Engine:     models.NewSpecConfig(models.EngineNoop),
+		Publisher:  models.NewSpecConfig(models.PublisherNoop).WithParam("Key", "results/"),
+		Checkpoint: &models.CheckpointConfig{Path: "/checkpoint", Interval: 60},
+	}
+	execution := &models.Execution{
+		ID:  "e-123",
+		Job: &models.Job{ID: "j-123", Tasks: []*models.Task{task}},
+	}
+	s.checkpointer = newCheckpointer(executor, store.LocalExecutionState{
+		Execution:       execution,
+		RequesterNodeID: "requester",
+	}, s.resultsDir)
+}
+
+func (s *CheckpointerSuite) write(name, content string) {
+	path := filepath.Join(s.resultsDir, models.CheckpointResultPathName, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	// make sure rewritten files are detected on file systems with coarse timestamps
+	modTime := time.Now().Add(time.Duration(len(s.published)) * time.Second)
+	s.Require().NoError(os.Chtimes(path, modTime, modTime))
+}
+
+func (s *CheckpointerSuite) listFiles(root string) []string {
+	var files []string
+	s.Require().NoError(filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	return files
+}
+
+func (s *CheckpointerSuite) checkpoint() {
+	s.Require().NoError(s.checkpointer.checkpoint(context.Background()))
+}
+
+func (s *CheckpointerSuite) TestPublishesAllFilesOnChange() {
+	// nothing was written yet
+	s.checkpoint()
+	s.Empty(s.published)
+
+	s.write("state.json", "1")
+	s.write("model/weights.bin", "w")
+	s.checkpoint()
+	s.Require().Len(s.published, 1)
+	s.Equal([]string{"model/weights.bin", "state.json"}, s.published[0])
+
+	// nothing changed, so nothing is published
+	s.checkpoint()
+	s.Len(s.published, 1)
+
+	s.write("state.json", "2")
+	s.checkpoint()
+	s.Require().Len(s.published, 2)
+	s.Equal([]string{"model/weights.bin", "state.json"}, s.published[1])
+
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, models.CheckpointResultPathName, "model/weights.bin")))
+	s.checkpoint()
+	s.Require().Len(s.published, 3)
+	s.Equal([]string{"state.json"}, s.published[2])
+
+	s.Require().Len(s.checkpoints, 3)
+	for i, checkpoint := range s.checkpoints {
+		s.Equal(i+1, checkpoint.Version)
+		s.Equal("e-123", checkpoint.ExecutionID)
+		s.Require().Len(checkpoint.Results, 1)
+	}
+	s.Equal("results-checkpoint-1/", s.checkpoints[0].Results[0].Result.Params["Key"])
+	s.Equal("results-checkpoint-3/", s.checkpoints[2].Results[0].Result.Params["Key"])
+
+	// staging folders are removed once published
+	entries, err := os.ReadDir(filepath.Dir(s.resultsDir))
+	s.Require().NoError(err)
+	s.Len(entries, 1)
+}
+
+func (s *CheckpointerSuite) TestPrepareRunArgumentsRestoresCheckpoint() {
+	execution := s.checkpointer.state.Execution
+	execution.CheckpointInput = &models.InputSource{
+		Source: models.NewSpecConfig(models.StorageSourceInline).WithParam("URL", "data:,restored"),
+		Alias:  models.CheckpointInputAlias,
+		Target: "/checkpoint",
+	}
+	storages := provider.NewMappedProvider(map[string]storage.Storage{
+		models.StorageSourceInline: inline.NewStorage(),
+	})
+
+	args, cleanup, err := PrepareRunArguments(context.Background(), storages, s.T().TempDir(), execution, s.resultsDir)
+	s.Require().NoError(err)
+	defer func() { s.NoError(cleanup(context.Background())) }()
+
+	s.Contains(args.Outputs, &models.ResultPath{Name: models.CheckpointResultPathName, Path: "/checkpoint"})
+	s.Empty(args.Inputs)
+	files := s.listFiles(filepath.Join(s.resultsDir, models.CheckpointResultPathName))
+	s.Require().Len(files, 1)
+	content, err := os.ReadFile(filepath.Join(s.resultsDir, models.CheckpointResultPathName, files[0]))
+	s.Require().NoError(err)
+	s.Equal("restored", string(content))
+}
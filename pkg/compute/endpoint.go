@@ -16,6 +16,14 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
 
+// ImageCache is the cache of the docker images pulled for jobs.
+type ImageCache interface {
+	// OnAskForBid records the image of a job the node is asked to bid on.
+	OnAskForBid(ctx context.Context, job models.Job)
+	// Advertised returns the cached images to advertise to the orchestrator.
+	Advertised() []string
+}
+
 type BaseEndpointParams struct {
 	ID              string
 	ExecutionStore  store.ExecutionStore
@@ -23,6 +31,8 @@ type BaseEndpointParams struct {
 	Bidder          Bidder
 	Executor        Executor
 	LogServer       *logstream.Server
+	// ImageCache is optional, and is nil if images are not cached.
+	ImageCache ImageCache
 }
 
 // Base implementation of Endpoint
@@ -33,6 +43,7 @@ type BaseEndpoint struct {
 	bidder          Bidder
 	executor        Executor
 	logServer       *logstream.Server
+	imageCache      ImageCache
 }
 
 func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
@@ -43,6 +54,7 @@ func NewBaseEndpoint(params BaseEndpointParams) BaseEndpoint {
 		bidder:          params.Bidder,
 		executor:        params.Executor,
 		logServer:       params.LogServer,
+		imageCache:      params.ImageCache,
 	}
 }
 
@@ -56,6 +68,9 @@ func (s BaseEndpoint) AskForBid(ctx context.Context, request AskForBidRequest) (
 	log.Ctx(ctx).Debug().Msgf("asked to bid on: %+v", request)
 	jobsReceived.Add(ctx, 1)
 
+	if s.imageCache != nil {
+		s.imageCache.OnAskForBid(ctx, *request.Execution.Job)
+	}
 	go s.bidder.RunBidding(ctx, request, s.usageCalculator) // TODO: context shareable?
 
 	return AskForBidResponse{ExecutionMetadata: ExecutionMetadata{
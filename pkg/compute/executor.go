@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/rs/zerolog/log"
@@ -13,9 +14,11 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/telemetry"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/scratch"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	wasmmodels "github.com/bacalhau-project/bacalhau/pkg/executor/wasm/models"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
@@ -33,6 +36,9 @@ type BaseExecutorParams struct {
 	Executors              executor.ExecutorProvider
 	ResultsPath            ResultsPath
 	Publishers             publisher.PublisherProvider
+	ManifestSigner         *ManifestSigner
+	Ports                  *PortAllocator
+	ScratchVolumes         *scratch.Manager
 	FailureInjectionConfig model.FailureInjectionComputeConfig
 }
 
@@ -47,10 +53,17 @@ type BaseExecutor struct {
 	executors        executor.ExecutorProvider
 	publishers       publisher.PublisherProvider
 	resultsPath      ResultsPath
+	manifestSigner   *ManifestSigner
+	ports            *PortAllocator
+	scratchVolumes   *scratch.Manager
 	failureInjection model.FailureInjectionComputeConfig
 }
 
 func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
+	resultsPath := params.ResultsPath
+	if resultsPath.dirs == nil {
+		resultsPath.dirs = new(sync.Map)
+	}
 	return &BaseExecutor{
 		ID:               params.ID,
 		callback:         params.Callback,
@@ -60,7 +73,10 @@ func NewBaseExecutor(params BaseExecutorParams) *BaseExecutor {
 		executors:        params.Executors,
 		publishers:       params.Publishers,
 		failureInjection: params.FailureInjectionConfig,
-		resultsPath:      params.ResultsPath,
+		resultsPath:      resultsPath,
+		manifestSigner:   params.ManifestSigner,
+		ports:            params.Ports,
+		scratchVolumes:   params.ScratchVolumes,
 	}
 }
 
@@ -131,6 +147,8 @@ func PrepareRunArguments(
 	resultsDir string,
 ) (*executor.RunCommandRequest, InputCleanupFn, error) {
 	var cleanupFuncs []func(context.Context) error
+	// storage drivers resolve the secrets of inputs in the job's namespace
+	ctx = secrets.ContextWithNamespace(ctx, execution.Job.Namespace)
 
 	inputVolumes, inputCleanup, err := prepareInputVolumes(ctx, strgprovider, storageDirectory, execution.Job.Task().InputSources...)
 	if err != nil {
@@ -138,6 +156,14 @@ func PrepareRunArguments(
 	}
 	cleanupFuncs = append(cleanupFuncs, inputCleanup)
 
+	outputs := execution.Job.Task().ResultPaths
+	if checkpoint := execution.Job.Task().Checkpoint; checkpoint.Enabled() {
+		if err = prepareCheckpointDir(ctx, strgprovider, storageDirectory, execution, resultsDir); err != nil {
+			return nil, nil, err
+		}
+		outputs = append(append([]*models.ResultPath{}, outputs...), checkpoint.ResultPath())
+	}
+
 	// TODO wasm requires special handling because its engine arguments are storage specs, and we need to
 	// download them before passing it to the wasm executor
 	/*
@@ -179,12 +205,14 @@ func PrepareRunArguments(
 	return &executor.RunCommandRequest{
 			JobID:        execution.Job.ID,
 			ExecutionID:  execution.ID,
+			Namespace:    execution.Job.Namespace,
 			Resources:    execution.TotalAllocatedResources(),
 			Network:      execution.Job.Task().Network,
-			Outputs:      execution.Job.Task().ResultPaths,
+			Outputs:      outputs,
 			Inputs:       inputVolumes,
 			ResultsDir:   resultsDir,
 			EngineParams: engineArgs,
+			HealthCheck:  execution.Job.Task().HealthCheck,
 			OutputLimits: executor.OutputLimits{
 				MaxStdoutFileLength:   system.MaxStdoutFileLength,
 				MaxStdoutReturnLength: system.MaxStdoutReturnLength,
@@ -206,14 +234,26 @@ func PrepareRunArguments(
 
 type StartResult struct {
 	cleanup InputCleanupFn
-	Err     error
+	// scratch is the scratch volume of the execution, if the node creates them.
+	scratch *scratch.Volume
+	// Endpoints are the addresses the published ports of the execution can be reached on.
+	Endpoints []*models.ExecutionEndpoint
+	Err       error
 }
 
 func (r *StartResult) Cleanup(ctx context.Context) error {
+	cleanupErr := new(multierror.Error)
 	if r.cleanup != nil {
-		return r.cleanup(ctx)
+		if err := r.cleanup(ctx); err != nil {
+			cleanupErr = multierror.Append(cleanupErr, err)
+		}
+	}
+	if r.scratch != nil {
+		if err := r.scratch.Remove(ctx); err != nil {
+			cleanupErr = multierror.Append(cleanupErr, err)
+		}
 	}
-	return nil
+	return cleanupErr.ErrorOrNil()
 }
 
 func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *StartResult {
@@ -224,6 +264,17 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		return result
 	}
 
+	// executions get a scratch volume sized to their disk request, which is reused if the execution was already started.
+	// their results are written to the volume too, so that they count towards its size.
+	if resources := execution.TotalAllocatedResources(); e.scratchVolumes != nil && resources != nil && resources.Disk > 0 {
+		result.scratch, err = e.scratchVolumes.Create(ctx, execution.ID, resources.Disk)
+		if err != nil {
+			result.Err = fmt.Errorf("preparing scratch volume: %w", err)
+			return result
+		}
+		e.resultsPath.SetResultsDir(execution.ID, result.scratch.ResultsDir())
+	}
+
 	resultFolder, err := e.resultsPath.PrepareResultsDir(execution.ID)
 	if err != nil {
 		result.Err = fmt.Errorf("preparing results path: %w", err)
@@ -243,6 +294,26 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		return result
 	}
 
+	if result.scratch != nil {
+		args.ScratchVolume = &storage.StorageVolume{
+			Type:   storage.StorageVolumeConnectorBind,
+			Source: result.scratch.ScratchDir(),
+			Target: scratch.Target,
+		}
+	}
+
+	if network := execution.Job.Task().Network; network != nil && len(network.Ports) > 0 {
+		if e.ports == nil {
+			result.Err = fmt.Errorf("publishing ports is not enabled on this node")
+			return result
+		}
+		args.Endpoints, err = e.ports.Allocate(execution.ID, network.Ports)
+		if err != nil {
+			result.Err = fmt.Errorf("allocating ports: %w", err)
+			return result
+		}
+	}
+
 	if err := e.store.UpdateExecutionState(ctx, store.UpdateExecutionStateRequest{
 		ExecutionID: execution.ID,
 		ExpectedStates: []store.LocalExecutionStateType{
@@ -266,6 +337,7 @@ func (e *BaseExecutor) Start(ctx context.Context, execution *models.Execution) *
 		log.Ctx(ctx).Error().Err(err).Msg("failed to start execution")
 		result.Err = err
 	}
+	result.Endpoints = args.Endpoints
 
 	return result
 }
@@ -318,6 +390,10 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		if err := res.Cleanup(ctx); err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to clean up start arguments")
 		}
+		if e.ports != nil {
+			e.ports.Release(execution.ID)
+		}
+		e.resultsPath.ResetResultsDir(execution.ID)
 	}()
 	if err := res.Err; err != nil {
 		if errors.Is(err, executor.ErrAlreadyStarted) {
@@ -335,7 +411,24 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		}
 	}
 
+	if len(res.Endpoints) > 0 {
+		e.callback.OnEndpointsAllocated(ctx, EndpointsResult{
+			ExecutionMetadata: NewExecutionMetadata(execution),
+			RoutingMetadata: RoutingMetadata{
+				SourcePeerID: e.ID,
+				TargetPeerID: state.RequesterNodeID,
+			},
+			Endpoints: res.Endpoints,
+		})
+	}
+
+	stopSnapshots := e.startSnapshots(ctx, state)
+	stopCheckpoints := e.startCheckpoints(ctx, state)
+	stopHealthReports := e.startHealthReports(ctx, state)
 	result, err := e.Wait(ctx, state)
+	stopHealthReports()
+	stopCheckpoints()
+	stopSnapshots()
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			// TODO(forrest) [correctness]:
@@ -359,6 +452,15 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 		}
 		return err
 	}
+	if (result.ErrorMsg != "" || result.ExitCode != 0) && res.scratch != nil {
+		// executions that failed after filling their scratch volume most likely failed because of it
+		full, err := res.scratch.Full()
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to check usage of scratch volume")
+		} else if full {
+			return res.scratch.QuotaExceededError()
+		}
+	}
 	if result.ErrorMsg != "" {
 		return fmt.Errorf("execution error: %s", result.ErrorMsg)
 	}
@@ -366,9 +468,10 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 
 	expectedState := store.ExecutionStateRunning
 	publishedResult := models.SpecConfig{}
+	var publishedResults []*models.PublishedResult
 
 	// publish if the job has a publisher defined
-	if !execution.Job.Task().Publisher.IsEmpty() {
+	if len(execution.Job.Task().AllPublishers()) > 0 {
 		operation = "Publishing"
 		if err := e.store.UpdateExecutionState(ctx, store.UpdateExecutionStateRequest{
 			ExecutionID:    execution.ID,
@@ -394,10 +497,26 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 			}
 		}()
 
-		publishedResult, err = e.publish(ctx, state, resultsDir)
+		// checkpoints are only needed while the execution runs, and are not published with its results
+		if err = os.RemoveAll(filepath.Join(resultsDir, models.CheckpointResultPathName)); err != nil {
+			return err
+		}
+		if _, err = e.resultsPath.FinalizeResults(ctx, resultsDir, execution.Job.Task().ResultPaths); err != nil {
+			return err
+		}
+		if e.manifestSigner != nil {
+			if result.Manifest, err = e.manifestSigner.Sign(resultsDir); err != nil {
+				return err
+			}
+		}
+
+		publishedResults, err = e.publish(ctx, execution, execution.Job.Task().AllPublishers(), resultsDir)
 		if err != nil {
 			return err
 		}
+		if len(publishedResults) > 0 {
+			publishedResult = *publishedResults[0].Result
+		}
 	}
 
 	// mark the execution as completed
@@ -417,33 +536,96 @@ func (e *BaseExecutor) Run(ctx context.Context, state store.LocalExecutionState)
 			TargetPeerID: state.RequesterNodeID,
 		},
 		PublishResult:    &publishedResult,
+		PublishResults:   publishedResults,
 		RunCommandResult: result,
 	})
 	return err
 }
 
-// Publish the result of an execution after it has been verified.
-func (e *BaseExecutor) publish(ctx context.Context, localExecutionState store.LocalExecutionState,
-	resultFolder string) (publishedResult models.SpecConfig, err error) {
-	execution := localExecutionState.Execution
+// startSnapshots publishes snapshots of the results of a running execution
+// if the task enables them. The returned function stops taking snapshots and
+// waits for any snapshot in progress to be published.
+func (e *BaseExecutor) startSnapshots(ctx context.Context, state store.LocalExecutionState) func() {
+	if !state.Execution.Job.Task().Snapshots.Enabled() {
+		return func() {}
+	}
+	resultsDir, err := e.resultsPath.EnsureResultsDir(state.Execution.ID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to find results folder. snapshots will not be published")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		newSnapshotter(e, state, resultsDir).run(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// Publish the result of an execution with each of the task's publishers in
+// parallel. Results are returned in the order the publishers are declared.
+// Failures of optional publishers are logged and their results omitted.
+func (e *BaseExecutor) publish(ctx context.Context, execution *models.Execution,
+	publishers []*models.PublisherConfig, resultFolder string) ([]*models.PublishedResult, error) {
 	log.Ctx(ctx).Debug().Msgf("Publishing execution %s", execution.ID)
 
-	jobPublisher, err := e.publishers.Get(ctx, execution.Job.Task().Publisher.Type)
-	if err != nil {
-		err = fmt.Errorf("failed to get publisher %s: %w", execution.Job.Task().Publisher.Type, err)
-		return
+	results := make([]*models.PublishedResult, len(publishers))
+	errs := make([]error, len(publishers))
+
+	var wg sync.WaitGroup
+	for i, config := range publishers {
+		wg.Add(1)
+		go func(i int, config *models.PublisherConfig) {
+			defer wg.Done()
+			result, err := e.publishWith(ctx, execution, config, resultFolder)
+			if err == nil {
+				results[i] = &models.PublishedResult{Publisher: config.Type, Result: &result}
+			} else if config.Optional {
+				log.Ctx(ctx).Warn().Err(err).Str("publisher", config.Type).Msg("optional publisher failed")
+			} else {
+				errs[i] = err
+			}
+		}(i, config)
 	}
-	publishedResult, err = jobPublisher.PublishResult(ctx, execution, resultFolder)
-	if err != nil {
-		err = fmt.Errorf("failed to publish result: %w", err)
-		return
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("failed to publish result: %w", err)
+	}
+
+	publishedResults := make([]*models.PublishedResult, 0, len(results))
+	for _, result := range results {
+		if result != nil {
+			publishedResults = append(publishedResults, result)
+		}
 	}
 
 	log.Ctx(ctx).Debug().
 		Str("execution", execution.ID).
+		Int("results", len(publishedResults)).
 		Msg("Execution published")
 
-	return
+	return publishedResults, nil
+}
+
+// publishWith publishes the result of an execution with a single publisher.
+func (e *BaseExecutor) publishWith(ctx context.Context, execution *models.Execution,
+	config *models.PublisherConfig, resultFolder string) (models.SpecConfig, error) {
+	jobPublisher, err := e.publishers.Get(ctx, config.Type)
+	if err != nil {
+		return models.SpecConfig{}, fmt.Errorf("failed to get publisher %s: %w", config.Type, err)
+	}
+
+	// publishers read their configuration from the task, so each one is
+	// given a copy of the execution with its own config as the task publisher
+	publisherExecution := execution.Copy()
+	publisherExecution.Job.Task().Publisher = config.Spec().Copy()
+	return jobPublisher.PublishResult(ctx, publisherExecution, resultFolder)
 }
 
 // Cancel the execution.
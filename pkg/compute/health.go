@@ -0,0 +1,59 @@
+package compute
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
+)
+
+// startHealthReports reports the changes in the health of a running
+// execution to the requester if its task has a health check. The returned
+// function stops reporting.
+func (e *BaseExecutor) startHealthReports(ctx context.Context, state store.LocalExecutionState) func() {
+	execution := state.Execution
+	if execution.Job.Task().HealthCheck == nil {
+		return func() {}
+	}
+	jobExecutor, err := e.executors.Get(ctx, execution.Job.Task().Engine.Type)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to get executor. health checks will not be reported")
+		return func() {}
+	}
+	reporter, ok := jobExecutor.(executor.HealthReporter)
+	if !ok {
+		log.Ctx(ctx).Warn().Msgf("executor %s does not support health checks", execution.Job.Task().Engine.Type)
+		return func() {}
+	}
+	updates, err := reporter.WatchHealth(ctx, execution.ID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to watch execution health. health checks will not be reported")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case health, ok := <-updates:
+				if !ok {
+					return
+				}
+				log.Ctx(ctx).Debug().Msgf("execution %s is %s", execution.ID, health.Status)
+				e.callback.OnHealthUpdate(ctx, HealthResult{
+					ExecutionMetadata: NewExecutionMetadata(execution),
+					RoutingMetadata: RoutingMetadata{
+						SourcePeerID: e.ID,
+						TargetPeerID: state.RequesterNodeID,
+					},
+					Health: health.Copy(),
+				})
+			}
+		}
+	}()
+	return cancel
+}
@@ -0,0 +1,58 @@
+package compute
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ManifestSigner signs the results manifest of executions with the node's key.
+type ManifestSigner struct {
+	nodeID    string
+	key       crypto.PrivKey
+	publicKey string
+}
+
+func NewManifestSigner(nodeID string, key crypto.PrivKey) (*ManifestSigner, error) {
+	publicKey, err := crypto.MarshalPublicKey(key.GetPublic())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node public key: %w", err)
+	}
+	return &ManifestSigner{
+		nodeID:    nodeID,
+		key:       key,
+		publicKey: base64.StdEncoding.EncodeToString(publicKey),
+	}, nil
+}
+
+// PublicKey returns the base64 encoded public key of the node, which verifies
+// the signatures.
+func (s *ManifestSigner) PublicKey() string {
+	return s.publicKey
+}
+
+// Sign signs the manifest written at the root of the results directory.
+func (s *ManifestSigner) Sign(resultsDir string) (*models.ResultManifestSignature, error) {
+	data, err := os.ReadFile(filepath.Join(resultsDir, models.ResultManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results manifest: %w", err)
+	}
+	signature, err := s.key.Sign(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign results manifest: %w", err)
+	}
+	checksum := sha256.Sum256(data)
+	return &models.ResultManifestSignature{
+		NodeID:    s.nodeID,
+		SHA256:    hex.EncodeToString(checksum[:]),
+		Signature: base64.StdEncoding.EncodeToString(signature),
+		PublicKey: s.publicKey,
+	}, nil
+}
@@ -227,6 +227,30 @@ func (mr *MockCallbackMockRecorder) OnComputeFailure(ctx, err any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnComputeFailure", reflect.TypeOf((*MockCallback)(nil).OnComputeFailure), ctx, err)
 }
 
+// OnEndpointsAllocated mocks base method.
+func (m *MockCallback) OnEndpointsAllocated(ctx context.Context, result EndpointsResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnEndpointsAllocated", ctx, result)
+}
+
+// OnEndpointsAllocated indicates an expected call of OnEndpointsAllocated.
+func (mr *MockCallbackMockRecorder) OnEndpointsAllocated(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnEndpointsAllocated", reflect.TypeOf((*MockCallback)(nil).OnEndpointsAllocated), ctx, result)
+}
+
+// OnHealthUpdate mocks base method.
+func (m *MockCallback) OnHealthUpdate(ctx context.Context, result HealthResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnHealthUpdate", ctx, result)
+}
+
+// OnHealthUpdate indicates an expected call of OnHealthUpdate.
+func (mr *MockCallbackMockRecorder) OnHealthUpdate(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnHealthUpdate", reflect.TypeOf((*MockCallback)(nil).OnHealthUpdate), ctx, result)
+}
+
 // OnRunComplete mocks base method.
 func (m *MockCallback) OnRunComplete(ctx context.Context, result RunResult) {
 	m.ctrl.T.Helper()
@@ -238,3 +262,27 @@ func (mr *MockCallbackMockRecorder) OnRunComplete(ctx, result any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnRunComplete", reflect.TypeOf((*MockCallback)(nil).OnRunComplete), ctx, result)
 }
+
+// OnSnapshotComplete mocks base method.
+func (m *MockCallback) OnSnapshotComplete(ctx context.Context, result SnapshotResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnSnapshotComplete", ctx, result)
+}
+
+// OnSnapshotComplete indicates an expected call of OnSnapshotComplete.
+func (mr *MockCallbackMockRecorder) OnSnapshotComplete(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnSnapshotComplete", reflect.TypeOf((*MockCallback)(nil).OnSnapshotComplete), ctx, result)
+}
+
+// OnCheckpointComplete mocks base method.
+func (m *MockCallback) OnCheckpointComplete(ctx context.Context, result CheckpointResult) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnCheckpointComplete", ctx, result)
+}
+
+// OnCheckpointComplete indicates an expected call of OnCheckpointComplete.
+func (mr *MockCallbackMockRecorder) OnCheckpointComplete(ctx, result any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnCheckpointComplete", reflect.TypeOf((*MockCallback)(nil).OnCheckpointComplete), ctx, result)
+}
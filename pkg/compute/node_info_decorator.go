@@ -17,6 +17,11 @@ type NodeInfoDecoratorParams struct {
 	CapacityTracker    capacity.Tracker
 	ExecutorBuffer     *ExecutorBuffer
 	MaxJobRequirements models.Resources
+	// ImageCache is optional, and is nil if images are not cached.
+	ImageCache ImageCache
+	// PublicKey is the key that verifies the results manifests signed by the
+	// node, which is empty if they are not signed.
+	PublicKey string
 }
 
 type NodeInfoDecorator struct {
@@ -26,6 +31,8 @@ type NodeInfoDecorator struct {
 	capacityTracker    capacity.Tracker
 	executorBuffer     *ExecutorBuffer
 	maxJobRequirements models.Resources
+	imageCache         ImageCache
+	publicKey          string
 }
 
 func NewNodeInfoDecorator(params NodeInfoDecoratorParams) *NodeInfoDecorator {
@@ -36,11 +43,14 @@ func NewNodeInfoDecorator(params NodeInfoDecoratorParams) *NodeInfoDecorator {
 		capacityTracker:    params.CapacityTracker,
 		executorBuffer:     params.ExecutorBuffer,
 		maxJobRequirements: params.MaxJobRequirements,
+		imageCache:         params.ImageCache,
+		publicKey:          params.PublicKey,
 	}
 }
 
 func (n *NodeInfoDecorator) DecorateNodeInfo(ctx context.Context, nodeInfo models.NodeInfo) models.NodeInfo {
 	nodeInfo.NodeType = models.NodeTypeCompute
+	nodeInfo.PublicKey = n.publicKey
 	nodeInfo.ComputeNodeInfo = &models.ComputeNodeInfo{
 		ExecutionEngines:   n.executors.Keys(ctx),
 		Publishers:         n.publishers.Keys(ctx),
@@ -51,6 +61,9 @@ func (n *NodeInfoDecorator) DecorateNodeInfo(ctx context.Context, nodeInfo model
 		RunningExecutions:  len(n.executorBuffer.RunningExecutions()),
 		EnqueuedExecutions: n.executorBuffer.EnqueuedExecutionsCount(),
 	}
+	if n.imageCache != nil {
+		nodeInfo.ComputeNodeInfo.CachedImages = n.imageCache.Advertised()
+	}
 	return nodeInfo
 }
 
@@ -0,0 +1,150 @@
+package compute
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/network"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type PortAllocatorParams struct {
+	// Start and End are the range of host ports that are allocated, inclusive.
+	Start int
+	End   int
+	// Address is the address advertised for the allocated ports, or a type
+	// of address such as private or public to look up on the host.
+	Address string
+}
+
+// PortAllocator allocates the host ports that the ports of tasks are
+// published on.
+type PortAllocator struct {
+	start     int
+	end       int
+	host      string
+	next      int
+	allocated map[int]string
+	endpoints map[string][]*models.ExecutionEndpoint
+	mu        sync.Mutex
+}
+
+func NewPortAllocator(params PortAllocatorParams) (*PortAllocator, error) {
+	if params.Start <= 0 || params.End > 65535 || params.Start > params.End {
+		return nil, fmt.Errorf("invalid port range %d-%d", params.Start, params.End)
+	}
+	return &PortAllocator{
+		start:     params.Start,
+		end:       params.End,
+		host:      resolveHost(params.Address),
+		next:      params.Start,
+		allocated: make(map[int]string),
+		endpoints: make(map[string][]*models.ExecutionEndpoint),
+	}, nil
+}
+
+// resolveHost returns the address to advertise, looking up an address of
+// the host if given a type of address.
+func resolveHost(address string) string {
+	addressType, ok := network.AddressTypeFromString(address)
+	if !ok {
+		return address
+	}
+	addrs, err := network.GetNetworkAddress(addressType, network.AllAddresses)
+	if err == nil && len(addrs) > 0 {
+		return addrs[0]
+	}
+	log.Error().Err(err).Stringer("AddressType", addressType).
+		Msg("unable to find address for published ports, using 127.0.0.1")
+	return "127.0.0.1"
+}
+
+// Allocate allocates a host port to each of the ports, and returns the
+// endpoints they can be reached on. The same endpoints are returned if the
+// execution already has ports allocated.
+func (a *PortAllocator) Allocate(executionID string, ports []*models.PortConfig) ([]*models.ExecutionEndpoint, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if endpoints, ok := a.endpoints[executionID]; ok {
+		return endpoints, nil
+	}
+
+	endpoints := make([]*models.ExecutionEndpoint, 0, len(ports))
+	for _, port := range ports {
+		hostPort, err := a.allocate(executionID, port.Protocol)
+		if err != nil {
+			a.release(executionID)
+			return nil, fmt.Errorf("failed to allocate host port for port %q: %w", port.Name, err)
+		}
+		endpoints = append(endpoints, &models.ExecutionEndpoint{
+			Name:     port.Name,
+			Protocol: port.Protocol,
+			Host:     a.host,
+			Port:     hostPort,
+			Target:   port.Target,
+		})
+	}
+	a.endpoints[executionID] = endpoints
+	return endpoints, nil
+}
+
+// allocate returns the next host port in the range that is neither
+// allocated nor in use by another process.
+func (a *PortAllocator) allocate(executionID string, protocol string) (int, error) {
+	size := a.end - a.start + 1
+	for i := 0; i < size; i++ {
+		port := a.next
+		a.next++
+		if a.next > a.end {
+			a.next = a.start
+		}
+		if _, ok := a.allocated[port]; ok {
+			continue
+		}
+		if !portAvailable(port, protocol) {
+			continue
+		}
+		a.allocated[port] = executionID
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free ports left in range %d-%d", a.start, a.end)
+}
+
+// Release frees the host ports allocated to an execution.
+func (a *PortAllocator) Release(executionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.release(executionID)
+}
+
+func (a *PortAllocator) release(executionID string) {
+	for port, owner := range a.allocated {
+		if owner == executionID {
+			delete(a.allocated, port)
+		}
+	}
+	delete(a.endpoints, executionID)
+}
+
+// portAvailable checks whether a host port can be bound.
+func portAvailable(port int, protocol string) bool {
+	address := ":" + strconv.Itoa(port)
+	if protocol == models.PortProtocolUDP {
+		conn, err := net.ListenPacket("udp", address)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return false
+	}
+	_ = listener.Close()
+	return true
+}
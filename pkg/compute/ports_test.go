@@ -0,0 +1,81 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/network"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type PortAllocatorSuite struct {
+	suite.Suite
+	start     int
+	allocator *PortAllocator
+}
+
+func TestPortAllocatorSuite(t *testing.T) {
+	suite.Run(t, new(PortAllocatorSuite))
+}
+
+func (s *PortAllocatorSuite) SetupTest() {
+	var err error
+	s.start, err = network.GetFreePort()
+	s.Require().NoError(err)
+	s.allocator, err = NewPortAllocator(PortAllocatorParams{Start: s.start, End: s.start + 1, Address: "10.0.0.1"})
+	s.Require().NoError(err)
+}
+
+func (s *PortAllocatorSuite) ports(names ...string) []*models.PortConfig {
+	ports := make([]*models.PortConfig, len(names))
+	for i, name := range names {
+		ports[i] = &models.PortConfig{Name: name, Target: 8080 + i, Protocol: models.PortProtocolTCP}
+	}
+	return ports
+}
+
+func (s *PortAllocatorSuite) TestAllocate() {
+	endpoints, err := s.allocator.Allocate("e-1", s.ports("http"))
+	s.Require().NoError(err)
+	s.Require().Len(endpoints, 1)
+	s.Equal(models.ExecutionEndpoint{
+		Name: "http", Protocol: models.PortProtocolTCP, Host: "10.0.0.1", Port: s.start, Target: 8080,
+	}, *endpoints[0])
+
+	// allocating again returns the same endpoints
+	again, err := s.allocator.Allocate("e-1", s.ports("http"))
+	s.Require().NoError(err)
+	s.Equal(endpoints, again)
+}
+
+func (s *PortAllocatorSuite) TestRangeExhausted() {
+	_, err := s.allocator.Allocate("e-1", s.ports("http", "metrics"))
+	s.Require().NoError(err)
+
+	_, err = s.allocator.Allocate("e-2", s.ports("http"))
+	s.ErrorContains(err, "no free ports left")
+
+	s.allocator.Release("e-1")
+	_, err = s.allocator.Allocate("e-2", s.ports("http"))
+	s.NoError(err)
+}
+
+func (s *PortAllocatorSuite) TestSkipsPortsInUse() {
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(s.start)))
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	endpoints, err := s.allocator.Allocate("e-1", s.ports("http"))
+	s.Require().NoError(err)
+	s.Equal(s.start+1, endpoints[0].Port)
+}
+
+func (s *PortAllocatorSuite) TestInvalidRange() {
+	_, err := NewPortAllocator(PortAllocatorParams{Start: 32000, End: 31000})
+	s.ErrorContains(err, "invalid port range")
+}
@@ -1,16 +1,41 @@
 package compute
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
-	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+	"github.com/klauspost/compress/zstd"
 	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
 )
 
+// compressionExtensions maps each compression to the extension appended to
+// the names of compressed files.
+var compressionExtensions = map[string]string{
+	models.CompressionGzip: ".gz",
+	models.CompressionZstd: ".zst",
+}
+
 type ResultsPath struct {
 	// where do we copy the results from jobs temporarily?
 	ResultsDir string
+
+	// dirs holds the results directories of executions that don't use the
+	// default one, e.g. because their results are written to their scratch volume.
+	dirs *sync.Map
 }
 
 func NewResultsPath() (*ResultsPath, error) {
@@ -20,13 +45,37 @@ func NewResultsPath() (*ResultsPath, error) {
 	}
 	return &ResultsPath{
 		ResultsDir: dir,
+		dirs:       new(sync.Map),
 	}, nil
 }
 
 func (results *ResultsPath) getResultsDir(executionID string) string {
+	if results.dirs != nil {
+		if dir, ok := results.dirs.Load(executionID); ok {
+			return dir.(string)
+		}
+	}
+	return results.getDefaultResultsDir(executionID)
+}
+
+// getDefaultResultsDir returns the results directory of an execution in ResultsDir.
+func (results *ResultsPath) getDefaultResultsDir(executionID string) string {
 	return fmt.Sprintf("%s/%s", results.ResultsDir, executionID)
 }
 
+// SetResultsDir sets the results directory of an execution, instead of the
+// default one in ResultsDir. The ResultsPath must be created by NewResultsPath.
+func (results *ResultsPath) SetResultsDir(executionID, dir string) {
+	results.dirs.Store(executionID, dir)
+}
+
+// ResetResultsDir forgets the results directory set for an execution.
+func (results *ResultsPath) ResetResultsDir(executionID string) {
+	if results.dirs != nil {
+		results.dirs.Delete(executionID)
+	}
+}
+
 // PrepareResultsDir creates a temporary directory to store the results of a job execution.
 func (results *ResultsPath) PrepareResultsDir(executionID string) (string, error) {
 	dir := results.getResultsDir(executionID)
@@ -52,6 +101,32 @@ func (results *ResultsPath) EnsureResultsDir(executionID string) (string, error)
 	return dir, err
 }
 
+// PrepareSnapshotDir creates a directory to stage a snapshot of the results
+// of a running execution before it is published.
+func (results *ResultsPath) PrepareSnapshotDir(executionID string, version int) (string, error) {
+	dir := fmt.Sprintf("%s-snapshot-%d", results.getDefaultResultsDir(executionID), version)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("error removing stale snapshot dir %s: %w", dir, err)
+	}
+	if err := os.MkdirAll(dir, util.OS_ALL_RWX); err != nil {
+		return "", fmt.Errorf("error creating snapshot dir %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// PrepareCheckpointDir creates a directory to stage a checkpoint of a running
+// execution before it is published.
+func (results *ResultsPath) PrepareCheckpointDir(executionID string, version int) (string, error) {
+	dir := fmt.Sprintf("%s-checkpoint-%d", results.getDefaultResultsDir(executionID), version)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("error removing stale checkpoint dir %s: %w", dir, err)
+	}
+	if err := os.MkdirAll(dir, util.OS_ALL_RWX); err != nil {
+		return "", fmt.Errorf("error creating checkpoint dir %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
 func (results *ResultsPath) Close() error {
 	if _, err := os.Stat(results.ResultsDir); os.IsNotExist(err) {
 		return nil
@@ -59,3 +134,195 @@ func (results *ResultsPath) Close() error {
 
 	return os.RemoveAll(results.ResultsDir)
 }
+
+// FinalizeResults applies the filtering and compression options of the
+// result paths to the results directory of an execution, and writes a
+// manifest of the remaining files with their checksums at the root of the
+// directory. It must be called before the results are handed to publishers.
+func (results *ResultsPath) FinalizeResults(
+	ctx context.Context, resultsDir string, paths []*models.ResultPath) (*models.ResultManifest, error) {
+	for _, path := range paths {
+		if !path.HasLocalName() {
+			return nil, fmt.Errorf("invalid result path name %q", path.Name)
+		}
+		if err := filterResultPath(ctx, filepath.Join(resultsDir, path.Name), path); err != nil {
+			return nil, fmt.Errorf("error filtering result path %s: %w", path.Name, err)
+		}
+	}
+
+	manifest, err := buildManifest(resultsDir, paths)
+	if err != nil {
+		return nil, fmt.Errorf("error building results manifest: %w", err)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(filepath.Join(resultsDir, models.ResultManifestFilename), data, models.DownloadFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf("error writing results manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// filterResultPath removes the files under root that should not be published
+// and compresses the rest if requested.
+func filterResultPath(ctx context.Context, root string, path *models.ResultPath) error {
+	maxSize, err := path.MaxFileSizeBytes()
+	if err != nil {
+		return err
+	}
+	info, err := os.Lstat(root)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		// the result path is a single file, so only the size and compression apply
+		return filterResultFile(ctx, root, info, maxSize, path.Compression)
+	}
+
+	return filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if path.Excludes(rel) {
+				log.Ctx(ctx).Debug().Str("path", file).Msg("excluding result directory")
+				if err = os.RemoveAll(file); err != nil {
+					return err
+				}
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !path.Publishes(rel) {
+			log.Ctx(ctx).Debug().Str("path", file).Msg("excluding result file")
+			return os.Remove(file)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return filterResultFile(ctx, file, info, maxSize, path.Compression)
+	})
+}
+
+func filterResultFile(ctx context.Context, file string, info fs.FileInfo, maxSize uint64, compression string) error {
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	if maxSize > 0 && uint64(info.Size()) > maxSize {
+		log.Ctx(ctx).Warn().Str("path", file).Int64("size", info.Size()).
+			Msgf("result file exceeds max file size of %d bytes and will not be published", maxSize)
+		return os.Remove(file)
+	}
+	return compressResultFile(file, compression)
+}
+
+// compressResultFile replaces the file with a compressed copy that has the
+// extension of the compression format appended.
+func compressResultFile(file, compression string) (err error) {
+	var newWriter func(io.Writer) (io.WriteCloser, error)
+	switch compression {
+	case "", models.CompressionNone:
+		return nil
+	case models.CompressionGzip:
+		newWriter = func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil }
+	case models.CompressionZstd:
+		newWriter = func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) }
+	default:
+		return fmt.Errorf("unsupported compression %q", compression)
+	}
+	ext := compressionExtensions[compression]
+
+	in, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(file, in)
+
+	out, err := os.OpenFile(file+ext, os.O_CREATE|os.O_EXCL|os.O_WRONLY, models.DownloadFilePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	writer, err := newWriter(out)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(writer, in); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return os.Remove(file)
+}
+
+// buildManifest lists all regular files in the results directory along with
+// their checksums.
+func buildManifest(resultsDir string, paths []*models.ResultPath) (*models.ResultManifest, error) {
+	// compression is keyed by the top level entry of each result path, which
+	// for a single compressed file has the compression extension appended.
+	compression := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if ext, ok := compressionExtensions[path.Compression]; ok {
+			compression[path.Name] = path.Compression
+			compression[path.Name+ext] = path.Compression
+		}
+	}
+
+	manifest := &models.ResultManifest{Files: []models.ManifestFile{}}
+	err := filepath.WalkDir(resultsDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(resultsDir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == models.ResultManifestFilename {
+			return nil
+		}
+		size, checksum, err := sha256File(file)
+		if err != nil {
+			return err
+		}
+		name, _, _ := strings.Cut(rel, "/")
+		manifest.Files = append(manifest.Files, models.ManifestFile{
+			Path:        rel,
+			Size:        size,
+			SHA256:      checksum,
+			Compression: compression[name],
+		})
+		return nil
+	})
+	return manifest, err
+}
+
+func sha256File(file string) (int64, string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, "", err
+	}
+	defer closer.CloseWithLogOnError(file, f)
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,158 @@
+//go:build unit || !integration
+
+package compute_test
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ResultsPathSuite struct {
+	suite.Suite
+	results    *compute.ResultsPath
+	resultsDir string
+}
+
+func TestResultsPathSuite(t *testing.T) {
+	suite.Run(t, new(ResultsPathSuite))
+}
+
+func (s *ResultsPathSuite) SetupTest() {
+	s.results = &compute.ResultsPath{ResultsDir: s.T().TempDir()}
+	var err error
+	s.resultsDir, err = s.results.PrepareResultsDir("e-123")
+	s.Require().NoError(err)
+
+	s.write("stdout", "hello")
+	s.write("outputs/data.csv", "a,b,c")
+	s.write("outputs/report/summary.txt", "summary")
+	s.write("outputs/scratch/tmp.bin", strings.Repeat("x", 100))
+	s.write("outputs/large.bin", strings.Repeat("x", 2048))
+}
+
+func (s *ResultsPathSuite) write(name, content string) {
+	path := filepath.Join(s.resultsDir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+}
+
+func (s *ResultsPathSuite) finalize(paths ...*models.ResultPath) *models.ResultManifest {
+	manifest, err := s.results.FinalizeResults(context.Background(), s.resultsDir, paths)
+	s.Require().NoError(err)
+	return manifest
+}
+
+func (s *ResultsPathSuite) manifestPaths(manifest *models.ResultManifest) []string {
+	var paths []string
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func (s *ResultsPathSuite) TestNoFilters() {
+	manifest := s.finalize(&models.ResultPath{Name: "outputs", Path: "/outputs"})
+	s.ElementsMatch([]string{
+		"stdout",
+		"outputs/data.csv",
+		"outputs/report/summary.txt",
+		"outputs/scratch/tmp.bin",
+		"outputs/large.bin",
+	}, s.manifestPaths(manifest))
+
+	sum := sha256.Sum256([]byte("hello"))
+	for _, file := range manifest.Files {
+		if file.Path == "stdout" {
+			s.Equal(int64(5), file.Size)
+			s.Equal(hex.EncodeToString(sum[:]), file.SHA256)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.resultsDir, models.ResultManifestFilename))
+	s.Require().NoError(err)
+	var written models.ResultManifest
+	s.Require().NoError(json.Unmarshal(data, &written))
+	s.Equal(*manifest, written)
+}
+
+func (s *ResultsPathSuite) TestNameOutsideResults() {
+	outside := filepath.Join(filepath.Dir(s.resultsDir), "other")
+	s.Require().NoError(os.MkdirAll(outside, 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(outside, "data.csv"), []byte("a"), 0o600))
+
+	_, err := s.results.FinalizeResults(context.Background(), s.resultsDir, []*models.ResultPath{
+		{Name: "../other", Path: "/outputs", Exclude: []string{"**"}},
+	})
+	s.ErrorContains(err, "invalid result path name")
+	s.FileExists(filepath.Join(outside, "data.csv"))
+}
+
+func (s *ResultsPathSuite) TestIncludeExcludeAndMaxSize() {
+	manifest := s.finalize(&models.ResultPath{
+		Name:        "outputs",
+		Path:        "/outputs",
+		Include:     []string{"**/*.csv", "**/*.txt", "**/*.bin"},
+		Exclude:     []string{"scratch"},
+		MaxFileSize: "1KB",
+	})
+	s.ElementsMatch([]string{"stdout", "outputs/data.csv", "outputs/report/summary.txt"}, s.manifestPaths(manifest))
+	s.NoDirExists(filepath.Join(s.resultsDir, "outputs", "scratch"))
+	s.NoFileExists(filepath.Join(s.resultsDir, "outputs", "large.bin"))
+}
+
+func (s *ResultsPathSuite) TestGzip() {
+	manifest := s.finalize(&models.ResultPath{Name: "outputs", Path: "/outputs", Include: []string{"*.csv"},
+		Compression: models.CompressionGzip})
+	s.ElementsMatch([]string{"stdout", "outputs/data.csv.gz"}, s.manifestPaths(manifest))
+	for _, file := range manifest.Files {
+		if file.Path == "outputs/data.csv.gz" {
+			s.Equal(models.CompressionGzip, file.Compression)
+		} else {
+			s.Empty(file.Compression)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(s.resultsDir, "outputs", "data.csv.gz"))
+	s.Require().NoError(err)
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	s.Require().NoError(err)
+	content, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+	s.Equal("a,b,c", string(content))
+}
+
+func (s *ResultsPathSuite) TestZstdSingleFile() {
+	manifest := s.finalize(&models.ResultPath{Name: "stdout", Path: "/stdout", Compression: models.CompressionZstd})
+	s.Contains(s.manifestPaths(manifest), "stdout.zst")
+	s.NotContains(s.manifestPaths(manifest), "stdout")
+	for _, file := range manifest.Files {
+		if file.Path == "stdout.zst" {
+			s.Equal(models.CompressionZstd, file.Compression)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(s.resultsDir, "stdout.zst"))
+	s.Require().NoError(err)
+	defer f.Close()
+	reader, err := zstd.NewReader(f)
+	s.Require().NoError(err)
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	s.Require().NoError(err)
+	s.Equal("hello", string(content))
+}
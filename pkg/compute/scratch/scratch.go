@@ -0,0 +1,260 @@
+// Package scratch creates the scratch volumes that executions write to. Each
+// volume is sized to the disk request of its execution, which loopback and
+// XFS volumes enforce, so that an execution can't fill the disk of the node.
+// A volume holds both the scratch directory mounted into the execution and the
+// results directory its result paths are written to, so that both count
+// towards the size of the volume.
+package scratch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+)
+
+// The types of scratch volumes.
+const (
+	// TypeAuto picks the first type the node supports that enforces the size
+	// of volumes, which is xfs and then loopback, falling back to directory.
+	TypeAuto = "auto"
+	// TypeDirectory volumes are plain directories, which don't enforce the size of the volume.
+	TypeDirectory = "directory"
+	// TypeLoopback volumes are ext4 filesystems in sparse files, mounted as loop devices.
+	TypeLoopback = "loopback"
+	// TypeXFS volumes are directories limited by XFS project quotas.
+	TypeXFS = "xfs"
+)
+
+const (
+	// Target is the path scratch volumes are mounted at in executions.
+	Target = "/scratch"
+	// scratchDir and resultsDir are the directories of a volume that are
+	// mounted at Target and hold the results of the execution.
+	scratchDir = "scratch"
+	resultsDir = "results"
+	// volumePerms lets executions running as any user write to their volume.
+	volumePerms = 0o777
+	// loopbackImageSuffix is appended to the path of a loopback volume to get
+	// the image file it is mounted from.
+	loopbackImageSuffix = ".img"
+	// xfsProjectFileSuffix is appended to the path of an xfs volume to get the
+	// file its project ID is persisted in.
+	xfsProjectFileSuffix = ".project"
+	// fullThreshold is the free space below which a volume is considered full,
+	// as filesystems reserve some of their space and writes fail before a
+	// volume is completely used.
+	fullThreshold = 1 << 20
+)
+
+// ErrQuotaExceeded is returned for executions that failed after using all of their scratch volume.
+var ErrQuotaExceeded = errors.New("disk quota exceeded")
+
+// backend creates and removes the volumes of a type.
+type backend interface {
+	// create creates a volume of the size at the path, or reuses the volume
+	// if it already exists.
+	create(ctx context.Context, path string, size uint64) error
+	// remove removes the volume at the path.
+	remove(ctx context.Context, path string) error
+	// available returns the free space of the volume at the path.
+	available(path string, size uint64) (uint64, error)
+}
+
+type ManagerParams struct {
+	// Type is the type of the volumes that are created.
+	Type string
+	// Directory is where volumes are created.
+	Directory string
+}
+
+// Manager creates the scratch volumes of executions.
+type Manager struct {
+	directory string
+	backend   backend
+}
+
+func NewManager(params ManagerParams) (*Manager, error) {
+	if params.Directory == "" {
+		return nil, errors.New("scratch volumes directory is required")
+	}
+	if err := os.MkdirAll(params.Directory, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating scratch volumes directory %s: %w", params.Directory, err)
+	}
+
+	var b backend
+	var err error
+	volumeType := params.Type
+	switch params.Type {
+	case TypeAuto:
+		b, volumeType = newAutoBackend(params.Directory)
+	case TypeDirectory:
+		b = directoryBackend{}
+	case TypeLoopback:
+		b, err = newLoopbackBackend()
+	case TypeXFS:
+		b, err = newXFSBackend(params.Directory)
+	default:
+		return nil, fmt.Errorf("unknown scratch volume type %q", params.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if volumeType == TypeDirectory {
+		log.Warn().Str("directory", params.Directory).
+			Msg("Scratch volumes are plain directories, which don't enforce the disk requests of executions. " +
+				"Use loopback or xfs scratch volumes to enforce them.")
+	} else {
+		log.Debug().Str("type", volumeType).Str("directory", params.Directory).Msg("Scratch volumes enforce disk requests")
+	}
+	return &Manager{directory: params.Directory, backend: b}, nil
+}
+
+// Create creates the scratch volume of an execution. The existing volume is
+// returned if the execution already has one, e.g. when it is resumed after
+// the node restarted.
+func (m *Manager) Create(ctx context.Context, executionID string, size uint64) (*Volume, error) {
+	if size == 0 {
+		return nil, fmt.Errorf("scratch volume of execution %s has no size", executionID)
+	}
+	path := filepath.Join(m.directory, executionID)
+	if err := m.backend.create(ctx, path, size); err != nil {
+		return nil, fmt.Errorf("error creating scratch volume of %s for execution %s: %w",
+			units.BytesSize(float64(size)), executionID, err)
+	}
+	volume := &Volume{Path: path, Size: size, backend: m.backend}
+	for _, dir := range []string{volume.ScratchDir(), volume.ResultsDir()} {
+		if err := makeWritableDir(dir); err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("error creating %s in scratch volume of execution %s: %w", dir, executionID, err),
+				volume.Remove(ctx))
+		}
+	}
+	return volume, nil
+}
+
+// RemoveUnused removes the volumes of every execution other than the ones
+// given, such as the volumes left behind by executions that don't resume
+// after the node restarted.
+func (m *Manager) RemoveUnused(ctx context.Context, executionIDs []string) error {
+	entries, err := os.ReadDir(m.directory)
+	if err != nil {
+		return err
+	}
+	var unused []string
+	for _, entry := range entries {
+		// loopback images and xfs project files are kept next to their volume
+		executionID := strings.TrimSuffix(strings.TrimSuffix(entry.Name(), loopbackImageSuffix), xfsProjectFileSuffix)
+		if !entry.IsDir() && executionID == entry.Name() {
+			continue
+		}
+		if !slices.Contains(executionIDs, executionID) && !slices.Contains(unused, executionID) {
+			unused = append(unused, executionID)
+		}
+	}
+	var errs error
+	for _, executionID := range unused {
+		path := filepath.Join(m.directory, executionID)
+		if err = m.backend.remove(ctx, path); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("error removing unused scratch volume %s: %w", path, err))
+			continue
+		}
+		log.Ctx(ctx).Info().Str("ExecutionID", executionID).Msg("Removed unused scratch volume")
+	}
+	return errs
+}
+
+// Volume is the scratch volume of an execution.
+type Volume struct {
+	// Path is the root of the volume on the node.
+	Path string
+	// Size is the size of the volume in bytes.
+	Size    uint64
+	backend backend
+}
+
+// ScratchDir returns the directory of the volume that is mounted at Target.
+func (v *Volume) ScratchDir() string {
+	return filepath.Join(v.Path, scratchDir)
+}
+
+// ResultsDir returns the directory of the volume that the results of the
+// execution are written to.
+func (v *Volume) ResultsDir() string {
+	return filepath.Join(v.Path, resultsDir)
+}
+
+// Remove removes the volume and everything written to it.
+func (v *Volume) Remove(ctx context.Context) error {
+	if err := v.backend.remove(ctx, v.Path); err != nil {
+		return fmt.Errorf("error removing scratch volume %s: %w", v.Path, err)
+	}
+	return nil
+}
+
+// Full returns whether the volume has no space left.
+func (v *Volume) Full() (bool, error) {
+	available, err := v.backend.available(v.Path, v.Size)
+	if err != nil {
+		return false, err
+	}
+	return available < fullThreshold, nil
+}
+
+// QuotaExceededError returns the error of an execution that failed after
+// using all of the volume.
+func (v *Volume) QuotaExceededError() error {
+	return fmt.Errorf("%w: the execution used all of its %s scratch volume",
+		ErrQuotaExceeded, units.BytesSize(float64(v.Size)))
+}
+
+// directoryBackend creates volumes as plain directories. Their size isn't
+// enforced, but is used to tell whether an execution filled its volume.
+type directoryBackend struct{}
+
+func (directoryBackend) create(_ context.Context, path string, _ uint64) error {
+	return makeWritableDir(path)
+}
+
+func (directoryBackend) remove(_ context.Context, path string) error {
+	return os.RemoveAll(path)
+}
+
+func (directoryBackend) available(path string, size uint64) (uint64, error) {
+	var used uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		used += uint64(info.Size())
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if used >= size {
+		return 0, nil
+	}
+	return size - used, nil
+}
+
+// makeWritableDir creates a directory that executions running as any user can
+// write to.
+func makeWritableDir(path string) error {
+	if err := os.MkdirAll(path, volumePerms); err != nil {
+		return err
+	}
+	// set the permissions regardless of the umask
+	return os.Chmod(path, volumePerms)
+}
@@ -0,0 +1,351 @@
+//go:build linux
+
+package scratch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// xfsProjectIDStart is the first XFS project ID given to volumes, which is
+	// high enough not to clash with projects configured on the node by hand.
+	xfsProjectIDStart = 1_000_000
+	// loopControl is the device loop devices are allocated through.
+	loopControl = "/dev/loop-control"
+)
+
+// run runs a command, and returns its output in the error if it fails.
+func run(ctx context.Context, name string, args ...string) error {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// isMountPoint returns whether a filesystem is mounted at the path.
+func isMountPoint(path string) (bool, error) {
+	var stat, parent unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := unix.Stat(filepath.Dir(path), &parent); err != nil {
+		return false, err
+	}
+	return stat.Dev != parent.Dev, nil
+}
+
+// availableSpace returns the space of the filesystem at the path that is
+// available to unprivileged users, which is limited by any project quota of
+// the path on XFS.
+func availableSpace(path string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+// newAutoBackend returns the first backend the node supports that enforces
+// the size of volumes, and its type. XFS project quotas are used if the
+// directory is on XFS with project quotas enabled, then loopback volumes if
+// the node runs as root and can allocate loop devices.
+func newAutoBackend(directory string) (backend, string) {
+	if b, err := newXFSBackend(directory); err == nil {
+		return b, TypeXFS
+	}
+	if _, err := os.Stat(loopControl); err == nil && os.Geteuid() == 0 {
+		if b, err := newLoopbackBackend(); err == nil {
+			return b, TypeLoopback
+		}
+	}
+	return directoryBackend{}, TypeDirectory
+}
+
+// loopbackBackend creates volumes as ext4 filesystems in sparse files next to
+// their mount point, which are mounted as loop devices. The node must be able
+// to mount filesystems, which usually requires running as root.
+type loopbackBackend struct{}
+
+func newLoopbackBackend() (backend, error) {
+	for _, tool := range []string{"mkfs.ext4", "mount", "umount"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return nil, fmt.Errorf("loopback scratch volumes require %s: %w", tool, err)
+		}
+	}
+	return loopbackBackend{}, nil
+}
+
+func (b loopbackBackend) create(ctx context.Context, path string, size uint64) (err error) {
+	mounted, err := isMountPoint(path)
+	if err != nil || mounted {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = b.remove(ctx, path)
+		}
+	}()
+
+	image := path + loopbackImageSuffix
+	if _, err = os.Stat(image); os.IsNotExist(err) {
+		if err = b.createImage(ctx, image, size); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(path, volumePerms); err != nil {
+		return err
+	}
+	if err = run(ctx, "mount", "-o", "loop", image, path); err != nil {
+		return err
+	}
+	// the root of the new filesystem is only writable by root
+	return os.Chmod(path, volumePerms)
+}
+
+// createImage creates a sparse file of the size with an empty filesystem.
+func (loopbackBackend) createImage(ctx context.Context, image string, size uint64) error {
+	f, err := os.OpenFile(image, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	err = f.Truncate(int64(size))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	// don't reserve blocks for root, so that executions can use the whole volume
+	return run(ctx, "mkfs.ext4", "-q", "-F", "-m", "0", image)
+}
+
+func (loopbackBackend) remove(ctx context.Context, path string) error {
+	mounted, err := isMountPoint(path)
+	if err != nil {
+		return err
+	}
+	if mounted {
+		if err = run(ctx, "umount", path); err != nil {
+			return err
+		}
+	}
+	if err = os.Remove(path + loopbackImageSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(path)
+}
+
+func (loopbackBackend) available(path string, _ uint64) (uint64, error) {
+	return availableSpace(path)
+}
+
+// xfsBackend creates volumes as directories on an XFS filesystem mounted with
+// project quotas, and limits each directory with a project of its own. The
+// project ID of each volume is written to a file next to it, so that volumes
+// keep their project and new volumes don't reuse the project of an existing
+// one after the node restarts.
+type xfsBackend struct {
+	mountPoint string
+	mu         sync.Mutex
+	nextID     uint32
+	// projects holds the project ID of each volume
+	projects map[string]uint32
+}
+
+func newXFSBackend(directory string) (backend, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(directory, &stat); err != nil {
+		return nil, err
+	}
+	if stat.Type != unix.XFS_SUPER_MAGIC {
+		return nil, fmt.Errorf("xfs scratch volumes require %s to be on an XFS filesystem", directory)
+	}
+	if _, err := exec.LookPath("xfs_quota"); err != nil {
+		return nil, fmt.Errorf("xfs scratch volumes require xfs_quota: %w", err)
+	}
+	mountPoint, err := findMountPoint(directory)
+	if err != nil {
+		return nil, err
+	}
+	if enabled, err := hasProjectQuotas(mountPoint); err != nil {
+		return nil, err
+	} else if !enabled {
+		return nil, fmt.Errorf("xfs scratch volumes require %s to be mounted with project quotas", mountPoint)
+	}
+	b := &xfsBackend{
+		mountPoint: mountPoint,
+		nextID:     xfsProjectIDStart,
+		projects:   make(map[string]uint32),
+	}
+	if err = b.loadProjects(directory); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// loadProjects reads the project IDs of the volumes created before the node
+// restarted.
+func (b *xfsBackend) loadProjects(directory string) error {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), xfsProjectFileSuffix) {
+			continue
+		}
+		file := filepath.Join(directory, entry.Name())
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid xfs project ID in %s: %w", file, err)
+		}
+		b.projects[strings.TrimSuffix(file, xfsProjectFileSuffix)] = uint32(id)
+		if uint32(id) >= b.nextID {
+			b.nextID = uint32(id) + 1
+		}
+	}
+	return nil
+}
+
+// hasProjectQuotas returns whether the filesystem mounted at the mount point
+// enforces project quotas.
+func hasProjectQuotas(mountPoint string) (bool, error) {
+	mounts, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return false, err
+	}
+	enabled := false
+	for _, line := range strings.Split(string(mounts), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || unescapeMountPath(fields[1]) != mountPoint {
+			continue
+		}
+		// the last mount at the mount point is the one in use
+		enabled = false
+		for _, option := range strings.Split(fields[3], ",") {
+			if option == "prjquota" || option == "pquota" {
+				enabled = true
+			}
+		}
+	}
+	return enabled, nil
+}
+
+// unescapeMountPath decodes the octal escapes of whitespace and backslashes
+// in the paths of /proc/self/mounts.
+func unescapeMountPath(path string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(path)
+}
+
+// findMountPoint returns the mount point of the filesystem of the path.
+func findMountPoint(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	for path != filepath.Dir(path) {
+		mounted, err := isMountPoint(path)
+		if err != nil {
+			return "", err
+		}
+		if mounted {
+			return path, nil
+		}
+		path = filepath.Dir(path)
+	}
+	return path, nil
+}
+
+func (b *xfsBackend) create(ctx context.Context, path string, size uint64) (err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.projects[path]; ok {
+		return nil
+	}
+	quotedPath, err := quoteXFSPath(path)
+	if err != nil {
+		return err
+	}
+	if err = (directoryBackend{}).create(ctx, path, size); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(path)
+			_ = os.Remove(path + xfsProjectFileSuffix)
+		}
+	}()
+
+	// record the project before using it, so that it isn't reused if the node restarts in between
+	id := b.nextID
+	if err = os.WriteFile(path+xfsProjectFileSuffix, []byte(strconv.FormatUint(uint64(id), 10)), 0o600); err != nil {
+		return err
+	}
+	if err = b.quota(ctx, fmt.Sprintf("project -s -p %s %d", quotedPath, id)); err != nil {
+		return err
+	}
+	if err = b.quota(ctx, fmt.Sprintf("limit -p bhard=%d %d", size, id)); err != nil {
+		return err
+	}
+	b.nextID++
+	b.projects[path] = id
+	return nil
+}
+
+func (b *xfsBackend) remove(ctx context.Context, path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := os.RemoveAll(path); err != nil {
+		return err
+	}
+	id, ok := b.projects[path]
+	if !ok {
+		return nil
+	}
+	if err := b.quota(ctx, fmt.Sprintf("limit -p bhard=0 %d", id)); err != nil {
+		return err
+	}
+	delete(b.projects, path)
+	if err := os.Remove(path + xfsProjectFileSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (b *xfsBackend) available(path string, _ uint64) (uint64, error) {
+	return availableSpace(path)
+}
+
+// quota runs an xfs_quota command in expert mode on the filesystem.
+func (b *xfsBackend) quota(ctx context.Context, command string) error {
+	return run(ctx, "xfs_quota", "-x", "-c", command, b.mountPoint)
+}
+
+// quoteXFSPath quotes a path to be passed as an argument of an xfs_quota
+// command, which splits commands on whitespace.
+func quoteXFSPath(path string) (string, error) {
+	if strings.ContainsAny(path, "\"\\\n") {
+		return "", fmt.Errorf("xfs scratch volume path %q contains characters that can't be quoted", path)
+	}
+	return `"` + path + `"`, nil
+}
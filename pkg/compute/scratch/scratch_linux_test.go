@@ -0,0 +1,38 @@
+//go:build (unit || !integration) && linux
+
+package scratch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteXFSPath(t *testing.T) {
+	quoted, err := quoteXFSPath("/var/lib/bacalhau scratch/e-123")
+	require.NoError(t, err)
+	assert.Equal(t, `"/var/lib/bacalhau scratch/e-123"`, quoted)
+
+	for _, path := range []string{`/scratch/e-"123`, `/scratch/e-\123`, "/scratch/e-\n123"} {
+		_, err = quoteXFSPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestXFSLoadProjects(t *testing.T) {
+	directory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "e-1.project"), []byte("1000004\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "e-2.project"), []byte("1000001"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(directory, "e-1"), volumePerms))
+
+	b := &xfsBackend{nextID: xfsProjectIDStart, projects: make(map[string]uint32)}
+	require.NoError(t, b.loadProjects(directory))
+	assert.Equal(t, map[string]uint32{
+		filepath.Join(directory, "e-1"): 1000004,
+		filepath.Join(directory, "e-2"): 1000001,
+	}, b.projects)
+	assert.Equal(t, uint32(1000005), b.nextID)
+}
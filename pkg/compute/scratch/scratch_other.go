@@ -0,0 +1,18 @@
+//go:build !linux
+
+package scratch
+
+import "errors"
+
+// newAutoBackend falls back to directories, as no other type is supported.
+func newAutoBackend(string) (backend, string) {
+	return directoryBackend{}, TypeDirectory
+}
+
+func newLoopbackBackend() (backend, error) {
+	return nil, errors.New("loopback scratch volumes are only supported on Linux")
+}
+
+func newXFSBackend(string) (backend, error) {
+	return nil, errors.New("xfs scratch volumes are only supported on Linux")
+}
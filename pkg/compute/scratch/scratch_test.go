@@ -0,0 +1,104 @@
+//go:build unit || !integration
+
+package scratch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ScratchSuite struct {
+	suite.Suite
+	directory string
+	manager   *Manager
+}
+
+func TestScratchSuite(t *testing.T) {
+	suite.Run(t, new(ScratchSuite))
+}
+
+func (s *ScratchSuite) SetupTest() {
+	s.directory = filepath.Join(s.T().TempDir(), "scratch")
+	var err error
+	s.manager, err = NewManager(ManagerParams{Type: TypeDirectory, Directory: s.directory})
+	s.Require().NoError(err)
+}
+
+func (s *ScratchSuite) TestNewManagerRejectsUnknownType() {
+	_, err := NewManager(ManagerParams{Type: "tmpfs", Directory: s.directory})
+	s.ErrorContains(err, `unknown scratch volume type "tmpfs"`)
+}
+
+func (s *ScratchSuite) TestCreateAndRemove() {
+	ctx := context.Background()
+	volume, err := s.manager.Create(ctx, "e-123", 4<<20)
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(s.directory, "e-123"), volume.Path)
+	s.Equal(uint64(4<<20), volume.Size)
+
+	s.Equal(filepath.Join(volume.Path, "scratch"), volume.ScratchDir())
+	s.Equal(filepath.Join(volume.Path, "results"), volume.ResultsDir())
+	for _, dir := range []string{volume.Path, volume.ScratchDir(), volume.ResultsDir()} {
+		info, err := os.Stat(dir)
+		s.Require().NoError(err)
+		s.True(info.IsDir())
+		s.Equal(os.FileMode(volumePerms), info.Mode().Perm())
+	}
+
+	s.Require().NoError(volume.Remove(ctx))
+	s.NoDirExists(volume.Path)
+}
+
+func (s *ScratchSuite) TestCreateReusesExistingVolume() {
+	ctx := context.Background()
+	volume, err := s.manager.Create(ctx, "e-123", 4<<20)
+	s.Require().NoError(err)
+	s.Require().NoError(os.WriteFile(filepath.Join(volume.ScratchDir(), "data"), []byte("data"), 0o600))
+
+	volume, err = s.manager.Create(ctx, "e-123", 4<<20)
+	s.Require().NoError(err)
+	s.FileExists(filepath.Join(volume.ScratchDir(), "data"))
+}
+
+func (s *ScratchSuite) TestCreateRequiresSize() {
+	_, err := s.manager.Create(context.Background(), "e-123", 0)
+	s.Error(err)
+}
+
+func (s *ScratchSuite) TestFull() {
+	volume, err := s.manager.Create(context.Background(), "e-123", 4<<20)
+	s.Require().NoError(err)
+
+	full, err := volume.Full()
+	s.Require().NoError(err)
+	s.False(full)
+
+	// results count towards the size of the volume too
+	s.Require().NoError(os.WriteFile(filepath.Join(volume.ScratchDir(), "data"), make([]byte, 2<<20), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(volume.ResultsDir(), "data"), make([]byte, 1<<20+1), 0o600))
+	full, err = volume.Full()
+	s.Require().NoError(err)
+	s.True(full)
+
+	s.ErrorIs(volume.QuotaExceededError(), ErrQuotaExceeded)
+	s.ErrorContains(volume.QuotaExceededError(), "disk quota exceeded: the execution used all of its 4MiB scratch volume")
+}
+
+func (s *ScratchSuite) TestRemoveUnused() {
+	ctx := context.Background()
+	for _, executionID := range []string{"e-1", "e-2"} {
+		_, err := s.manager.Create(ctx, executionID, 4<<20)
+		s.Require().NoError(err)
+	}
+	// files that don't belong to a volume are left alone
+	s.Require().NoError(os.WriteFile(filepath.Join(s.directory, "README"), nil, 0o600))
+
+	s.Require().NoError(s.manager.RemoveUnused(ctx, []string{"e-2"}))
+	s.NoDirExists(filepath.Join(s.directory, "e-1"))
+	s.DirExists(filepath.Join(s.directory, "e-2"))
+	s.FileExists(filepath.Join(s.directory, "README"))
+}
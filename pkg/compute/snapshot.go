@@ -0,0 +1,291 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/maps"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/util/filecopy"
+)
+
+// snapshotSettleTime is how long to wait after a file is closed before taking
+// a snapshot, so that closing many files at once results in a single snapshot.
+const snapshotSettleTime = time.Second
+
+// snapshotter publishes the files of a running execution's result paths that
+// changed since its previous snapshot.
+type snapshotter struct {
+	executor   *BaseExecutor
+	state      store.LocalExecutionState
+	config     *models.SnapshotConfig
+	resultsDir string
+	paths      []*models.ResultPath
+	version    int
+	// files holds the state of the files in the previous snapshot, keyed by
+	// their path relative to the results directory
+	files map[string]snapshotFile
+}
+
+type snapshotFile struct {
+	size    int64
+	modTime time.Time
+	// published is the path of the file in the published results, which has
+	// the extension of the result path's compression appended
+	published string
+}
+
+func newSnapshotter(e *BaseExecutor, state store.LocalExecutionState, resultsDir string) *snapshotter {
+	task := state.Execution.Job.Task()
+	var paths []*models.ResultPath
+	for _, path := range task.ResultPaths {
+		if task.Snapshots.Includes(path.Name) {
+			paths = append(paths, path)
+		}
+	}
+	return &snapshotter{
+		executor:   e,
+		state:      state,
+		config:     task.Snapshots,
+		resultsDir: resultsDir,
+		paths:      paths,
+		files:      make(map[string]snapshotFile),
+	}
+}
+
+// run takes snapshots on the configured interval, or when files are closed,
+// until the context is canceled. Snapshots are taken at most every
+// models.MinSnapshotInterval.
+func (s *snapshotter) run(ctx context.Context) {
+	minInterval := time.Duration(models.MinSnapshotInterval) * time.Second
+	var last time.Time
+	var tick <-chan time.Time
+	if s.config.Interval > 0 {
+		ticker := time.NewTicker(s.config.GetInterval())
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var closed <-chan struct{}
+	if s.config.OnFileClose {
+		var err error
+		if closed, err = watchFileClose(ctx, s.resultsDir); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to watch results for closed files. snapshots will only be taken on interval")
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case _, ok := <-closed:
+			if !ok {
+				closed = nil
+				continue
+			}
+			wait := snapshotSettleTime
+			if next := time.Until(last.Add(minInterval)); next > wait {
+				wait = next
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+		last = time.Now()
+		if err := s.snapshot(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Int("version", s.version+1).Msg("failed to publish results snapshot")
+		}
+	}
+}
+
+// snapshot publishes the files that changed since the previous snapshot, if
+// any. Every models.MaxSnapshots versions, starting with the first, all the
+// files are published so that earlier snapshots can be discarded.
+func (s *snapshotter) snapshot(ctx context.Context) error {
+	execution := s.state.Execution
+	changed, current, removed, err := s.scan()
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	full := s.version%models.MaxSnapshots == 0
+	if full {
+		changed, removed = maps.Keys(current), nil
+		sort.Strings(changed)
+	}
+
+	version := s.version + 1
+	snapshotDir, err := s.executor.resultsPath.PrepareSnapshotDir(execution.ID, version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(snapshotDir); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("failed to remove snapshot folder at %s", snapshotDir)
+		}
+	}()
+
+	for _, rel := range changed {
+		target := filepath.Join(snapshotDir, filepath.FromSlash(rel))
+		if err = os.MkdirAll(filepath.Dir(target), StorageDirectoryPerms); err != nil {
+			return err
+		}
+		// the file was a regular file when scanned, but the job could have
+		// replaced it or its folders with links since
+		if err = filecopy.CopyFileNoFollow(s.resultsDir, rel, target); err != nil {
+			return err
+		}
+	}
+	if _, err = s.executor.resultsPath.FinalizeResults(ctx, snapshotDir, s.paths); err != nil {
+		return err
+	}
+
+	snapshot := &models.ResultSnapshot{
+		Version:     version,
+		ExecutionID: execution.ID,
+		CreateTime:  time.Now().UTC().UnixNano(),
+		Removed:     removed,
+		Full:        full,
+	}
+	if s.executor.manifestSigner != nil {
+		if snapshot.Manifest, err = s.executor.manifestSigner.Sign(snapshotDir); err != nil {
+			return err
+		}
+	}
+
+	publishers := execution.Job.Task().AllPublishers()
+	for i, config := range publishers {
+		publishers[i] = snapshotPublisherConfig(config, version)
+	}
+	if snapshot.Results, err = s.executor.publish(ctx, execution, publishers, snapshotDir); err != nil {
+		return err
+	}
+
+	s.version = version
+	s.files = current
+	log.Ctx(ctx).Debug().
+		Int("version", version).
+		Int("changed", len(changed)).
+		Int("removed", len(removed)).
+		Msg("published results snapshot")
+
+	s.executor.callback.OnSnapshotComplete(ctx, SnapshotResult{
+		ExecutionMetadata: NewExecutionMetadata(execution),
+		RoutingMetadata: RoutingMetadata{
+			SourcePeerID: s.executor.ID,
+			TargetPeerID: s.state.RequesterNodeID,
+		},
+		Snapshot: snapshot,
+	})
+	return nil
+}
+
+// scan lists the files of the snapshot paths that would be published, and
+// compares them to the previous snapshot. It returns the paths of the files
+// that changed, the state of all current files, and the published paths of
+// the files that were removed.
+func (s *snapshotter) scan() ([]string, map[string]snapshotFile, []string, error) {
+	var changed []string
+	current := make(map[string]snapshotFile)
+	for _, path := range s.paths {
+		root := filepath.Join(s.resultsDir, path.Name)
+		ext := compressionExtensions[path.Compression]
+		err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if d.IsDir() {
+				if rel != "." && path.Excludes(rel) {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || (rel != "." && !path.Publishes(rel)) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			name := path.Name
+			if rel != "." {
+				name = strings.Join([]string{path.Name, rel}, "/")
+			}
+			state := snapshotFile{size: info.Size(), modTime: info.ModTime(), published: name + ext}
+			current[name] = state
+			if previous, ok := s.files[name]; !ok || previous.size != state.size || !previous.modTime.Equal(state.modTime) {
+				changed = append(changed, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("error scanning result path %s: %w", path.Name, err)
+		}
+	}
+
+	var removed []string
+	for name, state := range s.files {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, state.published)
+		}
+	}
+	sort.Strings(removed)
+	return changed, current, removed, nil
+}
+
+// snapshotPublisherConfig returns a copy of a publisher config that publishes
+// a snapshot to its own key or URL, so that snapshots do not overwrite each
+// other or the final results. Publishers without a key or URL, such as IPFS,
+// are returned unchanged.
+func snapshotPublisherConfig(config *models.PublisherConfig, version int) *models.PublisherConfig {
+	return versionedPublisherConfig(config, version, publisher.SnapshotKey)
+}
+
+// versionedPublisherConfig returns a copy of a publisher config whose key or
+// URL is rewritten with versionKey.
+func versionedPublisherConfig(
+	config *models.PublisherConfig, version int, versionKey func(string, int) string) *models.PublisherConfig {
+	cpy := config.Copy()
+	for name, value := range cpy.Params {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(name) {
+		case "key":
+			cpy.Params[name] = versionKey(str, version)
+		case "url":
+			base, query, hasQuery := strings.Cut(str, "?")
+			str = versionKey(base, version)
+			if hasQuery {
+				str += "?" + query
+			}
+			cpy.Params[name] = str
+		}
+	}
+	return cpy
+}
@@ -0,0 +1,193 @@
+//go:build unit || !integration
+
+package compute
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
+)
+
+type SnapshotterSuite struct {
+	suite.Suite
+	resultsDir  string
+	snapshotter *snapshotter
+	// published holds the files of each published snapshot
+	published [][]string
+	// snapshots holds the snapshots reported to the callback
+	snapshots []*models.ResultSnapshot
+}
+
+func TestSnapshotterSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotterSuite))
+}
+
+func (s *SnapshotterSuite) SetupTest() {
+	s.published = nil
+	s.snapshots = nil
+	results := ResultsPath{ResultsDir: s.T().TempDir()}
+	var err error
+	s.resultsDir, err = results.PrepareResultsDir("e-123")
+	s.Require().NoError(err)
+
+	publishers := provider.NewMappedProvider(map[string]publisher.Publisher{
+		models.PublisherNoop: noop.NewNoopPublisherWithConfig(noop.PublisherConfig{
+			ExternalHooks: noop.PublisherExternalHooks{
+				PublishResult: func(ctx context.Context, execution *models.Execution, resultPath string) (models.SpecConfig, error) {
+					s.published = append(s.published, s.listFiles(resultPath))
+					return *models.NewSpecConfig(models.PublisherNoop).
+						WithParam("Key", execution.Job.Task().Publisher.Params["Key"]), nil
+				},
+			},
+		}),
+	})
+	executor := NewBaseExecutor(BaseExecutorParams{
+		ID: "node-1",
+		Callback: CallbackMock{
+			OnSnapshotCompleteHandler: func(ctx context.Context, result SnapshotResult) {
+				s.snapshots = append(s.snapshots, result.Snapshot)
+			},
+		},
+		ResultsPath: results,
+		Publishers:  publishers,
+	})
+
+	task := &models.Task{
+		Name:      "task",
+		Engine:    models.NewSpecConfig(models.EngineNoop),
+		Publisher: models.NewSpecConfig(models.PublisherNoop).WithParam("Key", "results/"),
+		ResultPaths: []*models.ResultPath{
+			{Name: "outputs", Path: "/outputs", Exclude: []string{"tmp/**"}},
+			{Name: "logs", Path: "/logs"},
+		},
+		Snapshots: &models.SnapshotConfig{Interval: 60, Paths: []string{"outputs"}},
+	}
+	execution := &models.Execution{
+		ID:  "e-123",
+		Job: &models.Job{ID: "j-123", Tasks: []*models.Task{task}},
+	}
+	s.snapshotter = newSnapshotter(executor, store.LocalExecutionState{
+		Execution:       execution,
+		RequesterNodeID: "requester",
+	}, s.resultsDir)
+}
+
+func (s *SnapshotterSuite) write(name, content string) {
+	path := filepath.Join(s.resultsDir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	// make sure rewritten files are detected on file systems with coarse timestamps
+	modTime := time.Now().Add(time.Duration(len(s.published)) * time.Second)
+	s.Require().NoError(os.Chtimes(path, modTime, modTime))
+}
+
+func (s *SnapshotterSuite) listFiles(root string) []string {
+	var files []string
+	s.Require().NoError(filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	return files
+}
+
+func (s *SnapshotterSuite) snapshot() {
+	s.Require().NoError(s.snapshotter.snapshot(context.Background()))
+}
+
+func (s *SnapshotterSuite) TestPublishesChanges() {
+	s.write("outputs/a.txt", "a")
+	s.write("outputs/b.txt", "b")
+	s.write("outputs/tmp/scratch.txt", "scratch")
+	s.write("logs/log.txt", "log")
+	s.snapshot()
+	s.Require().Len(s.published, 1)
+	s.Equal([]string{models.ResultManifestFilename, "outputs/a.txt", "outputs/b.txt"}, s.published[0])
+
+	// nothing changed, so nothing is published
+	s.snapshot()
+	s.Len(s.published, 1)
+
+	s.write("outputs/b.txt", "bb")
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, "outputs/a.txt")))
+	s.snapshot()
+	s.Require().Len(s.published, 2)
+	s.Equal([]string{models.ResultManifestFilename, "outputs/b.txt"}, s.published[1])
+
+	s.Require().Len(s.snapshots, 2)
+	for i, snapshot := range s.snapshots {
+		s.Equal(i+1, snapshot.Version)
+		s.Equal("e-123", snapshot.ExecutionID)
+		s.Require().Len(snapshot.Results, 1)
+	}
+	s.Empty(s.snapshots[0].Removed)
+	s.True(s.snapshots[0].Full)
+	s.Equal([]string{"outputs/a.txt"}, s.snapshots[1].Removed)
+	s.False(s.snapshots[1].Full)
+	s.Equal("results-snapshot-1/", s.snapshots[0].Results[0].Result.Params["Key"])
+	s.Equal("results-snapshot-2/", s.snapshots[1].Results[0].Result.Params["Key"])
+
+	// staging folders are removed once published
+	entries, err := os.ReadDir(filepath.Dir(s.resultsDir))
+	s.Require().NoError(err)
+	s.Len(entries, 1)
+}
+
+func (s *SnapshotterSuite) TestFullSnapshot() {
+	s.write("outputs/a.txt", "a")
+	s.write("outputs/b.txt", "b")
+	s.snapshot()
+	s.snapshotter.version = models.MaxSnapshots
+
+	s.write("outputs/b.txt", "bb")
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, "outputs/a.txt")))
+	s.write("outputs/c.txt", "c")
+	s.snapshot()
+	s.Require().Len(s.snapshots, 2)
+	snapshot := s.snapshots[1]
+	s.Equal(models.MaxSnapshots+1, snapshot.Version)
+	s.True(snapshot.Full)
+	s.Empty(snapshot.Removed)
+	s.Equal([]string{models.ResultManifestFilename, "outputs/b.txt", "outputs/c.txt"}, s.published[1])
+}
+
+func (s *SnapshotterSuite) TestCompressedNames() {
+	s.snapshotter.paths[0].Compression = models.CompressionGzip
+	s.write("outputs/a.txt", "a")
+	s.snapshot()
+	s.Require().Len(s.published, 1)
+	s.Equal([]string{models.ResultManifestFilename, "outputs/a.txt.gz"}, s.published[0])
+
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, "outputs/a.txt")))
+	s.snapshot()
+	s.Require().Len(s.snapshots, 2)
+	s.Equal([]string{"outputs/a.txt.gz"}, s.snapshots[1].Removed)
+}
+
+func (s *SnapshotterSuite) TestSnapshotPublisherConfig() {
+	config := &models.PublisherConfig{SpecConfig: *models.NewSpecConfig(models.PublisherS3).
+		WithParam("Bucket", "b").
+		WithParam("Key", "results.tar.gz")}
+	cpy := snapshotPublisherConfig(config, 3)
+	s.Equal("results-snapshot-3.tar.gz", cpy.Params["Key"])
+	s.Equal("b", cpy.Params["Bucket"])
+	s.Equal("results.tar.gz", config.Params["Key"])
+
+	config = &models.PublisherConfig{SpecConfig: *models.NewSpecConfig(models.PublisherS3).
+		WithParam("url", "s3://bucket/results?region=eu-west-1")}
+	s.Equal("s3://bucket/results-snapshot-1?region=eu-west-1", snapshotPublisherConfig(config, 1).Params["url"])
+}
@@ -0,0 +1,105 @@
+//go:build linux
+
+package compute
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_MOVED_TO
+
+// watchFileClose returns a channel that receives a value whenever a file that
+// was open for writing under root is closed. Directories created under root
+// after the watch starts are watched as well. The channel is closed when the
+// context is canceled or the watch fails.
+func watchFileClose(ctx context.Context, root string) (<-chan struct{}, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	w := &inotifyWatcher{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		dirs:   make(map[int]string),
+		events: make(chan struct{}, 1),
+	}
+	if err = w.addRecursive(root); err != nil {
+		_ = w.file.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = w.file.Close()
+	}()
+	go w.read(ctx)
+	return w.events, nil
+}
+
+type inotifyWatcher struct {
+	fd   int
+	file *os.File
+	// dirs maps watch descriptors to the directories they watch
+	dirs   map[int]string
+	events chan struct{}
+}
+
+func (w *inotifyWatcher) addRecursive(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+		w.dirs[wd] = path
+		return nil
+	})
+}
+
+func (w *inotifyWatcher) read(ctx context.Context) {
+	defer close(w.events)
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("failed to read file events")
+			}
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameStart := offset + unix.SizeofInotifyEvent
+			offset = nameStart + int(event.Len)
+			if event.Mask&unix.IN_ISDIR != 0 {
+				name := strings.TrimRight(string(buf[nameStart:offset]), "\x00")
+				if err = w.addRecursive(filepath.Join(w.dirs[int(event.Wd)], name)); err != nil {
+					log.Ctx(ctx).Warn().Err(err).Msg("failed to watch new results directory")
+				}
+				continue
+			}
+			if event.Mask&(unix.IN_CLOSE_WRITE|unix.IN_MOVED_TO) != 0 {
+				select {
+				case w.events <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !linux
+
+package compute
+
+import (
+	"context"
+	"time"
+)
+
+// fileClosePollInterval is how often snapshots are attempted on platforms
+// where closed files cannot be watched. Snapshots are only published when
+// files changed, so polling only costs a scan of the results.
+const fileClosePollInterval = 5 * time.Second
+
+// watchFileClose returns a channel that receives a value periodically, as
+// closed files can only be watched on linux.
+func watchFileClose(ctx context.Context, _ string) (<-chan struct{}, error) {
+	events := make(chan struct{}, 1)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(fileClosePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				select {
+				case events <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return events, nil
+}
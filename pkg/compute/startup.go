@@ -3,6 +3,7 @@ package compute
 import (
 	"context"
 
+	"github.com/bacalhau-project/bacalhau/pkg/compute/scratch"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -13,12 +14,16 @@ import (
 type Startup struct {
 	executionStore store.ExecutionStore
 	execBuffer     Executor
+	scratchVolumes *scratch.Manager
 }
 
-func NewStartup(execStore store.ExecutionStore, execBuffer Executor) *Startup {
+// NewStartup returns the startup tasks of a compute node. The scratch volumes
+// of executions that don't resume are removed if scratchVolumes is set.
+func NewStartup(execStore store.ExecutionStore, execBuffer Executor, scratchVolumes *scratch.Manager) *Startup {
 	return &Startup{
 		executionStore: execStore,
 		execBuffer:     execBuffer,
+		scratchVolumes: scratchVolumes,
 	}
 }
 
@@ -50,6 +55,20 @@ func (s *Startup) ensureLiveJobs(ctx context.Context) error {
 
 	errs := new(multierror.Error)
 
+	// remove the scratch volumes left behind by executions that won't
+	// resume, before the ones that do are restarted
+	if s.scratchVolumes != nil {
+		var resumed []string
+		for _, localExecution := range localExecStates {
+			if isResumable(localExecution) {
+				resumed = append(resumed, localExecution.Execution.ID)
+			}
+		}
+		if err = s.scratchVolumes.RemoveUnused(ctx, resumed); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("Startup: failed to remove unused scratch volumes")
+		}
+	}
+
 	for idx := range localExecStates {
 		localExecution := localExecStates[idx]
 
@@ -77,6 +96,17 @@ func (s *Startup) ensureLiveJobs(ctx context.Context) error {
 	return errs.ErrorOrNil()
 }
 
+// isResumable returns whether a live execution is restarted when the node
+// restarts, rather than failed.
+func isResumable(execution store.LocalExecutionState) bool {
+	switch execution.Execution.Job.Type {
+	case models.JobTypeService, models.JobTypeDaemon:
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *Startup) failExecution(ctx context.Context, execution store.LocalExecutionState) error {
 	// Calling cancel with the execute buffer will update our local state, and
 	// then when it calls the underlying baseexecutor, that will inform the requester
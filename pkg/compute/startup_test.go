@@ -11,6 +11,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/scratch"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store/boltdb"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -89,9 +90,21 @@ func (s *StartupTestSuite) TestLongRunning() {
 	s.Require().NoError(err)
 	s.Require().Equal(2, len(execs))
 
-	startup := compute.NewStartup(database, mockExecutor)
+	// executions that are not live, or that won't resume, lose their scratch volumes
+	scratchDir := s.T().TempDir()
+	scratchVolumes, err := scratch.NewManager(scratch.ManagerParams{Type: scratch.TypeDirectory, Directory: scratchDir})
+	s.Require().NoError(err)
+	for _, executionID := range []string{"1", "2", "3"} {
+		_, err = scratchVolumes.Create(s.ctx, executionID, 1<<20)
+		s.Require().NoError(err)
+	}
+
+	startup := compute.NewStartup(database, mockExecutor, scratchVolumes)
 	err = startup.Execute(s.ctx)
 	s.Require().NoError(err)
+	s.NoDirExists(filepath.Join(scratchDir, "1"))
+	s.DirExists(filepath.Join(scratchDir, "2"))
+	s.NoDirExists(filepath.Join(scratchDir, "3"))
 
 	// If we get here we're good as mock expectations didn't fail.
 	ctrl.Finish()
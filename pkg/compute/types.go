@@ -39,6 +39,10 @@ type Executor interface {
 type Callback interface {
 	OnBidComplete(ctx context.Context, result BidResult)
 	OnRunComplete(ctx context.Context, result RunResult)
+	OnSnapshotComplete(ctx context.Context, result SnapshotResult)
+	OnCheckpointComplete(ctx context.Context, result CheckpointResult)
+	OnHealthUpdate(ctx context.Context, result HealthResult)
+	OnEndpointsAllocated(ctx context.Context, result EndpointsResult)
 	OnCancelComplete(ctx context.Context, result CancelResult)
 	OnComputeFailure(ctx context.Context, err ComputeError)
 }
@@ -139,9 +143,42 @@ type RunResult struct {
 	RoutingMetadata
 	ExecutionMetadata
 	PublishResult    *models.SpecConfig
+	PublishResults   []*models.PublishedResult
 	RunCommandResult *models.RunCommandResult
 }
 
+// SnapshotResult is a snapshot of the results of a running execution that is
+// returned to the caller through a Callback.
+type SnapshotResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Snapshot *models.ResultSnapshot
+}
+
+// CheckpointResult is a checkpoint of a running execution that is returned to
+// the caller through a Callback.
+type CheckpointResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Checkpoint *models.Checkpoint
+}
+
+// HealthResult is a change in the health of a running execution that is
+// returned to the caller through a Callback.
+type HealthResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Health *models.ExecutionHealth
+}
+
+// EndpointsResult holds the endpoints the published ports of a started
+// execution can be reached on, and is returned to the caller through a Callback.
+type EndpointsResult struct {
+	RoutingMetadata
+	ExecutionMetadata
+	Endpoints []*models.ExecutionEndpoint
+}
+
 // CancelResult Result of a job cancel that is returned to the caller through a Callback.
 type CancelResult struct {
 	RoutingMetadata
@@ -110,6 +110,7 @@ var LocalComputeConfig = types.ComputeConfig{
 			Disk:   "",
 			GPU:    "",
 		},
+		GPUSharing: 0,
 	},
 	ExecutionStore: types.JobStoreConfig{
 		Type: types.BoltDB,
@@ -145,6 +146,36 @@ var LocalComputeConfig = types.ComputeConfig{
 		Address: "127.0.0.1",
 		Port:    6001,
 	},
+	ImageCache: types.ImageCacheConfig{
+		Enabled:        true,
+		PruneFrequency: types.Duration(10 * time.Minute),
+		Advertised:     20,
+	},
+	Ports: types.PortsConfig{
+		Start:   30000,
+		End:     32767,
+		Address: "127.0.0.1",
+	},
+	Wasm: types.WasmConfig{
+		CompilationCacheDir: "",
+		MaxFuel:             0,
+	},
+	ContainerRuntime: types.ContainerRuntimeConfig{
+		Type: "docker",
+		Host: "",
+	},
+	DockerSecurity: types.DockerSecurityConfig{
+		DefaultProfile: "",
+	},
+	ScratchVolumes: types.ScratchVolumesConfig{
+		Type:      "auto",
+		Directory: "",
+	},
+	Git: types.GitStorageConfig{
+		MaxRepositorySize: 1 << 30,
+		MaxCacheSize:      10 << 30,
+		FetchTimeout:      types.Duration(10 * time.Minute),
+	},
 }
 
 var LocalRequesterConfig = types.RequesterConfig{
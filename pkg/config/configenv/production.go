@@ -127,6 +127,7 @@ var ProductionComputeConfig = types.ComputeConfig{
 			Disk:   "",
 			GPU:    "",
 		},
+		GPUSharing: 0,
 	},
 	ExecutionStore: types.JobStoreConfig{
 		Type: types.BoltDB,
@@ -162,6 +163,36 @@ var ProductionComputeConfig = types.ComputeConfig{
 		Address: "public",
 		Port:    6001,
 	},
+	ImageCache: types.ImageCacheConfig{
+		Enabled:        true,
+		PruneFrequency: types.Duration(10 * time.Minute),
+		Advertised:     20,
+	},
+	Ports: types.PortsConfig{
+		Start:   30000,
+		End:     32767,
+		Address: "public",
+	},
+	Wasm: types.WasmConfig{
+		CompilationCacheDir: "",
+		MaxFuel:             0,
+	},
+	ContainerRuntime: types.ContainerRuntimeConfig{
+		Type: "docker",
+		Host: "",
+	},
+	DockerSecurity: types.DockerSecurityConfig{
+		DefaultProfile: "",
+	},
+	ScratchVolumes: types.ScratchVolumesConfig{
+		Type:      "auto",
+		Directory: "",
+	},
+	Git: types.GitStorageConfig{
+		MaxRepositorySize: 1 << 30,
+		MaxCacheSize:      10 << 30,
+		FetchTimeout:      types.Duration(10 * time.Minute),
+	},
 }
 
 var ProductionRequesterConfig = types.RequesterConfig{
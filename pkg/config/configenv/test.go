@@ -114,6 +114,7 @@ var TestingComputeConfig = types.ComputeConfig{
 			Disk:   "",
 			GPU:    "",
 		},
+		GPUSharing: 0,
 	},
 	ExecutionStore: types.JobStoreConfig{
 		Type: types.BoltDB,
@@ -149,6 +150,36 @@ var TestingComputeConfig = types.ComputeConfig{
 		Address: "private",
 		Port:    6001,
 	},
+	ImageCache: types.ImageCacheConfig{
+		Enabled:        false,
+		PruneFrequency: types.Duration(10 * time.Minute),
+		Advertised:     20,
+	},
+	Ports: types.PortsConfig{
+		Start:   30000,
+		End:     32767,
+		Address: "127.0.0.1",
+	},
+	Wasm: types.WasmConfig{
+		CompilationCacheDir: "",
+		MaxFuel:             0,
+	},
+	ContainerRuntime: types.ContainerRuntimeConfig{
+		Type: "docker",
+		Host: "",
+	},
+	DockerSecurity: types.DockerSecurityConfig{
+		DefaultProfile: "",
+	},
+	ScratchVolumes: types.ScratchVolumesConfig{
+		Type:      "directory",
+		Directory: "",
+	},
+	Git: types.GitStorageConfig{
+		MaxRepositorySize: 1 << 30,
+		MaxCacheSize:      10 << 30,
+		FetchTimeout:      types.Duration(10 * time.Minute),
+	},
 }
 
 var TestingRequesterConfig = types.RequesterConfig{
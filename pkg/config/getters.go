@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,12 +32,15 @@ func ClientTLSConfig() types.ClientTLSConfig {
 		UseTLS:   viper.GetBool(types.NodeClientAPIClientTLSUseTLS),
 		Insecure: viper.GetBool(types.NodeClientAPIClientTLSInsecure),
 		CACert:   viper.GetString(types.NodeClientAPIClientTLSCACert),
+
+		ClientCert: viper.GetString(types.NodeClientAPIClientTLSClientCert),
+		ClientKey:  viper.GetString(types.NodeClientAPIClientTLSClientKey),
 	}
 
 	if !cfg.UseTLS {
 		// If we haven't explicitly turned on TLS, but implied it through
 		// the other options, then set it to true
-		if cfg.Insecure || cfg.CACert != "" {
+		if cfg.Insecure || cfg.CACert != "" || cfg.ClientCert != "" {
 			cfg.UseTLS = true
 		}
 	}
@@ -100,6 +104,12 @@ func GetRequesterCertificateSettings() (string, string) {
 	return cert, key
 }
 
+func GetRequesterClientCertificateSettings() (string, bool) {
+	caCert := viper.GetString(types.NodeServerAPITLSClientCACert)
+	required := viper.GetBool(types.NodeServerAPITLSRequireClientCert)
+	return caCert, required
+}
+
 func DevstackGetShouldPrintInfo() bool {
 	return os.Getenv("DEVSTACK_PRINT_INFO") != ""
 }
@@ -119,10 +129,16 @@ func ShouldKeepStack() bool {
 type DockerCredentials struct {
 	Username string
 	Password string
+	// IdentityToken is used to authenticate instead of the username and
+	// password, e.g. when it is returned by a credential helper.
+	IdentityToken string
+	// Registry is the registry the credentials are for. Credentials without
+	// a registry are only used for the default registry.
+	Registry string
 }
 
 func (d *DockerCredentials) IsValid() bool {
-	return d.Username != "" && d.Password != ""
+	return (d.Username != "" && d.Password != "") || d.IdentityToken != ""
 }
 
 func GetDockerCredentials() DockerCredentials {
@@ -226,6 +242,97 @@ func GetDockerManifestCacheSettings() (*types.DockerCacheConfig, error) {
 	}
 }
 
+// GetDockerRegistries returns the credentials configured for private docker registries.
+func GetDockerRegistries() ([]types.DockerRegistryConfig, error) {
+	if viper.Get(types.NodeComputeDockerRegistries) == nil {
+		return nil, nil
+	}
+	var registries []types.DockerRegistryConfig
+	if err := ForKey(types.NodeComputeDockerRegistries, &registries); err != nil {
+		return nil, err
+	}
+	return registries, nil
+}
+
+// GetSecrets returns the secrets jobs can reference, and where they can be sent.
+func GetSecrets() ([]types.SecretConfig, error) {
+	if viper.Get(types.NodeComputeSecrets) == nil {
+		return nil, nil
+	}
+	var secrets []types.SecretConfig
+	if err := ForKey(types.NodeComputeSecrets, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// GetUserSecrets returns the secrets the client sends when downloading
+// results, and the URLs they can be sent to.
+func GetUserSecrets() ([]types.SecretConfig, error) {
+	if viper.Get(types.UserSecrets) == nil {
+		return nil, nil
+	}
+	var secrets []types.SecretConfig
+	if err := ForKey(types.UserSecrets, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// GetDockerSecurityConfig returns the security profiles docker jobs run with.
+func GetDockerSecurityConfig() (types.DockerSecurityConfig, error) {
+	cfg := types.DockerSecurityConfig{
+		DefaultProfile: viper.GetString(types.NodeComputeDockerSecurityDefaultProfile),
+	}
+	if viper.Get(types.NodeComputeDockerSecurityProfiles) == nil {
+		return cfg, nil
+	}
+	if err := ForKey(types.NodeComputeDockerSecurityProfiles, &cfg.Profiles); err != nil {
+		return types.DockerSecurityConfig{}, err
+	}
+	return cfg, nil
+}
+
+// GetWasmConfig returns the configuration of the WASM executor, with the
+// compilation cache defaulting to a directory in the storage path.
+func GetWasmConfig() types.WasmConfig {
+	cfg := types.WasmConfig{
+		CompilationCacheDir: viper.GetString(types.NodeComputeWasmCompilationCacheDir),
+		MaxFuel:             viper.GetUint64(types.NodeComputeWasmMaxFuel),
+	}
+	if cfg.CompilationCacheDir == "" {
+		cfg.CompilationCacheDir = filepath.Join(GetStoragePath(), "bacalhau-wasm-cache")
+	}
+	return cfg
+}
+
+// GetGitStorageConfig returns how the git input source fetches repositories.
+func GetGitStorageConfig() types.GitStorageConfig {
+	return types.GitStorageConfig{
+		SSHKeyFile:        viper.GetString(types.NodeComputeGitSSHKeyFile),
+		SSHKnownHostsFile: viper.GetString(types.NodeComputeGitSSHKnownHostsFile),
+		MaxRepositorySize: viper.GetUint64(types.NodeComputeGitMaxRepositorySize),
+		MaxCacheSize:      viper.GetUint64(types.NodeComputeGitMaxCacheSize),
+		FetchTimeout:      types.Duration(viper.GetDuration(types.NodeComputeGitFetchTimeout)),
+	}
+}
+
+// GetContainerRuntimeConfig returns the configuration of the container runtime that runs docker jobs.
+func GetContainerRuntimeConfig() types.ContainerRuntimeConfig {
+	return types.ContainerRuntimeConfig{
+		Type: viper.GetString(types.NodeComputeContainerRuntimeType),
+		Host: viper.GetString(types.NodeComputeContainerRuntimeHost),
+	}
+}
+
+// GetObjectStorageConfig returns the custom object storage endpoints that are trusted with the node's credentials.
+func GetObjectStorageConfig() types.ObjectStorageConfig {
+	return types.ObjectStorageConfig{
+		AzureEndpoints: viper.GetStringSlice(types.NodeComputeObjectStorageAzureEndpoints),
+		GCSEndpoints:   viper.GetStringSlice(types.NodeComputeObjectStorageGCSEndpoints),
+	}
+}
+
 // PreferredAddress will allow for the specifying of
 // the preferred address to listen on for cases where it
 // is not clear, or where the address does not appear when
@@ -15,6 +15,24 @@ type ComputeConfig struct {
 	ManifestCache   DockerCacheConfig        `yaml:"ManifestCache"`
 	LogStreamConfig LogStreamConfig          `yaml:"LogStream"`
 	LocalPublisher  LocalPublisherConfig     `yaml:"LocalPublisher"`
+	ImageCache      ImageCacheConfig         `yaml:"ImageCache"`
+	Ports           PortsConfig              `yaml:"Ports"`
+	Wasm            WasmConfig               `yaml:"Wasm"`
+	// ContainerRuntime configures the container runtime that runs docker jobs.
+	ContainerRuntime ContainerRuntimeConfig `yaml:"ContainerRuntime"`
+	// DockerRegistries configures the credentials used to pull images from private docker registries.
+	DockerRegistries []DockerRegistryConfig `yaml:"DockerRegistries"`
+	// DockerSecurity configures the security profiles docker jobs run with.
+	DockerSecurity DockerSecurityConfig `yaml:"DockerSecurity"`
+	// ObjectStorage configures the custom object storage endpoints that are trusted with the node's credentials.
+	ObjectStorage ObjectStorageConfig `yaml:"ObjectStorage"`
+	// Secrets configures which of the node's secrets jobs can reference, and where they can be sent. Jobs cannot
+	// use secrets that are not listed.
+	Secrets []SecretConfig `yaml:"Secrets"`
+	// ScratchVolumes configures the scratch volumes that executions write to, which are sized to their disk request.
+	ScratchVolumes ScratchVolumesConfig `yaml:"ScratchVolumes"`
+	// Git configures how the git input source fetches repositories.
+	Git GitStorageConfig `yaml:"Git"`
 }
 
 type CapacityConfig struct {
@@ -25,6 +43,9 @@ type CapacityConfig struct {
 	JobResourceLimits        models.ResourcesConfig `yaml:"JobResourceLimits"`
 	DefaultJobResourceLimits models.ResourcesConfig `yaml:"DefaultJobResourceLimits"`
 	QueueResourceLimits      models.ResourcesConfig `yaml:"QueueResourceLimits"`
+	// GPUSharing is how many tasks can share each GPU by time-slicing it, for tasks that allow their GPUs to be
+	// shared. GPUs are not shared if it is less than two.
+	GPUSharing uint64 `yaml:"GPUSharing"`
 }
 
 type JobTimeoutConfig struct {
@@ -62,3 +83,147 @@ type LocalPublisherConfig struct {
 	Port      int    `yaml:"Port"`
 	Directory string `yaml:"Directory"`
 }
+
+type ImageCacheConfig struct {
+	// Enabled turns on management of the docker images pulled for jobs.
+	Enabled bool `yaml:"Enabled"`
+	// DiskBudget is the disk space cached images can use, e.g. 20Gb, before the least recently used ones are
+	// removed. Images are not removed automatically if it is empty.
+	DiskBudget string `yaml:"DiskBudget"`
+	// PruneFrequency is how often images exceeding the disk budget are removed.
+	PruneFrequency Duration `yaml:"PruneFrequency"`
+	// AutoPull pulls the images of jobs the node is asked to bid on in the background.
+	AutoPull bool `yaml:"AutoPull"`
+	// Images are pulled when the node starts, and are never removed.
+	Images []string `yaml:"Images"`
+	// Advertised is how many of the most recently used images are advertised to the orchestrator.
+	Advertised int `yaml:"Advertised"`
+}
+
+type PortsConfig struct {
+	// Start and End are the range of host ports, inclusive, allocated to the ports published by tasks.
+	Start int `yaml:"Start"`
+	End   int `yaml:"End"`
+	// Address is advertised as the host of the published ports. It is either an address, or a type of
+	// address to look up, such as private or public.
+	Address string `yaml:"Address"`
+}
+
+type WasmConfig struct {
+	// CompilationCacheDir is where compiled WASM modules are cached between executions. Defaults to a directory
+	// in the storage path.
+	CompilationCacheDir string `yaml:"CompilationCacheDir"`
+	// MaxFuel is the most fuel a WASM job can use. Jobs that don't set their fuel are metered with this amount,
+	// and jobs asking for more are not bid on. Jobs are only metered if they set their fuel when it is zero.
+	MaxFuel uint64 `yaml:"MaxFuel"`
+}
+
+type ContainerRuntimeConfig struct {
+	// Type is the container runtime that runs docker jobs, either docker or podman. Podman is used through its
+	// Docker compatible API, so it can run rootless. Defaults to docker.
+	Type string `yaml:"Type"`
+	// Host is the address of the API of the container runtime, e.g. unix:///run/podman/podman.sock. Docker
+	// defaults to the DOCKER_HOST environment variable, and podman to its socket for the user running the node.
+	Host string `yaml:"Host"`
+}
+
+type DockerRegistryConfig struct {
+	// Registry is the host of the registry, e.g. ghcr.io. Images without a registry are pulled from docker.io.
+	Registry string `yaml:"Registry"`
+	// Secret is the name of a node secret that holds the credentials as username:password.
+	Secret string `yaml:"Secret"`
+	// CredentialHelper is the name of a docker credential helper, e.g. ecr-login, which is run as
+	// docker-credential-<name> to get the credentials. It is used if Secret is empty.
+	CredentialHelper string `yaml:"CredentialHelper"`
+}
+
+type ObjectStorageConfig struct {
+	// AzureEndpoints are the custom Azure Blob Storage service URLs that are sent the node's Azure credentials. Jobs
+	// using any other custom endpoint access it anonymously.
+	AzureEndpoints []string `yaml:"AzureEndpoints"`
+	// GCSEndpoints are the custom Google Cloud Storage endpoints that are sent the node's Google credentials. Jobs
+	// using any other custom endpoint access it anonymously.
+	GCSEndpoints []string `yaml:"GCSEndpoints"`
+}
+
+type SecretConfig struct {
+	// Name of the secret, or <namespace>/<name> for a secret of the jobs of a namespace.
+	Name string `yaml:"Name"`
+	// Shared makes a secret without a namespace available to the jobs of every namespace that don't have their own
+	// secret with the same name.
+	Shared bool `yaml:"Shared"`
+	// URLs are the prefixes of the URLs the secret can be sent to, e.g. https://github.com/my-org/. They match on
+	// the scheme, host and whole path segments. The secret is never sent anywhere else.
+	URLs []string `yaml:"URLs"`
+}
+
+type ScratchVolumesConfig struct {
+	// Type is how scratch volumes are created: auto, directory, loopback or xfs. Directories don't enforce the disk
+	// request of executions, while loopback files and XFS project quotas do on Linux. Defaults to auto, which uses
+	// xfs or loopback volumes if the node supports them, and directories otherwise.
+	Type string `yaml:"Type"`
+	// Directory is where scratch volumes are created. Defaults to a directory in the storage path. It must be on
+	// an XFS filesystem mounted with project quotas for the xfs type.
+	Directory string `yaml:"Directory"`
+}
+
+type GitStorageConfig struct {
+	// SSHKeyFile is the private key used to fetch ssh repositories. The node's ssh agent and ssh configuration are
+	// never used, so ssh repositories are fetched without a key if it is empty.
+	SSHKeyFile string `yaml:"SSHKeyFile"`
+	// SSHKnownHostsFile is the known hosts file the host keys of ssh repositories are checked against. Defaults to
+	// the known hosts of the user running the node. Unknown hosts are rejected.
+	SSHKnownHostsFile string `yaml:"SSHKnownHostsFile"`
+	// MaxRepositorySize is the largest repository in bytes that is fetched. Fetches are stopped once the
+	// repository grows beyond it.
+	MaxRepositorySize uint64 `yaml:"MaxRepositorySize"`
+	// MaxCacheSize is how many bytes of repositories are kept in the cache. The least recently used repositories
+	// are removed once it is exceeded.
+	MaxCacheSize uint64 `yaml:"MaxCacheSize"`
+	// FetchTimeout is how long fetching a repository can take.
+	FetchTimeout Duration `yaml:"FetchTimeout"`
+}
+
+type DockerSecurityConfig struct {
+	// DefaultProfile is the name of the profile applied to jobs that don't request one. Jobs that don't request a
+	// profile run with the container runtime's defaults if it is empty.
+	DefaultProfile string `yaml:"DefaultProfile"`
+	// Profiles are the security profiles jobs can request by name. Jobs requesting a profile that isn't listed are
+	// not bid on.
+	Profiles []DockerSecurityProfileConfig `yaml:"Profiles"`
+}
+
+type DockerSecurityProfileConfig struct {
+	// Name identifies the profile in the docker engine spec of jobs.
+	Name string `yaml:"Name"`
+	// ReadOnlyRootFilesystem mounts the root filesystem of containers as read-only. A tmpfs is mounted at /tmp so
+	// that jobs can still write temporary files.
+	ReadOnlyRootFilesystem bool `yaml:"ReadOnlyRootFilesystem"`
+	// DropCapabilities are the Linux capabilities removed from containers, e.g. ALL.
+	DropCapabilities []string `yaml:"DropCapabilities"`
+	// AllowedCapabilities are the Linux capabilities jobs can add back to their containers. Jobs asking for any
+	// other capability are not bid on.
+	AllowedCapabilities []string `yaml:"AllowedCapabilities"`
+	// NoNewPrivileges stops the processes of containers from gaining privileges, e.g. through setuid binaries.
+	NoNewPrivileges bool `yaml:"NoNewPrivileges"`
+	// Seccomp is the path of a seccomp profile in JSON, or unconfined. The runtime's default profile is used if
+	// it is empty.
+	Seccomp string `yaml:"Seccomp"`
+	// AppArmor is the name of an AppArmor profile loaded on the node, or unconfined. The runtime's default profile
+	// is used if it is empty.
+	AppArmor string `yaml:"AppArmor"`
+	// UsernsMode is the user namespace containers run in, e.g. host to opt out of the user namespace remapping of
+	// the docker daemon, or auto with podman. The runtime's default is used if it is empty.
+	UsernsMode string `yaml:"UsernsMode"`
+	// PidsLimit is the most processes a container can run. It is unlimited if zero.
+	PidsLimit int64 `yaml:"PidsLimit"`
+	// Ulimits are the resource limits of the processes of containers.
+	Ulimits []DockerUlimitConfig `yaml:"Ulimits"`
+}
+
+type DockerUlimitConfig struct {
+	// Name of the limit, e.g. nofile or nproc.
+	Name string `yaml:"Name"`
+	Soft int64  `yaml:"Soft"`
+	Hard int64  `yaml:"Hard"`
+}
@@ -14,6 +14,9 @@ type UserConfig struct {
 	KeyPath        string `yaml:"KeyPath"`
 	Libp2pKeyPath  string `yaml:"Libp2PKeyPath"`
 	InstallationID string `yaml:"InstallationID"`
+	// Secrets configures which of the client's secrets are sent when downloading results published with a secret,
+	// and the URLs they can be sent to. Shared does not apply to them.
+	Secrets []SecretConfig `yaml:"Secrets"`
 }
 
 type MetricsConfig struct {
@@ -10,11 +10,15 @@ const NodeClientAPIClientTLS = "Node.ClientAPI.ClientTLS"
 const NodeClientAPIClientTLSUseTLS = "Node.ClientAPI.ClientTLS.UseTLS"
 const NodeClientAPIClientTLSCACert = "Node.ClientAPI.ClientTLS.CACert"
 const NodeClientAPIClientTLSInsecure = "Node.ClientAPI.ClientTLS.Insecure"
+const NodeClientAPIClientTLSClientCert = "Node.ClientAPI.ClientTLS.ClientCert"
+const NodeClientAPIClientTLSClientKey = "Node.ClientAPI.ClientTLS.ClientKey"
 const NodeClientAPITLS = "Node.ClientAPI.TLS"
 const NodeClientAPITLSAutoCert = "Node.ClientAPI.TLS.AutoCert"
 const NodeClientAPITLSAutoCertCachePath = "Node.ClientAPI.TLS.AutoCertCachePath"
 const NodeClientAPITLSServerCertificate = "Node.ClientAPI.TLS.ServerCertificate"
 const NodeClientAPITLSServerKey = "Node.ClientAPI.TLS.ServerKey"
+const NodeClientAPITLSClientCACert = "Node.ClientAPI.TLS.ClientCACert"
+const NodeClientAPITLSRequireClientCert = "Node.ClientAPI.TLS.RequireClientCert"
 const NodeServerAPI = "Node.ServerAPI"
 const NodeServerAPIHost = "Node.ServerAPI.Host"
 const NodeServerAPIPort = "Node.ServerAPI.Port"
@@ -22,11 +26,15 @@ const NodeServerAPIClientTLS = "Node.ServerAPI.ClientTLS"
 const NodeServerAPIClientTLSUseTLS = "Node.ServerAPI.ClientTLS.UseTLS"
 const NodeServerAPIClientTLSCACert = "Node.ServerAPI.ClientTLS.CACert"
 const NodeServerAPIClientTLSInsecure = "Node.ServerAPI.ClientTLS.Insecure"
+const NodeServerAPIClientTLSClientCert = "Node.ServerAPI.ClientTLS.ClientCert"
+const NodeServerAPIClientTLSClientKey = "Node.ServerAPI.ClientTLS.ClientKey"
 const NodeServerAPITLS = "Node.ServerAPI.TLS"
 const NodeServerAPITLSAutoCert = "Node.ServerAPI.TLS.AutoCert"
 const NodeServerAPITLSAutoCertCachePath = "Node.ServerAPI.TLS.AutoCertCachePath"
 const NodeServerAPITLSServerCertificate = "Node.ServerAPI.TLS.ServerCertificate"
 const NodeServerAPITLSServerKey = "Node.ServerAPI.TLS.ServerKey"
+const NodeServerAPITLSClientCACert = "Node.ServerAPI.TLS.ClientCACert"
+const NodeServerAPITLSRequireClientCert = "Node.ServerAPI.TLS.RequireClientCert"
 const NodeLibp2p = "Node.Libp2p"
 const NodeLibp2pSwarmPort = "Node.Libp2p.SwarmPort"
 const NodeLibp2pPeerConnect = "Node.Libp2p.PeerConnect"
@@ -48,21 +56,26 @@ const NodeComputeCapacityTotalResourceLimitsCPU = "Node.Compute.Capacity.TotalRe
 const NodeComputeCapacityTotalResourceLimitsMemory = "Node.Compute.Capacity.TotalResourceLimits.Memory"
 const NodeComputeCapacityTotalResourceLimitsDisk = "Node.Compute.Capacity.TotalResourceLimits.Disk"
 const NodeComputeCapacityTotalResourceLimitsGPU = "Node.Compute.Capacity.TotalResourceLimits.GPU"
+const NodeComputeCapacityTotalResourceLimitsGPUSelector = "Node.Compute.Capacity.TotalResourceLimits.GPUSelector"
 const NodeComputeCapacityJobResourceLimits = "Node.Compute.Capacity.JobResourceLimits"
 const NodeComputeCapacityJobResourceLimitsCPU = "Node.Compute.Capacity.JobResourceLimits.CPU"
 const NodeComputeCapacityJobResourceLimitsMemory = "Node.Compute.Capacity.JobResourceLimits.Memory"
 const NodeComputeCapacityJobResourceLimitsDisk = "Node.Compute.Capacity.JobResourceLimits.Disk"
 const NodeComputeCapacityJobResourceLimitsGPU = "Node.Compute.Capacity.JobResourceLimits.GPU"
+const NodeComputeCapacityJobResourceLimitsGPUSelector = "Node.Compute.Capacity.JobResourceLimits.GPUSelector"
 const NodeComputeCapacityDefaultJobResourceLimits = "Node.Compute.Capacity.DefaultJobResourceLimits"
 const NodeComputeCapacityDefaultJobResourceLimitsCPU = "Node.Compute.Capacity.DefaultJobResourceLimits.CPU"
 const NodeComputeCapacityDefaultJobResourceLimitsMemory = "Node.Compute.Capacity.DefaultJobResourceLimits.Memory"
 const NodeComputeCapacityDefaultJobResourceLimitsDisk = "Node.Compute.Capacity.DefaultJobResourceLimits.Disk"
 const NodeComputeCapacityDefaultJobResourceLimitsGPU = "Node.Compute.Capacity.DefaultJobResourceLimits.GPU"
+const NodeComputeCapacityDefaultJobResourceLimitsGPUSelector = "Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector"
 const NodeComputeCapacityQueueResourceLimits = "Node.Compute.Capacity.QueueResourceLimits"
 const NodeComputeCapacityQueueResourceLimitsCPU = "Node.Compute.Capacity.QueueResourceLimits.CPU"
 const NodeComputeCapacityQueueResourceLimitsMemory = "Node.Compute.Capacity.QueueResourceLimits.Memory"
 const NodeComputeCapacityQueueResourceLimitsDisk = "Node.Compute.Capacity.QueueResourceLimits.Disk"
 const NodeComputeCapacityQueueResourceLimitsGPU = "Node.Compute.Capacity.QueueResourceLimits.GPU"
+const NodeComputeCapacityQueueResourceLimitsGPUSelector = "Node.Compute.Capacity.QueueResourceLimits.GPUSelector"
+const NodeComputeCapacityGPUSharing = "Node.Compute.Capacity.GPUSharing"
 const NodeComputeExecutionStore = "Node.Compute.ExecutionStore"
 const NodeComputeExecutionStoreType = "Node.Compute.ExecutionStore.Type"
 const NodeComputeExecutionStorePath = "Node.Compute.ExecutionStore.Path"
@@ -91,6 +104,40 @@ const NodeComputeLocalPublisher = "Node.Compute.LocalPublisher"
 const NodeComputeLocalPublisherAddress = "Node.Compute.LocalPublisher.Address"
 const NodeComputeLocalPublisherPort = "Node.Compute.LocalPublisher.Port"
 const NodeComputeLocalPublisherDirectory = "Node.Compute.LocalPublisher.Directory"
+const NodeComputeImageCache = "Node.Compute.ImageCache"
+const NodeComputeImageCacheEnabled = "Node.Compute.ImageCache.Enabled"
+const NodeComputeImageCacheDiskBudget = "Node.Compute.ImageCache.DiskBudget"
+const NodeComputeImageCachePruneFrequency = "Node.Compute.ImageCache.PruneFrequency"
+const NodeComputeImageCacheAutoPull = "Node.Compute.ImageCache.AutoPull"
+const NodeComputeImageCacheImages = "Node.Compute.ImageCache.Images"
+const NodeComputeImageCacheAdvertised = "Node.Compute.ImageCache.Advertised"
+const NodeComputePorts = "Node.Compute.Ports"
+const NodeComputePortsStart = "Node.Compute.Ports.Start"
+const NodeComputePortsEnd = "Node.Compute.Ports.End"
+const NodeComputePortsAddress = "Node.Compute.Ports.Address"
+const NodeComputeWasm = "Node.Compute.Wasm"
+const NodeComputeWasmCompilationCacheDir = "Node.Compute.Wasm.CompilationCacheDir"
+const NodeComputeWasmMaxFuel = "Node.Compute.Wasm.MaxFuel"
+const NodeComputeContainerRuntime = "Node.Compute.ContainerRuntime"
+const NodeComputeContainerRuntimeType = "Node.Compute.ContainerRuntime.Type"
+const NodeComputeContainerRuntimeHost = "Node.Compute.ContainerRuntime.Host"
+const NodeComputeDockerRegistries = "Node.Compute.DockerRegistries"
+const NodeComputeDockerSecurity = "Node.Compute.DockerSecurity"
+const NodeComputeDockerSecurityDefaultProfile = "Node.Compute.DockerSecurity.DefaultProfile"
+const NodeComputeDockerSecurityProfiles = "Node.Compute.DockerSecurity.Profiles"
+const NodeComputeObjectStorage = "Node.Compute.ObjectStorage"
+const NodeComputeObjectStorageAzureEndpoints = "Node.Compute.ObjectStorage.AzureEndpoints"
+const NodeComputeObjectStorageGCSEndpoints = "Node.Compute.ObjectStorage.GCSEndpoints"
+const NodeComputeSecrets = "Node.Compute.Secrets"
+const NodeComputeScratchVolumes = "Node.Compute.ScratchVolumes"
+const NodeComputeScratchVolumesType = "Node.Compute.ScratchVolumes.Type"
+const NodeComputeScratchVolumesDirectory = "Node.Compute.ScratchVolumes.Directory"
+const NodeComputeGit = "Node.Compute.Git"
+const NodeComputeGitSSHKeyFile = "Node.Compute.Git.SSHKeyFile"
+const NodeComputeGitSSHKnownHostsFile = "Node.Compute.Git.SSHKnownHostsFile"
+const NodeComputeGitMaxRepositorySize = "Node.Compute.Git.MaxRepositorySize"
+const NodeComputeGitMaxCacheSize = "Node.Compute.Git.MaxCacheSize"
+const NodeComputeGitFetchTimeout = "Node.Compute.Git.FetchTimeout"
 const NodeRequester = "Node.Requester"
 const NodeRequesterJobDefaults = "Node.Requester.JobDefaults"
 const NodeRequesterJobDefaultsExecutionTimeout = "Node.Requester.JobDefaults.ExecutionTimeout"
@@ -129,6 +176,7 @@ const NodeRequesterTagCacheSize = "Node.Requester.TagCache.Size"
 const NodeRequesterTagCacheDuration = "Node.Requester.TagCache.Duration"
 const NodeRequesterTagCacheFrequency = "Node.Requester.TagCache.Frequency"
 const NodeRequesterDefaultPublisher = "Node.Requester.DefaultPublisher"
+const NodeRequesterManualNodeApproval = "Node.Requester.ManualNodeApproval"
 const NodeBootstrapAddresses = "Node.BootstrapAddresses"
 const NodeDownloadURLRequestRetries = "Node.DownloadURLRequestRetries"
 const NodeDownloadURLRequestTimeout = "Node.DownloadURLRequestTimeout"
@@ -152,17 +200,23 @@ const NodeNetworkType = "Node.Network.Type"
 const NodeNetworkPort = "Node.Network.Port"
 const NodeNetworkAdvertisedAddress = "Node.Network.AdvertisedAddress"
 const NodeNetworkAuthSecret = "Node.Network.AuthSecret"
+const NodeNetworkJoinToken = "Node.Network.JoinToken"
 const NodeNetworkOrchestrators = "Node.Network.Orchestrators"
 const NodeNetworkCluster = "Node.Network.Cluster"
 const NodeNetworkClusterName = "Node.Network.Cluster.Name"
 const NodeNetworkClusterPort = "Node.Network.Cluster.Port"
 const NodeNetworkClusterAdvertisedAddress = "Node.Network.Cluster.AdvertisedAddress"
 const NodeNetworkClusterPeers = "Node.Network.Cluster.Peers"
+const NodeNetworkTLS = "Node.Network.TLS"
+const NodeNetworkTLSCACert = "Node.Network.TLS.CACert"
+const NodeNetworkTLSCertificate = "Node.Network.TLS.Certificate"
+const NodeNetworkTLSKey = "Node.Network.TLS.Key"
 const NodeStrictVersionMatch = "Node.StrictVersionMatch"
 const User = "User"
 const UserKeyPath = "User.KeyPath"
 const UserLibp2pKeyPath = "User.Libp2pKeyPath"
 const UserInstallationID = "User.InstallationID"
+const UserSecrets = "User.Secrets"
 const Metrics = "Metrics"
 const MetricsLibp2pTracerPath = "Metrics.Libp2pTracerPath"
 const MetricsEventTracerPath = "Metrics.EventTracerPath"
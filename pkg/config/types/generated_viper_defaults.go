@@ -32,11 +32,15 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeClientAPIClientTLSUseTLS, cfg.Node.ClientAPI.ClientTLS.UseTLS)
 	p.Viper.SetDefault(NodeClientAPIClientTLSCACert, cfg.Node.ClientAPI.ClientTLS.CACert)
 	p.Viper.SetDefault(NodeClientAPIClientTLSInsecure, cfg.Node.ClientAPI.ClientTLS.Insecure)
+	p.Viper.SetDefault(NodeClientAPIClientTLSClientCert, cfg.Node.ClientAPI.ClientTLS.ClientCert)
+	p.Viper.SetDefault(NodeClientAPIClientTLSClientKey, cfg.Node.ClientAPI.ClientTLS.ClientKey)
 	p.Viper.SetDefault(NodeClientAPITLS, cfg.Node.ClientAPI.TLS)
 	p.Viper.SetDefault(NodeClientAPITLSAutoCert, cfg.Node.ClientAPI.TLS.AutoCert)
 	p.Viper.SetDefault(NodeClientAPITLSAutoCertCachePath, cfg.Node.ClientAPI.TLS.AutoCertCachePath)
 	p.Viper.SetDefault(NodeClientAPITLSServerCertificate, cfg.Node.ClientAPI.TLS.ServerCertificate)
 	p.Viper.SetDefault(NodeClientAPITLSServerKey, cfg.Node.ClientAPI.TLS.ServerKey)
+	p.Viper.SetDefault(NodeClientAPITLSClientCACert, cfg.Node.ClientAPI.TLS.ClientCACert)
+	p.Viper.SetDefault(NodeClientAPITLSRequireClientCert, cfg.Node.ClientAPI.TLS.RequireClientCert)
 	p.Viper.SetDefault(NodeServerAPI, cfg.Node.ServerAPI)
 	p.Viper.SetDefault(NodeServerAPIHost, cfg.Node.ServerAPI.Host)
 	p.Viper.SetDefault(NodeServerAPIPort, cfg.Node.ServerAPI.Port)
@@ -44,11 +48,15 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeServerAPIClientTLSUseTLS, cfg.Node.ServerAPI.ClientTLS.UseTLS)
 	p.Viper.SetDefault(NodeServerAPIClientTLSCACert, cfg.Node.ServerAPI.ClientTLS.CACert)
 	p.Viper.SetDefault(NodeServerAPIClientTLSInsecure, cfg.Node.ServerAPI.ClientTLS.Insecure)
+	p.Viper.SetDefault(NodeServerAPIClientTLSClientCert, cfg.Node.ServerAPI.ClientTLS.ClientCert)
+	p.Viper.SetDefault(NodeServerAPIClientTLSClientKey, cfg.Node.ServerAPI.ClientTLS.ClientKey)
 	p.Viper.SetDefault(NodeServerAPITLS, cfg.Node.ServerAPI.TLS)
 	p.Viper.SetDefault(NodeServerAPITLSAutoCert, cfg.Node.ServerAPI.TLS.AutoCert)
 	p.Viper.SetDefault(NodeServerAPITLSAutoCertCachePath, cfg.Node.ServerAPI.TLS.AutoCertCachePath)
 	p.Viper.SetDefault(NodeServerAPITLSServerCertificate, cfg.Node.ServerAPI.TLS.ServerCertificate)
 	p.Viper.SetDefault(NodeServerAPITLSServerKey, cfg.Node.ServerAPI.TLS.ServerKey)
+	p.Viper.SetDefault(NodeServerAPITLSClientCACert, cfg.Node.ServerAPI.TLS.ClientCACert)
+	p.Viper.SetDefault(NodeServerAPITLSRequireClientCert, cfg.Node.ServerAPI.TLS.RequireClientCert)
 	p.Viper.SetDefault(NodeLibp2p, cfg.Node.Libp2p)
 	p.Viper.SetDefault(NodeLibp2pSwarmPort, cfg.Node.Libp2p.SwarmPort)
 	p.Viper.SetDefault(NodeLibp2pPeerConnect, cfg.Node.Libp2p.PeerConnect)
@@ -70,21 +78,26 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsMemory, cfg.Node.Compute.Capacity.TotalResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsDisk, cfg.Node.Compute.Capacity.TotalResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsGPU, cfg.Node.Compute.Capacity.TotalResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityTotalResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.TotalResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimits, cfg.Node.Compute.Capacity.JobResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsCPU, cfg.Node.Compute.Capacity.JobResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsMemory, cfg.Node.Compute.Capacity.JobResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsDisk, cfg.Node.Compute.Capacity.JobResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsGPU, cfg.Node.Compute.Capacity.JobResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.JobResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimits, cfg.Node.Compute.Capacity.DefaultJobResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsCPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsMemory, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsDisk, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsGPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityDefaultJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimits, cfg.Node.Compute.Capacity.QueueResourceLimits)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsCPU, cfg.Node.Compute.Capacity.QueueResourceLimits.CPU)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsMemory, cfg.Node.Compute.Capacity.QueueResourceLimits.Memory)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsDisk, cfg.Node.Compute.Capacity.QueueResourceLimits.Disk)
 	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsGPU, cfg.Node.Compute.Capacity.QueueResourceLimits.GPU)
+	p.Viper.SetDefault(NodeComputeCapacityQueueResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.QueueResourceLimits.GPUSelector)
+	p.Viper.SetDefault(NodeComputeCapacityGPUSharing, cfg.Node.Compute.Capacity.GPUSharing)
 	p.Viper.SetDefault(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.SetDefault(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.SetDefault(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
@@ -113,6 +126,40 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeComputeLocalPublisherAddress, cfg.Node.Compute.LocalPublisher.Address)
 	p.Viper.SetDefault(NodeComputeLocalPublisherPort, cfg.Node.Compute.LocalPublisher.Port)
 	p.Viper.SetDefault(NodeComputeLocalPublisherDirectory, cfg.Node.Compute.LocalPublisher.Directory)
+	p.Viper.SetDefault(NodeComputeImageCache, cfg.Node.Compute.ImageCache)
+	p.Viper.SetDefault(NodeComputeImageCacheEnabled, cfg.Node.Compute.ImageCache.Enabled)
+	p.Viper.SetDefault(NodeComputeImageCacheDiskBudget, cfg.Node.Compute.ImageCache.DiskBudget)
+	p.Viper.SetDefault(NodeComputeImageCachePruneFrequency, cfg.Node.Compute.ImageCache.PruneFrequency.AsTimeDuration())
+	p.Viper.SetDefault(NodeComputeImageCacheAutoPull, cfg.Node.Compute.ImageCache.AutoPull)
+	p.Viper.SetDefault(NodeComputeImageCacheImages, cfg.Node.Compute.ImageCache.Images)
+	p.Viper.SetDefault(NodeComputeImageCacheAdvertised, cfg.Node.Compute.ImageCache.Advertised)
+	p.Viper.SetDefault(NodeComputePorts, cfg.Node.Compute.Ports)
+	p.Viper.SetDefault(NodeComputePortsStart, cfg.Node.Compute.Ports.Start)
+	p.Viper.SetDefault(NodeComputePortsEnd, cfg.Node.Compute.Ports.End)
+	p.Viper.SetDefault(NodeComputePortsAddress, cfg.Node.Compute.Ports.Address)
+	p.Viper.SetDefault(NodeComputeWasm, cfg.Node.Compute.Wasm)
+	p.Viper.SetDefault(NodeComputeWasmCompilationCacheDir, cfg.Node.Compute.Wasm.CompilationCacheDir)
+	p.Viper.SetDefault(NodeComputeWasmMaxFuel, cfg.Node.Compute.Wasm.MaxFuel)
+	p.Viper.SetDefault(NodeComputeContainerRuntime, cfg.Node.Compute.ContainerRuntime)
+	p.Viper.SetDefault(NodeComputeContainerRuntimeType, cfg.Node.Compute.ContainerRuntime.Type)
+	p.Viper.SetDefault(NodeComputeContainerRuntimeHost, cfg.Node.Compute.ContainerRuntime.Host)
+	p.Viper.SetDefault(NodeComputeDockerRegistries, cfg.Node.Compute.DockerRegistries)
+	p.Viper.SetDefault(NodeComputeDockerSecurity, cfg.Node.Compute.DockerSecurity)
+	p.Viper.SetDefault(NodeComputeDockerSecurityDefaultProfile, cfg.Node.Compute.DockerSecurity.DefaultProfile)
+	p.Viper.SetDefault(NodeComputeDockerSecurityProfiles, cfg.Node.Compute.DockerSecurity.Profiles)
+	p.Viper.SetDefault(NodeComputeObjectStorage, cfg.Node.Compute.ObjectStorage)
+	p.Viper.SetDefault(NodeComputeObjectStorageAzureEndpoints, cfg.Node.Compute.ObjectStorage.AzureEndpoints)
+	p.Viper.SetDefault(NodeComputeObjectStorageGCSEndpoints, cfg.Node.Compute.ObjectStorage.GCSEndpoints)
+	p.Viper.SetDefault(NodeComputeSecrets, cfg.Node.Compute.Secrets)
+	p.Viper.SetDefault(NodeComputeScratchVolumes, cfg.Node.Compute.ScratchVolumes)
+	p.Viper.SetDefault(NodeComputeScratchVolumesType, cfg.Node.Compute.ScratchVolumes.Type)
+	p.Viper.SetDefault(NodeComputeScratchVolumesDirectory, cfg.Node.Compute.ScratchVolumes.Directory)
+	p.Viper.SetDefault(NodeComputeGit, cfg.Node.Compute.Git)
+	p.Viper.SetDefault(NodeComputeGitSSHKeyFile, cfg.Node.Compute.Git.SSHKeyFile)
+	p.Viper.SetDefault(NodeComputeGitSSHKnownHostsFile, cfg.Node.Compute.Git.SSHKnownHostsFile)
+	p.Viper.SetDefault(NodeComputeGitMaxRepositorySize, cfg.Node.Compute.Git.MaxRepositorySize)
+	p.Viper.SetDefault(NodeComputeGitMaxCacheSize, cfg.Node.Compute.Git.MaxCacheSize)
+	p.Viper.SetDefault(NodeComputeGitFetchTimeout, cfg.Node.Compute.Git.FetchTimeout.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequester, cfg.Node.Requester)
 	p.Viper.SetDefault(NodeRequesterJobDefaults, cfg.Node.Requester.JobDefaults)
 	p.Viper.SetDefault(NodeRequesterJobDefaultsExecutionTimeout, cfg.Node.Requester.JobDefaults.ExecutionTimeout.AsTimeDuration())
@@ -151,6 +198,7 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeRequesterTagCacheDuration, cfg.Node.Requester.TagCache.Duration.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequesterTagCacheFrequency, cfg.Node.Requester.TagCache.Frequency.AsTimeDuration())
 	p.Viper.SetDefault(NodeRequesterDefaultPublisher, cfg.Node.Requester.DefaultPublisher)
+	p.Viper.SetDefault(NodeRequesterManualNodeApproval, cfg.Node.Requester.ManualNodeApproval)
 	p.Viper.SetDefault(NodeBootstrapAddresses, cfg.Node.BootstrapAddresses)
 	p.Viper.SetDefault(NodeDownloadURLRequestRetries, cfg.Node.DownloadURLRequestRetries)
 	p.Viper.SetDefault(NodeDownloadURLRequestTimeout, cfg.Node.DownloadURLRequestTimeout.AsTimeDuration())
@@ -174,17 +222,23 @@ func SetDefaults(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.SetDefault(NodeNetworkPort, cfg.Node.Network.Port)
 	p.Viper.SetDefault(NodeNetworkAdvertisedAddress, cfg.Node.Network.AdvertisedAddress)
 	p.Viper.SetDefault(NodeNetworkAuthSecret, cfg.Node.Network.AuthSecret)
+	p.Viper.SetDefault(NodeNetworkJoinToken, cfg.Node.Network.JoinToken)
 	p.Viper.SetDefault(NodeNetworkOrchestrators, cfg.Node.Network.Orchestrators)
 	p.Viper.SetDefault(NodeNetworkCluster, cfg.Node.Network.Cluster)
 	p.Viper.SetDefault(NodeNetworkClusterName, cfg.Node.Network.Cluster.Name)
 	p.Viper.SetDefault(NodeNetworkClusterPort, cfg.Node.Network.Cluster.Port)
 	p.Viper.SetDefault(NodeNetworkClusterAdvertisedAddress, cfg.Node.Network.Cluster.AdvertisedAddress)
 	p.Viper.SetDefault(NodeNetworkClusterPeers, cfg.Node.Network.Cluster.Peers)
+	p.Viper.SetDefault(NodeNetworkTLS, cfg.Node.Network.TLS)
+	p.Viper.SetDefault(NodeNetworkTLSCACert, cfg.Node.Network.TLS.CACert)
+	p.Viper.SetDefault(NodeNetworkTLSCertificate, cfg.Node.Network.TLS.Certificate)
+	p.Viper.SetDefault(NodeNetworkTLSKey, cfg.Node.Network.TLS.Key)
 	p.Viper.SetDefault(NodeStrictVersionMatch, cfg.Node.StrictVersionMatch)
 	p.Viper.SetDefault(User, cfg.User)
 	p.Viper.SetDefault(UserKeyPath, cfg.User.KeyPath)
 	p.Viper.SetDefault(UserLibp2pKeyPath, cfg.User.Libp2pKeyPath)
 	p.Viper.SetDefault(UserInstallationID, cfg.User.InstallationID)
+	p.Viper.SetDefault(UserSecrets, cfg.User.Secrets)
 	p.Viper.SetDefault(Metrics, cfg.Metrics)
 	p.Viper.SetDefault(MetricsLibp2pTracerPath, cfg.Metrics.Libp2pTracerPath)
 	p.Viper.SetDefault(MetricsEventTracerPath, cfg.Metrics.EventTracerPath)
@@ -215,11 +269,15 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeClientAPIClientTLSUseTLS, cfg.Node.ClientAPI.ClientTLS.UseTLS)
 	p.Viper.Set(NodeClientAPIClientTLSCACert, cfg.Node.ClientAPI.ClientTLS.CACert)
 	p.Viper.Set(NodeClientAPIClientTLSInsecure, cfg.Node.ClientAPI.ClientTLS.Insecure)
+	p.Viper.Set(NodeClientAPIClientTLSClientCert, cfg.Node.ClientAPI.ClientTLS.ClientCert)
+	p.Viper.Set(NodeClientAPIClientTLSClientKey, cfg.Node.ClientAPI.ClientTLS.ClientKey)
 	p.Viper.Set(NodeClientAPITLS, cfg.Node.ClientAPI.TLS)
 	p.Viper.Set(NodeClientAPITLSAutoCert, cfg.Node.ClientAPI.TLS.AutoCert)
 	p.Viper.Set(NodeClientAPITLSAutoCertCachePath, cfg.Node.ClientAPI.TLS.AutoCertCachePath)
 	p.Viper.Set(NodeClientAPITLSServerCertificate, cfg.Node.ClientAPI.TLS.ServerCertificate)
 	p.Viper.Set(NodeClientAPITLSServerKey, cfg.Node.ClientAPI.TLS.ServerKey)
+	p.Viper.Set(NodeClientAPITLSClientCACert, cfg.Node.ClientAPI.TLS.ClientCACert)
+	p.Viper.Set(NodeClientAPITLSRequireClientCert, cfg.Node.ClientAPI.TLS.RequireClientCert)
 	p.Viper.Set(NodeServerAPI, cfg.Node.ServerAPI)
 	p.Viper.Set(NodeServerAPIHost, cfg.Node.ServerAPI.Host)
 	p.Viper.Set(NodeServerAPIPort, cfg.Node.ServerAPI.Port)
@@ -227,11 +285,15 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeServerAPIClientTLSUseTLS, cfg.Node.ServerAPI.ClientTLS.UseTLS)
 	p.Viper.Set(NodeServerAPIClientTLSCACert, cfg.Node.ServerAPI.ClientTLS.CACert)
 	p.Viper.Set(NodeServerAPIClientTLSInsecure, cfg.Node.ServerAPI.ClientTLS.Insecure)
+	p.Viper.Set(NodeServerAPIClientTLSClientCert, cfg.Node.ServerAPI.ClientTLS.ClientCert)
+	p.Viper.Set(NodeServerAPIClientTLSClientKey, cfg.Node.ServerAPI.ClientTLS.ClientKey)
 	p.Viper.Set(NodeServerAPITLS, cfg.Node.ServerAPI.TLS)
 	p.Viper.Set(NodeServerAPITLSAutoCert, cfg.Node.ServerAPI.TLS.AutoCert)
 	p.Viper.Set(NodeServerAPITLSAutoCertCachePath, cfg.Node.ServerAPI.TLS.AutoCertCachePath)
 	p.Viper.Set(NodeServerAPITLSServerCertificate, cfg.Node.ServerAPI.TLS.ServerCertificate)
 	p.Viper.Set(NodeServerAPITLSServerKey, cfg.Node.ServerAPI.TLS.ServerKey)
+	p.Viper.Set(NodeServerAPITLSClientCACert, cfg.Node.ServerAPI.TLS.ClientCACert)
+	p.Viper.Set(NodeServerAPITLSRequireClientCert, cfg.Node.ServerAPI.TLS.RequireClientCert)
 	p.Viper.Set(NodeLibp2p, cfg.Node.Libp2p)
 	p.Viper.Set(NodeLibp2pSwarmPort, cfg.Node.Libp2p.SwarmPort)
 	p.Viper.Set(NodeLibp2pPeerConnect, cfg.Node.Libp2p.PeerConnect)
@@ -253,21 +315,26 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsMemory, cfg.Node.Compute.Capacity.TotalResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsDisk, cfg.Node.Compute.Capacity.TotalResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsGPU, cfg.Node.Compute.Capacity.TotalResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityTotalResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.TotalResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimits, cfg.Node.Compute.Capacity.JobResourceLimits)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsCPU, cfg.Node.Compute.Capacity.JobResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsMemory, cfg.Node.Compute.Capacity.JobResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsDisk, cfg.Node.Compute.Capacity.JobResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityJobResourceLimitsGPU, cfg.Node.Compute.Capacity.JobResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.JobResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimits, cfg.Node.Compute.Capacity.DefaultJobResourceLimits)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsCPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsMemory, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsDisk, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsGPU, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityDefaultJobResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.DefaultJobResourceLimits.GPUSelector)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimits, cfg.Node.Compute.Capacity.QueueResourceLimits)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsCPU, cfg.Node.Compute.Capacity.QueueResourceLimits.CPU)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsMemory, cfg.Node.Compute.Capacity.QueueResourceLimits.Memory)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsDisk, cfg.Node.Compute.Capacity.QueueResourceLimits.Disk)
 	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsGPU, cfg.Node.Compute.Capacity.QueueResourceLimits.GPU)
+	p.Viper.Set(NodeComputeCapacityQueueResourceLimitsGPUSelector, cfg.Node.Compute.Capacity.QueueResourceLimits.GPUSelector)
+	p.Viper.Set(NodeComputeCapacityGPUSharing, cfg.Node.Compute.Capacity.GPUSharing)
 	p.Viper.Set(NodeComputeExecutionStore, cfg.Node.Compute.ExecutionStore)
 	p.Viper.Set(NodeComputeExecutionStoreType, cfg.Node.Compute.ExecutionStore.Type)
 	p.Viper.Set(NodeComputeExecutionStorePath, cfg.Node.Compute.ExecutionStore.Path)
@@ -296,6 +363,40 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeComputeLocalPublisherAddress, cfg.Node.Compute.LocalPublisher.Address)
 	p.Viper.Set(NodeComputeLocalPublisherPort, cfg.Node.Compute.LocalPublisher.Port)
 	p.Viper.Set(NodeComputeLocalPublisherDirectory, cfg.Node.Compute.LocalPublisher.Directory)
+	p.Viper.Set(NodeComputeImageCache, cfg.Node.Compute.ImageCache)
+	p.Viper.Set(NodeComputeImageCacheEnabled, cfg.Node.Compute.ImageCache.Enabled)
+	p.Viper.Set(NodeComputeImageCacheDiskBudget, cfg.Node.Compute.ImageCache.DiskBudget)
+	p.Viper.Set(NodeComputeImageCachePruneFrequency, cfg.Node.Compute.ImageCache.PruneFrequency.AsTimeDuration())
+	p.Viper.Set(NodeComputeImageCacheAutoPull, cfg.Node.Compute.ImageCache.AutoPull)
+	p.Viper.Set(NodeComputeImageCacheImages, cfg.Node.Compute.ImageCache.Images)
+	p.Viper.Set(NodeComputeImageCacheAdvertised, cfg.Node.Compute.ImageCache.Advertised)
+	p.Viper.Set(NodeComputePorts, cfg.Node.Compute.Ports)
+	p.Viper.Set(NodeComputePortsStart, cfg.Node.Compute.Ports.Start)
+	p.Viper.Set(NodeComputePortsEnd, cfg.Node.Compute.Ports.End)
+	p.Viper.Set(NodeComputePortsAddress, cfg.Node.Compute.Ports.Address)
+	p.Viper.Set(NodeComputeWasm, cfg.Node.Compute.Wasm)
+	p.Viper.Set(NodeComputeWasmCompilationCacheDir, cfg.Node.Compute.Wasm.CompilationCacheDir)
+	p.Viper.Set(NodeComputeWasmMaxFuel, cfg.Node.Compute.Wasm.MaxFuel)
+	p.Viper.Set(NodeComputeContainerRuntime, cfg.Node.Compute.ContainerRuntime)
+	p.Viper.Set(NodeComputeContainerRuntimeType, cfg.Node.Compute.ContainerRuntime.Type)
+	p.Viper.Set(NodeComputeContainerRuntimeHost, cfg.Node.Compute.ContainerRuntime.Host)
+	p.Viper.Set(NodeComputeDockerRegistries, cfg.Node.Compute.DockerRegistries)
+	p.Viper.Set(NodeComputeDockerSecurity, cfg.Node.Compute.DockerSecurity)
+	p.Viper.Set(NodeComputeDockerSecurityDefaultProfile, cfg.Node.Compute.DockerSecurity.DefaultProfile)
+	p.Viper.Set(NodeComputeDockerSecurityProfiles, cfg.Node.Compute.DockerSecurity.Profiles)
+	p.Viper.Set(NodeComputeObjectStorage, cfg.Node.Compute.ObjectStorage)
+	p.Viper.Set(NodeComputeObjectStorageAzureEndpoints, cfg.Node.Compute.ObjectStorage.AzureEndpoints)
+	p.Viper.Set(NodeComputeObjectStorageGCSEndpoints, cfg.Node.Compute.ObjectStorage.GCSEndpoints)
+	p.Viper.Set(NodeComputeSecrets, cfg.Node.Compute.Secrets)
+	p.Viper.Set(NodeComputeScratchVolumes, cfg.Node.Compute.ScratchVolumes)
+	p.Viper.Set(NodeComputeScratchVolumesType, cfg.Node.Compute.ScratchVolumes.Type)
+	p.Viper.Set(NodeComputeScratchVolumesDirectory, cfg.Node.Compute.ScratchVolumes.Directory)
+	p.Viper.Set(NodeComputeGit, cfg.Node.Compute.Git)
+	p.Viper.Set(NodeComputeGitSSHKeyFile, cfg.Node.Compute.Git.SSHKeyFile)
+	p.Viper.Set(NodeComputeGitSSHKnownHostsFile, cfg.Node.Compute.Git.SSHKnownHostsFile)
+	p.Viper.Set(NodeComputeGitMaxRepositorySize, cfg.Node.Compute.Git.MaxRepositorySize)
+	p.Viper.Set(NodeComputeGitMaxCacheSize, cfg.Node.Compute.Git.MaxCacheSize)
+	p.Viper.Set(NodeComputeGitFetchTimeout, cfg.Node.Compute.Git.FetchTimeout.AsTimeDuration())
 	p.Viper.Set(NodeRequester, cfg.Node.Requester)
 	p.Viper.Set(NodeRequesterJobDefaults, cfg.Node.Requester.JobDefaults)
 	p.Viper.Set(NodeRequesterJobDefaultsExecutionTimeout, cfg.Node.Requester.JobDefaults.ExecutionTimeout.AsTimeDuration())
@@ -334,6 +435,7 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeRequesterTagCacheDuration, cfg.Node.Requester.TagCache.Duration.AsTimeDuration())
 	p.Viper.Set(NodeRequesterTagCacheFrequency, cfg.Node.Requester.TagCache.Frequency.AsTimeDuration())
 	p.Viper.Set(NodeRequesterDefaultPublisher, cfg.Node.Requester.DefaultPublisher)
+	p.Viper.Set(NodeRequesterManualNodeApproval, cfg.Node.Requester.ManualNodeApproval)
 	p.Viper.Set(NodeBootstrapAddresses, cfg.Node.BootstrapAddresses)
 	p.Viper.Set(NodeDownloadURLRequestRetries, cfg.Node.DownloadURLRequestRetries)
 	p.Viper.Set(NodeDownloadURLRequestTimeout, cfg.Node.DownloadURLRequestTimeout.AsTimeDuration())
@@ -357,17 +459,23 @@ func Set(cfg BacalhauConfig, opts ...SetOption) {
 	p.Viper.Set(NodeNetworkPort, cfg.Node.Network.Port)
 	p.Viper.Set(NodeNetworkAdvertisedAddress, cfg.Node.Network.AdvertisedAddress)
 	p.Viper.Set(NodeNetworkAuthSecret, cfg.Node.Network.AuthSecret)
+	p.Viper.Set(NodeNetworkJoinToken, cfg.Node.Network.JoinToken)
 	p.Viper.Set(NodeNetworkOrchestrators, cfg.Node.Network.Orchestrators)
 	p.Viper.Set(NodeNetworkCluster, cfg.Node.Network.Cluster)
 	p.Viper.Set(NodeNetworkClusterName, cfg.Node.Network.Cluster.Name)
 	p.Viper.Set(NodeNetworkClusterPort, cfg.Node.Network.Cluster.Port)
 	p.Viper.Set(NodeNetworkClusterAdvertisedAddress, cfg.Node.Network.Cluster.AdvertisedAddress)
 	p.Viper.Set(NodeNetworkClusterPeers, cfg.Node.Network.Cluster.Peers)
+	p.Viper.Set(NodeNetworkTLS, cfg.Node.Network.TLS)
+	p.Viper.Set(NodeNetworkTLSCACert, cfg.Node.Network.TLS.CACert)
+	p.Viper.Set(NodeNetworkTLSCertificate, cfg.Node.Network.TLS.Certificate)
+	p.Viper.Set(NodeNetworkTLSKey, cfg.Node.Network.TLS.Key)
 	p.Viper.Set(NodeStrictVersionMatch, cfg.Node.StrictVersionMatch)
 	p.Viper.Set(User, cfg.User)
 	p.Viper.Set(UserKeyPath, cfg.User.KeyPath)
 	p.Viper.Set(UserLibp2pKeyPath, cfg.User.Libp2pKeyPath)
 	p.Viper.Set(UserInstallationID, cfg.User.InstallationID)
+	p.Viper.Set(UserSecrets, cfg.User.Secrets)
 	p.Viper.Set(Metrics, cfg.Metrics)
 	p.Viper.Set(MetricsLibp2pTracerPath, cfg.Metrics.Libp2pTracerPath)
 	p.Viper.Set(MetricsEventTracerPath, cfg.Metrics.EventTracerPath)
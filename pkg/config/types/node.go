@@ -74,6 +74,14 @@ type ClientTLSConfig struct {
 	// Used for NodeConfig.ClientAPI, and when true instructs the client to use
 	// HTTPS, but not to attempt to verify the certificate.
 	Insecure bool `yaml:"Insecure"`
+
+	// Used for NodeConfig.ClientAPI, specifies the location of a client
+	// certificate file to present to servers that require mutual TLS.
+	ClientCert string `yaml:"ClientCert"`
+
+	// Used for NodeConfig.ClientAPI, specifies the location of the key
+	// matching ClientCert.
+	ClientKey string `yaml:"ClientKey"`
 }
 
 type WebUIConfig struct {
@@ -98,6 +106,15 @@ type TLSConfiguration struct {
 	// ServerKey is the TLS server key to match the certificate to allow the
 	// requester to server TLS.
 	ServerKey string `yaml:"ServerTLSKey"`
+
+	// ClientCACert specifies the location of a CA certificate file used to
+	// verify certificates presented by clients. Setting this enables mutual
+	// TLS, and verified clients are identified to the authorization policy.
+	ClientCACert string `yaml:"ClientCACert"`
+
+	// RequireClientCert rejects clients that do not present a certificate
+	// signed by ClientCACert. Otherwise presenting one is optional.
+	RequireClientCert bool `yaml:"RequireClientCert"`
 }
 
 type Libp2pConfig struct {
@@ -152,8 +169,20 @@ type NetworkConfig struct {
 	Port              int                  `yaml:"Port"`
 	AdvertisedAddress string               `yaml:"AdvertisedAddress"`
 	AuthSecret        string               `yaml:"AuthSecret"`
+	JoinToken         string               `yaml:"JoinToken"`
 	Orchestrators     []string             `yaml:"Orchestrators"`
 	Cluster           NetworkClusterConfig `yaml:"Cluster"`
+	TLS               NetworkTLSConfig     `yaml:"TLS"`
+}
+
+// NetworkTLSConfig configures mutual TLS between orchestrator and compute
+// nodes. Orchestrators serve with the certificate and require connecting nodes
+// to present a certificate signed by CACert. Compute nodes present the
+// certificate and verify orchestrators against CACert.
+type NetworkTLSConfig struct {
+	CACert      string `yaml:"CACert"`
+	Certificate string `yaml:"Certificate"`
+	Key         string `yaml:"Key"`
 }
 
 type NetworkClusterConfig struct {
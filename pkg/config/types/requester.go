@@ -25,6 +25,10 @@ type RequesterConfig struct {
 
 	TagCache         DockerCacheConfig `yaml:"TagCache"`
 	DefaultPublisher string            `yaml:"DefaultPublisher"`
+
+	// ManualNodeApproval requires compute nodes to present a join token, or
+	// to be approved by an operator, before they are sent work.
+	ManualNodeApproval bool `yaml:"ManualNodeApproval"`
 }
 
 type EvaluationBrokerConfig struct {
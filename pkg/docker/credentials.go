@@ -0,0 +1,190 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/distribution/reference"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+)
+
+const (
+	defaultRegistry = "docker.io"
+	// defaultRegistryServerURL is the server docker credential helpers know
+	// the default registry by.
+	defaultRegistryServerURL = "https://index.docker.io/v1/"
+	// credentialHelperTokenUsername is returned by credential helpers as the
+	// username when the secret is an identity token.
+	credentialHelperTokenUsername = "<token>"
+)
+
+// CredentialHelper runs a docker credential helper to get the credentials
+// for a registry.
+type CredentialHelper func(ctx context.Context, helper, serverURL string) (config.DockerCredentials, error)
+
+type CredentialsResolverParams struct {
+	// Registries are the credentials configured for private registries.
+	Registries []types.DockerRegistryConfig
+	// Secrets resolves the secrets referenced by jobs and registries.
+	Secrets secrets.Resolver
+	// SecretsPolicy sets the secrets jobs can reference, and the registries they can be sent to.
+	SecretsPolicy *secrets.Policy
+	// CredentialHelper is optional, and runs docker-credential-<helper> by default.
+	CredentialHelper CredentialHelper
+}
+
+// CredentialsResolver resolves the credentials used to pull an image. The
+// credentials are only ever held in memory, and are never written to the job.
+type CredentialsResolver struct {
+	registries map[string]types.DockerRegistryConfig
+	secrets    secrets.Resolver
+	policy     *secrets.Policy
+	helper     CredentialHelper
+}
+
+func NewCredentialsResolver(params CredentialsResolverParams) *CredentialsResolver {
+	r := &CredentialsResolver{
+		registries: make(map[string]types.DockerRegistryConfig, len(params.Registries)),
+		secrets:    params.Secrets,
+		policy:     params.SecretsPolicy,
+		helper:     params.CredentialHelper,
+	}
+	for _, registry := range params.Registries {
+		r.registries[normalizeRegistry(registry.Registry)] = registry
+	}
+	if r.helper == nil {
+		r.helper = runCredentialHelper
+	}
+	return r
+}
+
+// NewCredentialsResolverFromConfig returns a resolver using the registries
+// configured for the node, and the node's secrets.
+func NewCredentialsResolverFromConfig() (*CredentialsResolver, error) {
+	registries, err := config.GetDockerRegistries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load docker registry configuration: %w", err)
+	}
+	policy, err := secrets.NewPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewCredentialsResolver(CredentialsResolverParams{
+		Registries:    registries,
+		Secrets:       secrets.NewEnvResolver(),
+		SecretsPolicy: policy,
+	}), nil
+}
+
+// Resolve returns the credentials to pull an image for a job in namespace.
+// The secret referenced by the job takes precedence over the credentials
+// configured for the image's registry, which take precedence over
+// DOCKER_USERNAME and DOCKER_PASSWORD. The secret is resolved for the
+// namespace of the job, and must be allowed to be sent to the image's
+// repository.
+func (r *CredentialsResolver) Resolve(
+	ctx context.Context, image, namespace, secret string) (config.DockerCredentials, error) {
+	registry := ImageRegistry(image)
+	if secret != "" {
+		value, err := secrets.ForNamespace(r.secrets, r.policy, namespace).ResolveFor(ctx, secret, imageURL(image))
+		if err != nil {
+			return config.DockerCredentials{}, fmt.Errorf("failed to resolve registry credentials: %w", err)
+		}
+		return parseCredentials(value, registry), nil
+	}
+
+	if cfg, ok := r.registries[registry]; ok {
+		switch {
+		case cfg.Secret != "":
+			value, err := r.secrets.Resolve(ctx, cfg.Secret)
+			if err != nil {
+				return config.DockerCredentials{}, fmt.Errorf("failed to resolve credentials of registry %s: %w", registry, err)
+			}
+			return parseCredentials(value, registry), nil
+		case cfg.CredentialHelper != "":
+			serverURL := registry
+			if registry == defaultRegistry {
+				serverURL = defaultRegistryServerURL
+			}
+			creds, err := r.helper(ctx, cfg.CredentialHelper, serverURL)
+			if err != nil {
+				return config.DockerCredentials{}, fmt.Errorf("failed to get credentials of registry %s: %w", registry, err)
+			}
+			creds.Registry = registry
+			return creds, nil
+		}
+	}
+	return config.GetDockerCredentials(), nil
+}
+
+// ImageRegistry returns the registry an image is pulled from.
+func ImageRegistry(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return defaultRegistry
+	}
+	return reference.Domain(named)
+}
+
+// imageURL returns the URL of an image's repository that the secrets of jobs
+// must be allowed to be sent to, e.g. https://ghcr.io/my-org/image.
+func imageURL(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return ""
+	}
+	return "https://" + reference.Domain(named) + "/" + reference.Path(named)
+}
+
+func normalizeRegistry(registry string) string {
+	registry = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://"), "/")
+	switch registry {
+	case "", "index.docker.io", "registry-1.docker.io", "index.docker.io/v1":
+		return defaultRegistry
+	default:
+		return registry
+	}
+}
+
+// parseCredentials parses credentials held as username:password. A value
+// without a username is used as an identity token.
+func parseCredentials(value, registry string) config.DockerCredentials {
+	username, password, found := strings.Cut(value, ":")
+	if !found {
+		return config.DockerCredentials{IdentityToken: value, Registry: registry}
+	}
+	return config.DockerCredentials{Username: username, Password: password, Registry: registry}
+}
+
+// runCredentialHelper implements the get command of the docker credential
+// helper protocol.
+func runCredentialHelper(ctx context.Context, helper, serverURL string) (config.DockerCredentials, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return config.DockerCredentials{}, fmt.Errorf("credential helper %s failed: %w: %s",
+			helper, err, strings.TrimSpace(stderr.String()+stdout.String()))
+	}
+
+	var output struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return config.DockerCredentials{}, fmt.Errorf("failed to decode output of credential helper %s: %w", helper, err)
+	}
+	if output.Username == credentialHelperTokenUsername {
+		return config.DockerCredentials{IdentityToken: output.Secret}, nil
+	}
+	return config.DockerCredentials{Username: output.Username, Password: output.Secret}, nil
+}
@@ -0,0 +1,149 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/registry"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/configenv"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+)
+
+type CredentialsResolverSuite struct {
+	suite.Suite
+	ctx      context.Context
+	resolver *CredentialsResolver
+	helped   []string
+}
+
+func TestCredentialsResolverSuite(t *testing.T) {
+	config.Set(configenv.Testing)
+	suite.Run(t, new(CredentialsResolverSuite))
+}
+
+func (s *CredentialsResolverSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.helped = nil
+	policy, err := secrets.NewPolicy([]types.SecretConfig{
+		{Name: "private", Shared: true, URLs: []string{"https://ghcr.io/org/"}},
+		{Name: "team-a/private", URLs: []string{"https://ghcr.io/org/"}},
+	})
+	s.Require().NoError(err)
+	s.resolver = NewCredentialsResolver(CredentialsResolverParams{
+		Registries: []types.DockerRegistryConfig{
+			{Registry: "ghcr.io", Secret: "ghcr"},
+			{Registry: "https://index.docker.io/v1/", CredentialHelper: "pass"},
+			{Registry: "registry.example.com", CredentialHelper: "ecr-login"},
+		},
+		Secrets: secrets.StaticResolver{
+			"ghcr":           "node:node-password",
+			"private":        "node-token",
+			"team-a/private": "team:team-password",
+		},
+		SecretsPolicy: policy,
+		CredentialHelper: func(_ context.Context, helper, serverURL string) (config.DockerCredentials, error) {
+			s.helped = append(s.helped, helper+" "+serverURL)
+			return config.DockerCredentials{Username: helper, Password: "helper-password"}, nil
+		},
+	})
+}
+
+func (s *CredentialsResolverSuite) TestJobSecret() {
+	creds, err := s.resolver.Resolve(s.ctx, "ghcr.io/org/image", "team-a", "private")
+	s.Require().NoError(err)
+	s.Equal(config.DockerCredentials{Username: "team", Password: "team-password", Registry: "ghcr.io"}, creds)
+
+	// other namespaces fall back to the node-wide secret
+	creds, err = s.resolver.Resolve(s.ctx, "ghcr.io/org/image", "team-b", "private")
+	s.Require().NoError(err)
+	s.Equal(config.DockerCredentials{IdentityToken: "node-token", Registry: "ghcr.io"}, creds)
+
+	// namespaces cannot reference the secrets of other namespaces
+	_, err = s.resolver.Resolve(s.ctx, "ghcr.io/org/image", "team-b", "team-a/private")
+	s.Error(err)
+
+	_, err = s.resolver.Resolve(s.ctx, "ghcr.io/org/image", "team-a", "missing")
+	s.ErrorContains(err, "failed to resolve registry credentials")
+
+	// secrets are only sent to the repositories they are configured for
+	_, err = s.resolver.Resolve(s.ctx, "evil.example.com/org/image", "team-a", "private")
+	s.ErrorContains(err, "cannot be sent to")
+	_, err = s.resolver.Resolve(s.ctx, "ghcr.io/other/image", "team-b", "private")
+	s.ErrorContains(err, "cannot be sent to")
+
+	// node-wide secrets that are not shared, such as registry secrets, cannot be referenced by jobs
+	_, err = s.resolver.Resolve(s.ctx, "ghcr.io/org/image", "team-a", "ghcr")
+	s.ErrorContains(err, "not available to jobs")
+}
+
+func (s *CredentialsResolverSuite) TestRegistryConfig() {
+	creds, err := s.resolver.Resolve(s.ctx, "ghcr.io/org/image:1.0", "team-a", "")
+	s.Require().NoError(err)
+	s.Equal(config.DockerCredentials{Username: "node", Password: "node-password", Registry: "ghcr.io"}, creds)
+
+	creds, err = s.resolver.Resolve(s.ctx, "ubuntu", "", "")
+	s.Require().NoError(err)
+	s.Equal(config.DockerCredentials{Username: "pass", Password: "helper-password", Registry: "docker.io"}, creds)
+
+	_, err = s.resolver.Resolve(s.ctx, "registry.example.com/image", "", "")
+	s.Require().NoError(err)
+	s.Equal([]string{"pass https://index.docker.io/v1/", "ecr-login registry.example.com"}, s.helped)
+
+	// registries without configuration use the default credentials
+	creds, err = s.resolver.Resolve(s.ctx, "quay.io/org/image", "", "")
+	s.Require().NoError(err)
+	s.Equal(config.GetDockerCredentials(), creds)
+}
+
+func (s *CredentialsResolverSuite) TestRunCredentialHelper() {
+	dir := s.T().TempDir()
+	script := "#!/bin/sh\n" +
+		"read server\n" +
+		"echo '{\"ServerURL\":\"'$server'\",\"Username\":\"<token>\",\"Secret\":\"identity-token\"}'\n"
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, "docker-credential-fake"), []byte(script), 0o700))
+	s.T().Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	creds, err := runCredentialHelper(s.ctx, "fake", "registry.example.com")
+	s.Require().NoError(err)
+	s.Equal(config.DockerCredentials{IdentityToken: "identity-token"}, creds)
+
+	_, err = runCredentialHelper(s.ctx, "missing", "registry.example.com")
+	s.ErrorContains(err, "credential helper missing failed")
+}
+
+func (s *CredentialsResolverSuite) TestAuthToken() {
+	decode := func(token string) registry.AuthConfig {
+		var auth registry.AuthConfig
+		data, err := base64.URLEncoding.DecodeString(token)
+		s.Require().NoError(err)
+		s.Require().NoError(json.Unmarshal(data, &auth))
+		return auth
+	}
+
+	creds := config.DockerCredentials{Username: "user", Password: "password", Registry: "ghcr.io"}
+	auth := decode(getAuthToken(s.ctx, "ghcr.io/org/image", creds))
+	s.Equal("user", auth.Username)
+	s.Equal("ghcr.io", auth.ServerAddress)
+
+	// credentials are never sent to another registry
+	s.Empty(getAuthToken(s.ctx, "quay.io/org/image", creds))
+	s.Empty(getAuthToken(s.ctx, "ubuntu", creds))
+
+	token := config.DockerCredentials{IdentityToken: "token", Registry: "docker.io"}
+	s.Equal("token", decode(getAuthToken(s.ctx, "org/image", token)).IdentityToken)
+
+	// credentials without a registry are only used for the default registry
+	creds = config.DockerCredentials{Username: "user", Password: "password"}
+	s.Equal("user", decode(getAuthToken(s.ctx, "org/image", creds)).Username)
+	s.Empty(getAuthToken(s.ctx, "ghcr.io/org/image", creds))
+}
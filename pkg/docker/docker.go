@@ -29,6 +29,7 @@ import (
 	"golang.org/x/exp/slices"
 
 	"github.com/bacalhau-project/bacalhau/pkg/config"
+	cfgtypes "github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/docker/tracing"
 	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
 )
@@ -41,15 +42,32 @@ const DistributionInspectError = `Could not inspect image %q - could be due to r
 
 type Client struct {
 	tracing.TracedClient
+	// Runtime is the container runtime the client is connected to.
+	Runtime string
 }
 
+// NewDockerClient returns a client of the container runtime configured for the node.
 func NewDockerClient() (*Client, error) {
-	client, err := tracing.NewTracedClient()
+	return NewRuntimeClient(config.GetContainerRuntimeConfig())
+}
+
+// NewRuntimeClient returns a client of the API of the container runtime.
+func NewRuntimeClient(cfg cfgtypes.ContainerRuntimeConfig) (*Client, error) {
+	opts, err := runtimeOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := tracing.NewTracedClient(opts...)
 	if err != nil {
 		return nil, err
 	}
+	runtime := cfg.Type
+	if runtime == "" {
+		runtime = RuntimeDocker
+	}
 	return &Client{
-		client,
+		TracedClient: client,
+		Runtime:      runtime,
 	}, nil
 }
 
@@ -457,13 +475,22 @@ func logImagePullStatus(ctx context.Context, m *sync.Map) {
 
 func getAuthToken(ctx context.Context, image string, dockerCreds config.DockerCredentials) string {
 	if dockerCreds.IsValid() {
-		// We only currently support auth for the default registry, so any
+		// Credentials resolved for a registry are only sent to that registry.
+		// Credentials without a registry are for the default registry, so any
 		// pulls for `image` or `user/image` should be okay, anything trying
 		// to pull `repo/user/image` should not.
-		if strings.Count(image, "/") < 2 {
+		allowed := strings.Count(image, "/") < 2
+		if dockerCreds.Registry != "" {
+			allowed = ImageRegistry(image) == normalizeRegistry(dockerCreds.Registry)
+		}
+		if allowed {
 			authConfig := registry.AuthConfig{
-				Username: dockerCreds.Username,
-				Password: dockerCreds.Password,
+				Username:      dockerCreds.Username,
+				Password:      dockerCreds.Password,
+				IdentityToken: dockerCreds.IdentityToken,
+			}
+			if dockerCreds.Registry != "" {
+				authConfig.ServerAddress = dockerCreds.Registry
 			}
 
 			encodedJSON, err := json.Marshal(authConfig)
@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dockerclient "github.com/docker/docker/client"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+// The container runtimes that can run docker jobs. Podman is used through
+// its Docker compatible API, so docker jobs run the same on either runtime.
+// Only runtimes with a Docker compatible API are supported, as the docker
+// executor is built on that API.
+const (
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+	// RuntimeContainerd is not supported, as containerd has no Docker
+	// compatible API and would need an executor of its own. It is only named
+	// to reject it with a helpful error.
+	RuntimeContainerd = "containerd"
+)
+
+const (
+	// podmanRootfulSocket is the API socket of the system podman service.
+	podmanRootfulSocket = "/run/podman/podman.sock"
+	// podmanRootlessSocket is the API socket of a user's podman service,
+	// relative to their runtime directory.
+	podmanRootlessSocket = "podman/podman.sock"
+)
+
+// ValidateRuntimeConfig returns an error if the container runtime is not
+// supported.
+func ValidateRuntimeConfig(cfg types.ContainerRuntimeConfig) error {
+	switch cfg.Type {
+	case "", RuntimeDocker, RuntimePodman:
+		return nil
+	case RuntimeContainerd:
+		return fmt.Errorf("container runtime %s is not supported as it has no Docker compatible API, "+
+			"expected %s or %s. Podman can be installed alongside containerd to run docker jobs",
+			cfg.Type, RuntimeDocker, RuntimePodman)
+	default:
+		return fmt.Errorf("unsupported container runtime %q, expected %s or %s", cfg.Type, RuntimeDocker, RuntimePodman)
+	}
+}
+
+// runtimeOptions returns the client options that connect to the API of the
+// container runtime.
+func runtimeOptions(cfg types.ContainerRuntimeConfig) ([]dockerclient.Opt, error) {
+	if err := ValidateRuntimeConfig(cfg); err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case "", RuntimeDocker:
+		if cfg.Host == "" {
+			return nil, nil
+		}
+		return []dockerclient.Opt{dockerclient.WithHost(cfg.Host)}, nil
+	case RuntimePodman:
+		host := cfg.Host
+		if host == "" {
+			host = podmanHost(os.Getuid(), os.Getenv("XDG_RUNTIME_DIR"))
+		}
+		return []dockerclient.Opt{dockerclient.WithHost(host)}, nil
+	}
+	return nil, nil
+}
+
+// podmanHost returns the address of the podman API socket for the user,
+// which is in the user's runtime directory when podman is rootless.
+func podmanHost(uid int, runtimeDir string) string {
+	if uid != 0 {
+		if runtimeDir == "" {
+			runtimeDir = filepath.Join("/run/user", fmt.Sprint(uid))
+		}
+		return "unix://" + filepath.Join(runtimeDir, podmanRootlessSocket)
+	}
+	return "unix://" + podmanRootfulSocket
+}
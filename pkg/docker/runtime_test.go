@@ -0,0 +1,54 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+type RuntimeSuite struct {
+	suite.Suite
+}
+
+func TestRuntimeSuite(t *testing.T) {
+	suite.Run(t, new(RuntimeSuite))
+}
+
+func (s *RuntimeSuite) TestPodmanHost() {
+	s.Require().Equal("unix:///run/podman/podman.sock", podmanHost(0, "/run/user/0"))
+	s.Require().Equal("unix:///run/user/1000/podman/podman.sock", podmanHost(1000, "/run/user/1000"))
+	s.Require().Equal("unix:///run/user/1000/podman/podman.sock", podmanHost(1000, ""))
+}
+
+func (s *RuntimeSuite) TestNewRuntimeClient() {
+	client, err := NewRuntimeClient(types.ContainerRuntimeConfig{})
+	s.Require().NoError(err)
+	s.Require().Equal(RuntimeDocker, client.Runtime)
+
+	client, err = NewRuntimeClient(types.ContainerRuntimeConfig{
+		Type: RuntimePodman,
+		Host: "unix:///tmp/podman.sock",
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(RuntimePodman, client.Runtime)
+	s.Require().Equal("unix:///tmp/podman.sock", client.DaemonHost())
+}
+
+func (s *RuntimeSuite) TestUnsupportedRuntime() {
+	_, err := NewRuntimeClient(types.ContainerRuntimeConfig{Type: "cri-o"})
+	s.Require().ErrorContains(err, "unsupported container runtime")
+
+	_, err = NewRuntimeClient(types.ContainerRuntimeConfig{Type: RuntimeContainerd})
+	s.Require().ErrorContains(err, "container runtime containerd is not supported")
+}
+
+func (s *RuntimeSuite) TestValidateRuntimeConfig() {
+	for _, runtime := range []string{"", RuntimeDocker, RuntimePodman} {
+		s.NoError(ValidateRuntimeConfig(types.ContainerRuntimeConfig{Type: runtime}), runtime)
+	}
+	s.Error(ValidateRuntimeConfig(types.ContainerRuntimeConfig{Type: RuntimeContainerd}))
+}
@@ -0,0 +1,185 @@
+package docker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+const (
+	// allCapabilities stands for every Linux capability in a list of capabilities.
+	allCapabilities = "ALL"
+	// unconfined disables a seccomp or AppArmor profile.
+	unconfined = "unconfined"
+	// readOnlyTmpfs is where a tmpfs is mounted in containers with a read-only root filesystem.
+	readOnlyTmpfs = "/tmp"
+)
+
+// SecurityProfile is a security profile of the node that a docker job runs
+// with, along with the capabilities the job added to it.
+type SecurityProfile struct {
+	types.DockerSecurityProfileConfig
+	// Capabilities are the capabilities the job added, which the profile allows.
+	Capabilities []string
+	// seccomp is the JSON of the profile's seccomp profile, or unconfined
+	seccomp string
+}
+
+// SecurityPolicy holds the security profiles of the node, and decides the
+// profile each docker job runs with.
+type SecurityPolicy struct {
+	defaultProfile string
+	profiles       map[string]types.DockerSecurityProfileConfig
+	// seccomp holds the seccomp profile of each profile, as given to the runtime
+	seccomp map[string]string
+}
+
+// NewSecurityPolicyFromConfig returns a policy with the security profiles
+// configured for the node.
+func NewSecurityPolicyFromConfig() (*SecurityPolicy, error) {
+	cfg, err := config.GetDockerSecurityConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load docker security configuration: %w", err)
+	}
+	return NewSecurityPolicy(cfg)
+}
+
+// NewSecurityPolicy returns a policy with the given security profiles, or an
+// error if they are invalid.
+func NewSecurityPolicy(cfg types.DockerSecurityConfig) (*SecurityPolicy, error) {
+	policy := &SecurityPolicy{
+		defaultProfile: cfg.DefaultProfile,
+		profiles:       make(map[string]types.DockerSecurityProfileConfig),
+		seccomp:        make(map[string]string),
+	}
+	for _, profile := range cfg.Profiles {
+		if profile.Name == "" {
+			return nil, errors.New("docker security profiles must have a name")
+		}
+		if _, ok := policy.profiles[profile.Name]; ok {
+			return nil, fmt.Errorf("duplicate docker security profile %q", profile.Name)
+		}
+		for _, ulimit := range profile.Ulimits {
+			if _, err := units.ParseUlimit(fmt.Sprintf("%s=%d:%d", ulimit.Name, ulimit.Soft, ulimit.Hard)); err != nil {
+				return nil, fmt.Errorf("invalid ulimit of docker security profile %q: %w", profile.Name, err)
+			}
+		}
+		if profile.PidsLimit < 0 {
+			return nil, fmt.Errorf("invalid pids limit of docker security profile %q: %d", profile.Name, profile.PidsLimit)
+		}
+		switch profile.Seccomp {
+		case "":
+		case unconfined:
+			policy.seccomp[profile.Name] = unconfined
+		default:
+			// the runtime expects the profile itself rather than its path
+			data, err := os.ReadFile(profile.Seccomp)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read seccomp profile of docker security profile %q: %w", profile.Name, err)
+			}
+			policy.seccomp[profile.Name] = string(data)
+		}
+		policy.profiles[profile.Name] = profile
+	}
+	if cfg.DefaultProfile != "" {
+		if _, ok := policy.profiles[cfg.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("default docker security profile %q is not configured", cfg.DefaultProfile)
+		}
+	}
+	return policy, nil
+}
+
+// Resolve returns the security profile a job that requested the profile and
+// capabilities runs with, or an error if the node does not allow them. Jobs
+// that don't request a profile run with the node's default profile. A nil
+// profile is returned if the job runs with the runtime's defaults.
+func (p *SecurityPolicy) Resolve(name string, capabilities []string) (*SecurityProfile, error) {
+	if name == "" {
+		name = p.defaultProfile
+	}
+	if name == "" {
+		if len(capabilities) > 0 {
+			return nil, fmt.Errorf("capabilities %s require a docker security profile that allows them",
+				strings.Join(capabilities, ", "))
+		}
+		return nil, nil
+	}
+	profile, ok := p.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("docker security profile %q is not configured on this node", name)
+	}
+
+	added := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		capability = normalizeCapability(capability)
+		if !containsCapability(profile.AllowedCapabilities, capability) {
+			return nil, fmt.Errorf("capability %s is not allowed by docker security profile %q", capability, name)
+		}
+		added = append(added, capability)
+	}
+	return &SecurityProfile{
+		DockerSecurityProfileConfig: profile,
+		Capabilities:                added,
+		seccomp:                     p.seccomp[name],
+	}, nil
+}
+
+// Apply sets the security options of the profile on the config of a container.
+func (p *SecurityProfile) Apply(hostConfig *container.HostConfig) {
+	if p == nil {
+		return
+	}
+	hostConfig.ReadonlyRootfs = p.ReadOnlyRootFilesystem
+	if p.ReadOnlyRootFilesystem {
+		if hostConfig.Tmpfs == nil {
+			hostConfig.Tmpfs = make(map[string]string)
+		}
+		hostConfig.Tmpfs[readOnlyTmpfs] = ""
+	}
+	for _, capability := range p.DropCapabilities {
+		hostConfig.CapDrop = append(hostConfig.CapDrop, normalizeCapability(capability))
+	}
+	hostConfig.CapAdd = append(hostConfig.CapAdd, p.Capabilities...)
+	if p.NoNewPrivileges {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "no-new-privileges:true")
+	}
+	if p.seccomp != "" {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "seccomp="+p.seccomp)
+	}
+	if p.AppArmor != "" {
+		hostConfig.SecurityOpt = append(hostConfig.SecurityOpt, "apparmor="+p.AppArmor)
+	}
+	if p.UsernsMode != "" {
+		hostConfig.UsernsMode = container.UsernsMode(p.UsernsMode)
+	}
+	if p.PidsLimit > 0 {
+		pidsLimit := p.PidsLimit
+		hostConfig.PidsLimit = &pidsLimit
+	}
+	for _, ulimit := range p.Ulimits {
+		hostConfig.Ulimits = append(hostConfig.Ulimits, &units.Ulimit{Name: ulimit.Name, Soft: ulimit.Soft, Hard: ulimit.Hard})
+	}
+}
+
+// normalizeCapability returns the name of a capability the way the runtime
+// expects it, e.g. NET_ADMIN for cap_net_admin.
+func normalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(capability)), "CAP_")
+}
+
+func containsCapability(capabilities []string, capability string) bool {
+	for _, c := range capabilities {
+		c = normalizeCapability(c)
+		if c == allCapabilities || c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+type SecuritySuite struct {
+	suite.Suite
+}
+
+func TestSecuritySuite(t *testing.T) {
+	suite.Run(t, new(SecuritySuite))
+}
+
+func (s *SecuritySuite) policy() *SecurityPolicy {
+	seccomp := filepath.Join(s.T().TempDir(), "seccomp.json")
+	s.Require().NoError(os.WriteFile(seccomp, []byte(`{"defaultAction":"SCMP_ACT_ERRNO"}`), 0o600))
+	policy, err := NewSecurityPolicy(types.DockerSecurityConfig{
+		DefaultProfile: "restricted",
+		Profiles: []types.DockerSecurityProfileConfig{
+			{
+				Name:                   "restricted",
+				ReadOnlyRootFilesystem: true,
+				DropCapabilities:       []string{"ALL"},
+				AllowedCapabilities:    []string{"cap_chown", "NET_BIND_SERVICE"},
+				NoNewPrivileges:        true,
+				Seccomp:                seccomp,
+				AppArmor:               "bacalhau-jobs",
+				UsernsMode:             "auto",
+				PidsLimit:              256,
+				Ulimits:                []types.DockerUlimitConfig{{Name: "nofile", Soft: 1024, Hard: 2048}},
+			},
+			{Name: "privileged", AllowedCapabilities: []string{"ALL"}, Seccomp: "unconfined"},
+		},
+	})
+	s.Require().NoError(err)
+	return policy
+}
+
+func (s *SecuritySuite) TestResolve() {
+	policy := s.policy()
+
+	profile, err := policy.Resolve("", []string{"chown"})
+	s.Require().NoError(err)
+	s.Equal("restricted", profile.Name)
+	s.Equal([]string{"CHOWN"}, profile.Capabilities)
+
+	profile, err = policy.Resolve("privileged", []string{"SYS_ADMIN"})
+	s.Require().NoError(err)
+	s.Equal("privileged", profile.Name)
+
+	_, err = policy.Resolve("", []string{"SYS_ADMIN"})
+	s.ErrorContains(err, `capability SYS_ADMIN is not allowed by docker security profile "restricted"`)
+
+	_, err = policy.Resolve("root", nil)
+	s.ErrorContains(err, `docker security profile "root" is not configured on this node`)
+}
+
+func (s *SecuritySuite) TestResolveWithoutProfiles() {
+	policy, err := NewSecurityPolicy(types.DockerSecurityConfig{})
+	s.Require().NoError(err)
+
+	profile, err := policy.Resolve("", nil)
+	s.Require().NoError(err)
+	s.Nil(profile)
+
+	// the runtime's defaults are used, which are not changed
+	hostConfig := &container.HostConfig{}
+	profile.Apply(hostConfig)
+	s.Equal(&container.HostConfig{}, hostConfig)
+
+	_, err = policy.Resolve("", []string{"NET_ADMIN"})
+	s.Error(err)
+}
+
+func (s *SecuritySuite) TestApply() {
+	profile, err := s.policy().Resolve("", []string{"NET_BIND_SERVICE"})
+	s.Require().NoError(err)
+
+	hostConfig := &container.HostConfig{}
+	profile.Apply(hostConfig)
+	s.True(hostConfig.ReadonlyRootfs)
+	s.Equal(map[string]string{"/tmp": ""}, hostConfig.Tmpfs)
+	s.Equal([]string{"ALL"}, []string(hostConfig.CapDrop))
+	s.Equal([]string{"NET_BIND_SERVICE"}, []string(hostConfig.CapAdd))
+	s.Equal([]string{
+		"no-new-privileges:true",
+		`seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`,
+		"apparmor=bacalhau-jobs",
+	}, hostConfig.SecurityOpt)
+	s.Equal(container.UsernsMode("auto"), hostConfig.UsernsMode)
+	s.Require().NotNil(hostConfig.PidsLimit)
+	s.Equal(int64(256), *hostConfig.PidsLimit)
+	s.Equal([]*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}}, hostConfig.Ulimits)
+}
+
+func (s *SecuritySuite) TestInvalidConfig() {
+	for name, cfg := range map[string]types.DockerSecurityConfig{
+		"missing default": {DefaultProfile: "restricted"},
+		"missing name":    {Profiles: []types.DockerSecurityProfileConfig{{}}},
+		"duplicate name": {Profiles: []types.DockerSecurityProfileConfig{
+			{Name: "restricted"}, {Name: "restricted"},
+		}},
+		"invalid ulimit": {Profiles: []types.DockerSecurityProfileConfig{
+			{Name: "restricted", Ulimits: []types.DockerUlimitConfig{{Name: "files", Soft: 1, Hard: 1}}},
+		}},
+		"soft above hard ulimit": {Profiles: []types.DockerSecurityProfileConfig{
+			{Name: "restricted", Ulimits: []types.DockerUlimitConfig{{Name: "nofile", Soft: 2, Hard: 1}}},
+		}},
+		"missing seccomp profile": {Profiles: []types.DockerSecurityProfileConfig{
+			{Name: "restricted", Seccomp: "/does/not/exist.json"},
+		}},
+	} {
+		_, err := NewSecurityPolicy(cfg)
+		s.Error(err, name)
+	}
+}
@@ -18,8 +18,10 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func NewTracedClient() (TracedClient, error) {
-	c, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// NewTracedClient creates a client configured from the environment, to which the options are then applied.
+func NewTracedClient(opts ...client.Opt) (TracedClient, error) {
+	opts = append([]client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}, opts...)
+	c, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return TracedClient{}, err
 	}
@@ -38,6 +40,11 @@ type TracedClient struct {
 	hostname string
 }
 
+// DaemonHost returns the address of the API the client is connected to.
+func (c TracedClient) DaemonHost() string {
+	return c.hostname
+}
+
 func (c TracedClient) ContainerCreate(
 	ctx context.Context,
 	config *container.Config,
@@ -54,6 +61,27 @@ func (c TracedClient) ContainerCreate(
 	)
 }
 
+func (c TracedClient) ContainerExecCreate(ctx context.Context, containerID string, config types.ExecConfig) (types.IDResponse, error) {
+	ctx, span := c.span(ctx, "container.exec.create")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.IDResponse](span)(c.client.ContainerExecCreate(ctx, containerID, config))
+}
+
+func (c TracedClient) ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error {
+	ctx, span := c.span(ctx, "container.exec.start")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpan(span)(c.client.ContainerExecStart(ctx, execID, config))
+}
+
+func (c TracedClient) ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error) {
+	ctx, span := c.span(ctx, "container.exec.inspect")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[types.ContainerExecInspect](span)(c.client.ContainerExecInspect(ctx, execID))
+}
+
 func (c TracedClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
 	ctx, span := c.span(ctx, "container.inspect")
 	defer span.End()
@@ -142,6 +170,14 @@ func (c TracedClient) ImagePull(ctx context.Context, refStr string, options type
 	return telemetry.RecordErrorOnSpanReadCloserAndClose(span)(c.client.ImagePull(ctx, refStr, options))
 }
 
+func (c TracedClient) ImageRemove(
+	ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	ctx, span := c.span(ctx, "image.remove")
+	defer span.End()
+
+	return telemetry.RecordErrorOnSpanTwo[[]types.ImageDeleteResponseItem](span)(c.client.ImageRemove(ctx, imageID, options))
+}
+
 func (c TracedClient) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
 	ctx, span := c.span(ctx, "network.connect")
 	defer span.End()
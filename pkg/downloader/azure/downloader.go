@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/rs/zerolog/log"
+
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+type DownloaderParams struct {
+	ClientProvider *azurehelper.ClientProvider
+}
+
+// Downloader fetches result archives from Azure Blob Storage using the
+// client's own credentials.
+type Downloader struct {
+	clientProvider *azurehelper.ClientProvider
+}
+
+func NewDownloader(params DownloaderParams) *Downloader {
+	return &Downloader{
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled returns true as public containers can be read without credentials.
+func (d *Downloader) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadItem) (string, error) {
+	if item.SingleFile != "" {
+		return "", errors.New("azure downloader does not support single file downloads")
+	}
+	source, err := azurehelper.DecodeSourceSpec(item.Result)
+	if err != nil {
+		return "", err
+	}
+
+	localPath := filepath.Join(item.ParentPath, strings.ReplaceAll(source.Container+"/"+source.Key, "/", "_"))
+	alreadyExists, err := downloader.IsAlreadyDownloaded(localPath)
+	if err != nil {
+		return "", err
+	}
+	if alreadyExists {
+		log.Ctx(ctx).Debug().Str("Key", source.Key).Msg("File already downloaded.")
+		return localPath, nil
+	}
+
+	client, err := d.clientProvider.GetClient(source.Account, source.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	out, err := os.OpenFile(localPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, downloader.DownloadFilePerm)
+	if err != nil {
+		return "", err
+	}
+	defer closer.CloseWithLogOnError("file", out)
+
+	options := &azblob.DownloadFileOptions{}
+	if source.ETag != "" {
+		etag := azcore.ETag(source.ETag)
+		options.AccessConditions = &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfMatch: &etag},
+		}
+	}
+	log.Ctx(ctx).Debug().Msgf("Downloading az://%s/%s to %s", source.Container, source.Key, localPath)
+	if _, err = client.DownloadFile(ctx, source.Container, source.Key, out, options); err != nil {
+		return "", err
+	}
+	return localPath, nil
+}
@@ -38,9 +38,17 @@ var specialFiles = map[string]bool{
 // * iterate over each output volume
 // * make new folder for output volume
 // * iterate over each result and merge files in output folder to results dir
+//
+// manifests holds the signed manifest of each published result, in the same
+// order. Results are verified against their signed manifest, and the download
+// fails if they do not match or have no signed manifest, unless
+// settings.NoVerify is set. nodeKeys maps node IDs to the public keys the orchestrator pinned for
+// them, which the manifests must be signed with.
 func DownloadResults( //nolint:funlen,gocyclo
 	ctx context.Context,
 	publishedResults []*models.SpecConfig,
+	manifests []*models.ResultManifestSignature,
+	nodeKeys map[string]string,
 	downloadProvider DownloaderProvider,
 	settings *DownloaderSettings,
 ) error {
@@ -75,8 +83,10 @@ func DownloadResults( //nolint:funlen,gocyclo
 	}
 
 	log.Ctx(ctx).Info().Msgf("Downloading %d results to: %s.", len(publishedResults), resultsOutputDir)
-	downloadedResults := make(map[string]struct{})
-	for _, publishedResult := range publishedResults {
+	// downloadedResults maps the path of each downloaded result to the
+	// signature of its manifest, if any
+	downloadedResults := make(map[string]*models.ResultManifestSignature)
+	for i, publishedResult := range publishedResults {
 		downloader, err := downloadProvider.Get(ctx, publishedResult.Type)
 		if err != nil {
 			return err
@@ -89,13 +99,22 @@ func DownloadResults( //nolint:funlen,gocyclo
 		if err != nil {
 			return err
 		}
-		downloadedResults[resultPath] = struct{}{}
+		var manifest *models.ResultManifestSignature
+		if i < len(manifests) {
+			manifest = manifests[i]
+		}
+		downloadedResults[resultPath] = manifest
 	}
 
 	if settings.Raw {
+		for resultPath, manifest := range downloadedResults {
+			if err = verifyRawResult(ctx, resultPath, manifest, nodeKeys, settings); err != nil {
+				return err
+			}
+		}
 		return nil
 	} else {
-		for resultPath := range downloadedResults {
+		for resultPath, manifest := range downloadedResults {
 			log.Ctx(ctx).Debug().
 				Str("Source", resultPath).
 				Str("Target", resultsOutputDir).
@@ -103,7 +122,7 @@ func DownloadResults( //nolint:funlen,gocyclo
 
 			// if the result is a tar.gz file, we uncompress it first to a folder with the same name (minus the extension)
 			// TODO: We could also do this using the content-type for the download (for _some_ downloaders).
-			if strings.HasSuffix(resultPath, ".tar.gz") || strings.HasSuffix(resultPath, ".tgz") {
+			if isCompressed(resultPath) {
 				newResultPath := strings.TrimSuffix(resultPath, ".tar.gz")
 				newResultPath = strings.TrimSuffix(newResultPath, ".tgz")
 
@@ -121,6 +140,10 @@ func DownloadResults( //nolint:funlen,gocyclo
 				resultPath = newResultPath
 			}
 
+			if err = verifyDownloadedResult(ctx, resultPath, manifest, nodeKeys, settings); err != nil {
+				return err
+			}
+
 			err = moveData(ctx, resultPath, resultsOutputDir, len(downloadedResults) > 1)
 			if err != nil {
 				return err
@@ -131,6 +154,58 @@ func DownloadResults( //nolint:funlen,gocyclo
 	}
 }
 
+func isCompressed(resultPath string) bool {
+	return strings.HasSuffix(resultPath, ".tar.gz") || strings.HasSuffix(resultPath, ".tgz")
+}
+
+// verifyDownloadedResult verifies the result in resultDir against its signed
+// manifest. Results without a signed manifest are rejected unless
+// verification is disabled, and single file downloads are only verified for
+// the files that were downloaded.
+func verifyDownloadedResult(
+	ctx context.Context,
+	resultDir string,
+	manifest *models.ResultManifestSignature,
+	nodeKeys map[string]string,
+	settings *DownloaderSettings,
+) error {
+	switch {
+	case settings.NoVerify:
+		log.Ctx(ctx).Warn().Str("Source", resultDir).Msg("Skipping verification of results")
+		return nil
+	case manifest == nil:
+		return fmt.Errorf("%w: results are not signed and cannot be verified, "+
+			"download them with --no-verify to skip verification", ErrVerificationFailed)
+	case settings.SingleFile != "":
+		return VerifyPartialResult(resultDir, manifest, nodeKeys[manifest.NodeID])
+	default:
+		return VerifyResult(resultDir, manifest, nodeKeys[manifest.NodeID])
+	}
+}
+
+// verifyRawResult verifies a result that is kept as it was downloaded.
+// Compressed results are uncompressed to a temporary folder to verify them.
+func verifyRawResult(
+	ctx context.Context,
+	resultPath string,
+	manifest *models.ResultManifestSignature,
+	nodeKeys map[string]string,
+	settings *DownloaderSettings,
+) error {
+	if settings.NoVerify || manifest == nil || !isCompressed(resultPath) {
+		return verifyDownloadedResult(ctx, resultPath, manifest, nodeKeys, settings)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(resultPath), ".verify-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err = gzip.Decompress(resultPath, dir); err != nil {
+		return err
+	}
+	return verifyDownloadedResult(ctx, dir, manifest, nodeKeys, settings)
+}
+
 func moveData(
 	ctx context.Context,
 	fromFolder string,
@@ -66,6 +66,8 @@ func (ds *DownloaderSuite) SetupTest() {
 
 	ds.downloadSettings = &downloader.DownloaderSettings{
 		Timeout: downloader.DefaultDownloadTimeout,
+		// the mock results are not signed
+		NoVerify: true,
 	}
 
 	// Setup ipfs node
@@ -231,6 +233,8 @@ func (ds *DownloaderSuite) TestNoExpectedResults() {
 	err := downloader.DownloadResults(
 		ds.Ctx,
 		[]*models.SpecConfig{},
+		nil,
+		nil,
 		ds.downloadProvider,
 		ds.downloadSettings,
 	)
@@ -242,6 +246,8 @@ func (ds *DownloaderSuite) download(results ...*models.SpecConfig) error {
 	return downloader.DownloadResults(
 		ds.Ctx,
 		results,
+		nil,
+		nil,
 		ds.downloadProvider,
 		ds.downloadSettings,
 	)
@@ -0,0 +1,65 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
+	gcsstorage "github.com/bacalhau-project/bacalhau/pkg/storage/gcs"
+)
+
+type DownloaderParams struct {
+	ClientProvider *gcshelper.ClientProvider
+}
+
+// Downloader fetches result archives from Google Cloud Storage using the
+// client's own credentials.
+type Downloader struct {
+	clientProvider *gcshelper.ClientProvider
+}
+
+func NewDownloader(params DownloaderParams) *Downloader {
+	return &Downloader{
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled returns true as public buckets can be read without credentials.
+func (d *Downloader) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadItem) (string, error) {
+	if item.SingleFile != "" {
+		return "", errors.New("gcs downloader does not support single file downloads")
+	}
+	source, err := gcshelper.DecodeSourceSpec(item.Result)
+	if err != nil {
+		return "", err
+	}
+
+	localPath := filepath.Join(item.ParentPath, strings.ReplaceAll(source.Bucket+"/"+source.Key, "/", "_"))
+	alreadyExists, err := downloader.IsAlreadyDownloaded(localPath)
+	if err != nil {
+		return "", err
+	}
+	if alreadyExists {
+		log.Ctx(ctx).Debug().Str("Key", source.Key).Msg("File already downloaded.")
+		return localPath, nil
+	}
+
+	client, err := d.clientProvider.GetClient(ctx, source.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	log.Ctx(ctx).Debug().Msgf("Downloading gs://%s/%s to %s", source.Bucket, source.Key, localPath)
+	if err = gcsstorage.DownloadObject(ctx, client, source.Bucket, source.Key, source.Generation, localPath); err != nil {
+		return "", err
+	}
+	return localPath, nil
+}
@@ -60,11 +60,11 @@ func (httpDownloader *Downloader) FetchResult(ctx context.Context, item download
 		return localPath, nil
 	}
 
-	return localPath, httpDownloader.fetch(ctx, sourceSpec.URL, localPath)
+	return localPath, httpDownloader.fetch(ctx, sourceSpec.URL, localPath, nil)
 }
 
 // fetch makes an HTTP GET request to the given URL and writes the response to the given filepath.
-func (httpDownloader *Downloader) fetch(ctx context.Context, url string, filepath string) error {
+func (httpDownloader *Downloader) fetch(ctx context.Context, url string, filepath string, header http.Header) error {
 	out, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, downloader.DownloadFilePerm)
 	if err != nil {
 		return err
@@ -75,6 +75,9 @@ func (httpDownloader *Downloader) fetch(ctx context.Context, url string, filepat
 	if err != nil {
 		return err
 	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
 
 	response, err := httpDownloader.httpClient.Do(req)
 	if err != nil {
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	httppublisher "github.com/bacalhau-project/bacalhau/pkg/publisher/http"
+)
+
+// ResultDownloader downloads results published by the HTTP publisher. When
+// the results were published with a secret, the client's own value of that
+// secret is sent with each request, if the client allows the secret to be
+// sent to the URL of the results.
+type ResultDownloader struct {
+	*Downloader
+	secrets *secrets.ClientResolver
+}
+
+func NewResultDownloader(resolver secrets.Resolver, policy *secrets.Policy) *ResultDownloader {
+	return &ResultDownloader{Downloader: NewHTTPDownloader(), secrets: secrets.ForClient(resolver, policy)}
+}
+
+func (d *ResultDownloader) FetchResult(ctx context.Context, item downloader.DownloadItem) (string, error) {
+	spec, err := httppublisher.DecodeResultSpec(item.Result)
+	if err != nil {
+		return "", err
+	}
+
+	fetcher := d.Downloader
+	header := http.Header{}
+	if spec.Secret != "" {
+		value, err := d.secrets.ResolveFor(ctx, spec.Secret, spec.URL)
+		if err != nil {
+			return "", err
+		}
+		name := spec.AuthHeader
+		if name == "" {
+			name = httppublisher.DefaultAuthHeader
+		}
+		header.Set(name, value)
+		fetcher = &Downloader{httpClient: clientWithoutHeaderOnRedirect(d.httpClient, name)}
+	}
+
+	name, err := SanitizeFileName(spec.URL)
+	if err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(item.ParentPath, name)
+	alreadyExists, err := downloader.IsAlreadyDownloaded(localPath)
+	if err != nil {
+		return "", err
+	}
+	if alreadyExists {
+		log.Ctx(ctx).Debug().Str("URL", spec.URL).Msg("File already downloaded.")
+		return localPath, nil
+	}
+
+	// archives are downloaded as a single file and extracted by the caller
+	if len(spec.Files) == 0 {
+		return localPath, fetcher.fetch(ctx, spec.URL, localPath, header)
+	}
+
+	for _, file := range spec.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return "", errors.New("refusing to download result file outside of the result directory: " + file)
+		}
+		target := filepath.Join(localPath, filepath.FromSlash(file))
+		if err = os.MkdirAll(filepath.Dir(target), downloader.DownloadFolderPerm); err != nil {
+			return "", err
+		}
+		if err = fetcher.fetch(ctx, spec.FileURL(file), target, header); err != nil {
+			return "", err
+		}
+	}
+	return localPath, nil
+}
+
+var _ downloader.Downloader = (*ResultDownloader)(nil)
+
+// maxRedirects is the number of redirects followed, as by the default client.
+const maxRedirects = 10
+
+// clientWithoutHeaderOnRedirect returns a copy of client that drops the named
+// header when following a redirect to another scheme or host, so that the
+// secret it holds is only sent to the URL it was resolved for.
+func clientWithoutHeaderOnRedirect(client *http.Client, header string) *http.Client {
+	cpy := *client
+	cpy.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if !strings.EqualFold(req.URL.Scheme, via[0].URL.Scheme) || !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+			req.Header.Del(header)
+		}
+		return nil
+	}
+	return &cpy
+}
@@ -0,0 +1,122 @@
+//go:build unit || !integration
+
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	httppublisher "github.com/bacalhau-project/bacalhau/pkg/publisher/http"
+)
+
+func TestResultDownloaderPerFile(t *testing.T) {
+	files := map[string]string{
+		"/results/stdout":             "hello",
+		"/results/outputs/a%20b.txt":  "nested",
+		"/results/outputs/other.json": "{}",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret-value" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		content, ok := files[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	result := httppublisher.ResultSpec{
+		URL:        server.URL + "/results/",
+		Files:      []string{"stdout", "outputs/a b.txt", "outputs/other.json"},
+		Secret:     "gateway",
+		AuthHeader: "X-Token",
+	}
+	d := NewResultDownloader(secrets.StaticResolver{"gateway": "secret-value"}, policy(t, server.URL+"/results"))
+	path, err := d.FetchResult(context.Background(), downloader.DownloadItem{
+		Result:     &models.SpecConfig{Type: models.StorageSourceHTTP, Params: result.ToMap()},
+		ParentPath: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(path, "outputs", "a b.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "nested", string(data))
+	require.FileExists(t, filepath.Join(path, "stdout"))
+
+	// without the client's secret the download fails
+	d = NewResultDownloader(secrets.StaticResolver{}, policy(t, server.URL+"/results"))
+	_, err = d.FetchResult(context.Background(), downloader.DownloadItem{
+		Result:     &models.SpecConfig{Type: models.StorageSourceHTTP, Params: result.ToMap()},
+		ParentPath: t.TempDir(),
+	})
+	require.Error(t, err)
+}
+
+func policy(t *testing.T, urls ...string) *secrets.Policy {
+	p, err := secrets.NewPolicy([]types.SecretConfig{{Name: "gateway", URLs: urls}})
+	require.NoError(t, err)
+	return p
+}
+
+func TestResultDownloaderSecretOnlySentToItsURLs(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Token"))
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	result := httppublisher.ResultSpec{URL: server.URL + "/results.tar.gz", Secret: "gateway", AuthHeader: "X-Token"}
+	d := NewResultDownloader(secrets.StaticResolver{"gateway": "secret-value"}, policy(t, "https://results.example.com/"))
+	_, err := d.FetchResult(context.Background(), downloader.DownloadItem{
+		Result:     &models.SpecConfig{Type: models.StorageSourceHTTP, Params: result.ToMap()},
+		ParentPath: t.TempDir(),
+	})
+	require.ErrorContains(t, err, "cannot be sent to")
+	require.Empty(t, received)
+}
+
+func TestResultDownloaderRedirectDropsSecret(t *testing.T) {
+	var received []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Token"))
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/results.tar.gz", http.StatusFound)
+	}))
+	defer server.Close()
+
+	result := httppublisher.ResultSpec{URL: server.URL + "/results.tar.gz", Secret: "gateway", AuthHeader: "X-Token"}
+	d := NewResultDownloader(secrets.StaticResolver{"gateway": "secret-value"}, policy(t, server.URL))
+	_, err := d.FetchResult(context.Background(), downloader.DownloadItem{
+		Result:     &models.SpecConfig{Type: models.StorageSourceHTTP, Params: result.ToMap()},
+		ParentPath: t.TempDir(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, received)
+}
+
+func TestResultDownloaderRejectsEscapingFiles(t *testing.T) {
+	result := httppublisher.ResultSpec{URL: "http://127.0.0.1/results/", Files: []string{"../escape"}}
+	_, err := NewResultDownloader(secrets.StaticResolver{}, nil).FetchResult(context.Background(), downloader.DownloadItem{
+		Result:     &models.SpecConfig{Type: models.StorageSourceHTTP, Params: result.ToMap()},
+		ParentPath: t.TempDir(),
+	})
+	require.Error(t, err)
+}
@@ -14,6 +14,7 @@ import (
 	bac_config "github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
 	ipfssource "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 )
@@ -113,6 +114,9 @@ func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadIt
 	cid := sourceSpec.CID
 	resultPath := filepath.Join(item.ParentPath, cid)
 	downloadPath := resultPath
+	// manifestCID is the CID of the results manifest when downloading a single
+	// file, which is downloaded alongside the file so that it can be verified
+	var manifestCID string
 
 	// If we're downloading a single file, we need to find the CID of that file,
 	if item.SingleFile != "" {
@@ -125,6 +129,9 @@ func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadIt
 			return "", fmt.Errorf("failed to find cid for %s", item.SingleFile)
 		}
 		cid = fileCID
+		if item.SingleFile != models.ResultManifestFilename {
+			manifestCID = filemap[models.ResultManifestFilename]
+		}
 		downloadPath = filepath.Join(resultPath, item.SingleFile)
 		err = os.MkdirAll(filepath.Dir(downloadPath), downloader.DownloadFolderPerm)
 		if err != nil {
@@ -163,6 +170,11 @@ func (d *Downloader) FetchResult(ctx context.Context, item downloader.DownloadIt
 
 		return "", err
 	}
+	if manifestCID != "" {
+		if err = ipfsClient.Get(ctx, manifestCID, filepath.Join(resultPath, models.ResultManifestFilename)); err != nil {
+			return "", err
+		}
+	}
 	// we always return the path of the result cid, even if it's a single file
 	return resultPath, nil
 }
@@ -34,6 +34,14 @@ type DownloaderSettings struct {
 	OutputDir  string
 	SingleFile string
 	Raw        bool
+	// NoVerify skips verifying results against their signed manifests, and
+	// allows downloading results that have no signed manifest
+	NoVerify bool
+	// Publisher restricts the download to results of the given publisher type
+	Publisher string
+	// Snapshot downloads the snapshots of running executions up to the given
+	// version, or up to the latest version if set to models.LatestSnapshot
+	Snapshot int
 }
 
 type DownloadItem struct {
@@ -1,17 +1,28 @@
 package util
 
 import (
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	"github.com/bacalhau-project/bacalhau/pkg/downloader/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/downloader/gcs"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader/http"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/downloader/s3signed"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
+	"github.com/rs/zerolog/log"
 )
 
 func NewStandardDownloaders(
 	cm *system.CleanupManager) downloader.DownloaderProvider {
+	// without a valid configuration, no secrets are sent with downloads
+	secretsPolicy, err := secrets.NewClientPolicyFromConfig()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load secrets configuration. Results published with a secret cannot be downloaded")
+	}
 	ipfsDownloader := ipfs.NewIPFSDownloader(cm)
 	s3PreSignedDownloader := s3signed.NewDownloader(s3signed.DownloaderParams{
 		HTTPDownloader: http.NewHTTPDownloader(),
@@ -21,5 +32,12 @@ func NewStandardDownloaders(
 		models.StorageSourceIPFS:        ipfsDownloader,
 		models.StorageSourceS3PreSigned: s3PreSignedDownloader,
 		models.StorageSourceURL:         http.NewHTTPDownloader(),
+		models.StorageSourceHTTP:        http.NewResultDownloader(secrets.NewEnvResolver(), secretsPolicy),
+		models.StorageSourceAzure: azure.NewDownloader(azure.DownloaderParams{
+			ClientProvider: azurehelper.NewClientProvider(azurehelper.DefaultClientProviderParams()),
+		}),
+		models.StorageSourceGCS: gcs.NewDownloader(gcs.DownloaderParams{
+			ClientProvider: gcshelper.NewClientProvider(gcshelper.DefaultClientProviderParams()),
+		}),
 	})
 }
@@ -0,0 +1,176 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+// ErrVerificationFailed is returned when downloaded results do not match the
+// manifest signed by the compute node that produced them.
+var ErrVerificationFailed = errors.New("result verification failed")
+
+// VerifyResult checks that the results in resultDir match their manifest,
+// and that the manifest was signed by the compute node. nodeKey is the public
+// key the orchestrator pinned for the node, or empty if it is unknown.
+func VerifyResult(resultDir string, signature *models.ResultManifestSignature, nodeKey string) error {
+	return verifyResult(resultDir, signature, nodeKey, false)
+}
+
+// VerifyPartialResult checks that the files in resultDir match their entries
+// in the signed manifest, but unlike VerifyResult allows files of the
+// manifest to be missing, e.g. when downloading a single file.
+func VerifyPartialResult(resultDir string, signature *models.ResultManifestSignature, nodeKey string) error {
+	return verifyResult(resultDir, signature, nodeKey, true)
+}
+
+func verifyResult(resultDir string, signature *models.ResultManifestSignature, nodeKey string, partial bool) error {
+	data, err := os.ReadFile(filepath.Join(resultDir, models.ResultManifestFilename))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: results manifest is missing", ErrVerificationFailed)
+	} else if err != nil {
+		return err
+	}
+	if err = verifySignature(data, signature, nodeKey); err != nil {
+		return err
+	}
+
+	var manifest models.ResultManifest
+	if err = json.NewDecoder(bytes.NewReader(data)).Decode(&manifest); err != nil {
+		return fmt.Errorf("%w: invalid results manifest: %s", ErrVerificationFailed, err)
+	}
+
+	expected := make(map[string]models.ManifestFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		if !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			return fmt.Errorf("%w: invalid path %q in results manifest", ErrVerificationFailed, file.Path)
+		}
+		expected[file.Path] = file
+	}
+
+	err = filepath.WalkDir(resultDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(resultDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == models.ResultManifestFilename {
+			return nil
+		}
+		file, ok := expected[rel]
+		if !ok {
+			return fmt.Errorf("%w: %s is not in the results manifest", ErrVerificationFailed, rel)
+		}
+		delete(expected, rel)
+		return verifyFile(path, file)
+	})
+	if err != nil {
+		return err
+	}
+	if len(expected) > 0 && !partial {
+		missing := maps.Keys(expected)
+		slices.Sort(missing)
+		return fmt.Errorf("%w: %s missing from the results", ErrVerificationFailed, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// verifySignature checks the manifest against its checksum and signature,
+// and that the signing key belongs to the node that produced the results.
+func verifySignature(manifest []byte, signature *models.ResultManifestSignature, nodeKey string) error {
+	checksum := sha256.Sum256(manifest)
+	if hex.EncodeToString(checksum[:]) != signature.SHA256 {
+		return fmt.Errorf("%w: results manifest checksum does not match", ErrVerificationFailed)
+	}
+
+	keyBytes, err := base64.StdEncoding.DecodeString(signature.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: invalid public key: %s", ErrVerificationFailed, err)
+	}
+	key, err := crypto.UnmarshalPublicKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("%w: invalid public key: %s", ErrVerificationFailed, err)
+	}
+	if err = verifyNodeKey(signature.NodeID, key, nodeKey); err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: invalid signature: %s", ErrVerificationFailed, err)
+	}
+	valid, err := key.Verify(manifest, sig)
+	if err != nil || !valid {
+		return fmt.Errorf("%w: invalid results manifest signature", ErrVerificationFailed)
+	}
+	return nil
+}
+
+// verifyNodeKey checks that key is the key pinned for the node. Without a
+// pinned key, only node IDs derived from the key identify its owner.
+func verifyNodeKey(nodeID string, key crypto.PubKey, nodeKey string) error {
+	if nodeKey == "" {
+		id, err := peer.Decode(nodeID)
+		if err != nil {
+			return fmt.Errorf("%w: public key of node %s is unknown", ErrVerificationFailed, nodeID)
+		}
+		if !id.MatchesPublicKey(key) {
+			return fmt.Errorf("%w: results manifest was not signed by node %s", ErrVerificationFailed, nodeID)
+		}
+		return nil
+	}
+
+	keyBytes, err := base64.StdEncoding.DecodeString(nodeKey)
+	if err != nil {
+		return fmt.Errorf("%w: invalid public key of node %s: %s", ErrVerificationFailed, nodeID, err)
+	}
+	pinned, err := crypto.UnmarshalPublicKey(keyBytes)
+	if err != nil {
+		return fmt.Errorf("%w: invalid public key of node %s: %s", ErrVerificationFailed, nodeID, err)
+	}
+	if !pinned.Equals(key) {
+		return fmt.Errorf("%w: results manifest was not signed by node %s", ErrVerificationFailed, nodeID)
+	}
+	return nil
+}
+
+func verifyFile(path string, expected models.ManifestFile) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(path, f)
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+	if size != expected.Size {
+		return fmt.Errorf("%w: %s has size %d, expected %d", ErrVerificationFailed, expected.Path, size, expected.Size)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != expected.SHA256 {
+		return fmt.Errorf("%w: %s checksum does not match", ErrVerificationFailed, expected.Path)
+	}
+	return nil
+}
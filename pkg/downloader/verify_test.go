@@ -0,0 +1,130 @@
+//go:build unit || !integration
+
+package downloader_test
+
+import (
+	"context"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/downloader"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type VerifySuite struct {
+	suite.Suite
+	key        crypto.PrivKey
+	nodeID     string
+	resultsDir string
+	signature  *models.ResultManifestSignature
+}
+
+func TestVerifySuite(t *testing.T) {
+	suite.Run(t, new(VerifySuite))
+}
+
+func (s *VerifySuite) SetupTest() {
+	var err error
+	s.key, _, err = crypto.GenerateEd25519Key(rand.Reader)
+	s.Require().NoError(err)
+	id, err := peer.IDFromPrivateKey(s.key)
+	s.Require().NoError(err)
+	s.nodeID = id.String()
+
+	results := &compute.ResultsPath{ResultsDir: s.T().TempDir()}
+	s.resultsDir, err = results.PrepareResultsDir("e-123")
+	s.Require().NoError(err)
+	s.write(downloader.DownloadFilenameStdout, "hello")
+	s.write("outputs/data.csv", "a,b,c")
+
+	_, err = results.FinalizeResults(context.Background(), s.resultsDir, []*models.ResultPath{{Name: "outputs", Path: "/outputs"}})
+	s.Require().NoError(err)
+	s.signature = s.sign(s.nodeID, s.key)
+}
+
+func (s *VerifySuite) write(name, content string) {
+	path := filepath.Join(s.resultsDir, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+}
+
+func (s *VerifySuite) sign(nodeID string, key crypto.PrivKey) *models.ResultManifestSignature {
+	signer, err := compute.NewManifestSigner(nodeID, key)
+	s.Require().NoError(err)
+	signature, err := signer.Sign(s.resultsDir)
+	s.Require().NoError(err)
+	return signature
+}
+
+func (s *VerifySuite) TestValid() {
+	s.NoError(downloader.VerifyResult(s.resultsDir, s.signature, ""))
+}
+
+func (s *VerifySuite) TestNodeNotDerivedFromKey() {
+	// without a pinned key, anyone could sign for a node whose ID is not derived from its key
+	s.ErrorIs(downloader.VerifyResult(s.resultsDir, s.sign("node-0", s.key), ""), downloader.ErrVerificationFailed)
+}
+
+func (s *VerifySuite) TestPinnedKey() {
+	signature := s.sign("node-0", s.key)
+	s.NoError(downloader.VerifyResult(s.resultsDir, signature, signature.PublicKey))
+
+	other, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	s.Require().NoError(err)
+	forged := s.sign("node-0", other)
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, forged, signature.PublicKey), "not signed by node")
+}
+
+func (s *VerifySuite) TestModifiedFile() {
+	s.write("outputs/data.csv", "a,b,d")
+	s.ErrorIs(downloader.VerifyResult(s.resultsDir, s.signature, ""), downloader.ErrVerificationFailed)
+}
+
+func (s *VerifySuite) TestMissingFile() {
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, "outputs", "data.csv")))
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, s.signature, ""), "outputs/data.csv missing")
+}
+
+func (s *VerifySuite) TestPartialResult() {
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, "outputs", "data.csv")))
+	s.NoError(downloader.VerifyPartialResult(s.resultsDir, s.signature, ""))
+
+	s.write("stdout", "modified")
+	s.ErrorIs(downloader.VerifyPartialResult(s.resultsDir, s.signature, ""), downloader.ErrVerificationFailed)
+}
+
+func (s *VerifySuite) TestUnexpectedFile() {
+	s.write("outputs/extra.csv", "x")
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, s.signature, ""), "not in the results manifest")
+}
+
+func (s *VerifySuite) TestMissingManifest() {
+	s.Require().NoError(os.Remove(filepath.Join(s.resultsDir, models.ResultManifestFilename)))
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, s.signature, ""), "manifest is missing")
+}
+
+func (s *VerifySuite) TestModifiedManifest() {
+	// a manifest rewritten to match modified results no longer matches its signature
+	s.write("outputs/data.csv", "a,b,d")
+	_, err := (&compute.ResultsPath{}).FinalizeResults(context.Background(), s.resultsDir, nil)
+	s.Require().NoError(err)
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, s.signature, ""), "checksum does not match")
+}
+
+func (s *VerifySuite) TestForgedSignature() {
+	other, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	s.Require().NoError(err)
+	forged := s.sign(s.nodeID, other)
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, forged, ""), "not signed by node")
+
+	// a signature by another key over the same manifest is rejected
+	forged.PublicKey = s.signature.PublicKey
+	s.ErrorContains(downloader.VerifyResult(s.resultsDir, forged, ""), "invalid results manifest signature")
+}
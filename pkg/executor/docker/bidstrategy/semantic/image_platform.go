@@ -13,7 +13,6 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/cache"
-	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/docker"
 )
 
@@ -24,7 +23,8 @@ var _ bidstrategy.SemanticBidStrategy = (*ImagePlatformBidStrategy)(nil)
 var ManifestCache cache.Cache[docker.ImageManifest]
 var mu sync.Mutex
 
-func NewImagePlatformBidStrategy(client *docker.Client) *ImagePlatformBidStrategy {
+func NewImagePlatformBidStrategy(
+	client *docker.Client, credentials *docker.CredentialsResolver) *ImagePlatformBidStrategy {
 	mu.Lock()
 	// We will create the local reference to a manifest cache on demand,
 	// ensuring that we lock access to the cache here to avoid race
@@ -34,11 +34,23 @@ func NewImagePlatformBidStrategy(client *docker.Client) *ImagePlatformBidStrateg
 	}
 	mu.Unlock()
 
-	return &ImagePlatformBidStrategy{client: client}
+	return &ImagePlatformBidStrategy{client: client, credentials: credentials}
 }
 
 type ImagePlatformBidStrategy struct {
-	client *docker.Client
+	client      *docker.Client
+	credentials *docker.CredentialsResolver
+}
+
+// manifestCacheKey returns the key the manifest of a job's image is cached
+// under. Manifests resolved with a job's registry secret are only shared with
+// jobs using the same secret in the same namespace, so that other jobs do not
+// learn of images they cannot pull.
+func manifestCacheKey(namespace string, spec dockermodels.EngineSpec) string {
+	if spec.RegistrySecret == "" {
+		return spec.Image
+	}
+	return namespace + "/" + spec.RegistrySecret + "@" + spec.Image
 }
 
 // ShouldBid implements semantic.SemanticBidStrategy
@@ -64,12 +76,21 @@ func (s *ImagePlatformBidStrategy) ShouldBid(
 		}, nil
 	}
 
-	manifest, found := ManifestCache.Get(dockerEngine.Image)
+	cacheKey := manifestCacheKey(request.Job.Namespace, dockerEngine)
+	manifest, found := ManifestCache.Get(cacheKey)
 	if !found {
 		log.Ctx(ctx).Debug().Str("Image", dockerEngine.Image).Msg("Image not found in manifest cache")
 
+		creds, err := s.credentials.Resolve(ctx, dockerEngine.Image, request.Job.Namespace, dockerEngine.RegistrySecret)
+		if err != nil {
+			return bidstrategy.BidStrategyResponse{
+				ShouldBid: false,
+				Reason:    err.Error(),
+			}, nil
+		}
+
 		var m *docker.ImageManifest
-		m, ierr = s.client.ImageDistribution(ctx, dockerEngine.Image, config.GetDockerCredentials())
+		m, ierr = s.client.ImageDistribution(ctx, dockerEngine.Image, creds)
 		if m != nil {
 			manifest = *m
 		}
@@ -80,7 +101,7 @@ func (s *ImagePlatformBidStrategy) ShouldBid(
 		// Set even when don't have to, to reset the expiry time.
 		defer func() {
 			err = ManifestCache.Set(
-				dockerEngine.Image, manifest, 1, oneDayInSeconds,
+				cacheKey, manifest, 1, oneDayInSeconds,
 			) //nolint:gomnd
 			if err != nil {
 				// Log the error but continue as it is not serious enough to stop
@@ -35,7 +35,7 @@ func TestBidsBasedOnImagePlatform(t *testing.T) {
 	client, err := docker.NewDockerClient()
 	require.NoError(t, err)
 
-	strategy := semantic.NewImagePlatformBidStrategy(client)
+	strategy := semantic.NewImagePlatformBidStrategy(client, docker.NewCredentialsResolver(docker.CredentialsResolverParams{}))
 
 	t.Run("positive response for supported architecture", func(t *testing.T) {
 		response, err := strategy.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{
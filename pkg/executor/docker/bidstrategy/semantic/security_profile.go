@@ -0,0 +1,41 @@
+package semantic
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+var _ bidstrategy.SemanticBidStrategy = (*SecurityProfileBidStrategy)(nil)
+
+// SecurityProfileBidStrategy rejects docker jobs that request a security
+// profile the node does not have, or capabilities that their profile does
+// not allow.
+type SecurityProfileBidStrategy struct {
+	policy *docker.SecurityPolicy
+}
+
+func NewSecurityProfileBidStrategy(policy *docker.SecurityPolicy) *SecurityProfileBidStrategy {
+	return &SecurityProfileBidStrategy{policy: policy}
+}
+
+// ShouldBid implements semantic.SemanticBidStrategy
+func (s *SecurityProfileBidStrategy) ShouldBid(
+	ctx context.Context,
+	request bidstrategy.BidStrategyRequest,
+) (bidstrategy.BidStrategyResponse, error) {
+	if request.Job.Task().Engine.Type != models.EngineDocker {
+		return bidstrategy.NewBidResponse(true, "examine security profiles of non-Docker jobs"), nil
+	}
+	dockerEngine, err := dockermodels.DecodeSpec(request.Job.Task().Engine)
+	if err != nil {
+		return bidstrategy.BidStrategyResponse{ShouldBid: false, Reason: err.Error()}, nil
+	}
+	if _, err = s.policy.Resolve(dockerEngine.SecurityProfile, dockerEngine.Capabilities); err != nil {
+		return bidstrategy.BidStrategyResponse{ShouldBid: false, Reason: err.Error()}, nil
+	}
+	return bidstrategy.NewBidResponse(true, "allow the security profile and capabilities of the job"), nil
+}
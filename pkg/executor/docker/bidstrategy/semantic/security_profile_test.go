@@ -0,0 +1,58 @@
+//go:build unit || !integration
+
+package semantic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/docker/bidstrategy/semantic"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+func TestBidsBasedOnSecurityProfile(t *testing.T) {
+	policy, err := docker.NewSecurityPolicy(types.DockerSecurityConfig{
+		DefaultProfile: "restricted",
+		Profiles: []types.DockerSecurityProfileConfig{
+			{Name: "restricted", DropCapabilities: []string{"ALL"}},
+			{Name: "network", AllowedCapabilities: []string{"NET_ADMIN"}},
+		},
+	})
+	require.NoError(t, err)
+	strategy := semantic.NewSecurityProfileBidStrategy(policy)
+
+	for _, tc := range []struct {
+		name         string
+		profile      string
+		capabilities []string
+		shouldBid    bool
+	}{
+		{name: "default profile", shouldBid: true},
+		{name: "requested profile", profile: "network", capabilities: []string{"NET_ADMIN"}, shouldBid: true},
+		{name: "unknown profile", profile: "privileged", shouldBid: false},
+		{name: "capability not allowed", capabilities: []string{"NET_ADMIN"}, shouldBid: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.Task().Engine = dockermodels.NewDockerEngineBuilder("ubuntu").
+				WithSecurityProfile(tc.profile).
+				WithCapabilities(tc.capabilities...).
+				Build()
+			response, err := strategy.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: *job})
+			require.NoError(t, err)
+			require.Equal(t, tc.shouldBid, response.ShouldBid, response.Reason)
+		})
+	}
+
+	t.Run("non-docker job", func(t *testing.T) {
+		response, err := strategy.ShouldBid(context.Background(), bidstrategy.BidStrategyRequest{Job: *mock.Job()})
+		require.NoError(t, err)
+		require.True(t, response.ShouldBid)
+	})
+}
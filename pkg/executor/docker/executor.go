@@ -52,6 +52,8 @@ type Executor struct {
 	activeFlags map[string]chan struct{}
 	complete    map[string]chan struct{}
 	client      *docker.Client
+	credentials *docker.CredentialsResolver
+	security    *docker.SecurityPolicy
 }
 
 func NewExecutor(
@@ -63,9 +65,21 @@ func NewExecutor(
 		return nil, err
 	}
 
+	credentials, err := docker.NewCredentialsResolverFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	security, err := docker.NewSecurityPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	de := &Executor{
 		ID:          id,
 		client:      dockerClient,
+		credentials: credentials,
+		security:    security,
 		activeFlags: make(map[string]chan struct{}),
 		complete:    make(map[string]chan struct{}),
 	}
@@ -97,7 +111,11 @@ func (e *Executor) ShouldBid(
 	ctx context.Context,
 	request bidstrategy.BidStrategyRequest,
 ) (bidstrategy.BidStrategyResponse, error) {
-	return semantic.NewImagePlatformBidStrategy(e.client).ShouldBid(ctx, request)
+	response, err := semantic.NewSecurityProfileBidStrategy(e.security).ShouldBid(ctx, request)
+	if err != nil || !response.ShouldBid {
+		return response, err
+	}
+	return semantic.NewImagePlatformBidStrategy(e.client, e.credentials).ShouldBid(ctx, request)
 }
 
 func (e *Executor) ShouldBidBasedOnUsage(
@@ -136,12 +154,15 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		jobContainer, err := e.newDockerJobContainer(ctx, &dockerJobContainerParams{
 			ExecutionID:   request.ExecutionID,
 			JobID:         request.JobID,
+			Namespace:     request.Namespace,
 			EngineSpec:    request.EngineParams,
 			NetworkConfig: request.Network,
+			Endpoints:     request.Endpoints,
 			Resources:     request.Resources,
 			Inputs:        request.Inputs,
 			Outputs:       request.Outputs,
 			ResultsDir:    request.ResultsDir,
+			ScratchVolume: request.ScratchVolume,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create docker job container: %w", err)
@@ -167,6 +188,10 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		activeCh:    make(chan bool),
 		running:     atomic.NewBool(false),
 	}
+	if request.HealthCheck != nil {
+		handler.health = newHealthMonitor(request.HealthCheck,
+			healthCheck(e.client, containerID, request.Network, request.HealthCheck))
+	}
 
 	// register the handler for this executionID
 	e.handlers.Put(request.ExecutionID, handler)
@@ -175,6 +200,19 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	return nil
 }
 
+// WatchHealth returns the changes in the health of an execution, as reported
+// by the health check of its task. The channel is closed once the container exits.
+func (e *Executor) WatchHealth(ctx context.Context, executionID string) (<-chan models.ExecutionHealth, error) {
+	handler, found := e.handlers.Get(executionID)
+	if !found {
+		return nil, fmt.Errorf("watching health of execution (%s): %w", executionID, executor.ErrNotFound)
+	}
+	if handler.health == nil {
+		return nil, fmt.Errorf("execution (%s) has no health check", executionID)
+	}
+	return handler.health.updates, nil
+}
+
 // Wait initiates a wait for the completion of a specific execution using its
 // executionID. The function returns two channels: one for the result and another
 // for any potential error. If the executionID is not found, an error is immediately
@@ -305,12 +343,15 @@ func (e *Executor) Run(
 type dockerJobContainerParams struct {
 	ExecutionID   string
 	JobID         string
+	Namespace     string
 	EngineSpec    *models.SpecConfig
 	NetworkConfig *models.NetworkConfig
+	Endpoints     []*models.ExecutionEndpoint
 	Resources     *models.Resources
 	Inputs        []storage.PreparedStorage
 	Outputs       []*models.ResultPath
 	ResultsDir    string
+	ScratchVolume *storage.StorageVolume
 }
 
 // newDockerJobContainer is an internal method called by Start to set up a new Docker container
@@ -334,7 +375,13 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 		WorkingDir: dockerArgs.WorkingDirectory,
 	}
 
-	mounts, err := makeContainerMounts(ctx, params.Inputs, params.Outputs, params.ResultsDir)
+	// jobs are only bid on if their security profile is allowed, but check it again before creating the container
+	securityProfile, err := e.security.Resolve(dockerArgs.SecurityProfile, dockerArgs.Capabilities)
+	if err != nil {
+		return container.CreateResponse{}, fmt.Errorf("resolving security profile: %w", err)
+	}
+
+	mounts, err := makeContainerMounts(ctx, params.Inputs, params.Outputs, params.ResultsDir, params.ScratchVolume)
 	if err != nil {
 		return container.CreateResponse{}, fmt.Errorf("creating container mounts: %w", err)
 	}
@@ -356,9 +403,13 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 			Devices:        deviceMappings,
 		},
 	}
+	securityProfile.Apply(hostConfig)
 
 	if _, set := os.LookupEnv("SKIP_IMAGE_PULL"); !set {
-		dockerCreds := config.GetDockerCredentials()
+		dockerCreds, credsErr := e.credentials.Resolve(ctx, dockerArgs.Image, params.Namespace, dockerArgs.RegistrySecret)
+		if credsErr != nil {
+			return container.CreateResponse{}, fmt.Errorf("failed to pull docker image: %w", credsErr)
+		}
 		if pullErr := e.client.PullImage(ctx, dockerArgs.Image, dockerCreds); pullErr != nil {
 			pullErr = errors.Wrapf(pullErr, docker.ImagePullError, dockerArgs.Image)
 			return container.CreateResponse{}, fmt.Errorf("failed to pull docker image: %w", pullErr)
@@ -366,7 +417,8 @@ func (e *Executor) newDockerJobContainer(ctx context.Context, params *dockerJobC
 	}
 	log.Ctx(ctx).Trace().Msgf("Container: %+v %+v", containerConfig, mounts)
 	// Create a network if the job requests it, modifying the containerConfig and hostConfig.
-	err = e.setupNetworkForJob(ctx, params.JobID, params.ExecutionID, params.NetworkConfig, containerConfig, hostConfig)
+	err = e.setupNetworkForJob(
+		ctx, params.JobID, params.ExecutionID, params.NetworkConfig, params.Endpoints, containerConfig, hostConfig)
 	if err != nil {
 		return container.CreateResponse{}, fmt.Errorf("setting up network: %w", err)
 	}
@@ -436,7 +488,12 @@ func configureDevices(ctx context.Context, resources *models.Resources) ([]conta
 }
 
 func makeContainerMounts(
-	ctx context.Context, inputs []storage.PreparedStorage, outputs []*models.ResultPath, resultsDir string) ([]mount.Mount, error) {
+	ctx context.Context,
+	inputs []storage.PreparedStorage,
+	outputs []*models.ResultPath,
+	resultsDir string,
+	scratch *storage.StorageVolume,
+) ([]mount.Mount, error) {
 	// the actual mounts we will give to the container
 	// these are paths for both input and output data
 	var mounts []mount.Mount
@@ -468,8 +525,14 @@ func makeContainerMounts(
 			return nil, fmt.Errorf("output volume has no Location: %+v", output)
 		}
 
+		if !output.HasLocalName() {
+			return nil, fmt.Errorf("output volume name is not a relative path: %+v", output)
+		}
+
 		srcd := filepath.Join(resultsDir, output.Name)
-		if err := os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W); err != nil {
+		// only the checkpoint path already exists, as the checkpoint is restored to it
+		err := os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W)
+		if err != nil && !(os.IsExist(err) && output.Name == models.CheckpointResultPathName) {
 			return nil, fmt.Errorf("failed to create results dir for execution: %w", err)
 		}
 
@@ -486,6 +549,15 @@ func makeContainerMounts(
 			Target: output.Path,
 		})
 	}
+
+	if scratch != nil {
+		log.Ctx(ctx).Trace().Msgf("Scratch Volume: %+v", scratch)
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: scratch.Source,
+			Target: scratch.Target,
+		})
+	}
 	return mounts, nil
 }
 
@@ -517,6 +589,7 @@ func labelExecutionValue(executorID string, executionID string) string {
 
 // Compile-time interface check:
 var _ executor.Executor = (*Executor)(nil)
+var _ executor.HealthReporter = (*Executor)(nil)
 
 // FindRunningContainer, not part of the Executor interface, is a utility function that
 // helps locate a container durin a restart check.
@@ -36,6 +36,8 @@ type executionHandler struct {
 	resultsDir  string
 	limits      executor.OutputLimits
 	keepStack   bool
+	// health runs the task's health check, if it has one
+	health *healthMonitor
 
 	//
 	// synchronization
@@ -64,6 +66,14 @@ func (h *executionHandler) run(ctx context.Context) {
 		close(h.waitCh)
 		ActiveExecutions.Dec(ctx, attribute.String("executor_id", h.ID))
 	}()
+	// health checks run from when the container starts until it exits
+	stopHealthChecks := func() {}
+	if h.health != nil {
+		var healthCtx context.Context
+		healthCtx, stopHealthChecks = context.WithCancel(ctx)
+		defer stopHealthChecks()
+		go h.health.run(healthCtx, h.activeCh)
+	}
 	// start the container
 	h.logger.Info().Msg("starting container execution")
 	if err := h.client.ContainerStart(ctx, h.containerID, dockertypes.ContainerStartOptions{}); err != nil {
@@ -134,6 +144,7 @@ func (h *executionHandler) run(ctx context.Context) {
 				Msg("received status from container")
 		}
 	}
+	stopHealthChecks()
 
 	stdoutPipe, stderrPipe, err := h.client.FollowLogs(ctx, h.containerID)
 	if err != nil {
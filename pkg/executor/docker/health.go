@@ -0,0 +1,231 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// execPollInterval is how often the status of a health check command is polled.
+const execPollInterval = 100 * time.Millisecond
+
+// healthCheckClient sends the HTTP health checks of executions. Redirects are
+// not followed, as they could point the compute node at any other address,
+// and the node's proxy is not used to reach containers.
+var healthCheckClient = &http.Client{
+	Transport: &http.Transport{DisableKeepAlives: true},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// healthMonitor runs the health checks of an execution, and reports changes
+// in its health.
+type healthMonitor struct {
+	config  *models.HealthCheckConfig
+	check   func(ctx context.Context) error
+	updates chan models.ExecutionHealth
+	started time.Time
+	health  models.ExecutionHealth
+}
+
+func newHealthMonitor(config *models.HealthCheckConfig, check func(ctx context.Context) error) *healthMonitor {
+	return &healthMonitor{
+		config: config,
+		check:  check,
+		// only the latest change is kept for the caller
+		updates: make(chan models.ExecutionHealth, 1),
+	}
+}
+
+// run checks the health of the execution from when it becomes active until
+// ctx is done, and then closes the updates channel.
+func (m *healthMonitor) run(ctx context.Context, active <-chan bool) {
+	defer close(m.updates)
+	select {
+	case <-ctx.Done():
+		return
+	case <-active:
+	}
+	m.started = time.Now()
+	ticker := time.NewTicker(m.config.GetInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, m.config.GetTimeout())
+			err := m.check(checkCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			if m.record(err, time.Now()) {
+				m.publish(m.health)
+			}
+		}
+	}
+}
+
+// record updates the health of the execution with the result of a check, and
+// returns true if its status changed. Failed checks are not counted during
+// the start period, unless the execution was already healthy.
+func (m *healthMonitor) record(err error, now time.Time) bool {
+	previous := m.health.Status
+	if err == nil {
+		m.health.Status = models.ExecutionHealthHealthy
+		m.health.Message = ""
+		m.health.ConsecutiveFailures = 0
+	} else {
+		if previous != models.ExecutionHealthHealthy && now.Sub(m.started) < m.config.GetStartPeriod() {
+			return false
+		}
+		m.health.ConsecutiveFailures++
+		m.health.Message = err.Error()
+		if m.health.ConsecutiveFailures >= m.config.GetFailureThreshold() {
+			m.health.Status = models.ExecutionHealthUnhealthy
+		}
+	}
+	if m.health.Status == previous {
+		return false
+	}
+	m.health.UpdateTime = now.UTC().UnixNano()
+	return true
+}
+
+// publish sends a change to the caller, replacing any change it has not
+// received yet.
+func (m *healthMonitor) publish(health models.ExecutionHealth) {
+	for {
+		select {
+		case m.updates <- health:
+			return
+		default:
+			select {
+			case <-m.updates:
+			default:
+			}
+		}
+	}
+}
+
+// healthCheck returns the check to run for the health check config of a container.
+func healthCheck(
+	client *docker.Client, containerID string, network *models.NetworkConfig, config *models.HealthCheckConfig,
+) func(ctx context.Context) error {
+	switch config.Type {
+	case models.HealthCheckHTTP:
+		return func(ctx context.Context) error {
+			host, err := containerHost(ctx, client, containerID, network)
+			if err != nil {
+				return err
+			}
+			return checkHTTP(ctx, net.JoinHostPort(host, strconv.Itoa(config.Port)), config.Path)
+		}
+	case models.HealthCheckTCP:
+		return func(ctx context.Context) error {
+			host, err := containerHost(ctx, client, containerID, network)
+			if err != nil {
+				return err
+			}
+			return checkTCP(ctx, net.JoinHostPort(host, strconv.Itoa(config.Port)))
+		}
+	default:
+		return func(ctx context.Context) error {
+			return checkExec(ctx, client, containerID, config.Command)
+		}
+	}
+}
+
+func checkHTTP(ctx context.Context, address, path string) error {
+	if path == "" {
+		path = "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := healthCheckClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// a redirect is the result of the check, like any other status
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("health check request returned status %s", resp.Status)
+	}
+	return nil
+}
+
+func checkTCP(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("health check connection failed: %w", err)
+	}
+	return conn.Close()
+}
+
+func checkExec(ctx context.Context, client *docker.Client, containerID string, command []string) error {
+	exec, err := client.ContainerExecCreate(ctx, containerID, dockertypes.ExecConfig{Cmd: command})
+	if err != nil {
+		return fmt.Errorf("failed to create health check command: %w", err)
+	}
+	if err = client.ContainerExecStart(ctx, exec.ID, dockertypes.ExecStartCheck{Detach: true}); err != nil {
+		return fmt.Errorf("failed to start health check command: %w", err)
+	}
+	ticker := time.NewTicker(execPollInterval)
+	defer ticker.Stop()
+	for {
+		inspect, err := client.ContainerExecInspect(ctx, exec.ID)
+		if err != nil {
+			return fmt.Errorf("failed to inspect health check command: %w", err)
+		}
+		if !inspect.Running {
+			if inspect.ExitCode != 0 {
+				return fmt.Errorf("health check command exited with status %d", inspect.ExitCode)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("health check command timed out: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// containerHost returns the address the compute node can reach a container on.
+func containerHost(
+	ctx context.Context, client *docker.Client, containerID string, network *models.NetworkConfig) (string, error) {
+	// containers with full networking use the host's network, unless they publish ports
+	if network != nil && network.Type == models.NetworkFull && len(network.Ports) == 0 {
+		return "127.0.0.1", nil
+	}
+	container, err := client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if container.NetworkSettings != nil {
+		if container.NetworkSettings.IPAddress != "" {
+			return container.NetworkSettings.IPAddress, nil
+		}
+		for _, endpoint := range container.NetworkSettings.Networks {
+			if endpoint != nil && endpoint.IPAddress != "" {
+				return endpoint.IPAddress, nil
+			}
+		}
+	}
+	log.Ctx(ctx).Debug().Str("container", containerID).Msg("container has no network address for health checks")
+	return "", fmt.Errorf("container has no network address")
+}
@@ -0,0 +1,92 @@
+//go:build unit || !integration
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type HealthMonitorSuite struct {
+	suite.Suite
+	monitor *healthMonitor
+	start   time.Time
+}
+
+func TestHealthMonitorSuite(t *testing.T) {
+	suite.Run(t, new(HealthMonitorSuite))
+}
+
+func (s *HealthMonitorSuite) SetupTest() {
+	s.start = time.Now()
+	s.monitor = newHealthMonitor(&models.HealthCheckConfig{
+		Type:             models.HealthCheckExec,
+		Command:          []string{"true"},
+		StartPeriod:      60,
+		FailureThreshold: 2,
+	}, nil)
+	s.monitor.started = s.start
+}
+
+func (s *HealthMonitorSuite) TestFailuresDuringStartPeriodAreIgnored() {
+	s.Require().False(s.monitor.record(errors.New("connection refused"), s.start.Add(time.Second)))
+	s.Require().False(s.monitor.record(errors.New("connection refused"), s.start.Add(2*time.Second)))
+	s.Require().Equal(models.ExecutionHealthUnknown, s.monitor.health.Status)
+	s.Require().Zero(s.monitor.health.ConsecutiveFailures)
+}
+
+func (s *HealthMonitorSuite) TestBecomesUnhealthyAfterFailureThreshold() {
+	s.Require().True(s.monitor.record(nil, s.start.Add(time.Second)))
+	s.Require().Equal(models.ExecutionHealthHealthy, s.monitor.health.Status)
+
+	// the start period no longer applies once the execution is healthy
+	s.Require().False(s.monitor.record(errors.New("connection refused"), s.start.Add(2*time.Second)))
+	s.Require().Equal(models.ExecutionHealthHealthy, s.monitor.health.Status)
+	s.Require().Equal(1, s.monitor.health.ConsecutiveFailures)
+
+	s.Require().True(s.monitor.record(errors.New("connection refused"), s.start.Add(3*time.Second)))
+	s.Require().Equal(models.ExecutionHealthUnhealthy, s.monitor.health.Status)
+	s.Require().Equal("connection refused", s.monitor.health.Message)
+
+	s.Require().True(s.monitor.record(nil, s.start.Add(4*time.Second)))
+	s.Require().Equal(models.ExecutionHealthHealthy, s.monitor.health.Status)
+	s.Require().Zero(s.monitor.health.ConsecutiveFailures)
+}
+
+func (s *HealthMonitorSuite) TestPublishKeepsLatestChange() {
+	s.monitor.publish(models.ExecutionHealth{Status: models.ExecutionHealthHealthy})
+	s.monitor.publish(models.ExecutionHealth{Status: models.ExecutionHealthUnhealthy})
+	s.Require().Equal(models.ExecutionHealthUnhealthy, (<-s.monitor.updates).Status)
+	s.Require().Empty(s.monitor.updates)
+}
+
+func (s *HealthMonitorSuite) TestHTTPCheckDoesNotFollowRedirects() {
+	var followed bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	s.T().Cleanup(target.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, target.URL, http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	s.T().Cleanup(server.Close)
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	s.Require().NoError(checkHTTP(context.Background(), address, "/redirect"))
+	s.Require().False(followed)
+	s.Require().ErrorContains(checkHTTP(context.Background(), address, "/unavailable"), "503")
+}
@@ -0,0 +1,345 @@
+// Package imagecache manages the docker images held by a compute node, so
+// that jobs can start without waiting for their image to be pulled.
+package imagecache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/rs/zerolog/log"
+	"go.uber.org/multierr"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+const (
+	// requestQueueSize is how many requested images can wait to be pulled.
+	// Requests beyond it are dropped, and the image is pulled by the
+	// execution that needs it instead.
+	requestQueueSize = 32
+	stateFilePerm    = 0o600
+)
+
+// Client is the subset of the docker client used to manage images.
+type Client interface {
+	PullImage(ctx context.Context, image string, dockerCreds config.DockerCredentials) error
+	ImageInspectWithRaw(ctx context.Context, image string) (types.ImageInspect, []byte, error)
+	ImageRemove(ctx context.Context, image string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+}
+
+// Credentials resolves the credentials to pull an image.
+type Credentials interface {
+	Resolve(ctx context.Context, image, namespace, secret string) (config.DockerCredentials, error)
+}
+
+type Params struct {
+	Client Client
+	// Credentials resolves the node's credentials for private registries.
+	// DOCKER_USERNAME and DOCKER_PASSWORD are used if it is nil.
+	Credentials Credentials
+	// DiskBudget is the disk space, in bytes, the images may use before the
+	// least recently used ones are pruned. Images are never pruned
+	// automatically if it is zero.
+	DiskBudget uint64
+	// PruneFrequency is how often the disk budget is enforced.
+	PruneFrequency time.Duration
+	// AutoPull pulls the images of jobs the node is asked to bid on.
+	AutoPull bool
+	// Images are pulled when the cache starts, and are never pruned.
+	Images []string
+	// Advertised is how many of the most recently used images are advertised
+	// to the orchestrator.
+	Advertised int
+	// StatePath is the file the cache is persisted to across restarts. The
+	// cache is only kept in memory if it is empty.
+	StatePath string
+}
+
+// Cache keeps track of the images pulled for jobs, and prunes the least
+// recently used ones once they exceed a disk budget. Only images recorded by
+// the cache are ever removed.
+type Cache struct {
+	client         Client
+	credentials    Credentials
+	diskBudget     uint64
+	pruneFrequency time.Duration
+	autoPull       bool
+	pinned         []string
+	advertised     int
+	statePath      string
+
+	mu       sync.Mutex
+	images   map[string]*models.CachedImage
+	requests chan string
+	pulling  map[string]bool
+}
+
+func New(params Params) (*Cache, error) {
+	c := &Cache{
+		client:         params.Client,
+		credentials:    params.Credentials,
+		diskBudget:     params.DiskBudget,
+		pruneFrequency: params.PruneFrequency,
+		autoPull:       params.AutoPull,
+		advertised:     params.Advertised,
+		statePath:      params.StatePath,
+		images:         make(map[string]*models.CachedImage),
+		requests:       make(chan string, requestQueueSize),
+		pulling:        make(map[string]bool),
+	}
+	for _, image := range params.Images {
+		c.pinned = append(c.pinned, dockermodels.NormalizeImage(image))
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DiskBudget returns the disk space the images may use, or zero if it is not limited.
+func (c *Cache) DiskBudget() uint64 {
+	return c.diskBudget
+}
+
+// Start pulls the pinned images, and then pulls requested images and
+// enforces the disk budget in the background until ctx is done.
+func (c *Cache) Start(ctx context.Context) {
+	go c.run(ctx)
+}
+
+func (c *Cache) run(ctx context.Context) {
+	for _, image := range c.pinned {
+		if _, err := c.Pull(ctx, image, true); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("image", image).Msg("failed to pull pinned image")
+		}
+	}
+
+	var prune <-chan time.Time
+	if c.diskBudget > 0 && c.pruneFrequency > 0 {
+		ticker := time.NewTicker(c.pruneFrequency)
+		defer ticker.Stop()
+		prune = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case image := <-c.requests:
+			if _, err := c.Pull(ctx, image, false); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("image", image).Msg("failed to pull requested image")
+			}
+			c.mu.Lock()
+			delete(c.pulling, image)
+			c.mu.Unlock()
+		case <-prune:
+			if _, err := c.Prune(ctx, c.diskBudget); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("failed to prune image cache")
+			}
+		}
+	}
+}
+
+// Pull pulls an image unless it is already present, and records it in the
+// cache. Pinned images are never pruned.
+func (c *Cache) Pull(ctx context.Context, image string, pin bool) (models.CachedImage, error) {
+	image = dockermodels.NormalizeImage(image)
+	creds := config.GetDockerCredentials()
+	if c.credentials != nil {
+		var err error
+		if creds, err = c.credentials.Resolve(ctx, image, "", ""); err != nil {
+			return models.CachedImage{}, fmt.Errorf("failed to pull image %s: %w", image, err)
+		}
+	}
+	if err := c.client.PullImage(ctx, image, creds); err != nil {
+		return models.CachedImage{}, fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+	info, _, err := c.client.ImageInspectWithRaw(ctx, image)
+	if err != nil {
+		return models.CachedImage{}, fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+
+	now := time.Now().UTC()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.images[image]
+	if !ok {
+		cached = &models.CachedImage{Image: image, PulledAt: now}
+		c.images[image] = cached
+	}
+	cached.ID = info.ID
+	cached.Size = info.Size
+	cached.Pinned = cached.Pinned || pin || c.isPinned(image)
+	cached.LastUsed = now
+	c.save(ctx)
+	return *cached, nil
+}
+
+// OnAskForBid records that a job asked for its docker image, and pulls the
+// image in the background if it is not cached yet and AutoPull is enabled.
+func (c *Cache) OnAskForBid(ctx context.Context, job models.Job) {
+	task := job.Task()
+	if task == nil || task.Engine == nil || !task.Engine.IsType(models.EngineDocker) {
+		return
+	}
+	spec, err := dockermodels.DecodeSpec(task.Engine)
+	if err != nil {
+		return
+	}
+	image := dockermodels.NormalizeImage(spec.Image)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.images[image]; ok {
+		cached.LastUsed = time.Now().UTC()
+		c.save(ctx)
+		return
+	}
+	// Images needing the job's registry secret are only pulled by the job's
+	// execution, as the cache only has the node's credentials.
+	if !c.autoPull || c.pulling[image] || spec.RegistrySecret != "" {
+		return
+	}
+	select {
+	case c.requests <- image:
+		c.pulling[image] = true
+	default:
+		log.Ctx(ctx).Debug().Str("image", image).Msg("image pull queue is full, not pulling requested image")
+	}
+}
+
+// List returns the cached images, most recently used first.
+func (c *Cache) List() []models.CachedImage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	images := make([]models.CachedImage, 0, len(c.images))
+	for _, cached := range c.images {
+		images = append(images, *cached)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].LastUsed.Equal(images[j].LastUsed) {
+			return images[i].Image < images[j].Image
+		}
+		return images[i].LastUsed.After(images[j].LastUsed)
+	})
+	return images
+}
+
+// Advertised returns the most recently used images to advertise to the orchestrator.
+func (c *Cache) Advertised() []string {
+	images := c.List()
+	if len(images) > c.advertised {
+		images = images[:c.advertised]
+	}
+	names := make([]string, len(images))
+	for i, cached := range images {
+		names[i] = cached.Image
+	}
+	return names
+}
+
+// Prune removes the least recently used images that are not pinned until the
+// cached images use no more than budget bytes, and returns the removed
+// images. Images that are in use by a container cannot be removed and are
+// skipped.
+func (c *Cache) Prune(ctx context.Context, budget uint64) ([]models.CachedImage, error) {
+	images := c.List()
+	var total uint64
+	for _, cached := range images {
+		total += uint64(cached.Size)
+	}
+
+	var removed []models.CachedImage
+	var errs error
+	// images are listed most recently used first, so prune from the end
+	for i := len(images) - 1; i >= 0 && total > budget; i-- {
+		cached := images[i]
+		if cached.Pinned {
+			continue
+		}
+		_, err := c.client.ImageRemove(ctx, cached.Image, types.ImageRemoveOptions{PruneChildren: true})
+		if err != nil && !dockerclient.IsErrNotFound(err) {
+			log.Ctx(ctx).Debug().Err(err).Str("image", cached.Image).Msg("could not remove cached image")
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		total -= uint64(cached.Size)
+		removed = append(removed, cached)
+
+		c.mu.Lock()
+		delete(c.images, cached.Image)
+		c.mu.Unlock()
+	}
+
+	if len(removed) > 0 {
+		c.mu.Lock()
+		c.save(ctx)
+		c.mu.Unlock()
+	}
+	if total > budget && errs != nil {
+		return removed, fmt.Errorf("cached images still use %d bytes, which exceeds the budget of %d bytes: %w",
+			total, budget, errs)
+	}
+	return removed, nil
+}
+
+func (c *Cache) isPinned(image string) bool {
+	for _, pinned := range c.pinned {
+		if pinned == image {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cache) load() error {
+	if c.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read image cache state: %w", err)
+	}
+	var images []*models.CachedImage
+	if err := json.Unmarshal(data, &images); err != nil {
+		return fmt.Errorf("failed to decode image cache state %s: %w", c.statePath, err)
+	}
+	for _, cached := range images {
+		c.images[cached.Image] = cached
+	}
+	return nil
+}
+
+// save persists the cache. It must be called with the lock held. Failures
+// are only logged, as the cache is still usable in memory.
+func (c *Cache) save(ctx context.Context) {
+	if c.statePath == "" {
+		return
+	}
+	images := make([]*models.CachedImage, 0, len(c.images))
+	for _, cached := range c.images {
+		images = append(images, cached)
+	}
+	data, err := json.Marshal(images)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(c.statePath), os.ModePerm)
+	}
+	if err == nil {
+		err = os.WriteFile(c.statePath, data, stateFilePerm)
+	}
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to save image cache state")
+	}
+}
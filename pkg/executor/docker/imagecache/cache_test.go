@@ -0,0 +1,192 @@
+//go:build unit || !integration
+
+package imagecache
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+const megabyte = 1 << 20
+
+// fakeClient is a docker daemon holding images of a fixed size.
+type fakeClient struct {
+	images map[string]bool
+	// inUse images cannot be removed
+	inUse map[string]bool
+	pulls []string
+}
+
+func (f *fakeClient) PullImage(_ context.Context, image string, _ config.DockerCredentials) error {
+	f.pulls = append(f.pulls, image)
+	f.images[image] = true
+	return nil
+}
+
+func (f *fakeClient) ImageInspectWithRaw(_ context.Context, image string) (types.ImageInspect, []byte, error) {
+	return types.ImageInspect{ID: "sha256:" + image, Size: 100 * megabyte}, nil, nil
+}
+
+func (f *fakeClient) ImageRemove(_ context.Context, image string, _ types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error) {
+	if f.inUse[image] {
+		return nil, errors.New("image is being used by a running container")
+	}
+	delete(f.images, image)
+	return nil, nil
+}
+
+type CacheSuite struct {
+	suite.Suite
+	ctx    context.Context
+	client *fakeClient
+	params Params
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+func (s *CacheSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.client = &fakeClient{images: map[string]bool{}, inUse: map[string]bool{}}
+	s.params = Params{
+		Client:     s.client,
+		Advertised: 2,
+		StatePath:  filepath.Join(s.T().TempDir(), "image_cache.json"),
+	}
+}
+
+func (s *CacheSuite) newCache() *Cache {
+	cache, err := New(s.params)
+	s.Require().NoError(err)
+	return cache
+}
+
+func (s *CacheSuite) pull(cache *Cache, images ...string) {
+	for _, image := range images {
+		_, err := cache.Pull(s.ctx, image, false)
+		s.Require().NoError(err)
+		// make sure images are ordered by when they were used
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (s *CacheSuite) images(cache *Cache) []string {
+	var images []string
+	for _, cached := range cache.List() {
+		images = append(images, cached.Image)
+	}
+	return images
+}
+
+func (s *CacheSuite) TestNormalizeImage() {
+	s.Equal("docker.io/library/ubuntu:latest", dockermodels.NormalizeImage("ubuntu"))
+	s.Equal("docker.io/library/ubuntu:22.04", dockermodels.NormalizeImage("docker.io/ubuntu:22.04"))
+	s.Equal("ghcr.io/org/image:latest", dockermodels.NormalizeImage("ghcr.io/org/image"))
+	s.Equal("not a reference", dockermodels.NormalizeImage("not a reference"))
+}
+
+func (s *CacheSuite) TestPullAndList() {
+	cache := s.newCache()
+	s.pull(cache, "ubuntu", "alpine:3.19", "busybox")
+
+	cached, err := cache.Pull(s.ctx, "ubuntu:latest", true)
+	s.Require().NoError(err)
+	s.True(cached.Pinned)
+	s.Equal("sha256:docker.io/library/ubuntu:latest", cached.ID)
+	s.EqualValues(100*megabyte, cached.Size)
+
+	s.Equal([]string{
+		"docker.io/library/ubuntu:latest",
+		"docker.io/library/busybox:latest",
+		"docker.io/library/alpine:3.19",
+	}, s.images(cache))
+	s.Equal([]string{"docker.io/library/ubuntu:latest", "docker.io/library/busybox:latest"}, cache.Advertised())
+
+	// the cache is restored from its state file
+	s.Equal(s.images(cache), s.images(s.newCache()))
+}
+
+func (s *CacheSuite) TestPrune() {
+	cache := s.newCache()
+	s.pull(cache, "a", "b", "c", "d")
+	_, err := cache.Pull(s.ctx, "a", true)
+	s.Require().NoError(err)
+	s.client.inUse["docker.io/library/b:latest"] = true
+
+	// a is pinned and b is in use, so c and d are removed
+	removed, err := cache.Prune(s.ctx, 150*megabyte)
+	s.ErrorContains(err, "exceeds the budget")
+	s.Len(removed, 2)
+	s.Equal([]string{"docker.io/library/a:latest", "docker.io/library/b:latest"}, s.images(cache))
+	s.False(s.client.images["docker.io/library/c:latest"])
+	s.True(s.client.images["docker.io/library/a:latest"])
+
+	delete(s.client.inUse, "docker.io/library/b:latest")
+	removed, err = cache.Prune(s.ctx, 150*megabyte)
+	s.Require().NoError(err)
+	s.Require().Len(removed, 1)
+	s.Equal("docker.io/library/b:latest", removed[0].Image)
+
+	// within budget, nothing is removed
+	removed, err = cache.Prune(s.ctx, 150*megabyte)
+	s.Require().NoError(err)
+	s.Empty(removed)
+}
+
+func (s *CacheSuite) TestOnAskForBid() {
+	s.params.AutoPull = true
+	cache := s.newCache()
+	s.pull(cache, "alpine", "ubuntu")
+
+	job := mock.Job()
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder("alpine").Build()
+	cache.OnAskForBid(s.ctx, *job)
+	s.Equal("docker.io/library/alpine:latest", cache.List()[0].Image)
+
+	// uncached images are pulled in the background, once
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder("busybox").Build()
+	cache.OnAskForBid(s.ctx, *job)
+	cache.OnAskForBid(s.ctx, *job)
+	s.Equal([]string{"docker.io/library/busybox:latest"}, drain(cache.requests))
+
+	// images needing the job's registry secret are left to the execution
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder("ghcr.io/org/private").WithRegistrySecret("ghcr").Build()
+	cache.OnAskForBid(s.ctx, *job)
+	s.Empty(drain(cache.requests))
+
+	// non docker jobs are ignored
+	cache.OnAskForBid(s.ctx, *mock.Job())
+	s.Empty(drain(cache.requests))
+}
+
+func (s *CacheSuite) TestOnAskForBidWithoutAutoPull() {
+	cache := s.newCache()
+	job := mock.Job()
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder("busybox").Build()
+	cache.OnAskForBid(s.ctx, *job)
+	s.Empty(drain(cache.requests))
+	s.Empty(cache.List())
+}
+
+func drain(requests chan string) []string {
+	var images []string
+	for {
+		select {
+		case image := <-requests:
+			images = append(images, image)
+		default:
+			return images
+		}
+	}
+}
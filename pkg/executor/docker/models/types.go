@@ -7,6 +7,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/distribution/reference"
 	"github.com/fatih/structs"
 )
 
@@ -16,6 +17,9 @@ const (
 	EngineKeyParametersDocker           = "Parameters"
 	EngineKeyEnvironmentVariablesDocker = "EnvironmentVariables"
 	EngineKeyWorkingDirectoryDocker     = "WorkingDirectory"
+	EngineKeyRegistrySecretDocker       = "RegistrySecret"
+	EngineKeySecurityProfileDocker      = "SecurityProfile"
+	EngineKeyCapabilitiesDocker         = "Capabilities"
 )
 
 // EngineSpec contains necessary parameters to execute a docker job.
@@ -30,6 +34,15 @@ type EngineSpec struct {
 	EnvironmentVariables []string `json:"EnvironmentVariables,omitempty"`
 	// WorkingDirectory inside the container
 	WorkingDirectory string `json:"WorkingDirectory,omitempty"`
+	// RegistrySecret names the secret holding the credentials to pull Image.
+	// It is resolved in the job's namespace by the compute node.
+	RegistrySecret string `json:"RegistrySecret,omitempty"`
+	// SecurityProfile names the security profile of the compute node the
+	// container runs with. The node's default profile is used if empty.
+	SecurityProfile string `json:"SecurityProfile,omitempty"`
+	// Capabilities are Linux capabilities added to the container, which the
+	// security profile must allow.
+	Capabilities []string `json:"Capabilities,omitempty"`
 }
 
 func (c EngineSpec) Validate() error {
@@ -66,6 +79,17 @@ func DecodeSpec(spec *models.SpecConfig) (EngineSpec, error) {
 	return *c, c.Validate()
 }
 
+// NormalizeImage returns the fully qualified reference of an image, so that
+// e.g. ubuntu and docker.io/library/ubuntu:latest compare equal. Images that
+// cannot be parsed are returned unchanged.
+func NormalizeImage(image string) string {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return image
+	}
+	return reference.TagNameOnly(named).String()
+}
+
 // DockerEngineBuilder is a struct that is used for constructing an EngineSpec object
 // specifically for Docker engines using the Builder pattern.
 // It embeds an EngineBuilder object for handling the common builder methods.
@@ -109,6 +133,27 @@ func (b *DockerEngineBuilder) WithParameters(e ...string) *DockerEngineBuilder {
 	return b
 }
 
+// WithRegistrySecret is a builder method that sets the secret holding the credentials to pull the image.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithRegistrySecret(e string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeyRegistrySecretDocker, e)
+	return b
+}
+
+// WithSecurityProfile is a builder method that sets the security profile the container runs with.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithSecurityProfile(e string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeySecurityProfileDocker, e)
+	return b
+}
+
+// WithCapabilities is a builder method that sets the Linux capabilities added to the container.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithCapabilities(e ...string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeyCapabilitiesDocker, e)
+	return b
+}
+
 // Build method constructs the final SpecConfig object by calling the embedded EngineBuilder's Build method.
 func (b *DockerEngineBuilder) Build() *models.SpecConfig {
 	return b.eb
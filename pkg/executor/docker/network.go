@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"go.uber.org/multierr"
@@ -66,6 +68,7 @@ func (e *Executor) setupNetworkForJob(
 	job string,
 	executionID string,
 	network *models.NetworkConfig,
+	endpoints []*models.ExecutionEndpoint,
 	containerConfig *container.Config,
 	hostConfig *container.HostConfig,
 ) (err error) {
@@ -74,7 +77,13 @@ func (e *Executor) setupNetworkForJob(
 	case models.NetworkNone:
 		hostConfig.NetworkMode = dockerNetworkNone
 	case models.NetworkFull:
-		hostConfig.NetworkMode = dockerNetworkHost
+		if len(endpoints) > 0 {
+			// ports cannot be published with host networking
+			hostConfig.NetworkMode = dockerNetworkBridge
+			containerConfig.ExposedPorts, hostConfig.PortBindings = portBindings(endpoints)
+		} else {
+			hostConfig.NetworkMode = dockerNetworkHost
+		}
 		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, dockerHostAddCommand)
 	case models.NetworkHTTP:
 		var internalNetwork *types.NetworkResource
@@ -94,6 +103,19 @@ func (e *Executor) setupNetworkForJob(
 	return
 }
 
+// portBindings returns the container ports to expose and the host ports to
+// publish them on.
+func portBindings(endpoints []*models.ExecutionEndpoint) (nat.PortSet, nat.PortMap) {
+	exposed := make(nat.PortSet, len(endpoints))
+	bindings := make(nat.PortMap, len(endpoints))
+	for _, endpoint := range endpoints {
+		port := nat.Port(fmt.Sprintf("%d/%s", endpoint.Target, endpoint.Protocol))
+		exposed[port] = struct{}{}
+		bindings[port] = append(bindings[port], nat.PortBinding{HostPort: strconv.Itoa(endpoint.Port)})
+	}
+	return exposed, bindings
+}
+
 //nolint:funlen,gocyclo
 func (e *Executor) createHTTPGateway(
 	ctx context.Context,
@@ -14,6 +14,11 @@ import (
 // ExecutorProvider returns a executor for the given engine type
 type ExecutorProvider = provider.Provider[Executor]
 
+// PluginInfoProvider reports the executor plugins that provide engines to a node.
+type PluginInfoProvider interface {
+	Plugins(ctx context.Context) []*models.ExecutorPluginInfo
+}
+
 // Executor serves as an execution manager for running jobs on a specific backend, such as a Docker daemon.
 // It provides a comprehensive set of methods to initiate, monitor, terminate, and retrieve output streams for executions.
 type Executor interface {
@@ -65,6 +70,7 @@ type LogStreamRequest struct {
 type RunCommandRequest struct {
 	JobID        string                    // Unique identifier for the job.
 	ExecutionID  string                    // Unique identifier for a specific execution of the job.
+	Namespace    string                    // Namespace of the job, used to resolve the job's secrets.
 	Resources    *models.Resources         // Resource requirements like CPU, Memory, GPU, Disk.
 	Network      *models.NetworkConfig     // Network configuration for the execution.
 	Outputs      []*models.ResultPath      // Paths where the execution should store its outputs.
@@ -72,6 +78,21 @@ type RunCommandRequest struct {
 	ResultsDir   string                    // Directory where results should be stored.
 	EngineParams *models.SpecConfig        // Engine-specific configuration parameters.
 	OutputLimits OutputLimits              // Output size limits for the execution.
+	HealthCheck  *models.HealthCheckConfig // Health check of the task, if any.
+	// Endpoints are the host ports allocated to the ports of the task's network config.
+	Endpoints []*models.ExecutionEndpoint
+	// ScratchVolume is the scratch volume of the execution, which is writable and sized to its disk request.
+	// Executions have no scratch volume if it is nil.
+	ScratchVolume *storage.StorageVolume
+}
+
+// HealthReporter is implemented by executors that run the health checks of
+// tasks. Executions run by other executors have no health status.
+type HealthReporter interface {
+	// WatchHealth returns the changes in the health of an execution that was
+	// started with a health check. The channel is closed once the execution
+	// completes. Only the latest change is kept if the caller falls behind.
+	WatchHealth(ctx context.Context, executionID string) (<-chan models.ExecutionHealth, error)
 }
 
 // Error variables for execution states.
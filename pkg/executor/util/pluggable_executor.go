@@ -2,29 +2,60 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/rs/zerolog/log"
 
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/plugins/grpc"
-	"github.com/hashicorp/go-plugin"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	pkgUtil "github.com/bacalhau-project/bacalhau/pkg/util"
+)
+
+const (
+	// DefaultPluginHealthCheckInterval is how often plugins are health
+	// checked, and the delay before a failed plugin is first restarted.
+	DefaultPluginHealthCheckInterval = 10 * time.Second
+	// maxPluginRestartBackoff is the longest delay between restarts of a
+	// plugin that keeps failing.
+	maxPluginRestartBackoff = 5 * time.Minute
 )
 
 func NewPluginExecutorManager() *PluginExecutorManager {
 	return &PluginExecutorManager{
-		registered: make(map[string]PluginExecutorManagerConfig),
-		active:     make(map[string]*activeExecutor),
+		registered:          make(map[string]PluginExecutorManagerConfig),
+		active:              make(map[string]*activeExecutor),
+		states:              make(map[string]*pluginState),
+		healthCheckInterval: DefaultPluginHealthCheckInterval,
 	}
 }
 
+// PluginExecutorManager launches executor plugins and provides their
+// executors. Plugins are health checked, and restarted with a backoff when
+// they crash or fail their health check. Only the engines of healthy plugins
+// are provided.
 type PluginExecutorManager struct {
-	registered map[string]PluginExecutorManagerConfig
-	active     map[string]*activeExecutor
+	mu                  sync.RWMutex
+	registered          map[string]PluginExecutorManagerConfig
+	active              map[string]*activeExecutor
+	states              map[string]*pluginState
+	healthCheckInterval time.Duration
+
+	stop func()
+	done chan struct{}
 }
 
 func (e *PluginExecutorManager) Get(ctx context.Context, key string) (executor.Executor, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	engine, ok := e.active[key]
 	if !ok {
 		return nil, fmt.Errorf("plugin %s not found", key)
@@ -33,31 +64,90 @@ func (e *PluginExecutorManager) Get(ctx context.Context, key string) (executor.E
 }
 
 func (e *PluginExecutorManager) Has(ctx context.Context, key string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	_, ok := e.active[key]
 	return ok
 }
 
-// Keys returns the keys of the registered executors
+// Keys returns the keys of the running executors
 func (e *PluginExecutorManager) Keys(ctx context.Context) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 	keys := make([]string, 0, len(e.active))
 	for k := range e.active {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
 
+// Plugins returns the state of the registered plugins.
+func (e *PluginExecutorManager) Plugins(ctx context.Context) []*models.ExecutorPluginInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	plugins := make([]*models.ExecutorPluginInfo, 0, len(e.states))
+	for _, state := range e.states {
+		info := state.info
+		plugins = append(plugins, &info)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
 // compile-time check that PluginExecutorManager implements ExecutorProvider
 var _ executor.ExecutorProvider = (*PluginExecutorManager)(nil)
+var _ executor.PluginInfoProvider = (*PluginExecutorManager)(nil)
 
 type activeExecutor struct {
-	Impl   executor.Executor
-	Closer func()
+	Impl     executor.Executor
+	Closer   func()
+	client   *plugin.Client
+	protocol plugin.ClientProtocol
+}
+
+// healthy returns an error if the plugin has exited or fails to respond.
+func (a *activeExecutor) healthy() error {
+	if a.client.Exited() {
+		return errors.New("plugin exited")
+	}
+	if err := a.protocol.Ping(); err != nil {
+		return fmt.Errorf("plugin failed health check: %w", err)
+	}
+	return nil
+}
+
+// pluginState tracks the launches of a plugin.
+type pluginState struct {
+	info models.ExecutorPluginInfo
+	// launched is true once the plugin has been launched successfully, after
+	// which launches are counted as restarts.
+	launched bool
+	// failures is the number of times the plugin has failed since it was
+	// last healthy, which sets the delay before it is next launched.
+	failures int
+	retryAt  time.Time
+}
+
+func (s *pluginState) failed(err error, interval time.Duration) {
+	backoff := interval << s.failures
+	if backoff > maxPluginRestartBackoff || backoff <= 0 {
+		backoff = maxPluginRestartBackoff
+	}
+	s.failures++
+	s.retryAt = time.Now().Add(backoff)
+	s.info.State = models.ExecutorPluginFailed
+	s.info.Error = err.Error()
 }
 
 type PluginExecutorManagerConfig struct {
-	Name             string
-	Path             string
-	Command          string
+	Name    string
+	Version string
+	Path    string
+	Command string
+	// Checksum of the binary as sha256:<hex>, which is verified before the
+	// plugin is launched if it is set.
+	Checksum         string
 	ProtocolVersion  uint
 	MagicCookieKey   string
 	MagicCookieValue string
@@ -77,33 +167,129 @@ func (e *PluginExecutorManager) RegisterPlugin(config PluginExecutorManagerConfi
 	// TODO check if binary is executable
 
 	e.registered[config.Name] = config
+	e.states[config.Name] = &pluginState{
+		info: models.ExecutorPluginInfo{
+			Name:     config.Name,
+			Version:  config.Version,
+			Path:     filepath.Join(config.Path, config.Command),
+			Checksum: config.Checksum,
+			State:    models.ExecutorPluginStarting,
+		},
+	}
 	return nil
 }
 
+// Start launches the registered plugins, and then supervises them until
+// the manager is stopped. Plugins that fail to launch are retried.
 func (e *PluginExecutorManager) Start(ctx context.Context) error {
-	for name, config := range e.registered {
-		pluginExecutor, closer, err := e.dispense(name, config)
-		if err != nil {
-			return err
-		}
-		e.active[name] = &activeExecutor{
-			Impl:   pluginExecutor,
-			Closer: closer,
-		}
+	for name := range e.registered {
+		e.launch(ctx, name)
 	}
+
+	// The context passed in may be canceled before the manager is stopped.
+	supervisorCtx, cancel := context.WithCancel(pkgUtil.NewDetachedContext(ctx))
+	e.stop = cancel
+	e.done = make(chan struct{})
+	go e.supervise(supervisorCtx)
 	return nil
 }
 
 func (e *PluginExecutorManager) Stop(ctx context.Context) error {
-	for _, active := range e.active {
+	if e.stop != nil {
+		e.stop()
+		<-e.done
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for name, active := range e.active {
 		active.Closer()
+		delete(e.active, name)
 	}
 	return nil
 }
 
+func (e *PluginExecutorManager) supervise(ctx context.Context) {
+	defer close(e.done)
+	ticker := time.NewTicker(e.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.check(ctx)
+		}
+	}
+}
+
+// check health checks the running plugins, and relaunches failed plugins
+// whose backoff has passed.
+func (e *PluginExecutorManager) check(ctx context.Context) {
+	for name := range e.registered {
+		e.mu.RLock()
+		active, running := e.active[name]
+		retryAt := e.states[name].retryAt
+		e.mu.RUnlock()
+
+		if !running {
+			if !time.Now().Before(retryAt) {
+				e.launch(ctx, name)
+			}
+			continue
+		}
+
+		err := active.healthy()
+		if err == nil {
+			continue
+		}
+		log.Ctx(ctx).Warn().Err(err).Str("plugin", name).Msg("executor plugin failed, restarting it")
+		e.mu.Lock()
+		delete(e.active, name)
+		e.states[name].failed(err, e.healthCheckInterval)
+		e.mu.Unlock()
+		active.Closer()
+	}
+}
+
+// launch launches a plugin, recording the failure if it fails.
+func (e *PluginExecutorManager) launch(ctx context.Context, name string) {
+	e.mu.Lock()
+	state := e.states[name]
+	state.info.State = models.ExecutorPluginStarting
+	state.info.StartedAt = time.Now()
+	e.mu.Unlock()
+
+	active, err := e.dispense(e.registered[name])
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("plugin", name).Msg("failed to launch executor plugin")
+		state.failed(err, e.healthCheckInterval)
+		return
+	}
+	if state.launched {
+		state.info.Restarts++
+	}
+	state.launched = true
+	state.failures = 0
+	state.info.State = models.ExecutorPluginRunning
+	state.info.Error = ""
+	e.active[name] = active
+}
+
 const PluggableExecutorPluginName = "PLUGGABLE_EXECUTOR"
 
-func (e *PluginExecutorManager) dispense(name string, config PluginExecutorManagerConfig) (executor.Executor, func(), error) {
+func (e *PluginExecutorManager) dispense(config PluginExecutorManagerConfig) (*activeExecutor, error) {
+	command := filepath.Join(config.Path, config.Command)
+	// the checksum is verified on every launch, as the binary could have
+	// been replaced since the plugin was registered
+	if config.Checksum != "" {
+		if err := verifyChecksum(command, config.Checksum); err != nil {
+			return nil, err
+		}
+	}
+
 	client := plugin.NewClient(&plugin.ClientConfig{
 		Plugins: map[string]plugin.Plugin{
 			PluggableExecutorPluginName: &grpc.ExecutorGRPCPlugin{},
@@ -116,26 +302,31 @@ func (e *PluginExecutorManager) dispense(name string, config PluginExecutorManag
 			MagicCookieValue: config.MagicCookieValue,
 		},
 		//nolint:gosec
-		Cmd: exec.Command(filepath.Join(config.Path, config.Command)),
+		Cmd: exec.Command(command),
 	})
 
 	rpcClient, err := client.Client()
 	if err != nil {
 		client.Kill()
-		return nil, nil, err
+		return nil, err
 	}
 
 	raw, err := rpcClient.Dispense(PluggableExecutorPluginName)
 	if err != nil {
 		client.Kill()
-		return nil, nil, err
+		return nil, err
 	}
 
 	pluginExecutor, ok := raw.(executor.Executor)
 	if !ok {
 		client.Kill()
-		return nil, nil, fmt.Errorf("plugin is not of type: PluggableExecutor")
+		return nil, fmt.Errorf("plugin is not of type: PluggableExecutor")
 	}
 
-	return pluginExecutor, func() { client.Kill() }, nil
+	return &activeExecutor{
+		Impl:     pluginExecutor,
+		Closer:   func() { client.Kill() },
+		client:   client,
+		protocol: rpcClient,
+	}, nil
 }
@@ -0,0 +1,198 @@
+//go:build unit || !integration
+
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/executor/noop"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/plugins/grpc"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// The test binary serves the noop executor as a plugin when it is launched
+// with this handshake, so that plugins can be tested without building one.
+const (
+	testPluginCookieKey   = "BACALHAU_TEST_EXECUTOR_PLUGIN"
+	testPluginCookieValue = "noop"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testPluginCookieKey) == testPluginCookieValue {
+		plugin.Serve(&plugin.ServeConfig{
+			HandshakeConfig: plugin.HandshakeConfig{
+				ProtocolVersion:  1,
+				MagicCookieKey:   testPluginCookieKey,
+				MagicCookieValue: testPluginCookieValue,
+			},
+			Plugins: map[string]plugin.Plugin{
+				PluggableExecutorPluginName: &grpc.ExecutorGRPCPlugin{Impl: noop.NewNoopExecutor()},
+			},
+			Logger:     hclog.NewNullLogger(),
+			GRPCServer: plugin.DefaultGRPCServer,
+		})
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type PluginExecutorManagerSuite struct {
+	suite.Suite
+	ctx context.Context
+	dir string
+}
+
+func TestPluginExecutorManagerSuite(t *testing.T) {
+	suite.Run(t, new(PluginExecutorManagerSuite))
+}
+
+func (s *PluginExecutorManagerSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.dir = s.T().TempDir()
+}
+
+// writePlugin adds a plugin to the plugins directory that runs the test
+// binary, with a manifest with the given checksum, or the binary's checksum
+// if it is empty.
+func (s *PluginExecutorManagerSuite) writePlugin(name, checksum string) {
+	binary, err := os.Executable()
+	s.Require().NoError(err)
+	pluginDir := filepath.Join(s.dir, name)
+	s.Require().NoError(os.Mkdir(pluginDir, 0o755))
+	s.Require().NoError(os.Symlink(binary, filepath.Join(pluginDir, "executor")))
+
+	if checksum == "" {
+		file, err := os.Open(binary)
+		s.Require().NoError(err)
+		defer file.Close()
+		hash := sha256.New()
+		_, err = io.Copy(hash, file)
+		s.Require().NoError(err)
+		checksum = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	}
+	manifest := fmt.Sprintf("Name: %s\nVersion: 1.0.0\nCommand: executor\nChecksum: %s\nMagicCookieKey: %s\nMagicCookieValue: %s\n",
+		name, checksum, testPluginCookieKey, testPluginCookieValue)
+	s.Require().NoError(os.WriteFile(filepath.Join(pluginDir, PluginManifestFile), []byte(manifest), 0o600))
+}
+
+func (s *PluginExecutorManagerSuite) startManager() *PluginExecutorManager {
+	configs, err := DiscoverPlugins(s.ctx, s.dir)
+	s.Require().NoError(err)
+	manager := NewPluginExecutorManager()
+	manager.healthCheckInterval = time.Hour
+	for _, config := range configs {
+		s.Require().NoError(manager.RegisterPlugin(config))
+	}
+	s.Require().NoError(manager.Start(s.ctx))
+	s.T().Cleanup(func() { _ = manager.Stop(s.ctx) })
+	return manager
+}
+
+func (s *PluginExecutorManagerSuite) TestDiscoverPlugins() {
+	s.writePlugin("valid", "")
+	s.Require().NoError(os.Mkdir(filepath.Join(s.dir, "invalid"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, "invalid", PluginManifestFile), []byte("Name: invalid\n"), 0o600))
+	s.Require().NoError(os.Mkdir(filepath.Join(s.dir, "no-manifest"), 0o755))
+
+	configs, err := DiscoverPlugins(s.ctx, s.dir)
+	s.Require().NoError(err)
+	s.Require().Len(configs, 1)
+	s.Require().Equal("valid", configs[0].Name)
+	s.Require().Equal("1.0.0", configs[0].Version)
+	s.Require().Equal(filepath.Join(s.dir, "valid"), configs[0].Path)
+	s.Require().Equal(uint(defaultPluginProtocolVersion), configs[0].ProtocolVersion)
+
+	configs, err = DiscoverPlugins(s.ctx, filepath.Join(s.dir, "missing"))
+	s.Require().NoError(err)
+	s.Require().Empty(configs)
+}
+
+func (s *PluginExecutorManagerSuite) TestInvalidManifest() {
+	for _, manifest := range []PluginManifest{
+		{Command: "executor", Checksum: "sha256:" + hex.EncodeToString(make([]byte, sha256.Size))},
+		{Name: "test", Checksum: "sha256:" + hex.EncodeToString(make([]byte, sha256.Size))},
+		{Name: "test", Command: "../executor", Checksum: "sha256:" + hex.EncodeToString(make([]byte, sha256.Size))},
+		{Name: "test", Command: "executor"},
+		{Name: "test", Command: "executor", Checksum: "md5:00"},
+		{Name: "test", Command: "executor", Checksum: "sha256:00"},
+	} {
+		s.Require().Error(manifest.Validate(), manifest)
+	}
+}
+
+func (s *PluginExecutorManagerSuite) TestStartPlugin() {
+	s.writePlugin("test", "")
+	manager := s.startManager()
+
+	s.Require().Equal([]string{"test"}, manager.Keys(s.ctx))
+	engine, err := manager.Get(s.ctx, "test")
+	s.Require().NoError(err)
+	installed, err := engine.IsInstalled(s.ctx)
+	s.Require().NoError(err)
+	s.Require().True(installed)
+
+	plugins := manager.Plugins(s.ctx)
+	s.Require().Len(plugins, 1)
+	s.Require().Equal(models.ExecutorPluginRunning, plugins[0].State)
+	s.Require().Equal("1.0.0", plugins[0].Version)
+	s.Require().Zero(plugins[0].Restarts)
+}
+
+func (s *PluginExecutorManagerSuite) TestChecksumMismatch() {
+	s.writePlugin("test", "sha256:"+hex.EncodeToString(make([]byte, sha256.Size)))
+	manager := s.startManager()
+
+	s.Require().Empty(manager.Keys(s.ctx))
+	plugins := manager.Plugins(s.ctx)
+	s.Require().Len(plugins, 1)
+	s.Require().Equal(models.ExecutorPluginFailed, plugins[0].State)
+	s.Require().Contains(plugins[0].Error, "checksum")
+}
+
+func (s *PluginExecutorManagerSuite) TestRestartCrashedPlugin() {
+	s.writePlugin("test", "")
+	manager := s.startManager()
+
+	// crash the plugin
+	manager.mu.RLock()
+	active := manager.active["test"]
+	manager.mu.RUnlock()
+	active.client.Kill()
+
+	manager.check(s.ctx)
+	s.Require().Empty(manager.Keys(s.ctx))
+	plugins := manager.Plugins(s.ctx)
+	s.Require().Equal(models.ExecutorPluginFailed, plugins[0].State)
+
+	// restart it once its backoff has passed
+	manager.mu.Lock()
+	manager.states["test"].retryAt = time.Time{}
+	manager.mu.Unlock()
+	manager.check(s.ctx)
+	s.Require().Equal([]string{"test"}, manager.Keys(s.ctx))
+	plugins = manager.Plugins(s.ctx)
+	s.Require().Equal(models.ExecutorPluginRunning, plugins[0].State)
+	s.Require().Equal(1, plugins[0].Restarts)
+	s.Require().Empty(plugins[0].Error)
+}
+
+func (s *PluginExecutorManagerSuite) TestRestartBackoff() {
+	state := &pluginState{}
+	for i := 0; i < 20; i++ {
+		state.failed(fmt.Errorf("failed"), time.Second)
+	}
+	s.Require().Equal(20, state.failures)
+	s.Require().WithinDuration(time.Now().Add(maxPluginRestartBackoff), state.retryAt, time.Second)
+}
@@ -0,0 +1,160 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"go.uber.org/multierr"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PluginManifestFile is the name of the manifest in the directory of each
+	// plugin in the plugins directory.
+	PluginManifestFile = "manifest.yaml"
+
+	// The handshake used by plugins whose manifest doesn't set one, which is
+	// the one used by the docker and wasm executor plugins.
+	defaultPluginProtocolVersion  = 1
+	defaultPluginMagicCookieKey   = "EXECUTOR_PLUGIN"
+	defaultPluginMagicCookieValue = "bacalhau_executor"
+
+	checksumAlgorithmSHA256 = "sha256"
+)
+
+// PluginManifest describes an executor plugin binary to the compute node.
+type PluginManifest struct {
+	// Name is the engine type the plugin provides.
+	Name    string `yaml:"Name"`
+	Version string `yaml:"Version"`
+	// Command is the plugin's binary, relative to the directory of the manifest.
+	Command string `yaml:"Command"`
+	// Checksum is the checksum of the binary as sha256:<hex>, which is
+	// verified every time the plugin is launched.
+	Checksum         string `yaml:"Checksum"`
+	ProtocolVersion  uint   `yaml:"ProtocolVersion"`
+	MagicCookieKey   string `yaml:"MagicCookieKey"`
+	MagicCookieValue string `yaml:"MagicCookieValue"`
+}
+
+// Validate checks the manifest is complete.
+func (m PluginManifest) Validate() error {
+	var errs error
+	if m.Name == "" {
+		errs = multierr.Append(errs, errors.New("missing name"))
+	}
+	if m.Command == "" {
+		errs = multierr.Append(errs, errors.New("missing command"))
+	} else if filepath.IsAbs(m.Command) || strings.Contains(m.Command, "..") {
+		errs = multierr.Append(errs, fmt.Errorf("command %q must be in the directory of the manifest", m.Command))
+	}
+	if _, err := parseChecksum(m.Checksum); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+	return errs
+}
+
+// DiscoverPlugins returns the executor plugins in the subdirectories of dir
+// that have a manifest. Plugins with invalid manifests are skipped, so that
+// they don't prevent the node from starting.
+func DiscoverPlugins(ctx context.Context, dir string) ([]PluginExecutorManagerConfig, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read executor plugins directory: %w", err)
+	}
+
+	var configs []PluginExecutorManagerConfig
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pluginDir := filepath.Join(dir, entry.Name())
+		manifestPath := filepath.Join(pluginDir, PluginManifestFile)
+		manifest, err := readPluginManifest(manifestPath)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("manifest", manifestPath).Msg("skipping executor plugin with invalid manifest")
+			continue
+		}
+		configs = append(configs, manifest.config(pluginDir))
+	}
+	return configs, nil
+}
+
+func readPluginManifest(path string) (PluginManifest, error) {
+	var manifest PluginManifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err = yaml.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return manifest, manifest.Validate()
+}
+
+func (m PluginManifest) config(dir string) PluginExecutorManagerConfig {
+	config := PluginExecutorManagerConfig{
+		Name:             m.Name,
+		Version:          m.Version,
+		Path:             dir,
+		Command:          m.Command,
+		Checksum:         m.Checksum,
+		ProtocolVersion:  m.ProtocolVersion,
+		MagicCookieKey:   m.MagicCookieKey,
+		MagicCookieValue: m.MagicCookieValue,
+	}
+	if config.ProtocolVersion == 0 {
+		config.ProtocolVersion = defaultPluginProtocolVersion
+	}
+	if config.MagicCookieKey == "" {
+		config.MagicCookieKey = defaultPluginMagicCookieKey
+		config.MagicCookieValue = defaultPluginMagicCookieValue
+	}
+	return config
+}
+
+func parseChecksum(checksum string) ([]byte, error) {
+	algorithm, value, found := strings.Cut(checksum, ":")
+	if !found || algorithm != checksumAlgorithmSHA256 {
+		return nil, fmt.Errorf("checksum %q must be of the form %s:<hex>", checksum, checksumAlgorithmSHA256)
+	}
+	sum, err := hex.DecodeString(value)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("checksum %q is not a valid %s hash", checksum, checksumAlgorithmSHA256)
+	}
+	return sum, nil
+}
+
+// verifyChecksum checks the file at path has the checksum.
+func verifyChecksum(path, checksum string) error {
+	expected, err := parseChecksum(checksum)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return err
+	}
+	if actual := hash.Sum(nil); !bytes.Equal(actual, expected) {
+		return fmt.Errorf("checksum of %s is %s:%s, expected %s", path, checksumAlgorithmSHA256, hex.EncodeToString(actual), checksum)
+	}
+	return nil
+}
@@ -2,16 +2,27 @@ package util
 
 import (
 	"context"
+	"path/filepath"
+	"time"
 
+	"github.com/rs/zerolog/log"
+
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/docker"
 	noop_executor "github.com/bacalhau-project/bacalhau/pkg/executor/noop"
 	"github.com/bacalhau-project/bacalhau/pkg/executor/wasm"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/gcs"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/git"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/inline"
 	ipfs_storage "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
 	localdirectory "github.com/bacalhau-project/bacalhau/pkg/storage/local_directory"
@@ -27,10 +38,15 @@ type StandardStorageProviderOptions struct {
 	API                   ipfs.Client
 	DownloadPath          string
 	AllowListedLocalPaths []string
+	// GitCacheDir is where the git storage keeps its repository mirrors.
+	// Defaults to a directory under the node's compute storage path.
+	GitCacheDir string
 }
 
 type StandardExecutorOptions struct {
 	DockerID string
+	// PluginsPath is the directory executor plugins are discovered in, if set.
+	PluginsPath string
 }
 
 func NewStandardStorageProvider(
@@ -60,6 +76,13 @@ func NewStandardStorageProvider(
 		return nil, err
 	}
 
+	azureStorage := azure.NewStorage(azure.StorageProviderParams{
+		ClientProvider: azurehelper.NewClientProvider(azurehelper.DefaultClientProviderParams()),
+	})
+	gcsStorage := gcs.NewStorage(gcs.StorageProviderParams{
+		ClientProvider: gcshelper.NewClientProvider(gcshelper.DefaultClientProviderParams()),
+	})
+
 	localDirectoryStorage, err := localdirectory.NewStorageProvider(localdirectory.StorageProviderParams{
 		AllowedPaths: localdirectory.ParseAllowPaths(options.AllowListedLocalPaths),
 	})
@@ -67,6 +90,29 @@ func NewStandardStorageProvider(
 		return nil, err
 	}
 
+	gitCacheDir := options.GitCacheDir
+	if gitCacheDir == "" {
+		gitCacheDir = filepath.Join(config.GetStoragePath(), "git-cache")
+	}
+	secretsPolicy, err := secrets.NewPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	gitConfig := config.GetGitStorageConfig()
+	gitStorage, err := git.NewStorageProvider(git.StorageProviderParams{
+		CacheDir:          gitCacheDir,
+		Secrets:           secrets.NewEnvResolver(),
+		SecretsPolicy:     secretsPolicy,
+		SSHKeyFile:        gitConfig.SSHKeyFile,
+		SSHKnownHostsFile: gitConfig.SSHKnownHostsFile,
+		MaxRepositorySize: gitConfig.MaxRepositorySize,
+		MaxCacheSize:      gitConfig.MaxCacheSize,
+		FetchTimeout:      gitConfig.FetchTimeout.AsTimeDuration(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var useIPFSDriver storage.Storage = ipfsAPICopyStorage
 
 	return provider.NewMappedProvider(map[string]storage.Storage{
@@ -76,7 +122,10 @@ func NewStandardStorageProvider(
 		models.StorageSourceRepoClone:      tracing.Wrap(repoCloneStorage),
 		models.StorageSourceRepoCloneLFS:   tracing.Wrap(repoCloneStorage),
 		models.StorageSourceS3:             tracing.Wrap(s3Storage),
+		models.StorageSourceAzure:          tracing.Wrap(azureStorage),
+		models.StorageSourceGCS:            tracing.Wrap(gcsStorage),
 		models.StorageSourceLocalDirectory: tracing.Wrap(localDirectoryStorage),
+		models.StorageSourceGit:            tracing.Wrap(gitStorage),
 	}), nil
 }
 
@@ -103,11 +152,13 @@ func NewNoopStorageProvider(
 	return provider.NewNoopProvider[storage.Storage](noopStorage), nil
 }
 
+// NewStandardExecutorProvider returns the docker and wasm executors, and the
+// executors of the plugins discovered in the plugins path.
 func NewStandardExecutorProvider(
 	ctx context.Context,
 	cm *system.CleanupManager,
 	executorOptions StandardExecutorOptions,
-) (executor.ExecutorProvider, error) {
+) (*ExecutorProviderWithPlugins, error) {
 	dockerExecutor, err := docker.NewExecutor(ctx, executorOptions.DockerID)
 	if err != nil {
 		return nil, err
@@ -118,10 +169,41 @@ func NewStandardExecutorProvider(
 		return nil, err
 	}
 
-	return provider.NewMappedProvider(map[string]executor.Executor{
+	builtin := provider.NewMappedProvider(map[string]executor.Executor{
 		models.EngineDocker: dockerExecutor,
 		models.EngineWasm:   wasmExecutor,
-	}), nil
+	})
+
+	plugins, err := DiscoverPlugins(ctx, executorOptions.PluginsPath)
+	if err != nil {
+		return nil, err
+	}
+	var pluginOptions PluginExecutorOptions
+	for _, plugin := range plugins {
+		if builtin.Has(ctx, plugin.Name) {
+			log.Ctx(ctx).Error().Str("plugin", plugin.Name).Msg("skipping executor plugin with the name of a built-in engine")
+			continue
+		}
+		pluginOptions.Plugins = append(pluginOptions.Plugins, plugin)
+	}
+	pluginManager, err := NewPluginExecutorProvider(ctx, cm, pluginOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExecutorProviderWithPlugins{
+		ExecutorProvider: &provider.ChainedProvider[executor.Executor]{
+			Providers: []executor.ExecutorProvider{builtin, pluginManager},
+		},
+		PluginInfoProvider: pluginManager,
+	}, nil
+}
+
+// ExecutorProviderWithPlugins is an executor provider that also reports the
+// state of the executor plugins that provide some of its executors.
+type ExecutorProviderWithPlugins struct {
+	executor.ExecutorProvider
+	executor.PluginInfoProvider
 }
 
 // return noop executors for all engines
@@ -132,14 +214,20 @@ func NewNoopExecutors(config noop_executor.ExecutorConfig) executor.ExecutorProv
 
 type PluginExecutorOptions struct {
 	Plugins []PluginExecutorManagerConfig
+	// HealthCheckInterval is how often the plugins are health checked.
+	// Defaults to DefaultPluginHealthCheckInterval.
+	HealthCheckInterval time.Duration
 }
 
 func NewPluginExecutorProvider(
 	ctx context.Context,
 	cm *system.CleanupManager,
 	pluginOptions PluginExecutorOptions,
-) (executor.ExecutorProvider, error) {
+) (*PluginExecutorManager, error) {
 	pe := NewPluginExecutorManager()
+	if pluginOptions.HealthCheckInterval > 0 {
+		pe.healthCheckInterval = pluginOptions.HealthCheckInterval
+	}
 	for _, cfg := range pluginOptions.Plugins {
 		if err := pe.RegisterPlugin(cfg); err != nil {
 			return nil, err
@@ -12,6 +12,7 @@ import (
 	"github.com/tetratelabs/wazero"
 	"go.uber.org/atomic"
 
+	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 
@@ -32,10 +33,21 @@ import (
 type Executor struct {
 	// handlers is a map of executionID to its handler.
 	handlers generic.SyncMap[string, *executionHandler]
+	// cache holds the modules compiled by all executions
+	cache wazero.CompilationCache
+	// maxFuel is the most fuel an execution can use, if non-zero
+	maxFuel uint64
 }
 
 func NewExecutor() (*Executor, error) {
-	return &Executor{}, nil
+	cfg := config.GetWasmConfig()
+	// compiled modules are cached on disk keyed by a hash of the module, so
+	// that modules used by many jobs are only compiled once
+	cache, err := wazero.NewCompilationCacheWithDir(cfg.CompilationCacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("creating wasm compilation cache in %s: %w", cfg.CompilationCacheDir, err)
+	}
+	return &Executor{cache: cache, maxFuel: cfg.MaxFuel}, nil
 }
 
 func (e *Executor) IsInstalled(context.Context) (bool, error) {
@@ -43,7 +55,16 @@ func (e *Executor) IsInstalled(context.Context) (bool, error) {
 	return true, nil
 }
 
-func (*Executor) ShouldBid(ctx context.Context, request bidstrategy.BidStrategyRequest) (bidstrategy.BidStrategyResponse, error) {
+func (e *Executor) ShouldBid(ctx context.Context, request bidstrategy.BidStrategyRequest) (bidstrategy.BidStrategyResponse, error) {
+	if e.maxFuel > 0 {
+		engineSpec, err := wasmmodels.DecodeSpec(request.Job.Task().Engine)
+		if err != nil {
+			return bidstrategy.BidStrategyResponse{}, err
+		}
+		if engineSpec.Fuel > e.maxFuel {
+			return bidstrategy.NewBidResponse(false, "provide %d fuel to WASM jobs, at most %d", engineSpec.Fuel, e.maxFuel), nil
+		}
+	}
 	return bidstrategy.NewBidResponse(true, "not place additional requirements on WASM jobs"), nil
 }
 
@@ -80,7 +101,9 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 	// Apply memory limits to the runtime. We have to do this in multiples of
 	// the WASM page size of 64kb, so round up to the nearest page size if the
 	// limit is not specified as a multiple of that.
-	engineConfig := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	engineConfig := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithCompilationCache(e.cache)
 	if request.Resources.Memory > 0 {
 		requestedPages := request.Resources.Memory/WasmPageSize + math.Min(request.Resources.Memory%WasmPageSize, 1)
 		if requestedPages > WasmMaxPagesLimit {
@@ -96,7 +119,19 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		return fmt.Errorf("decoding wasm arguments: %w", err)
 	}
 
-	rootFs, err := e.makeFsFromStorage(ctx, request.ResultsDir, request.Inputs, request.Outputs)
+	// Jobs are metered with the fuel they ask for, or the node's maximum if
+	// they don't ask for any.
+	fuel := engineParams.Fuel
+	if e.maxFuel > 0 {
+		if fuel > e.maxFuel {
+			return fmt.Errorf("requested fuel exceeds the node's maximum - %d > %d", fuel, e.maxFuel)
+		}
+		if fuel == 0 {
+			fuel = e.maxFuel
+		}
+	}
+
+	rootFs, err := e.makeFsFromStorage(ctx, request.ResultsDir, request.Inputs, request.Outputs, request.ScratchVolume)
 	if err != nil {
 		return err
 	}
@@ -108,6 +143,11 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		return err
 	}
 
+	logger := log.With().
+		Str("execution", request.ExecutionID).
+		Str("job", request.JobID).
+		Str("entrypoint", engineParams.EntryPoint).
+		Logger()
 	handler := &executionHandler{
 		runtime:     wazero.NewRuntimeWithConfig(ctx, engineConfig),
 		arguments:   engineParams,
@@ -116,15 +156,13 @@ func (e *Executor) Start(ctx context.Context, request *executor.RunCommandReques
 		executionID: request.ExecutionID,
 		resultsDir:  request.ResultsDir,
 		limits:      request.OutputLimits,
-		logger: log.With().
-			Str("execution", request.ExecutionID).
-			Str("job", request.JobID).
-			Str("entrypoint", engineParams.EntryPoint).
-			Logger(),
-		logManager: wasmLogs,
-		activeCh:   make(chan bool),
-		waitCh:     make(chan bool),
-		running:    atomic.NewBool(false),
+		fuel:        fuel,
+		network:     newNetworkHost(request.Network, request.JobID, logger),
+		logger:      logger,
+		logManager:  wasmLogs,
+		activeCh:    make(chan bool),
+		waitCh:      make(chan bool),
+		running:     atomic.NewBool(false),
 	}
 
 	// register the handler for this executionID
@@ -232,11 +270,13 @@ func (e *Executor) Run(
 //   - mount each input at the name specified by Path
 //   - make a directory in the job results directory for each output and mount that
 //     at the name specified by Name
+//   - mount the scratch volume, if any, at its target
 func (e *Executor) makeFsFromStorage(
 	ctx context.Context,
 	jobResultsDir string,
 	volumes []storage.PreparedStorage,
-	outputs []*models.ResultPath) (fs.FS, error) {
+	outputs []*models.ResultPath,
+	scratch *storage.StorageVolume) (fs.FS, error) {
 	var err error
 	rootFs := mountfs.New()
 
@@ -274,14 +314,19 @@ func (e *Executor) makeFsFromStorage(
 			return nil, fmt.Errorf("output volume has no path: %+v", output)
 		}
 
+		if !output.HasLocalName() {
+			return nil, fmt.Errorf("output volume name is not a relative path: %+v", output)
+		}
+
 		srcd := filepath.Join(jobResultsDir, output.Name)
 		log.Ctx(ctx).Debug().
 			Str("output", output.Name).
 			Str("dir", srcd).
 			Msg("Collecting output")
 
+		// only the checkpoint path already exists, as the checkpoint is restored to it
 		err = os.Mkdir(srcd, util.OS_ALL_R|util.OS_ALL_X|util.OS_USER_W)
-		if err != nil {
+		if err != nil && !(os.IsExist(err) && output.Name == models.CheckpointResultPathName) {
 			return nil, err
 		}
 
@@ -291,6 +336,18 @@ func (e *Executor) makeFsFromStorage(
 		}
 	}
 
+	if scratch != nil {
+		log.Ctx(ctx).Debug().
+			Str("scratch", scratch.Target).
+			Str("dir", scratch.Source).
+			Msg("Using scratch volume")
+
+		err = rootFs.Mount(scratch.Target, touchfs.New(scratch.Source))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return rootFs, nil
 }
 
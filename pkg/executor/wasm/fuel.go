@@ -0,0 +1,383 @@
+package wasm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/tetratelabs/wabin/binary"
+	"github.com/tetratelabs/wabin/leb128"
+	"github.com/tetratelabs/wabin/wasm"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// Fuel metering works by instrumenting every module before it is compiled,
+// so that each straight-line run of instructions first checks and takes the
+// number of instructions in it from a shared fuel global. The global is
+// imported from a small module that is instantiated before any other, so all
+// the modules of an execution draw from the same fuel. As the cost of each
+// run of instructions is fixed when the module is instrumented, the fuel used
+// by an execution is deterministic regardless of the engine or the node.
+const (
+	// FuelModuleName is the name of the module that holds the fuel global.
+	FuelModuleName = "bacalhau_fuel"
+	fuelGlobalName = "fuel"
+
+	// fuelExhausted is stored in the fuel global when a module runs out,
+	// so that the trap it raises can be told apart from any other.
+	fuelExhausted = math.MaxUint64
+)
+
+// ErrOutOfFuel is the reason a metered execution fails when it uses all of
+// its fuel.
+var ErrOutOfFuel = errors.New("execution ran out of fuel")
+
+// fuelMeter holds the fuel of a metered execution.
+type fuelMeter struct {
+	global api.MutableGlobal
+	limit  uint64
+}
+
+// newFuelMeter instantiates the fuel module in the runtime, with the limit
+// as the fuel available to the modules instantiated after it.
+func newFuelMeter(ctx context.Context, runtime wazero.Runtime, limit uint64) (*fuelMeter, error) {
+	if limit == 0 || limit == fuelExhausted {
+		return nil, fmt.Errorf("invalid fuel limit %d", limit)
+	}
+	module, err := runtime.InstantiateWithConfig(ctx, fuelModule(), wazero.NewModuleConfig().WithName(FuelModuleName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate fuel module: %w", err)
+	}
+	global, ok := module.ExportedGlobal(fuelGlobalName).(api.MutableGlobal)
+	if !ok {
+		return nil, fmt.Errorf("fuel module does not export a mutable %s global", fuelGlobalName)
+	}
+	global.Set(limit)
+	return &fuelMeter{global: global, limit: limit}, nil
+}
+
+// exhausted returns whether the execution ran out of fuel.
+func (f *fuelMeter) exhausted() bool {
+	return f.global.Get() == fuelExhausted
+}
+
+// used returns the fuel used by the execution so far.
+func (f *fuelMeter) used() uint64 {
+	if f.exhausted() {
+		return f.limit
+	}
+	return f.limit - f.global.Get()
+}
+
+// fuelModule returns a module exporting a single mutable i64 global, which
+// is the fuel imported by metered modules.
+func fuelModule() []byte {
+	return binary.EncodeModule(&wasm.Module{
+		GlobalSection: []*wasm.Global{{
+			Type: &wasm.GlobalType{ValType: wasm.ValueTypeI64, Mutable: true},
+			Init: &wasm.ConstantExpression{Opcode: wasm.OpcodeI64Const, Data: leb128.EncodeInt64(0)},
+		}},
+		ExportSection: []*wasm.Export{{Type: wasm.ExternTypeGlobal, Name: fuelGlobalName, Index: 0}},
+	})
+}
+
+// meterModule instruments a module to use fuel. The fuel global is imported
+// after any other global import, so the index of every global defined by the
+// module moves up by one.
+func meterModule(source []byte) ([]byte, error) {
+	module, err := binary.DecodeModule(source, wasm.CoreFeaturesV2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode module: %w", err)
+	}
+
+	fuelIndex := module.ImportGlobalCount()
+	relocate := func(index uint32) uint32 {
+		if index >= fuelIndex {
+			return index + 1
+		}
+		return index
+	}
+
+	module.ImportSection = append(module.ImportSection, &wasm.Import{
+		Type:       wasm.ExternTypeGlobal,
+		Module:     FuelModuleName,
+		Name:       fuelGlobalName,
+		DescGlobal: &wasm.GlobalType{ValType: wasm.ValueTypeI64, Mutable: true},
+	})
+	for _, global := range module.GlobalSection {
+		if err = relocateConstantExpression(global.Init, relocate); err != nil {
+			return nil, err
+		}
+	}
+	for _, segment := range module.ElementSection {
+		if err = relocateConstantExpression(segment.OffsetExpr, relocate); err != nil {
+			return nil, err
+		}
+	}
+	for _, segment := range module.DataSection {
+		if err = relocateConstantExpression(segment.OffsetExpression, relocate); err != nil {
+			return nil, err
+		}
+	}
+	for _, export := range module.ExportSection {
+		if export.Type == wasm.ExternTypeGlobal {
+			export.Index = relocate(export.Index)
+		}
+	}
+	for i, code := range module.CodeSection {
+		if code.Body, err = meterBody(code.Body, fuelIndex, relocate); err != nil {
+			return nil, fmt.Errorf("failed to meter function %d: %w", i, err)
+		}
+	}
+
+	metered := binary.EncodeModule(module)
+	if module.DataCountSection != nil {
+		return withDataCount(metered, *module.DataCountSection)
+	}
+	return metered, nil
+}
+
+func relocateConstantExpression(expr *wasm.ConstantExpression, relocate func(uint32) uint32) error {
+	if expr == nil || expr.Opcode != wasm.OpcodeGlobalGet {
+		return nil
+	}
+	index, _, err := leb128.DecodeUint32(bytes.NewReader(expr.Data))
+	if err != nil {
+		return fmt.Errorf("failed to decode global index: %w", err)
+	}
+	expr.Data = leb128.EncodeUint32(relocate(index))
+	return nil
+}
+
+// withDataCount adds the data count section, which the encoder drops, to an
+// encoded module. It is required before the code section by modules that use
+// bulk memory instructions.
+func withDataCount(module []byte, count uint32) ([]byte, error) {
+	r := bytes.NewReader(module[8:])
+	for r.Len() > 0 {
+		offset := len(module) - r.Len()
+		id, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size, _, err := leb128.DecodeUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		if id == wasm.SectionIDCode || id == wasm.SectionIDData {
+			return insertSection(module, offset, wasm.SectionIDDataCount, leb128.EncodeUint32(count)), nil
+		}
+		if _, err = r.Seek(int64(size), io.SeekCurrent); err != nil {
+			return nil, err
+		}
+	}
+	return insertSection(module, len(module), wasm.SectionIDDataCount, leb128.EncodeUint32(count)), nil
+}
+
+func insertSection(module []byte, offset int, id wasm.SectionID, contents []byte) []byte {
+	section := append([]byte{id}, leb128.EncodeUint32(uint32(len(contents)))...)
+	section = append(section, contents...)
+	result := make([]byte, 0, len(module)+len(section))
+	result = append(result, module[:offset]...)
+	result = append(result, section...)
+	return append(result, module[offset:]...)
+}
+
+// meterBody instruments a function body, charging each run of instructions
+// up to and including the next block, loop, if, else or end at its start.
+func meterBody(body []byte, fuelIndex uint32, relocate func(uint32) uint32) ([]byte, error) {
+	metered := make([]byte, 0, len(body)*2)
+	pending := make([]byte, 0, len(body))
+	var cost int64
+
+	r := bytes.NewReader(body)
+	for r.Len() > 0 {
+		start := len(body) - r.Len()
+		opcode, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case wasm.OpcodeGlobalGet, wasm.OpcodeGlobalSet:
+			index, _, err := leb128.DecodeUint32(r)
+			if err != nil {
+				return nil, err
+			}
+			pending = append(pending, opcode)
+			pending = append(pending, leb128.EncodeUint32(relocate(index))...)
+		default:
+			if err = skipImmediates(opcode, r); err != nil {
+				return nil, fmt.Errorf("instruction 0x%x at offset %d: %w", opcode, start, err)
+			}
+			pending = append(pending, body[start:len(body)-r.Len()]...)
+		}
+		cost++
+
+		switch opcode {
+		case wasm.OpcodeBlock, wasm.OpcodeLoop, wasm.OpcodeIf, wasm.OpcodeElse, wasm.OpcodeEnd:
+			metered = append(metered, chargeFuel(fuelIndex, cost)...)
+			metered = append(metered, pending...)
+			pending, cost = pending[:0], 0
+		}
+	}
+	if cost > 0 {
+		return nil, errors.New("function body does not end with end")
+	}
+	return metered, nil
+}
+
+// chargeFuel returns the instructions that take cost from the fuel global,
+// trapping if there is not enough left.
+func chargeFuel(fuelIndex uint32, cost int64) []byte {
+	index := leb128.EncodeUint32(fuelIndex)
+	amount := leb128.EncodeInt64(cost)
+
+	var code []byte
+	code = append(append(code, wasm.OpcodeGlobalGet), index...)
+	code = append(append(code, wasm.OpcodeI64Const), amount...)
+	code = append(code, wasm.OpcodeI64LtU, wasm.OpcodeIf, 0x40)
+	code = append(append(code, wasm.OpcodeI64Const), leb128.EncodeInt64(-1)...)
+	code = append(append(code, wasm.OpcodeGlobalSet), index...)
+	code = append(code, wasm.OpcodeUnreachable, wasm.OpcodeEnd)
+	code = append(append(code, wasm.OpcodeGlobalGet), index...)
+	code = append(append(code, wasm.OpcodeI64Const), amount...)
+	code = append(code, wasm.OpcodeI64Sub)
+	return append(append(code, wasm.OpcodeGlobalSet), index...)
+}
+
+// skipImmediates reads the immediate arguments of an instruction.
+//
+//nolint:gocyclo
+func skipImmediates(opcode wasm.Opcode, r *bytes.Reader) error {
+	switch {
+	case opcode == wasm.OpcodeBlock || opcode == wasm.OpcodeLoop || opcode == wasm.OpcodeIf:
+		return skipBlockType(r)
+	case opcode == wasm.OpcodeBr || opcode == wasm.OpcodeBrIf || opcode == wasm.OpcodeCall ||
+		opcode == wasm.OpcodeLocalGet || opcode == wasm.OpcodeLocalSet || opcode == wasm.OpcodeLocalTee ||
+		opcode == wasm.OpcodeTableGet || opcode == wasm.OpcodeTableSet || opcode == wasm.OpcodeRefFunc:
+		return skipUint32s(r, 1)
+	case opcode == wasm.OpcodeBrTable:
+		count, _, err := leb128.DecodeUint32(r)
+		if err != nil {
+			return err
+		}
+		return skipUint32s(r, int(count)+1)
+	case opcode == wasm.OpcodeCallIndirect:
+		return skipUint32s(r, 2)
+	case opcode == wasm.OpcodeTypedSelect:
+		count, _, err := leb128.DecodeUint32(r)
+		if err != nil {
+			return err
+		}
+		return skipBytes(r, int64(count))
+	case opcode >= wasm.OpcodeI32Load && opcode <= wasm.OpcodeI64Store32:
+		return skipUint32s(r, 2)
+	case opcode == wasm.OpcodeMemorySize || opcode == wasm.OpcodeMemoryGrow || opcode == wasm.OpcodeRefNull:
+		return skipBytes(r, 1)
+	case opcode == wasm.OpcodeI32Const:
+		_, _, err := leb128.DecodeInt32(r)
+		return err
+	case opcode == wasm.OpcodeI64Const:
+		_, _, err := leb128.DecodeInt64(r)
+		return err
+	case opcode == wasm.OpcodeF32Const:
+		return skipBytes(r, 4)
+	case opcode == wasm.OpcodeF64Const:
+		return skipBytes(r, 8)
+	case opcode == wasm.OpcodeUnreachable || opcode == wasm.OpcodeNop || opcode == wasm.OpcodeElse ||
+		opcode == wasm.OpcodeEnd || opcode == wasm.OpcodeReturn || opcode == wasm.OpcodeDrop ||
+		opcode == wasm.OpcodeSelect || opcode == wasm.OpcodeRefIsNull ||
+		(opcode >= wasm.OpcodeI32Eqz && opcode <= wasm.OpcodeI64Extend32S):
+		return nil
+	case opcode == wasm.OpcodeMiscPrefix:
+		return skipMiscImmediates(r)
+	case opcode == wasm.OpcodeVecPrefix:
+		return skipVecImmediates(r)
+	default:
+		return errors.New("unsupported instruction")
+	}
+}
+
+func skipBlockType(r *bytes.Reader) error {
+	_, _, err := leb128.DecodeInt33AsInt64(r)
+	return err
+}
+
+func skipMiscImmediates(r *bytes.Reader) error {
+	opcode, _, err := leb128.DecodeUint32(r)
+	if err != nil {
+		return err
+	}
+	if opcode > math.MaxUint8 {
+		return errors.New("unsupported instruction")
+	}
+	switch wasm.OpcodeMisc(opcode) {
+	case wasm.OpcodeMiscI32TruncSatF32S, wasm.OpcodeMiscI32TruncSatF32U,
+		wasm.OpcodeMiscI32TruncSatF64S, wasm.OpcodeMiscI32TruncSatF64U,
+		wasm.OpcodeMiscI64TruncSatF32S, wasm.OpcodeMiscI64TruncSatF32U,
+		wasm.OpcodeMiscI64TruncSatF64S, wasm.OpcodeMiscI64TruncSatF64U:
+		return nil
+	case wasm.OpcodeMiscMemoryInit:
+		if err = skipUint32s(r, 1); err != nil {
+			return err
+		}
+		return skipBytes(r, 1)
+	case wasm.OpcodeMiscDataDrop, wasm.OpcodeMiscElemDrop, wasm.OpcodeMiscTableGrow,
+		wasm.OpcodeMiscTableSize, wasm.OpcodeMiscTableFill:
+		return skipUint32s(r, 1)
+	case wasm.OpcodeMiscMemoryCopy:
+		return skipBytes(r, 2)
+	case wasm.OpcodeMiscMemoryFill:
+		return skipBytes(r, 1)
+	case wasm.OpcodeMiscTableInit, wasm.OpcodeMiscTableCopy:
+		return skipUint32s(r, 2)
+	default:
+		return errors.New("unsupported instruction")
+	}
+}
+
+func skipVecImmediates(r *bytes.Reader) error {
+	opcode, _, err := leb128.DecodeUint32(r)
+	if err != nil {
+		return err
+	}
+	if opcode > math.MaxUint8 {
+		return errors.New("unsupported instruction")
+	}
+	switch op := wasm.OpcodeVec(opcode); {
+	case op <= wasm.OpcodeVecV128Store, op == wasm.OpcodeVecV128Load32zero, op == wasm.OpcodeVecV128Load64zero:
+		return skipUint32s(r, 2)
+	case op == wasm.OpcodeVecV128Const, op == wasm.OpcodeVecV128i8x16Shuffle:
+		return skipBytes(r, 16)
+	case op >= wasm.OpcodeVecI8x16ExtractLaneS && op <= wasm.OpcodeVecF64x2ReplaceLane:
+		return skipBytes(r, 1)
+	case op >= wasm.OpcodeVecV128Load8Lane && op <= wasm.OpcodeVecV128Store64Lane:
+		if err = skipUint32s(r, 2); err != nil {
+			return err
+		}
+		return skipBytes(r, 1)
+	default:
+		return nil
+	}
+}
+
+func skipUint32s(r *bytes.Reader, count int) error {
+	for i := 0; i < count; i++ {
+		if _, _, err := leb128.DecodeUint32(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func skipBytes(r *bytes.Reader, count int64) error {
+	if int64(r.Len()) < count {
+		return io.ErrUnexpectedEOF
+	}
+	_, err := r.Seek(count, io.SeekCurrent)
+	return err
+}
@@ -0,0 +1,114 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/cat"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/csv"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/dynamic"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/easter"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/env"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/exit_code"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/logtest"
+	"github.com/bacalhau-project/bacalhau/testdata/wasm/noop"
+)
+
+type FuelSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestFuelSuite(t *testing.T) {
+	suite.Run(t, new(FuelSuite))
+}
+
+func (s *FuelSuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+// run runs the entry point of a metered program with the given fuel.
+func (s *FuelSuite) run(program []byte, fuel uint64) (*fuelMeter, error) {
+	runtime := wazero.NewRuntime(s.ctx)
+	s.T().Cleanup(func() { _ = runtime.Close(s.ctx) })
+	_, err := wasi_snapshot_preview1.Instantiate(s.ctx, runtime)
+	s.Require().NoError(err)
+
+	meter, err := newFuelMeter(s.ctx, runtime, fuel)
+	s.Require().NoError(err)
+	metered, err := meterModule(program)
+	s.Require().NoError(err)
+	module, err := runtime.InstantiateWithConfig(s.ctx, metered, wazero.NewModuleConfig().WithStartFunctions())
+	s.Require().NoError(err)
+
+	_, err = module.ExportedFunction("_start").Call(s.ctx)
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 0 {
+		err = nil
+	}
+	return meter, err
+}
+
+func (s *FuelSuite) TestMeteredProgramsCompile() {
+	programs := map[string][]byte{
+		"cat":       cat.Program(),
+		"csv":       csv.Program(),
+		"dynamic":   dynamic.Program(),
+		"easter":    easter.Program(),
+		"env":       env.Program(),
+		"exit_code": exit_code.Program(),
+		"logtest":   logtest.Program(),
+		"noop":      noop.Program(),
+	}
+	runtime := wazero.NewRuntime(s.ctx)
+	defer func() { _ = runtime.Close(s.ctx) }()
+
+	for name, program := range programs {
+		metered, err := meterModule(program)
+		s.Require().NoError(err, name)
+		_, err = runtime.CompileModule(s.ctx, metered)
+		s.Require().NoError(err, name)
+	}
+}
+
+func (s *FuelSuite) TestFuelUsageIsDeterministic() {
+	meter, err := s.run(noop.Program(), 1<<40)
+	s.Require().NoError(err)
+	s.Require().False(meter.exhausted())
+	used := meter.used()
+	s.Require().Greater(used, uint64(0))
+
+	meter, err = s.run(noop.Program(), 1<<40)
+	s.Require().NoError(err)
+	s.Require().Equal(used, meter.used())
+}
+
+func (s *FuelSuite) TestOutOfFuel() {
+	meter, err := s.run(noop.Program(), 1<<40)
+	s.Require().NoError(err)
+	used := meter.used()
+
+	meter, err = s.run(noop.Program(), used-1)
+	s.Require().Error(err)
+	s.Require().True(meter.exhausted())
+	s.Require().Equal(used-1, meter.used())
+
+	meter, err = s.run(noop.Program(), used)
+	s.Require().NoError(err)
+	s.Require().False(meter.exhausted())
+}
+
+func (s *FuelSuite) TestInvalidFuel() {
+	runtime := wazero.NewRuntime(s.ctx)
+	defer func() { _ = runtime.Close(s.ctx) }()
+	_, err := newFuelMeter(s.ctx, runtime, 0)
+	s.Require().Error(err)
+}
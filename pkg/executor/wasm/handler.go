@@ -38,6 +38,10 @@ type executionHandler struct {
 	executionID string
 	resultsDir  string
 	limits      executor.OutputLimits
+	// fuel the execution can use, or zero if it isn't metered
+	fuel uint64
+	// network host functions, enforcing the network config of the job
+	network *networkHost
 
 	// cancellation
 	cancel func()
@@ -127,6 +131,26 @@ func (h *executionHandler) run(ctx context.Context) {
 	h.logger.Info().Msg("instantiating wasm modules")
 	loader := NewModuleLoader(tracingEngine, config, h.inputs...)
 
+	// The fuel module has to be instantiated before the metered modules
+	// that import it.
+	var meter *fuelMeter
+	if h.fuel > 0 {
+		meter, err = newFuelMeter(ctx, tracingEngine, h.fuel)
+		if err != nil {
+			h.result = executor.NewFailedResult(err.Error())
+			return
+		}
+		loader = loader.WithFuelMetering()
+	}
+
+	// The network modules are always available so that modules importing
+	// them can be instantiated, with the network config checked on each call.
+	if err = h.network.instantiate(ctx, tracingEngine); err != nil {
+		h.result = executor.NewFailedResult(err.Error())
+		return
+	}
+	defer h.network.close()
+
 	// TODO we have been ignoring errors from this method for ages. Now that we actually check them tests fail! nice..
 	// v1.0.3: https://github.com/bacalhau-project/bacalhau/blob/v1.0.3/pkg/executor/wasm/executor.go#L243
 	// current: https://github.com/bacalhau-project/bacalhau/blob/ff1bd9cb1c09fa3652c4a68943a97476340dbe33/pkg/executor/wasm/executor.go#L216
@@ -187,6 +211,9 @@ func (h *executionHandler) run(ctx context.Context) {
 		wasmErr = nil
 		h.logger.Info().Int64("exit_code", exitCode).Msg("execution ended")
 	}
+	if meter != nil && meter.exhausted() {
+		wasmErr = fmt.Errorf("%w: used all %d units", ErrOutOfFuel, h.fuel)
+	}
 	if wasmErr != nil {
 		// in the event that an error is returned without an exist code we'll assume the operation
 		// failed and set the exit code to 1
@@ -200,6 +227,9 @@ func (h *executionHandler) run(ctx context.Context) {
 	stdoutReader, stderrReader := h.logManager.GetDefaultReaders(false)
 
 	h.result = executor.WriteJobResults(h.resultsDir, stdoutReader, stderrReader, int(exitCode), wasmErr, h.limits)
+	if meter != nil {
+		h.result.ResourceUsage = &models.ExecutionResourceUsage{Fuel: meter.used()}
+	}
 }
 
 func (h *executionHandler) active() bool {
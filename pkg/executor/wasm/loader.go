@@ -25,6 +25,8 @@ type ModuleLoader struct {
 	runtime  wazero.Runtime
 	config   wazero.ModuleConfig
 	storages []storage.PreparedStorage
+	// metered modules are instrumented to use the fuel of the execution
+	metered bool
 
 	// Runtime will throw an error if the same module is instantiated more than
 	// once. So we use this mutex around checking for modules and instantiating
@@ -35,6 +37,14 @@ func NewModuleLoader(runtime wazero.Runtime, config wazero.ModuleConfig, storage
 	return &ModuleLoader{runtime: runtime, config: config, storages: storages}
 }
 
+// WithFuelMetering instruments the modules compiled by the loader to use
+// fuel, which requires the fuel module to be instantiated in the runtime
+// before any of them.
+func (loader *ModuleLoader) WithFuelMetering() *ModuleLoader {
+	loader.metered = true
+	return loader
+}
+
 // Load compiles and returns a module located at the passed path.
 func (loader *ModuleLoader) Load(ctx context.Context, path string) (wazero.CompiledModule, error) {
 	ctx, span := system.NewSpan(ctx, system.GetTracer(), "pkg/executor/wasm.ModuleLoader.Load")
@@ -47,6 +57,12 @@ func (loader *ModuleLoader) Load(ctx context.Context, path string) (wazero.Compi
 		return nil, err
 	}
 
+	if loader.metered {
+		if bytes, err = meterModule(bytes); err != nil {
+			return nil, fmt.Errorf("failed to meter module %s: %w", path, err)
+		}
+	}
+
 	module, err := loader.runtime.CompileModule(ctx, bytes)
 	if err != nil {
 		return nil, err
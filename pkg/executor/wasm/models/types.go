@@ -31,6 +31,10 @@ type EngineSpec struct {
 	// ImportModules is a slice of StorageSpec's containing WASM modules whose exports will be available as imports
 	// to the EntryModule.
 	ImportModules []*models.InputSource `json:"ImportModules,omitempty"`
+
+	// Fuel is the most fuel the job can use, where a unit of fuel is roughly a WASM instruction.
+	// If zero, the job is metered with the compute node's maximum, if it has one.
+	Fuel uint64 `json:"Fuel,omitempty"`
 }
 
 func (c EngineSpec) Validate() error {
@@ -51,6 +55,7 @@ func (c EngineSpec) ToArguments(entryModule storage.PreparedStorage, importModul
 		Parameters:           c.Parameters,
 		EnvironmentVariables: c.EnvironmentVariables,
 		ImportModules:        importModules,
+		Fuel:                 c.Fuel,
 	}
 }
 
@@ -66,6 +71,7 @@ type legacyEngineSpec struct {
 	Parameters           []string
 	EnvironmentVariables map[string]string
 	ImportModules        []model.StorageSpec
+	Fuel                 uint64
 }
 
 func DecodeSpec(spec *models.SpecConfig) (EngineSpec, error) {
@@ -134,6 +140,7 @@ func DecodeLegacySpec(spec *models.SpecConfig) (EngineSpec, error) {
 		Parameters:           c.Parameters,
 		EnvironmentVariables: c.EnvironmentVariables,
 		ImportModules:        importModules,
+		Fuel:                 c.Fuel,
 	}
 	return engineSpec, engineSpec.Validate()
 }
@@ -149,6 +156,7 @@ type EngineArguments struct {
 	EnvironmentVariables map[string]string
 	EntryModule          storage.PreparedStorage
 	ImportModules        []storage.PreparedStorage
+	Fuel                 uint64
 }
 
 func (c EngineArguments) Validate() error {
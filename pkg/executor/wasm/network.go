@@ -0,0 +1,386 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// WASM jobs reach the network through host functions, rather than WASI
+// sockets which wazero only supports for listening. Requests are checked
+// against the network config of the job with the same semantics as the
+// Docker executor: HTTP networking only allows HTTP requests to the job's
+// domains, full networking allows HTTP requests and sockets to any host, and
+// no networking allows neither.
+//
+// Strings and buffers are passed as a pointer and length in the memory of
+// the calling module. Functions return a non-negative result on success, or
+// one of the negative error codes below, with the message of the last error
+// available from last_error.
+const (
+	// HTTPModuleName is the name of the module with the HTTP host functions.
+	HTTPModuleName = "bacalhau_http"
+	// SocketModuleName is the name of the module with the socket host functions.
+	SocketModuleName = "bacalhau_sock"
+
+	// jobIDHeader is added to outgoing requests, as by the Docker HTTP
+	// gateway, so that jobs trying to spawn other jobs can be detected.
+	jobIDHeader = "X-Bacalhau-Job-ID"
+
+	// maxTransferLen is the most bytes a single send, recv or read transfers,
+	// so that the number of bytes transferred can be returned as an int32.
+	maxTransferLen = math.MaxInt32
+)
+
+const (
+	// networkErrInvalid means the arguments are invalid.
+	networkErrInvalid int32 = -1
+	// networkErrDenied means the network config of the job does not allow it.
+	networkErrDenied int32 = -2
+	// networkErrFailed means the request or connection failed.
+	networkErrFailed int32 = -3
+	// networkErrBadHandle means the response or connection does not exist.
+	networkErrBadHandle int32 = -4
+)
+
+var errNetworkDenied = errors.New("denied by the network config of the job")
+
+// networkHost implements the network host functions of an execution.
+type networkHost struct {
+	network *models.NetworkConfig
+	jobID   string
+	client  *http.Client
+	logger  zerolog.Logger
+
+	mu        sync.Mutex
+	next      int32
+	responses map[int32]*http.Response
+	conns     map[int32]net.Conn
+	lastError string
+}
+
+func newNetworkHost(network *models.NetworkConfig, jobID string, logger zerolog.Logger) *networkHost {
+	if network == nil {
+		network = &models.NetworkConfig{Type: models.NetworkNone}
+	}
+	h := &networkHost{
+		network:   network,
+		jobID:     jobID,
+		logger:    logger,
+		responses: make(map[int32]*http.Response),
+		conns:     make(map[int32]net.Conn),
+	}
+	h.client = &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !h.network.AllowsHost(req.URL.Hostname()) {
+				return fmt.Errorf("redirect to %s %w", req.URL.Hostname(), errNetworkDenied)
+			}
+			if len(via) >= 10 { //nolint:gomnd
+				return errors.New("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+	return h
+}
+
+// instantiate adds the HTTP and socket host modules to the runtime.
+func (h *networkHost) instantiate(ctx context.Context, runtime wazero.Runtime) error {
+	_, err := runtime.NewHostModuleBuilder(HTTPModuleName).
+		NewFunctionBuilder().WithFunc(h.request).Export("request").
+		NewFunctionBuilder().WithFunc(h.responseStatus).Export("response_status").
+		NewFunctionBuilder().WithFunc(h.responseHeaders).Export("response_headers").
+		NewFunctionBuilder().WithFunc(h.responseRead).Export("response_read").
+		NewFunctionBuilder().WithFunc(h.responseClose).Export("response_close").
+		NewFunctionBuilder().WithFunc(h.readLastError).Export("last_error").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to instantiate %s module: %w", HTTPModuleName, err)
+	}
+	_, err = runtime.NewHostModuleBuilder(SocketModuleName).
+		NewFunctionBuilder().WithFunc(h.connect).Export("connect").
+		NewFunctionBuilder().WithFunc(h.send).Export("send").
+		NewFunctionBuilder().WithFunc(h.recv).Export("recv").
+		NewFunctionBuilder().WithFunc(h.closeConn).Export("close").
+		NewFunctionBuilder().WithFunc(h.readLastError).Export("last_error").
+		Instantiate(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to instantiate %s module: %w", SocketModuleName, err)
+	}
+	return nil
+}
+
+// close closes any responses and connections left open by the execution.
+func (h *networkHost) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for handle, response := range h.responses {
+		_ = response.Body.Close()
+		delete(h.responses, handle)
+	}
+	for handle, conn := range h.conns {
+		_ = conn.Close()
+		delete(h.conns, handle)
+	}
+}
+
+// fail records the error as the last error and returns its code.
+func (h *networkHost) fail(code int32, err error) int32 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastError = err.Error()
+	h.logger.Debug().Err(err).Int32("code", code).Msg("wasm network call failed")
+	return code
+}
+
+// request sends an HTTP request and returns a handle to its response. The
+// headers are lines of the form "Name: value".
+func (h *networkHost) request(ctx context.Context, mod api.Module,
+	methodPtr, methodLen, urlPtr, urlLen, headersPtr, headersLen, bodyPtr, bodyLen uint32,
+) int32 {
+	method, ok1 := readString(mod, methodPtr, methodLen)
+	rawURL, ok2 := readString(mod, urlPtr, urlLen)
+	headers, ok3 := readString(mod, headersPtr, headersLen)
+	body, ok4 := mod.Memory().Read(bodyPtr, bodyLen)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return h.fail(networkErrInvalid, errors.New("request arguments are out of range"))
+	}
+
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return h.fail(networkErrInvalid, err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return h.fail(networkErrInvalid, fmt.Errorf("unsupported scheme %q", target.Scheme))
+	}
+	if !h.network.AllowsHost(target.Hostname()) {
+		return h.fail(networkErrDenied, fmt.Errorf("request to %s %w", target.Hostname(), errNetworkDenied))
+	}
+
+	// the body is copied as the memory of the module can change while the
+	// request is being sent
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), strings.NewReader(string(body)))
+	if err != nil {
+		return h.fail(networkErrInvalid, err)
+	}
+	for _, line := range strings.Split(headers, "\n") {
+		name, value, found := strings.Cut(strings.TrimRight(line, "\r"), ":")
+		if !found {
+			continue
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	req.Header.Set(jobIDHeader, h.jobID)
+
+	response, err := h.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errNetworkDenied) {
+			return h.fail(networkErrDenied, err)
+		}
+		return h.fail(networkErrFailed, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.next++
+	h.responses[h.next] = response
+	return h.next
+}
+
+func (h *networkHost) response(handle int32) (*http.Response, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	response, ok := h.responses[handle]
+	return response, ok
+}
+
+// responseStatus returns the status code of a response.
+func (h *networkHost) responseStatus(handle int32) int32 {
+	response, ok := h.response(handle)
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown response %d", handle))
+	}
+	return int32(response.StatusCode)
+}
+
+// responseHeaders writes the headers of a response to the buffer as lines of
+// the form "Name: value", and returns their length. Nothing is written if the
+// buffer is too small, so it can be called again with a large enough buffer.
+func (h *networkHost) responseHeaders(_ context.Context, mod api.Module, handle int32, bufPtr, bufLen uint32) int32 {
+	response, ok := h.response(handle)
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown response %d", handle))
+	}
+	names := make([]string, 0, len(response.Header))
+	for name := range response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var headers strings.Builder
+	for _, name := range names {
+		for _, value := range response.Header[name] {
+			headers.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+	return h.writeIfFits(mod, headers.String(), bufPtr, bufLen)
+}
+
+// responseRead reads the body of a response into the buffer, and returns the
+// number of bytes read, which is zero once all of the body has been read.
+func (h *networkHost) responseRead(_ context.Context, mod api.Module, handle int32, bufPtr, bufLen uint32) int32 {
+	response, ok := h.response(handle)
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown response %d", handle))
+	}
+	return h.read(mod, response.Body, bufPtr, bufLen)
+}
+
+// responseClose closes a response.
+func (h *networkHost) responseClose(handle int32) int32 {
+	h.mu.Lock()
+	response, ok := h.responses[handle]
+	delete(h.responses, handle)
+	h.mu.Unlock()
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown response %d", handle))
+	}
+	_ = response.Body.Close()
+	return 0
+}
+
+// connect opens a tcp or udp connection to the address, which is of the form
+// host:port, and returns a handle to it.
+func (h *networkHost) connect(ctx context.Context, mod api.Module, networkPtr, networkLen, addressPtr, addressLen uint32) int32 {
+	network, ok1 := readString(mod, networkPtr, networkLen)
+	address, ok2 := readString(mod, addressPtr, addressLen)
+	if !ok1 || !ok2 {
+		return h.fail(networkErrInvalid, errors.New("connect arguments are out of range"))
+	}
+	if network != "tcp" && network != "udp" {
+		return h.fail(networkErrInvalid, fmt.Errorf("unsupported network %q", network))
+	}
+	// sockets bypass the domain checks of HTTP networking, so they need
+	// full networking
+	if h.network.Type != models.NetworkFull {
+		return h.fail(networkErrDenied, fmt.Errorf("sockets are %w, which needs %s networking",
+			errNetworkDenied, models.NetworkFull))
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return h.fail(networkErrFailed, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.next++
+	h.conns[h.next] = conn
+	return h.next
+}
+
+func (h *networkHost) conn(handle int32) (net.Conn, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conn, ok := h.conns[handle]
+	return conn, ok
+}
+
+// send writes the buffer to a connection, and returns the number of bytes written.
+func (h *networkHost) send(_ context.Context, mod api.Module, handle int32, bufPtr, bufLen uint32) int32 {
+	conn, ok := h.conn(handle)
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown connection %d", handle))
+	}
+	buf, ok := mod.Memory().Read(bufPtr, bufLen)
+	if !ok {
+		return h.fail(networkErrInvalid, errors.New("buffer is out of range"))
+	}
+	// larger buffers are sent partially, as the caller has to handle short writes anyway
+	if len(buf) > maxTransferLen {
+		buf = buf[:maxTransferLen]
+	}
+	n, err := conn.Write(buf)
+	if err != nil {
+		return h.fail(networkErrFailed, err)
+	}
+	return int32(n)
+}
+
+// recv reads from a connection into the buffer, and returns the number of
+// bytes read, which is zero once the connection is closed.
+func (h *networkHost) recv(_ context.Context, mod api.Module, handle int32, bufPtr, bufLen uint32) int32 {
+	conn, ok := h.conn(handle)
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown connection %d", handle))
+	}
+	return h.read(mod, conn, bufPtr, bufLen)
+}
+
+// closeConn closes a connection.
+func (h *networkHost) closeConn(handle int32) int32 {
+	h.mu.Lock()
+	conn, ok := h.conns[handle]
+	delete(h.conns, handle)
+	h.mu.Unlock()
+	if !ok {
+		return h.fail(networkErrBadHandle, fmt.Errorf("unknown connection %d", handle))
+	}
+	_ = conn.Close()
+	return 0
+}
+
+// readLastError writes the message of the last error to the buffer in the
+// same way as response_headers.
+func (h *networkHost) readLastError(_ context.Context, mod api.Module, bufPtr, bufLen uint32) int32 {
+	h.mu.Lock()
+	lastError := h.lastError
+	h.mu.Unlock()
+	return h.writeIfFits(mod, lastError, bufPtr, bufLen)
+}
+
+// read reads from the reader directly into the buffer in the memory of the
+// module, which is checked to be in range before anything is read.
+func (h *networkHost) read(mod api.Module, r io.Reader, bufPtr, bufLen uint32) int32 {
+	buf, ok := mod.Memory().Read(bufPtr, bufLen)
+	if !ok {
+		return h.fail(networkErrInvalid, errors.New("buffer is out of range"))
+	}
+	if len(buf) > maxTransferLen {
+		buf = buf[:maxTransferLen]
+	}
+	n, err := r.Read(buf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return h.fail(networkErrFailed, err)
+	}
+	return int32(n)
+}
+
+func (h *networkHost) writeIfFits(mod api.Module, value string, bufPtr, bufLen uint32) int32 {
+	if len(value) > maxTransferLen {
+		return h.fail(networkErrInvalid, fmt.Errorf("value of %d bytes is too large to return", len(value)))
+	}
+	if uint32(len(value)) <= bufLen && !mod.Memory().WriteString(bufPtr, value) {
+		return h.fail(networkErrInvalid, errors.New("buffer is out of range"))
+	}
+	return int32(len(value))
+}
+
+func readString(mod api.Module, ptr, length uint32) (string, bool) {
+	buf, ok := mod.Memory().Read(ptr, length)
+	return string(buf), ok
+}
@@ -0,0 +1,196 @@
+//go:build unit || !integration
+
+package wasm
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// memoryModule is a module that only exports a memory of one page.
+var memoryModule = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // magic and version
+	0x05, 0x03, 0x01, 0x00, 0x01, // memory section
+	0x07, 0x0a, 0x01, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, // export section
+}
+
+type NetworkSuite struct {
+	suite.Suite
+	ctx    context.Context
+	module api.Module
+	server *httptest.Server
+	offset uint32
+}
+
+func TestNetworkSuite(t *testing.T) {
+	suite.Run(t, new(NetworkSuite))
+}
+
+func (s *NetworkSuite) SetupTest() {
+	s.ctx = context.Background()
+	runtime := wazero.NewRuntime(s.ctx)
+	s.T().Cleanup(func() { _ = runtime.Close(s.ctx) })
+
+	var err error
+	s.module, err = runtime.Instantiate(s.ctx, memoryModule)
+	s.Require().NoError(err)
+	s.offset = 0
+
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "http://example.com/", http.StatusFound)
+		default:
+			w.Header().Set("X-Job", r.Header.Get(jobIDHeader))
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = w.Write([]byte("hello " + r.Method))
+		}
+	}))
+	s.T().Cleanup(s.server.Close)
+}
+
+// write writes the value to the memory of the module, returning its pointer and length.
+func (s *NetworkSuite) write(value string) (uint32, uint32) {
+	ptr := s.offset
+	s.Require().True(s.module.Memory().WriteString(ptr, value))
+	s.offset += uint32(len(value))
+	return ptr, uint32(len(value))
+}
+
+func (s *NetworkSuite) request(host *networkHost, method, url string) int32 {
+	methodPtr, methodLen := s.write(method)
+	urlPtr, urlLen := s.write(url)
+	headersPtr, headersLen := s.write("Accept: text/plain\n")
+	bodyPtr, bodyLen := s.write("")
+	return host.request(s.ctx, s.module, methodPtr, methodLen, urlPtr, urlLen, headersPtr, headersLen, bodyPtr, bodyLen)
+}
+
+func (s *NetworkSuite) newHost(network *models.NetworkConfig) *networkHost {
+	host := newNetworkHost(network, "job-id", zerolog.Nop())
+	s.T().Cleanup(host.close)
+	return host
+}
+
+func (s *NetworkSuite) TestAllowedRequest() {
+	host := s.newHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1"}})
+	handle := s.request(host, http.MethodGet, s.server.URL)
+	s.Require().Greater(handle, int32(0))
+	s.Require().Equal(int32(http.StatusTeapot), host.responseStatus(handle))
+
+	buf := uint32(4096)
+	n := host.responseHeaders(s.ctx, s.module, handle, buf, 1)
+	s.Require().Greater(n, int32(1))
+	s.Require().Equal(n, host.responseHeaders(s.ctx, s.module, handle, buf, uint32(n)))
+	headers, ok := s.module.Memory().Read(buf, uint32(n))
+	s.Require().True(ok)
+	s.Require().Contains(string(headers), "X-Job: job-id\r\n")
+
+	n = host.responseRead(s.ctx, s.module, handle, buf, 1024)
+	s.Require().Equal(int32(len("hello GET")), n)
+	body, ok := s.module.Memory().Read(buf, uint32(n))
+	s.Require().True(ok)
+	s.Require().Equal("hello GET", string(body))
+	s.Require().Equal(int32(0), host.responseRead(s.ctx, s.module, handle, buf, 1024))
+
+	s.Require().Equal(int32(0), host.responseClose(handle))
+	s.Require().Equal(networkErrBadHandle, host.responseStatus(handle))
+}
+
+func (s *NetworkSuite) TestReadOutOfRange() {
+	host := s.newHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1"}})
+	handle := s.request(host, http.MethodGet, s.server.URL)
+	s.Require().Greater(handle, int32(0))
+
+	// the buffer is checked before anything is read, so the body can still be read into a valid buffer
+	s.Require().Equal(networkErrInvalid, host.responseRead(s.ctx, s.module, handle, 0, math.MaxUint32))
+	buf := uint32(4096)
+	n := host.responseRead(s.ctx, s.module, handle, buf, 1024)
+	body, ok := s.module.Memory().Read(buf, uint32(n))
+	s.Require().True(ok)
+	s.Require().Equal("hello GET", string(body))
+}
+
+func (s *NetworkSuite) TestDeniedRequest() {
+	for _, network := range []*models.NetworkConfig{
+		nil,
+		{Type: models.NetworkNone},
+		{Type: models.NetworkHTTP, Domains: []string{"example.com"}},
+	} {
+		host := s.newHost(network)
+		s.Require().Equal(networkErrDenied, s.request(host, http.MethodGet, s.server.URL))
+
+		buf := uint32(4096)
+		n := host.readLastError(s.ctx, s.module, buf, 1024)
+		message, ok := s.module.Memory().Read(buf, uint32(n))
+		s.Require().True(ok)
+		s.Require().Contains(string(message), errNetworkDenied.Error())
+	}
+}
+
+func (s *NetworkSuite) TestDeniedRedirect() {
+	host := s.newHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1"}})
+	s.Require().Equal(networkErrDenied, s.request(host, http.MethodGet, s.server.URL+"/redirect"))
+}
+
+func (s *NetworkSuite) TestInvalidRequest() {
+	host := s.newHost(&models.NetworkConfig{Type: models.NetworkFull})
+	s.Require().Equal(networkErrInvalid, s.request(host, http.MethodGet, "file:///etc/passwd"))
+	s.Require().Equal(networkErrInvalid, host.request(s.ctx, s.module, 0, 1<<20, 0, 0, 0, 0, 0, 0))
+}
+
+func (s *NetworkSuite) TestSockets() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 4)
+		n, _ := conn.Read(buf)
+		_, _ = conn.Write(buf[:n])
+	}()
+
+	networkPtr, networkLen := s.write("tcp")
+	addressPtr, addressLen := s.write(listener.Addr().String())
+
+	host := s.newHost(&models.NetworkConfig{Type: models.NetworkHTTP, Domains: []string{"127.0.0.1"}})
+	s.Require().Equal(networkErrDenied, host.connect(s.ctx, s.module, networkPtr, networkLen, addressPtr, addressLen))
+
+	host = s.newHost(&models.NetworkConfig{Type: models.NetworkFull})
+	handle := host.connect(s.ctx, s.module, networkPtr, networkLen, addressPtr, addressLen)
+	s.Require().Greater(handle, int32(0))
+
+	dataPtr, dataLen := s.write("ping")
+	s.Require().Equal(int32(dataLen), host.send(s.ctx, s.module, handle, dataPtr, dataLen))
+	buf := uint32(4096)
+	s.Require().Equal(int32(dataLen), host.recv(s.ctx, s.module, handle, buf, dataLen))
+	data, ok := s.module.Memory().Read(buf, dataLen)
+	s.Require().True(ok)
+	s.Require().Equal("ping", string(data))
+
+	s.Require().Equal(int32(0), host.closeConn(handle))
+	s.Require().Equal(networkErrBadHandle, host.closeConn(handle))
+}
+
+func (s *NetworkSuite) TestInstantiate() {
+	runtime := wazero.NewRuntime(s.ctx)
+	defer func() { _ = runtime.Close(s.ctx) }()
+	host := s.newHost(nil)
+	s.Require().NoError(host.instantiate(s.ctx, runtime))
+	s.Require().NotNil(runtime.Module(HTTPModuleName).ExportedFunction("request"))
+	s.Require().NotNil(runtime.Module(SocketModuleName).ExportedFunction("connect"))
+}
@@ -0,0 +1,97 @@
+package gcs
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+)
+
+// EnvEmulatorHost is read by the storage client to connect to an emulator,
+// such as fake-gcs-server, without authentication.
+const EnvEmulatorHost = "STORAGE_EMULATOR_HOST"
+
+type ClientProviderParams struct {
+	// AllowedEndpoints are the custom endpoints that are sent application
+	// default credentials. Other custom endpoints are accessed anonymously.
+	AllowedEndpoints []string
+}
+
+// DefaultClientProviderParams reads the endpoints trusted with application
+// default credentials from the configuration.
+func DefaultClientProviderParams() ClientProviderParams {
+	return ClientProviderParams{
+		AllowedEndpoints: config.GetObjectStorageConfig().GCSEndpoints,
+	}
+}
+
+type ClientProvider struct {
+	params    ClientProviderParams
+	clients   map[string]*storage.Client
+	clientsMu sync.Mutex
+}
+
+func NewClientProvider(params ClientProviderParams) *ClientProvider {
+	return &ClientProvider{
+		params:  params,
+		clients: make(map[string]*storage.Client),
+	}
+}
+
+// IsInstalled returns true if Google application default credentials are
+// available, or an emulator is configured.
+func (p *ClientProvider) IsInstalled(ctx context.Context) bool {
+	if os.Getenv(EnvEmulatorHost) != "" {
+		return true
+	}
+	if _, err := google.FindDefaultCredentials(ctx, storage.ScopeReadWrite); err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("Failed to find Google application default credentials")
+		return false
+	}
+	return true
+}
+
+// GetClient returns a client for the given endpoint, or for Google Cloud
+// Storage if the endpoint is empty. Custom endpoints are accessed without
+// authentication unless they are allowed to be sent application default
+// credentials, and the credentials are available.
+func (p *ClientProvider) GetClient(ctx context.Context, endpoint string) (*storage.Client, error) {
+	p.clientsMu.Lock()
+	defer p.clientsMu.Unlock()
+	if client, ok := p.clients[endpoint]; ok {
+		return client, nil
+	}
+
+	var opts []option.ClientOption
+	if endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+		if !p.isAllowedEndpoint(endpoint) {
+			opts = append(opts, option.WithoutAuthentication())
+		} else if _, err := google.FindDefaultCredentials(ctx, storage.ScopeReadWrite); err != nil {
+			opts = append(opts, option.WithoutAuthentication())
+		}
+	}
+	// the client outlives the request context
+	client, err := storage.NewClient(context.Background(), opts...) //nolint:contextcheck
+	if err != nil {
+		return nil, err
+	}
+	p.clients[endpoint] = client
+	return client, nil
+}
+
+// isAllowedEndpoint returns true for the custom endpoints that application
+// default credentials can be sent to.
+func (p *ClientProvider) isAllowedEndpoint(endpoint string) bool {
+	return slices.ContainsFunc(p.params.AllowedEndpoints, func(allowed string) bool {
+		return strings.TrimSuffix(allowed, "/") == strings.TrimSuffix(endpoint, "/")
+	})
+}
@@ -0,0 +1,27 @@
+//go:build unit || !integration
+
+package gcs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientProviderTestSuite struct {
+	suite.Suite
+}
+
+func TestClientProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientProviderTestSuite))
+}
+
+func (s *ClientProviderTestSuite) TestIsAllowedEndpoint() {
+	provider := NewClientProvider(ClientProviderParams{
+		AllowedEndpoints: []string{"https://trusted.example.com/"},
+	})
+	s.True(provider.isAllowedEndpoint("https://trusted.example.com"))
+	s.True(provider.isAllowedEndpoint("https://trusted.example.com/"))
+	s.False(provider.isAllowedEndpoint("https://attacker.example.com"))
+	s.False(NewClientProvider(ClientProviderParams{}).isAllowedEndpoint("https://trusted.example.com"))
+}
@@ -0,0 +1,87 @@
+package gcs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type SourceSpec struct {
+	Bucket string
+	Key    string
+	Filter string
+	// Endpoint overrides the storage API endpoint, e.g. for an emulator
+	Endpoint   string
+	Generation int64
+}
+
+func (c SourceSpec) Validate() error {
+	if c.Bucket == "" {
+		return errors.New("invalid gcs storage params: bucket cannot be empty")
+	}
+	return nil
+}
+
+func (c SourceSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+type PublisherSpec struct {
+	Bucket   string `json:"Bucket"`
+	Key      string `json:"Key"`
+	Endpoint string `json:"Endpoint"`
+}
+
+func (c PublisherSpec) Validate() error {
+	if c.Bucket == "" {
+		return fmt.Errorf("invalid gcs params. bucket cannot be empty")
+	}
+	if c.Key == "" {
+		return fmt.Errorf("invalid gcs params. key cannot be empty")
+	}
+	return nil
+}
+
+func (c PublisherSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+func DecodeSourceSpec(spec *models.SpecConfig) (SourceSpec, error) {
+	if !spec.IsType(models.StorageSourceGCS) {
+		return SourceSpec{}, errors.New(
+			"invalid storage source type. expected " + models.StorageSourceGCS + ", but received: " + spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return SourceSpec{}, errors.New("invalid storage source params. cannot be nil")
+	}
+
+	var c SourceSpec
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+func DecodePublisherSpec(spec *models.SpecConfig) (PublisherSpec, error) {
+	if !spec.IsType(models.PublisherGCS) {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher type. expected %s, but received: %s",
+			models.PublisherGCS, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher params. cannot be nil")
+	}
+
+	var c PublisherSpec
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
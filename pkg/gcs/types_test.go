@@ -0,0 +1,77 @@
+//go:build unit || !integration
+
+package gcs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ParamsTestSuite struct {
+	suite.Suite
+}
+
+func TestParamsTestSuite(t *testing.T) {
+	suite.Run(t, new(ParamsTestSuite))
+}
+
+func (s *ParamsTestSuite) TestDecodePublisherLowerCase() {
+	decoded, err := DecodePublisherSpec(&models.SpecConfig{
+		Type: models.PublisherGCS,
+		Params: map[string]interface{}{
+			"bucket": "results",
+			"key":    "{jobID}.tar.gz",
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(PublisherSpec{Bucket: "results", Key: "{jobID}.tar.gz"}, decoded)
+}
+
+func (s *ParamsTestSuite) TestDecodePublisherInvalid() {
+	_, err := DecodePublisherSpec(&models.SpecConfig{
+		Type:   models.PublisherGCS,
+		Params: map[string]interface{}{"bucket": "results"},
+	})
+	s.Require().Error(err)
+
+	_, err = DecodePublisherSpec(&models.SpecConfig{
+		Type:   models.PublisherS3,
+		Params: PublisherSpec{Bucket: "results", Key: "key"}.ToMap(),
+	})
+	s.Require().Error(err)
+}
+
+// TestDecodeSourceJSON checks that the generation survives a JSON round trip,
+// where numbers are decoded as float64.
+func (s *ParamsTestSuite) TestDecodeSourceJSON() {
+	expected := SourceSpec{
+		Bucket:     "inputs",
+		Key:        "data/",
+		Filter:     ".*\\.csv",
+		Endpoint:   "http://127.0.0.1:4443/storage/v1/",
+		Generation: 1700000000123456,
+	}
+	bytes, err := json.Marshal(&models.SpecConfig{
+		Type:   models.StorageSourceGCS,
+		Params: expected.ToMap(),
+	})
+	s.Require().NoError(err)
+
+	var spec models.SpecConfig
+	s.Require().NoError(json.Unmarshal(bytes, &spec))
+	decoded, err := DecodeSourceSpec(&spec)
+	s.Require().NoError(err)
+	s.Equal(expected, decoded)
+}
+
+func (s *ParamsTestSuite) TestDecodeSourceMissingBucket() {
+	_, err := DecodeSourceSpec(&models.SpecConfig{
+		Type:   models.StorageSourceGCS,
+		Params: SourceSpec{Key: "data/"}.ToMap(),
+	})
+	s.Require().Error(err)
+}
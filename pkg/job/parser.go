@@ -17,8 +17,13 @@ import (
 const defaultStoragePath = "/inputs"
 
 const (
-	s3Prefix   = "s3"
-	ipfsPrefix = "ipfs"
+	s3Prefix    = "s3"
+	azurePrefix = "az"
+	gcsPrefix   = "gs"
+	jobPrefix   = "job"
+	ipfsPrefix  = "ipfs"
+	httpPrefix  = "http"
+	httpsPrefix = "https"
 )
 
 //nolint:gocyclo
@@ -68,6 +73,70 @@ func ParseStorageString(sourceURI, destinationPath string, options map[string]st
 				return model.StorageSpec{}, fmt.Errorf("unknown option %s", key)
 			}
 		}
+	case azurePrefix:
+		res = model.StorageSpec{
+			StorageSource: model.StorageSourceAzure,
+			Azure: &model.AzureStorageSpec{
+				Container: parsedURI.Host,
+				Key:       strings.TrimLeft(parsedURI.Path, "/"),
+			},
+		}
+		for key, value := range options {
+			switch key {
+			case "account":
+				res.Azure.Account = value
+			case "endpoint":
+				res.Azure.Endpoint = value
+			case "filter":
+				res.Azure.Filter = value
+			case "etag":
+				res.Azure.ETag = value
+			default:
+				return model.StorageSpec{}, fmt.Errorf("unknown option %s", key)
+			}
+		}
+	case gcsPrefix:
+		res = model.StorageSpec{
+			StorageSource: model.StorageSourceGCS,
+			GCS: &model.GCSStorageSpec{
+				Bucket: parsedURI.Host,
+				Key:    strings.TrimLeft(parsedURI.Path, "/"),
+			},
+		}
+		for key, value := range options {
+			switch key {
+			case "endpoint":
+				res.GCS.Endpoint = value
+			case "filter":
+				res.GCS.Filter = value
+			case "generation":
+				generation, parseErr := strconv.ParseInt(value, 10, 64)
+				if parseErr != nil {
+					return model.StorageSpec{}, fmt.Errorf("failed to parse generation option: %s", parseErr)
+				}
+				res.GCS.Generation = generation
+			default:
+				return model.StorageSpec{}, fmt.Errorf("unknown option %s", key)
+			}
+		}
+	case jobPrefix:
+		res = model.StorageSpec{
+			StorageSource: model.StorageSourceJobResult,
+			JobResult: &model.JobResultStorageSpec{
+				JobID: parsedURI.Host,
+				Path:  strings.TrimLeft(parsedURI.Path, "/"),
+			},
+		}
+		for key, value := range options {
+			switch key {
+			case "execution", "executionID", "execution-id", "execution_id":
+				res.JobResult.ExecutionID = value
+			case "publisher":
+				res.JobResult.Publisher = value
+			default:
+				return model.StorageSpec{}, fmt.Errorf("unknown option %s", key)
+			}
+		}
 	case "file":
 		res = model.StorageSpec{
 			StorageSource: model.StorageSourceLocalDirectory,
@@ -189,10 +258,38 @@ func PublisherStringToPublisherSpec(destinationURI string, options map[string]in
 			Type:   model.PublisherS3,
 			Params: options,
 		}
+	case azurePrefix:
+		if _, ok := options["container"]; !ok {
+			options["container"] = parsedURI.Host
+		}
+		if _, ok := options["key"]; !ok {
+			options["key"] = strings.TrimLeft(parsedURI.Path, "/")
+		}
+		res = model.PublisherSpec{
+			Type:   model.PublisherAzure,
+			Params: options,
+		}
+	case gcsPrefix:
+		if _, ok := options["bucket"]; !ok {
+			options["bucket"] = parsedURI.Host
+		}
+		if _, ok := options["key"]; !ok {
+			options["key"] = strings.TrimLeft(parsedURI.Path, "/")
+		}
+		res = model.PublisherSpec{
+			Type:   model.PublisherGCS,
+			Params: options,
+		}
 	case "local":
 		res = model.PublisherSpec{
 			Type: model.PublisherLocal,
 		}
+	case httpPrefix, httpsPrefix:
+		options["URL"] = destinationURI
+		res = model.PublisherSpec{
+			Type:   model.PublisherHTTP,
+			Params: options,
+		}
 	default:
 		return model.PublisherSpec{}, fmt.Errorf("unknown publisher type: %s", parsedURI.Scheme)
 	}
@@ -267,6 +364,34 @@ func ParsePublisherString(publisher string) (*models.SpecConfig, error) {
 			Type:   models.PublisherS3,
 			Params: options,
 		}
+	case azurePrefix:
+		if _, ok := options["container"]; !ok {
+			options["container"] = parsedURI.Host
+		}
+		if _, ok := options["key"]; !ok {
+			options["key"] = strings.TrimLeft(parsedURI.Path, "/")
+		}
+		res = models.SpecConfig{
+			Type:   models.PublisherAzure,
+			Params: options,
+		}
+	case gcsPrefix:
+		if _, ok := options["bucket"]; !ok {
+			options["bucket"] = parsedURI.Host
+		}
+		if _, ok := options["key"]; !ok {
+			options["key"] = strings.TrimLeft(parsedURI.Path, "/")
+		}
+		res = models.SpecConfig{
+			Type:   models.PublisherGCS,
+			Params: options,
+		}
+	case httpPrefix, httpsPrefix:
+		options["URL"] = destinationURI
+		res = models.SpecConfig{
+			Type:   models.PublisherHTTP,
+			Params: options,
+		}
 	default:
 		return nil, fmt.Errorf("unknown publisher type: %s", parsedURI.Scheme)
 	}
@@ -72,6 +72,73 @@ func TestParseStorageString(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "azure with account",
+			source: "az://myContainer/dir/",
+			options: map[string]string{
+				"account": "myaccount",
+				"filter":  ".*\\.csv",
+			},
+			expected: model.StorageSpec{
+				StorageSource: model.StorageSourceAzure,
+				Name:          "az://myContainer/dir/",
+				Path:          "/inputs",
+				Azure: &model.AzureStorageSpec{
+					Account:   "myaccount",
+					Container: "myContainer",
+					Key:       "dir/",
+					Filter:    ".*\\.csv",
+				},
+			},
+		},
+		{
+			name:   "gcs with generation",
+			source: "gs://myBucket/dir/file-001.txt",
+			options: map[string]string{
+				"generation": "1700000000000000",
+			},
+			expected: model.StorageSpec{
+				StorageSource: model.StorageSourceGCS,
+				Name:          "gs://myBucket/dir/file-001.txt",
+				Path:          "/inputs",
+				GCS: &model.GCSStorageSpec{
+					Bucket:     "myBucket",
+					Key:        "dir/file-001.txt",
+					Generation: 1700000000000000,
+				},
+			},
+		},
+		{
+			name:    "gcs with invalid generation",
+			source:  "gs://myBucket/dir/file-001.txt",
+			options: map[string]string{"generation": "latest"},
+			error:   true,
+		},
+		{
+			name:   "job result",
+			source: "job://j-51225160/outputs/data.csv",
+			options: map[string]string{
+				"execution": "e-8f1b2c3d",
+				"publisher": "ipfs",
+			},
+			expected: model.StorageSpec{
+				StorageSource: model.StorageSourceJobResult,
+				Name:          "job://j-51225160/outputs/data.csv",
+				Path:          "/inputs",
+				JobResult: &model.JobResultStorageSpec{
+					JobID:       "j-51225160",
+					ExecutionID: "e-8f1b2c3d",
+					Publisher:   "ipfs",
+					Path:        "outputs/data.csv",
+				},
+			},
+		},
+		{
+			name:    "job result with unknown option",
+			source:  "job://j-51225160",
+			options: map[string]string{"region": "us-east-1"},
+			error:   true,
+		},
 		{
 			name:   "empty",
 			source: "",
@@ -147,6 +214,46 @@ func TestParsePublisherString(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "http",
+			publisherURI: "https://example.com/results/{jobID}",
+			options: map[string]interface{}{
+				"secret": "token",
+			},
+			expected: model.PublisherSpec{
+				Type: model.PublisherHTTP,
+				Params: map[string]interface{}{
+					"URL":    "https://example.com/results/{jobID}",
+					"secret": "token",
+				},
+			},
+		},
+		{
+			name:         "azure",
+			publisherURI: "az://myContainer/results/{executionID}.tar.gz",
+			options: map[string]interface{}{
+				"account": "myaccount",
+			},
+			expected: model.PublisherSpec{
+				Type: model.PublisherAzure,
+				Params: map[string]interface{}{
+					"container": "myContainer",
+					"key":       "results/{executionID}.tar.gz",
+					"account":   "myaccount",
+				},
+			},
+		},
+		{
+			name:         "gcs",
+			publisherURI: "gs://myBucket/results/{executionID}.tar.gz",
+			expected: model.PublisherSpec{
+				Type: model.PublisherGCS,
+				Params: map[string]interface{}{
+					"bucket": "myBucket",
+					"key":    "results/{executionID}.tar.gz",
+				},
+			},
+		},
 		{
 			name:         "empty",
 			publisherURI: "",
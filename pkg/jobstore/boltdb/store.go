@@ -34,6 +34,7 @@ const (
 	BucketTagsIndex        = "idx_tags"        // tag -> Job id
 	BucketProgressIndex    = "idx_inprogress"  // job-id -> {}
 	BucketNamespacesIndex  = "idx_namespaces"  // namespace -> Job id
+	BucketOwnersIndex      = "idx_owners"      // owner -> Job id
 	BucketExecutionsIndex  = "idx_executions"  // execution-id -> Job id
 	BucketEvaluationsIndex = "idx_evaluations" // evaluation-id -> Job id
 
@@ -51,6 +52,7 @@ type BoltJobStore struct {
 
 	inProgressIndex  *Index
 	namespacesIndex  *Index
+	ownersIndex      *Index
 	tagsIndex        *Index
 	executionsIndex  *Index
 	evaluationsIndex *Index
@@ -83,6 +85,7 @@ func WithClock(clock clock.Clock) Option {
 //	TagsIndex        = tag -> Job id
 //	ProgressIndex    = job-id -> {}
 //	NamespacesIndex  = namespace -> Job id
+//	OwnersIndex      = owner -> Job id
 //	ExecutionsIndex  = execution-id -> Job id
 //	EvaluationsIndex = evaluation-id -> Job id
 func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
@@ -115,6 +118,7 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 			BucketTagsIndex,
 			BucketProgressIndex,
 			BucketNamespacesIndex,
+			BucketOwnersIndex,
 			BucketExecutionsIndex,
 			BucketEvaluationsIndex,
 		}
@@ -130,6 +134,7 @@ func NewBoltJobStore(dbPath string, options ...Option) (*BoltJobStore, error) {
 
 	store.inProgressIndex = NewIndex(BucketProgressIndex)
 	store.namespacesIndex = NewIndex(BucketNamespacesIndex)
+	store.ownersIndex = NewIndex(BucketOwnersIndex)
 	store.tagsIndex = NewIndex(BucketTagsIndex)
 	store.executionsIndex = NewIndex(BucketExecutionsIndex)
 	store.evaluationsIndex = NewIndex(BucketEvaluationsIndex)
@@ -317,6 +322,11 @@ func (b *BoltJobStore) getJobs(tx *bolt.Tx, query jobstore.JobQuery) (*jobstore.
 		return nil, err
 	}
 
+	jobSet, err = b.getJobsFilterOwner(tx, jobSet, query.Owner)
+	if err != nil {
+		return nil, err
+	}
+
 	jobSet, err = b.getJobsIncludeTags(tx, jobSet, query.IncludeTags)
 	if err != nil {
 		return nil, err
@@ -413,6 +423,32 @@ func (b *BoltJobStore) getJobsInitialSet(tx *bolt.Tx, query jobstore.JobQuery) (
 	return jobSet, nil
 }
 
+// getJobsFilterOwner filters out jobs that were not submitted by the owner specified in the query.
+func (b *BoltJobStore) getJobsFilterOwner(tx *bolt.Tx, jobSet map[string]struct{}, owner string) (map[string]struct{}, error) {
+	if owner == "" {
+		return jobSet, nil
+	}
+
+	ids, err := b.ownersIndex.List(tx, []byte(owner))
+	if err != nil {
+		return nil, err
+	}
+
+	ownerSet := make(map[string]struct{}, len(ids))
+	for _, k := range ids {
+		ownerSet[string(k)] = struct{}{}
+	}
+
+	// remove jobs that are not owned by the owner
+	for k := range jobSet {
+		if _, ok := ownerSet[k]; !ok {
+			delete(jobSet, k)
+		}
+	}
+
+	return jobSet, nil
+}
+
 // getJobsIncludeTags filters out jobs that don't have ANY of the tags specified in the query.
 func (b *BoltJobStore) getJobsIncludeTags(tx *bolt.Tx, jobSet map[string]struct{}, tags []string) (map[string]struct{}, error) {
 	if len(tags) == 0 {
@@ -721,6 +757,12 @@ func (b *BoltJobStore) createJob(tx *bolt.Tx, job models.Job) error {
 		return err
 	}
 
+	if job.Owner != "" {
+		if err = b.ownersIndex.Add(tx, jobIDKey, []byte(job.Owner)); err != nil {
+			return err
+		}
+	}
+
 	// Write sentinels keys for specific tags
 	for tag := range job.Labels {
 		tagBytes := []byte(strings.ToLower(tag))
@@ -768,6 +810,12 @@ func (b *BoltJobStore) deleteJob(tx *bolt.Tx, jobID string) error {
 		return err
 	}
 
+	if job.Owner != "" {
+		if err = b.ownersIndex.Remove(tx, jobIDKey, []byte(job.Owner)); err != nil {
+			return err
+		}
+	}
+
 	// Delete sentinels keys for specific tags
 	for tag := range job.Labels {
 		tagBytes := []byte(strings.ToLower(tag))
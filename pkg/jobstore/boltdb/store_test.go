@@ -46,6 +46,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 	jobFixtures := []struct {
 		id              string
 		client          string
+		owner           string
 		tags            map[string]string
 		jobStates       []models.JobStateType
 		executionStates []models.ExecutionStateType
@@ -53,6 +54,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		{
 			id:              "110",
 			client:          "client1",
+			owner:           "alice",
 			tags:            map[string]string{"gpu": "true", "fast": "true"},
 			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
 			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
@@ -60,6 +62,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		{
 			id:              "120",
 			client:          "client2",
+			owner:           "bob",
 			tags:            map[string]string{},
 			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning, models.JobStateTypeStopped},
 			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted, models.ExecutionStateCancelled},
@@ -67,6 +70,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		{
 			id:              "130",
 			client:          "client3",
+			owner:           "alice",
 			tags:            map[string]string{"slow": "true", "max": "10"},
 			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
 			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
@@ -74,6 +78,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		{
 			id:              "140",
 			client:          "client4",
+			owner:           "",
 			tags:            map[string]string{"max": "10"},
 			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
 			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
@@ -81,6 +86,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		{
 			id:              "150",
 			client:          "client5",
+			owner:           "bob",
 			tags:            map[string]string{"max": "10"},
 			jobStates:       []models.JobStateType{models.JobStateTypePending, models.JobStateTypeRunning},
 			executionStates: []models.ExecutionStateType{models.ExecutionStateAskForBid, models.ExecutionStateAskForBidAccepted},
@@ -95,6 +101,7 @@ func (s *BoltJobstoreTestSuite) SetupTest() {
 		job.ID = fixture.id
 		job.Labels = fixture.tags
 		job.Namespace = fixture.client
+		job.Owner = fixture.owner
 		err := s.store.CreateJob(s.ctx, *job)
 		s.Require().NoError(err)
 
@@ -301,6 +308,37 @@ func (s *BoltJobstoreTestSuite) TestSearchJobs() {
 		require.NotContains(t, jobs[0].Labels, "slow")
 	})
 
+	s.T().Run("by owner", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			Owner:  "alice",
+			SortBy: "created_at",
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 2, len(jobs))
+		require.Equal(t, "110", jobs[0].ID)
+		require.Equal(t, "130", jobs[1].ID)
+	})
+
+	s.T().Run("by owner and client ID", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			Namespace: "client2",
+			Owner:     "bob",
+		})
+		require.NoError(t, err)
+		jobs := response.Jobs
+		require.Equal(t, 1, len(jobs))
+		require.Equal(t, "120", jobs[0].ID)
+	})
+
+	s.T().Run("by unknown owner", func(t *testing.T) {
+		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
+			Owner: "mallory",
+		})
+		require.NoError(t, err)
+		require.Empty(t, response.Jobs)
+	})
+
 	s.T().Run("basic selectors", func(t *testing.T) {
 		response, err := s.store.GetJobs(s.ctx, jobstore.JobQuery{
 			Namespace: "client1",
@@ -11,6 +11,9 @@ import (
 type JobQuery struct {
 	Namespace string
 
+	// Owner restricts the results to jobs submitted by the given principal.
+	Owner string
+
 	// IncludeTags and ExcludeTags are used primarily by the requester's list API.
 	// In the orchestrator API, we insted use the Selector field to filter jobs.
 	IncludeTags []string
@@ -60,6 +60,8 @@ func (s *ExponentialBackoffSuite) TestBackoffDurationElapsed() {
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
+			s.Equal(tc.expected, s.backoff.Duration(tc.attempts))
+
 			ctx := context.Background()
 			startTime := time.Now()
 			s.backoff.Backoff(ctx, tc.attempts)
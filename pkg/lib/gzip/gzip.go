@@ -94,3 +94,49 @@ func DecompressInPlace(tarGzPath string) (string, error) {
 	}
 	return uncompressedPath, nil
 }
+
+// Compress writes the contents of sourceDir as a gzipped tarball to w. Entry
+// names are relative to sourceDir.
+func Compress(sourceDir string, w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	defer gw.Close()
+
+	tarWriter := tar.NewWriter(gw)
+	defer tarWriter.Close()
+
+	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Get the relative path for the file
+		relpath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relpath
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		// Open the file for reading.
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		// Write the file contents to the GZIP archive.
+		_, err = io.Copy(tarWriter, file)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,18 @@
+package secrets
+
+import "context"
+
+type namespaceContextKey struct{}
+
+// ContextWithNamespace returns a copy of ctx carrying the namespace of the job
+// that secrets are resolved for, for drivers that are not given the job.
+func ContextWithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceContextKey{}, namespace)
+}
+
+// NamespaceFromContext returns the namespace attached to ctx, or an empty
+// string if there is none, in which case only shared secrets are available.
+func NamespaceFromContext(ctx context.Context) string {
+	namespace, _ := ctx.Value(namespaceContextKey{}).(string)
+	return namespace
+}
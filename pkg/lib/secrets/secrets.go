@@ -0,0 +1,293 @@
+// Package secrets resolves named secrets referenced by job specs into their
+// values on the node that needs them, so that credentials never have to be
+// embedded in the job itself.
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+// EnvPrefix is prepended to the normalised secret name to find the
+// environment variable holding its value.
+const EnvPrefix = "BACALHAU_SECRET_"
+
+// Resolver looks up the value of a named secret.
+type Resolver interface {
+	Resolve(ctx context.Context, name string) (string, error)
+}
+
+// EnvResolver resolves secrets from the node's environment. A secret named
+// "github-token" is read from BACALHAU_SECRET_GITHUB_TOKEN.
+type EnvResolver struct {
+	lookup func(string) (string, bool)
+}
+
+func NewEnvResolver() *EnvResolver {
+	return &EnvResolver{lookup: os.LookupEnv}
+}
+
+func (r *EnvResolver) Resolve(_ context.Context, name string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	key := EnvName(name)
+	value, ok := r.lookup(key)
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret %q is not configured on this node (expected %s)", name, key)
+	}
+	return value, nil
+}
+
+// EnvName returns the environment variable that holds the named secret. The
+// secrets of a namespace, named "<namespace>/<name>", are read from
+// BACALHAU_SECRET_<NAMESPACE>__<NAME>.
+func EnvName(name string) string {
+	parts := strings.Split(name, namespaceSeparator)
+	for i, part := range parts {
+		parts[i] = normalize(part)
+	}
+	return EnvPrefix + strings.Join(parts, "__")
+}
+
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, name)
+}
+
+const namespaceSeparator = "/"
+
+// ValidateName checks that a secret name, optionally prefixed by its
+// namespace, maps to an environment variable that no other secret maps to.
+// Names that normalize to "__", or to a leading or trailing "_", are rejected
+// as they could be mistaken for the separator between a namespace and a name.
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("secret name cannot be empty")
+	}
+	parts := strings.Split(name, namespaceSeparator)
+	if len(parts) > 2 {
+		return fmt.Errorf("invalid secret name %q: only one %q is allowed", name, namespaceSeparator)
+	}
+	for _, part := range parts {
+		normalized := normalize(part)
+		if normalized == "" || strings.Contains(normalized, "__") ||
+			strings.HasPrefix(normalized, "_") || strings.HasSuffix(normalized, "_") {
+			return fmt.Errorf("invalid secret name %q: names must start and end with a letter or digit, "+
+				"and cannot have consecutive symbols", name)
+		}
+	}
+	return nil
+}
+
+// Policy is the node's configuration of the secrets jobs can reference, and
+// of the URLs each of them can be sent to.
+type Policy struct {
+	grants map[string]grant
+}
+
+type grant struct {
+	shared bool
+	urls   []*url.URL
+}
+
+func NewPolicy(configs []types.SecretConfig) (*Policy, error) {
+	p := &Policy{grants: make(map[string]grant, len(configs))}
+	for _, cfg := range configs {
+		if err := ValidateName(cfg.Name); err != nil {
+			return nil, err
+		}
+		if _, ok := p.grants[cfg.Name]; ok {
+			return nil, fmt.Errorf("secret %q is configured more than once", cfg.Name)
+		}
+		if cfg.Shared && strings.Contains(cfg.Name, namespaceSeparator) {
+			return nil, fmt.Errorf("secret %q belongs to a namespace and cannot be shared", cfg.Name)
+		}
+		g := grant{shared: cfg.Shared}
+		for _, prefix := range cfg.URLs {
+			u, err := ParseURL(prefix)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URL of secret %q: %w", cfg.Name, err)
+			}
+			g.urls = append(g.urls, u)
+		}
+		p.grants[cfg.Name] = g
+	}
+	return p, nil
+}
+
+// NewPolicyFromConfig returns the policy configured for the node.
+func NewPolicyFromConfig() (*Policy, error) {
+	configs, err := config.GetSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secrets configuration: %w", err)
+	}
+	return NewPolicy(configs)
+}
+
+// NewClientPolicyFromConfig returns the policy of the secrets the client
+// sends when downloading results.
+func NewClientPolicyFromConfig() (*Policy, error) {
+	configs, err := config.GetUserSecrets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load secrets configuration: %w", err)
+	}
+	return NewPolicy(configs)
+}
+
+// allows returns true if the secret can be sent to target.
+func (g grant) allows(target *url.URL) bool {
+	for _, prefix := range g.urls {
+		if matchesPrefix(prefix, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPrefix returns true if target has the scheme and host of prefix, and
+// its path is the path of prefix or below it.
+func matchesPrefix(prefix, target *url.URL) bool {
+	if !strings.EqualFold(prefix.Scheme, target.Scheme) || !strings.EqualFold(prefix.Host, target.Host) {
+		return false
+	}
+	prefixPath := strings.TrimSuffix(path.Clean("/"+prefix.Path), "/")
+	targetPath := path.Clean("/" + target.Path)
+	return prefixPath == "" || targetPath == prefixPath || strings.HasPrefix(targetPath, prefixPath+"/")
+}
+
+// ParseURL parses an absolute URL, or an scp-like address such as
+// git@github.com:org/repo.git, which is parsed as an ssh URL.
+func ParseURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		if host, repoPath, ok := strings.Cut(raw, ":"); ok && !strings.Contains(host, "/") {
+			raw = "ssh://" + host + "/" + strings.TrimPrefix(repoPath, "/")
+		}
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", raw)
+	}
+	return u, nil
+}
+
+// NamespacedResolver resolves the secrets referenced by jobs in a namespace.
+// A secret configured for the namespace, named "<namespace>/<name>", takes
+// precedence over the shared node-wide secret with the same name. Node-wide
+// secrets that are not shared are never resolved for jobs.
+type NamespacedResolver struct {
+	resolver  Resolver
+	policy    *Policy
+	namespace string
+}
+
+// ForNamespace returns a resolver for the secrets of jobs in namespace. The
+// secrets jobs can use, and where they can be sent, are set by policy.
+func ForNamespace(resolver Resolver, policy *Policy, namespace string) *NamespacedResolver {
+	if policy == nil {
+		policy = &Policy{}
+	}
+	return &NamespacedResolver{resolver: resolver, policy: policy, namespace: namespace}
+}
+
+// ResolveFor returns the value of the named secret, to be sent to target. It
+// fails if the secret is not granted to the namespace, or cannot be sent to
+// target.
+func (r *NamespacedResolver) ResolveFor(ctx context.Context, name, target string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if strings.Contains(name, namespaceSeparator) {
+		return "", fmt.Errorf("secret name %q cannot contain %q", name, namespaceSeparator)
+	}
+
+	candidates := []string{name}
+	if r.namespace != "" {
+		candidates = []string{r.namespace + namespaceSeparator + name, name}
+	}
+	for _, candidate := range candidates {
+		g, ok := r.policy.grants[candidate]
+		if !ok || (candidate == name && !g.shared) {
+			continue
+		}
+		u, err := ParseURL(target)
+		if err != nil || !g.allows(u) {
+			return "", fmt.Errorf("secret %q cannot be sent to %s", name, redact(target))
+		}
+		return r.resolver.Resolve(ctx, candidate)
+	}
+	return "", fmt.Errorf("secret %q is not available to jobs in namespace %q", name, r.namespace)
+}
+
+// ClientResolver resolves the client's own secrets, which results published
+// with a secret are downloaded with. The URLs of results are chosen by compute
+// nodes, so a secret is only sent to the URLs the client configured for it.
+type ClientResolver struct {
+	resolver Resolver
+	policy   *Policy
+}
+
+// ForClient returns a resolver for the client's secrets, which can be sent
+// to the URLs set by policy.
+func ForClient(resolver Resolver, policy *Policy) *ClientResolver {
+	if policy == nil {
+		policy = &Policy{}
+	}
+	return &ClientResolver{resolver: resolver, policy: policy}
+}
+
+// ResolveFor returns the value of the named secret, to be sent to target. It
+// fails if the secret is not configured, or cannot be sent to target.
+func (r *ClientResolver) ResolveFor(ctx context.Context, name, target string) (string, error) {
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	g, ok := r.policy.grants[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q is not configured to be sent when downloading results", name)
+	}
+	u, err := ParseURL(target)
+	if err != nil || !g.allows(u) {
+		return "", fmt.Errorf("secret %q cannot be sent to %s", name, redact(target))
+	}
+	return r.resolver.Resolve(ctx, name)
+}
+
+// redact removes any credentials embedded in a URL.
+func redact(target string) string {
+	u, err := ParseURL(target)
+	if err != nil {
+		return "an invalid URL"
+	}
+	return u.Redacted()
+}
+
+// StaticResolver resolves secrets from a fixed map. It is mostly useful in
+// tests and for embedding.
+type StaticResolver map[string]string
+
+func (r StaticResolver) Resolve(_ context.Context, name string) (string, error) {
+	value, ok := r[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	return value, nil
+}
+
+var _ Resolver = (*EnvResolver)(nil)
+var _ Resolver = StaticResolver(nil)
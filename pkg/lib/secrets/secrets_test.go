@@ -0,0 +1,141 @@
+//go:build unit || !integration
+
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+)
+
+func TestEnvName(t *testing.T) {
+	require.Equal(t, "BACALHAU_SECRET_GITHUB_TOKEN", EnvName("github-token"))
+	require.Equal(t, "BACALHAU_SECRET_MY_REGISTRY_IO", EnvName("my.registry.io"))
+	require.Equal(t, "BACALHAU_SECRET_TEAM_A__REGISTRY", EnvName("team-a/registry"))
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"github-token", "my.registry.io", "team-a/registry", "a1"} {
+		require.NoError(t, ValidateName(name), name)
+	}
+	// names that could be mistaken for the secret of another namespace are rejected
+	for _, name := range []string{"", "team-a__registry", "team--a/registry", "-token", "token-", "a/b/c", "/token", "team-a/"} {
+		require.Error(t, ValidateName(name), name)
+	}
+}
+
+func TestNamespacedResolver(t *testing.T) {
+	ctx := context.Background()
+	policy, err := NewPolicy([]types.SecretConfig{
+		{Name: "team-a/registry", URLs: []string{"https://ghcr.io/team-a/"}},
+		{Name: "registry", Shared: true, URLs: []string{"https://ghcr.io/"}},
+		{Name: "other", Shared: true, URLs: []string{"https://example.com/org"}},
+		{Name: "private", URLs: []string{"https://example.com/"}},
+	})
+	require.NoError(t, err)
+	static := StaticResolver{"team-a/registry": "a", "registry": "node", "other": "o", "private": "p"}
+	r := ForNamespace(static, policy, "team-a")
+
+	value, err := r.ResolveFor(ctx, "registry", "https://ghcr.io/team-a/image")
+	require.NoError(t, err)
+	require.Equal(t, "a", value)
+
+	value, err = r.ResolveFor(ctx, "other", "https://example.com/org/repo")
+	require.NoError(t, err)
+	require.Equal(t, "o", value)
+
+	value, err = ForNamespace(static, policy, "team-b").ResolveFor(ctx, "registry", "https://ghcr.io/team-b/image")
+	require.NoError(t, err)
+	require.Equal(t, "node", value)
+
+	// secrets are only sent to their URLs
+	for _, target := range []string{
+		"https://ghcr.io/team-b/image",
+		"http://ghcr.io/team-a/image",
+		"https://ghcr.io.evil.com/team-a/image",
+		"https://ghcr.io/team-a-other/image",
+		"https://ghcr.io/team-a/../team-b/image",
+	} {
+		_, err = r.ResolveFor(ctx, "registry", target)
+		require.Error(t, err, target)
+	}
+	_, err = r.ResolveFor(ctx, "other", "https://example.com/organization")
+	require.Error(t, err)
+
+	// node-wide secrets are only available to jobs if they are shared
+	_, err = r.ResolveFor(ctx, "private", "https://example.com/")
+	require.Error(t, err)
+	// secrets of other namespaces cannot be referenced
+	_, err = r.ResolveFor(ctx, "team-b/registry", "https://ghcr.io/")
+	require.Error(t, err)
+	_, err = r.ResolveFor(ctx, "missing", "https://ghcr.io/")
+	require.Error(t, err)
+	// there are no secrets without a policy
+	_, err = ForNamespace(static, nil, "team-a").ResolveFor(ctx, "registry", "https://ghcr.io/team-a/image")
+	require.Error(t, err)
+}
+
+func TestClientResolver(t *testing.T) {
+	ctx := context.Background()
+	policy, err := NewPolicy([]types.SecretConfig{{Name: "gateway", URLs: []string{"https://results.example.com/"}}})
+	require.NoError(t, err)
+	r := ForClient(StaticResolver{"gateway": "g", "other": "o"}, policy)
+
+	value, err := r.ResolveFor(ctx, "gateway", "https://results.example.com/j-1/stdout")
+	require.NoError(t, err)
+	require.Equal(t, "g", value)
+
+	// results URLs are chosen by compute nodes, so secrets are only sent to their URLs
+	_, err = r.ResolveFor(ctx, "gateway", "https://attacker.example.com/")
+	require.Error(t, err)
+	_, err = r.ResolveFor(ctx, "other", "https://results.example.com/")
+	require.Error(t, err)
+	_, err = ForClient(StaticResolver{"gateway": "g"}, nil).ResolveFor(ctx, "gateway", "https://results.example.com/")
+	require.Error(t, err)
+}
+
+func TestNewPolicy(t *testing.T) {
+	_, err := NewPolicy([]types.SecretConfig{{Name: "team-a/token", Shared: true}})
+	require.Error(t, err)
+	_, err = NewPolicy([]types.SecretConfig{{Name: "token"}, {Name: "token"}})
+	require.Error(t, err)
+	_, err = NewPolicy([]types.SecretConfig{{Name: "token", URLs: []string{"github.com/org"}}})
+	require.Error(t, err)
+	_, err = NewPolicy([]types.SecretConfig{{Name: "team__a"}})
+	require.Error(t, err)
+}
+
+func TestParseURL(t *testing.T) {
+	u, err := ParseURL("git@github.com:org/repo.git")
+	require.NoError(t, err)
+	require.Equal(t, "ssh", u.Scheme)
+	require.Equal(t, "github.com", u.Host)
+	require.Equal(t, "/org/repo.git", u.Path)
+
+	_, err = ParseURL("/local/repo")
+	require.Error(t, err)
+	_, err = ParseURL("file:///local/repo")
+	require.Error(t, err)
+}
+
+func TestEnvResolver(t *testing.T) {
+	env := map[string]string{"BACALHAU_SECRET_TOKEN": "abc", "BACALHAU_SECRET_EMPTY": ""}
+	r := &EnvResolver{lookup: func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}}
+
+	value, err := r.Resolve(context.Background(), "token")
+	require.NoError(t, err)
+	require.Equal(t, "abc", value)
+
+	_, err = r.Resolve(context.Background(), "empty")
+	require.Error(t, err)
+	_, err = r.Resolve(context.Background(), "missing")
+	require.Error(t, err)
+	_, err = r.Resolve(context.Background(), "")
+	require.Error(t, err)
+}
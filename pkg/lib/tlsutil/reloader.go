@@ -0,0 +1,249 @@
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultReloadInterval is how often the files backing a CertificateReloader
+// are checked for changes.
+const DefaultReloadInterval = 30 * time.Second
+
+// CertificateReloader holds a certificate/key pair and an optional CA bundle
+// loaded from disk, and reloads them whenever the underlying files change. This
+// allows certificates to be rotated without restarting the node.
+//
+// The reloader exposes callbacks that can be plugged into a tls.Config so that
+// every new handshake uses the most recently loaded material.
+type CertificateReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewCertificateReloader loads the passed files and returns a reloader for
+// them. Either the certificate and key or the CA file may be empty, but if a
+// certificate is passed then a key must be passed too.
+func NewCertificateReloader(certFile, keyFile, caFile string) (*CertificateReloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("a TLS certificate and key must be provided together")
+	}
+
+	r := &CertificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		modTimes: make(map[string]time.Time),
+	}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the certificate, key and CA files if any of them have
+// changed since they were last loaded. It returns true if new material was
+// loaded. If the new files cannot be parsed, the previously loaded material is
+// kept and an error is returned.
+func (r *CertificateReloader) Reload() (bool, error) {
+	modTimes, changed, err := r.checkModTimes()
+	if err != nil || !changed {
+		return false, err
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to load TLS certificate %s: %w", r.certFile, err)
+		}
+		cert = &pair
+	}
+
+	var caPool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read CA certificate %s: %w", r.caFile, err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no valid certificates found in CA file %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+	r.caPool = caPool
+	r.modTimes = modTimes
+	return true, nil
+}
+
+func (r *CertificateReloader) checkModTimes() (map[string]time.Time, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changed := false
+	modTimes := make(map[string]time.Time, len(r.modTimes))
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, false, err
+		}
+		modTimes[file] = info.ModTime()
+		if previous, ok := r.modTimes[file]; !ok || !previous.Equal(info.ModTime()) {
+			changed = true
+		}
+	}
+	return modTimes, changed, nil
+}
+
+// Start checks the files for changes on the passed interval until the context
+// is cancelled. Failures to reload are logged and the existing material is
+// retained.
+func (r *CertificateReloader) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if reloaded, err := r.Reload(); err != nil {
+					log.Ctx(ctx).Warn().Err(err).Msg("failed to reload TLS certificates, keeping existing ones")
+				} else if reloaded {
+					log.Ctx(ctx).Info().Str("certificate", r.certFile).Msg("reloaded TLS certificates")
+				}
+			}
+		}
+	}()
+}
+
+// Certificate returns the currently loaded certificate, or nil if the reloader
+// was not configured with one.
+func (r *CertificateReloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// CertPool returns the currently loaded CA pool, or nil if the reloader was
+// not configured with a CA file.
+func (r *CertificateReloader) CertPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// GetCertificate can be used as tls.Config.GetCertificate by servers.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if cert := r.Certificate(); cert != nil {
+		return cert, nil
+	}
+	return nil, errors.New("no TLS certificate configured")
+}
+
+// GetClientCertificate can be used as tls.Config.GetClientCertificate by
+// clients. If no certificate is configured, an empty certificate is returned
+// and the server will decide whether to accept the connection.
+func (r *CertificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if cert := r.Certificate(); cert != nil {
+		return cert, nil
+	}
+	return &tls.Certificate{}, nil
+}
+
+// ServerConfig returns a tls.Config for a server that presents the reloaded
+// certificate. If a CA file was configured, client certificates are verified
+// against it, and must be presented if requireClientCert is true. Requiring
+// client certificates without a CA file to verify them against is an error.
+//
+// The application protocols of the returned config are also used for each
+// client, so servers should set them on the returned config rather than rely
+// on them being added to a copy of it, as the http server does for HTTP/2.
+func (r *CertificateReloader) ServerConfig(requireClientCert bool) (*tls.Config, error) {
+	base := &tls.Config{
+		GetCertificate: r.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if r.caFile == "" {
+		if requireClientCert {
+			return nil, errors.New("a client CA certificate is required to require client certificates")
+		}
+		return base, nil
+	}
+
+	clientAuth := tls.VerifyClientCertIfGiven
+	if requireClientCert {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	base.ClientAuth = clientAuth
+	base.ClientCAs = r.CertPool()
+
+	// Resolve the client CA pool on each handshake so that it picks up any
+	// rotation of the CA file.
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return &tls.Config{
+			GetCertificate: r.GetCertificate,
+			ClientAuth:     clientAuth,
+			ClientCAs:      r.CertPool(),
+			NextProtos:     base.NextProtos,
+			MinVersion:     tls.VersionTLS12,
+		}, nil
+	}
+	return base, nil
+}
+
+// ClientConfig returns a tls.Config for a client that presents the reloaded
+// certificate, if any, and verifies the server against the reloaded CA pool,
+// falling back to the system roots if no CA file was configured.
+func (r *CertificateReloader) ClientConfig() *tls.Config {
+	config := &tls.Config{
+		GetClientCertificate: r.GetClientCertificate,
+		MinVersion:           tls.VersionTLS12,
+	}
+	if r.caFile == "" {
+		return config
+	}
+
+	// Standard verification would pin the CA pool at the time the config was
+	// created, so verify the server chain manually against the current pool.
+	config.InsecureSkipVerify = true //nolint:gosec // verification is performed in VerifyConnection
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.New("server did not present a certificate")
+		}
+		opts := x509.VerifyOptions{
+			DNSName:       state.ServerName,
+			Roots:         r.CertPool(),
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range state.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := state.PeerCertificates[0].Verify(opts)
+		return err
+	}
+	return config
+}
@@ -0,0 +1,183 @@
+//go:build unit || !integration
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/test/utils/certificates"
+)
+
+type ReloaderSuite struct {
+	suite.Suite
+	dir      string
+	ca       *certificates.CACertificate
+	caFile   string
+	certFile string
+	keyFile  string
+}
+
+func TestReloaderSuite(t *testing.T) {
+	suite.Run(t, new(ReloaderSuite))
+}
+
+// SetupSuite generates the certificates once as generating keys is slow.
+func (s *ReloaderSuite) SetupSuite() {
+	s.dir = s.T().TempDir()
+	s.caFile = filepath.Join(s.dir, "ca.pem")
+	s.certFile = filepath.Join(s.dir, "cert.pem")
+	s.keyFile = filepath.Join(s.dir, "key.pem")
+
+	var err error
+	s.ca, err = certificates.NewTestCACertificate(s.caFile, filepath.Join(s.dir, "ca_key.pem"))
+	s.Require().NoError(err)
+	_, err = s.ca.CreateTestSignedCertificate(s.certFile, s.keyFile)
+	s.Require().NoError(err)
+}
+
+func (s *ReloaderSuite) TestRequiresCertificateAndKeyTogether() {
+	_, err := NewCertificateReloader(s.certFile, "", "")
+	s.Require().Error(err)
+}
+
+// copyCertificate copies the suite's certificate and key so that a test can
+// modify them without affecting other tests.
+func (s *ReloaderSuite) copyCertificate() (string, string) {
+	dir := s.T().TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	for src, dst := range map[string]string{s.certFile: certFile, s.keyFile: keyFile} {
+		data, err := os.ReadFile(src)
+		s.Require().NoError(err)
+		s.Require().NoError(os.WriteFile(dst, data, 0600))
+	}
+	return certFile, keyFile
+}
+
+func (s *ReloaderSuite) TestReloadsChangedCertificate() {
+	certFile, keyFile := s.copyCertificate()
+	reloader, err := NewCertificateReloader(certFile, keyFile, s.caFile)
+	s.Require().NoError(err)
+	original := reloader.Certificate()
+	s.Require().NotNil(original)
+	s.Require().NotNil(reloader.CertPool())
+
+	reloaded, err := reloader.Reload()
+	s.Require().NoError(err)
+	s.Require().False(reloaded, "unchanged files should not be reloaded")
+
+	_, err = s.ca.CreateTestSignedCertificate(certFile, keyFile)
+	s.Require().NoError(err)
+	future := time.Now().Add(time.Minute)
+	s.Require().NoError(os.Chtimes(certFile, future, future))
+
+	reloaded, err = reloader.Reload()
+	s.Require().NoError(err)
+	s.Require().True(reloaded)
+	s.Require().NotEqual(original.Certificate[0], reloader.Certificate().Certificate[0])
+}
+
+func (s *ReloaderSuite) TestKeepsExistingCertificateOnInvalidFile() {
+	certFile, keyFile := s.copyCertificate()
+	reloader, err := NewCertificateReloader(certFile, keyFile, "")
+	s.Require().NoError(err)
+	original := reloader.Certificate()
+
+	s.Require().NoError(os.WriteFile(certFile, []byte("not a certificate"), 0600))
+	future := time.Now().Add(time.Minute)
+	s.Require().NoError(os.Chtimes(certFile, future, future))
+
+	_, err = reloader.Reload()
+	s.Require().Error(err)
+	s.Require().Equal(original, reloader.Certificate())
+}
+
+func (s *ReloaderSuite) TestMutualTLSHandshake() {
+	reloader, err := NewCertificateReloader(s.certFile, s.keyFile, s.caFile)
+	s.Require().NoError(err)
+
+	serverConfig, err := reloader.ServerConfig(true)
+	s.Require().NoError(err)
+	serverConfig.NextProtos = []string{"h2", "http/1.1"}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	accepted := make(chan *tls.ConnectionState, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if err := tlsConn.Handshake(); err != nil {
+			accepted <- nil
+			return
+		}
+		state := tlsConn.ConnectionState()
+		accepted <- &state
+	}()
+
+	clientConfig := reloader.ClientConfig()
+	clientConfig.NextProtos = []string{"h2", "http/1.1"}
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	s.Require().NoError(err)
+	defer conn.Close()
+	s.Require().NoError(conn.Handshake())
+
+	state := <-accepted
+	s.Require().NotNil(state)
+	s.Require().NotEmpty(state.VerifiedChains, "server should have verified the client certificate")
+	s.Require().Equal("h2", state.NegotiatedProtocol)
+}
+
+func (s *ReloaderSuite) TestRequireClientCertWithoutCA() {
+	reloader, err := NewCertificateReloader(s.certFile, s.keyFile, "")
+	s.Require().NoError(err)
+	_, err = reloader.ServerConfig(true)
+	s.Require().Error(err)
+	_, err = reloader.ServerConfig(false)
+	s.Require().NoError(err)
+}
+
+func (s *ReloaderSuite) TestRejectsClientWithoutCertificate() {
+	reloader, err := NewCertificateReloader(s.certFile, s.keyFile, s.caFile)
+	s.Require().NoError(err)
+
+	serverConfig, err := reloader.ServerConfig(true)
+	s.Require().NoError(err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	s.Require().NoError(err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	clientOnlyCA, err := NewCertificateReloader("", "", s.caFile)
+	s.Require().NoError(err)
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	s.Require().NoError(err)
+	tlsConn := tls.Client(conn, clientOnlyCA.ClientConfig())
+	defer tlsConn.Close()
+
+	// TLS 1.3 reports client certificate failures on the first read
+	err = tlsConn.Handshake()
+	if err == nil {
+		_, err = tlsConn.Read(make([]byte, 1))
+	}
+	s.Require().Error(err)
+}
@@ -48,6 +48,7 @@ type Libp2pTransport struct {
 	callbackProxy     *bprotocol.CallbackProxy
 	nodeInfoPubSub    pubsub.PubSub[models.NodeInfo]
 	nodeInfoDecorator models.NodeInfoDecorator
+	nodeJoiner        *bprotocol.JoinProxy
 }
 
 func NewLibp2pTransport(ctx context.Context,
@@ -132,6 +133,7 @@ func NewLibp2pTransport(ctx context.Context,
 		callbackProxy:     computeCallback,
 		nodeInfoPubSub:    nodeInfoPubSub,
 		nodeInfoDecorator: peerInfoDecorator,
+		nodeJoiner:        bprotocol.NewJoinProxy(bprotocol.JoinProxyParams{Host: libp2pHost}),
 	}, nil
 }
 
@@ -159,6 +161,15 @@ func (t *Libp2pTransport) RegisterComputeEndpoint(endpoint compute.Endpoint) err
 	return nil
 }
 
+// RegisterNodeJoiner registers the node joiner of an orchestrator with the transport layer.
+func (t *Libp2pTransport) RegisterNodeJoiner(joiner routing.NodeJoiner) error {
+	bprotocol.NewJoinHandler(bprotocol.JoinHandlerParams{
+		Host:   t.Host,
+		Joiner: joiner,
+	})
+	return nil
+}
+
 // ComputeProxy returns the compute proxy.
 func (t *Libp2pTransport) ComputeProxy() compute.Endpoint {
 	return t.computeProxy
@@ -174,6 +185,11 @@ func (t *Libp2pTransport) NodeInfoPubSub() pubsub.PubSub[models.NodeInfo] {
 	return t.nodeInfoPubSub
 }
 
+// NodeJoiner returns the proxy that presents join tokens to orchestrators.
+func (t *Libp2pTransport) NodeJoiner() routing.NodeJoiner {
+	return t.nodeJoiner
+}
+
 // NodeInfoDecorator returns the node info decorator.
 func (t *Libp2pTransport) NodeInfoDecorator() models.NodeInfoDecorator {
 	return t.nodeInfoDecorator
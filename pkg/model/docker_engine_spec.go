@@ -6,6 +6,9 @@ const (
 	EngineKeyParametersDocker           = "Parameters"
 	EngineKeyEnvironmentVariablesDocker = "EnvironmentVariables"
 	EngineKeyWorkingDirectoryDocker     = "WorkingDirectory"
+	EngineKeyRegistrySecretDocker       = "RegistrySecret"
+	EngineKeySecurityProfileDocker      = "SecurityProfile"
+	EngineKeyCapabilitiesDocker         = "Capabilities"
 )
 
 // DockerEngineSpec contains necessary parameters to execute a docker job.
@@ -20,6 +23,15 @@ type DockerEngineSpec struct {
 	EnvironmentVariables []string `json:"EnvironmentVariables,omitempty"`
 	// WorkingDirectory inside the container
 	WorkingDirectory string `json:"WorkingDirectory,omitempty"`
+	// RegistrySecret names the secret holding the credentials to pull Image.
+	// It is resolved in the job's namespace by the compute node.
+	RegistrySecret string `json:"RegistrySecret,omitempty"`
+	// SecurityProfile names the security profile of the compute node the
+	// container runs with. The node's default profile is used if empty.
+	SecurityProfile string `json:"SecurityProfile,omitempty"`
+	// Capabilities are Linux capabilities added to the container, which the
+	// security profile must allow.
+	Capabilities []string `json:"Capabilities,omitempty"`
 }
 
 // DockerEngineBuilder is a struct that is used for constructing an EngineSpec object
@@ -66,6 +78,27 @@ func (b *DockerEngineBuilder) WithParameters(e ...string) *DockerEngineBuilder {
 	return b
 }
 
+// WithRegistrySecret is a builder method that sets the secret holding the credentials to pull the image.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithRegistrySecret(e string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeyRegistrySecretDocker, e)
+	return b
+}
+
+// WithSecurityProfile is a builder method that sets the security profile the container runs with.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithSecurityProfile(e string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeySecurityProfileDocker, e)
+	return b
+}
+
+// WithCapabilities is a builder method that sets the Linux capabilities added to the container.
+// It returns the DockerEngineBuilder for further chaining of builder methods.
+func (b *DockerEngineBuilder) WithCapabilities(e ...string) *DockerEngineBuilder {
+	b.eb.WithParam(EngineKeyCapabilitiesDocker, e)
+	return b
+}
+
 // Build method constructs the final EngineSpec object by calling the embedded EngineBuilder's Build method.
 func (b *DockerEngineBuilder) Build() EngineSpec {
 	return b.eb.Build()
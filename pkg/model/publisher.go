@@ -12,6 +12,9 @@ const (
 	PublisherIpfs
 	PublisherS3
 	PublisherLocal
+	PublisherHTTP
+	PublisherAzure
+	PublisherGCS
 	publisherDone // must be last
 )
 
@@ -20,6 +23,9 @@ var publisherNames = map[Publisher]string{
 	PublisherIpfs:  "ipfs",
 	PublisherS3:    "s3",
 	PublisherLocal: "local",
+	PublisherHTTP:  "http",
+	PublisherAzure: "azure",
+	PublisherGCS:   "gcs",
 }
 
 func ParsePublisher(str string) (Publisher, error) {
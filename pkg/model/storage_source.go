@@ -19,6 +19,10 @@ const (
 	StorageSourceInline
 	StorageSourceLocalDirectory
 	StorageSourceS3
+	StorageSourceAzure
+	StorageSourceGCS
+	StorageSourceHTTP
+	StorageSourceJobResult
 	storageSourceDone // must be last
 )
 
@@ -30,6 +34,10 @@ var storageSourceNames = map[StorageSourceType]string{
 	StorageSourceInline:         "inline",
 	StorageSourceLocalDirectory: "localDirectory",
 	StorageSourceS3:             "s3",
+	StorageSourceAzure:          "azure",
+	StorageSourceGCS:            "gcs",
+	StorageSourceHTTP:           "http",
+	StorageSourceJobResult:      "jobResult",
 }
 
 func ParseStorageSourceType(str string) (StorageSourceType, error) {
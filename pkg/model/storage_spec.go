@@ -22,6 +22,14 @@ type StorageSpec struct {
 
 	S3 *S3StorageSpec `json:"S3,omitempty"`
 
+	Azure *AzureStorageSpec `json:"Azure,omitempty"`
+
+	GCS *GCSStorageSpec `json:"GCS,omitempty"`
+
+	HTTP *HTTPStorageSpec `json:"HTTP,omitempty"`
+
+	JobResult *JobResultStorageSpec `json:"JobResult,omitempty"`
+
 	// URL of the git Repo to clone
 	Repo string `json:"Repo,omitempty"`
 
@@ -49,6 +57,39 @@ type S3StorageSpec struct {
 	Region         string `json:"Region,omitempty"`
 }
 
+type AzureStorageSpec struct {
+	Account   string `json:"Account,omitempty"`
+	Container string `json:"Container,omitempty"`
+	Key       string `json:"Key,omitempty"`
+	Filter    string `json:"Filter,omitempty"`
+	Endpoint  string `json:"Endpoint,omitempty"`
+	ETag      string `json:"ETag,omitempty"`
+}
+
+type GCSStorageSpec struct {
+	Bucket     string `json:"Bucket,omitempty"`
+	Key        string `json:"Key,omitempty"`
+	Filter     string `json:"Filter,omitempty"`
+	Endpoint   string `json:"Endpoint,omitempty"`
+	Generation int64  `json:"Generation,omitempty"`
+}
+
+// HTTPStorageSpec references results published over HTTP.
+type HTTPStorageSpec struct {
+	URL        string   `json:"URL,omitempty"`
+	Files      []string `json:"Files,omitempty"`
+	Secret     string   `json:"Secret,omitempty"`
+	AuthHeader string   `json:"AuthHeader,omitempty"`
+}
+
+// JobResultStorageSpec references the published results of another job.
+type JobResultStorageSpec struct {
+	JobID       string `json:"JobID,omitempty"`
+	ExecutionID string `json:"ExecutionID,omitempty"`
+	Publisher   string `json:"Publisher,omitempty"`
+	Path        string `json:"Path,omitempty"`
+}
+
 // PublishedStorageSpec is a wrapper for a StorageSpec that has been published
 // by a compute provider - it keeps info about the host job that
 // lead to the given storage spec being published
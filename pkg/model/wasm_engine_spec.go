@@ -6,6 +6,7 @@ const (
 	EngineKeyParametersWasm           = "Parameters"
 	EngineKeyEnvironmentVariablesWasm = "EnvironmentVariables"
 	EngineKeyImportModulesWasm        = "ImportModules"
+	EngineKeyFuelWasm                 = "Fuel"
 )
 
 // WasmEngineSpec contains necessary parameters to execute a wasm job.
@@ -27,6 +28,10 @@ type WasmEngineSpec struct {
 	// ImportModules is a slice of StorageSpec's containing WASM modules whose exports will be available as imports
 	// to the EntryModule.
 	ImportModules []StorageSpec `json:"ImportModules,omitempty"`
+
+	// Fuel is the most fuel the job can use, where a unit of fuel is roughly a WASM instruction.
+	// If zero, the job is metered with the compute node's maximum, if it has one.
+	Fuel uint64 `json:"Fuel,omitempty"`
 }
 
 // WasmEngineBuilder is a struct used for constructing an EngineSpec object
@@ -73,6 +78,13 @@ func (b *WasmEngineBuilder) WithImportModules(e ...StorageSpec) *WasmEngineBuild
 	return b
 }
 
+// WithFuel is a builder method that sets the most fuel the WebAssembly job can use.
+// It returns the WasmEngineBuilder for further chaining of builder methods.
+func (b *WasmEngineBuilder) WithFuel(e uint64) *WasmEngineBuilder {
+	b.eb.WithParam(EngineKeyFuelWasm, e)
+	return b
+}
+
 // Build method constructs the final EngineSpec object by calling the embedded EngineBuilder's Build method.
 func (b *WasmEngineBuilder) Build() EngineSpec {
 	return b.eb.Build()
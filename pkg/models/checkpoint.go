@@ -0,0 +1,173 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// DefaultCheckpointInterval is the time between checkpoints of tasks that
+	// don't set an interval.
+	DefaultCheckpointInterval = 5 * time.Minute
+
+	// CheckpointResultPathName is the name of the result path the compute
+	// node mounts at a task's checkpoint path. It is not published with the
+	// task's results.
+	CheckpointResultPathName = ".checkpoint"
+
+	// CheckpointInputAlias is the alias of the input source an execution's
+	// checkpoint is restored from.
+	CheckpointInputAlias = "checkpoint"
+)
+
+// CheckpointConfig opts a task into checkpointing. The task writes its
+// checkpoints to a path, which the compute node periodically publishes with
+// the task's publishers. When an execution of the task fails and is
+// rescheduled, the latest checkpoint is restored to the path before the new
+// execution starts.
+type CheckpointConfig struct {
+	// Path is the directory the task writes its checkpoints to, and where the
+	// latest checkpoint is restored to when the task is rescheduled.
+	Path string `json:"Path"`
+
+	// Interval is the time between checkpoints in seconds.
+	// DefaultCheckpointInterval is used if zero.
+	Interval int64 `json:"Interval,omitempty"`
+}
+
+// Enabled returns true if checkpoints should be taken.
+func (c *CheckpointConfig) Enabled() bool {
+	return c != nil && c.Path != ""
+}
+
+// GetInterval returns the time between checkpoints.
+func (c *CheckpointConfig) GetInterval() time.Duration {
+	if c.Interval == 0 {
+		return DefaultCheckpointInterval
+	}
+	return time.Duration(c.Interval) * time.Second
+}
+
+// Copy returns a copy of the checkpoint config.
+func (c *CheckpointConfig) Copy() *CheckpointConfig {
+	if c == nil {
+		return nil
+	}
+	cpy := *c
+	return &cpy
+}
+
+// Validate checks the checkpoint config against the result paths and
+// publishers of its task.
+func (c *CheckpointConfig) Validate(resultPaths []*ResultPath, publishers []*PublisherConfig) error {
+	if c == nil {
+		return nil
+	}
+	var mErr multierror.Error
+	if !path.IsAbs(c.Path) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("checkpoint path must be absolute, got %q", c.Path))
+	}
+	if c.Interval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid checkpoint interval value: %s", c.GetInterval()))
+	}
+	if len(publishers) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("publisher must be set if checkpoints are enabled"))
+	}
+	for _, resultPath := range resultPaths {
+		if resultPath == nil {
+			continue
+		}
+		if resultPath.Name == CheckpointResultPathName {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("result path name %s is reserved for checkpoints", CheckpointResultPathName))
+		}
+		if path.Clean(resultPath.Path) == path.Clean(c.Path) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("checkpoint path %s is also a result path", c.Path))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ResultPath returns the result path the compute node mounts at the
+// checkpoint path, so that checkpoints are written to the node.
+func (c *CheckpointConfig) ResultPath() *ResultPath {
+	return &ResultPath{Name: CheckpointResultPathName, Path: c.Path}
+}
+
+// Checkpoint is a checkpoint of a running execution that was published with
+// the task's publishers. Unlike snapshots, each checkpoint holds all the files
+// of the checkpoint path.
+type Checkpoint struct {
+	// Version of the checkpoint, starting at 1 for the first checkpoint of an execution
+	Version int `json:"Version"`
+
+	// ExecutionID of the execution the checkpoint was taken from
+	ExecutionID string `json:"ExecutionID"`
+
+	// CreateTime is the time the checkpoint was taken
+	CreateTime int64 `json:"CreateTime"`
+
+	// Results holds the result of each of the task's publishers
+	Results []*PublishedResult `json:"Results"`
+}
+
+// Copy returns a deep copy of the checkpoint.
+func (c *Checkpoint) Copy() *Checkpoint {
+	if c == nil {
+		return nil
+	}
+	cpy := *c
+	cpy.Results = CopySlice(c.Results)
+	return &cpy
+}
+
+// GetCreateTime returns the time the checkpoint was taken.
+func (c *Checkpoint) GetCreateTime() time.Time {
+	return time.Unix(0, c.CreateTime).UTC()
+}
+
+// InputSource returns the input source that restores the checkpoint to the
+// checkpoint path, from the first of its results that was published, or nil
+// if none were.
+func (c *Checkpoint) InputSource(config *CheckpointConfig) *InputSource {
+	for _, published := range c.Results {
+		if published == nil || published.Result.IsEmpty() {
+			continue
+		}
+		return &InputSource{
+			Source: published.Result.Copy(),
+			Alias:  CheckpointInputAlias,
+			Target: config.Path,
+		}
+	}
+	return nil
+}
+
+// LatestCheckpoint returns the most recent checkpoint of the execution, or
+// nil if it has none.
+func (e *Execution) LatestCheckpoint() *Checkpoint {
+	if len(e.Checkpoints) == 0 {
+		return nil
+	}
+	return e.Checkpoints[len(e.Checkpoints)-1]
+}
+
+// AddCheckpoint returns the execution's checkpoints with the checkpoint added
+// in order of their versions, replacing any checkpoint with the same version.
+func (e *Execution) AddCheckpoint(checkpoint *Checkpoint) []*Checkpoint {
+	checkpoints := make([]*Checkpoint, 0, len(e.Checkpoints)+1)
+	for _, c := range e.Checkpoints {
+		if c.Version != checkpoint.Version {
+			checkpoints = append(checkpoints, c)
+		}
+	}
+	checkpoints = append(checkpoints, checkpoint)
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].Version < checkpoints[j].Version
+	})
+	return checkpoints
+}
@@ -39,6 +39,11 @@ const (
 	StorageSourceS3PreSigned    = "s3PreSigned"
 	StorageSourceInline         = "inline"
 	StorageSourceLocalDirectory = "localDirectory"
+	StorageSourceGit            = "git"
+	StorageSourceHTTP           = "http"
+	StorageSourceAzure          = "azure"
+	StorageSourceGCS            = "gcs"
+	StorageSourceJobResult      = "jobResult"
 )
 
 const (
@@ -46,6 +51,9 @@ const (
 	PublisherIPFS  = "ipfs"
 	PublisherS3    = "s3"
 	PublisherLocal = "local"
+	PublisherHTTP  = "http"
+	PublisherAzure = "azure"
+	PublisherGCS   = "gcs"
 )
 
 const (
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ExecutionEndpoint is the address a published port of an execution can be
+// reached on.
+type ExecutionEndpoint struct {
+	// Name is the name of the task's port.
+	Name string `json:"Name"`
+	// Protocol is either tcp or udp.
+	Protocol string `json:"Protocol"`
+	// Host is the address of the compute node running the execution.
+	Host string `json:"Host"`
+	// Port is the host port allocated to the task's port.
+	Port int `json:"Port"`
+	// Target is the port the task listens on.
+	Target int `json:"Target"`
+}
+
+// Address returns the host:port address of the endpoint.
+func (e *ExecutionEndpoint) Address() string {
+	return net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+}
+
+// Copy returns a deep copy of the endpoint.
+func (e *ExecutionEndpoint) Copy() *ExecutionEndpoint {
+	if e == nil {
+		return nil
+	}
+	cpy := *e
+	return &cpy
+}
+
+// ServiceEndpoint is an endpoint of a running execution of a job, as
+// returned by endpoint and service lookups.
+type ServiceEndpoint struct {
+	JobID       string `json:"JobID"`
+	ExecutionID string `json:"ExecutionID"`
+	NodeID      string `json:"NodeID"`
+	ExecutionEndpoint
+}
+
+// ServiceName is a service lookup name in the form
+// _<port>._<protocol>.<job>, where job is the name or ID of a job.
+type ServiceName struct {
+	Port     string
+	Protocol string
+	Job      string
+}
+
+// ParseServiceName parses a service lookup name in the form
+// _<port>._<protocol>.<job>.
+func ParseServiceName(name string) (ServiceName, error) {
+	parts := strings.SplitN(strings.TrimSuffix(strings.TrimSpace(name), "."), ".", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "_") || !strings.HasPrefix(parts[1], "_") || parts[2] == "" {
+		return ServiceName{}, fmt.Errorf("invalid service name %q. expected _<port>._<protocol>.<job>", name)
+	}
+	service := ServiceName{
+		Port:     strings.TrimPrefix(parts[0], "_"),
+		Protocol: strings.ToLower(strings.TrimPrefix(parts[1], "_")),
+		Job:      parts[2],
+	}
+	if service.Protocol != PortProtocolTCP && service.Protocol != PortProtocolUDP {
+		return ServiceName{}, fmt.Errorf("invalid protocol %q in service name %q. expected %s or %s",
+			service.Protocol, name, PortProtocolTCP, PortProtocolUDP)
+	}
+	return service, nil
+}
+
+// String returns the service lookup name.
+func (s ServiceName) String() string {
+	return fmt.Sprintf("_%s._%s.%s", s.Port, s.Protocol, s.Job)
+}
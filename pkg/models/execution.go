@@ -88,6 +88,30 @@ type Execution struct {
 	// the published results for this execution
 	PublishedResult *SpecConfig `json:"PublishedResult"`
 
+	// PublishedResults holds the result of each of the task's publishers.
+	// PublishedResult is the first of these, for backward compatibility.
+	PublishedResults []*PublishedResult `json:"PublishedResults,omitempty"`
+
+	// Snapshots holds the results published while the execution was running,
+	// in order of their versions.
+	Snapshots []*ResultSnapshot `json:"Snapshots,omitempty"`
+
+	// Checkpoints holds the checkpoints published while the execution was
+	// running, in order of their versions.
+	Checkpoints []*Checkpoint `json:"Checkpoints,omitempty"`
+
+	// CheckpointInput restores the checkpoint of a previous execution of the
+	// job to the task's checkpoint path before the execution starts.
+	CheckpointInput *InputSource `json:"CheckpointInput,omitempty"`
+
+	// Health is the latest result of the health checks of a running
+	// execution whose task has a health check.
+	Health *ExecutionHealth `json:"Health,omitempty"`
+
+	// Endpoints are the addresses the published ports of a running
+	// execution can be reached on.
+	Endpoints []*ExecutionEndpoint `json:"Endpoints,omitempty"`
+
 	// RunOutput is the output of the run command
 	// TODO: evaluate removing this from execution spec in favour of calling `bacalhau logs`
 	RunOutput *RunCommandResult `json:"RunOutput"`
@@ -157,9 +181,40 @@ func (e *Execution) Copy() *Execution {
 	na.Job = na.Job.Copy()
 	na.AllocatedResources = na.AllocatedResources.Copy()
 	na.PublishedResult = na.PublishedResult.Copy()
+	if na.PublishedResults != nil {
+		na.PublishedResults = CopySlice(na.PublishedResults)
+	}
+	if na.Snapshots != nil {
+		na.Snapshots = CopySlice(na.Snapshots)
+	}
+	if na.Checkpoints != nil {
+		na.Checkpoints = CopySlice(na.Checkpoints)
+	}
+	na.CheckpointInput = na.CheckpointInput.Copy()
+	na.Health = na.Health.Copy()
+	if na.Endpoints != nil {
+		na.Endpoints = CopySlice(na.Endpoints)
+	}
 	return na
 }
 
+// AllPublishedResults returns the results of each of the execution's publishers.
+// Executions completed by older compute nodes only record a single result,
+// which was published by the task's publisher.
+func (e *Execution) AllPublishedResults() []*PublishedResult {
+	if len(e.PublishedResults) > 0 {
+		return e.PublishedResults
+	}
+	if e.PublishedResult == nil {
+		return nil
+	}
+	published := &PublishedResult{Result: e.PublishedResult}
+	if publisher := e.Job.Task().Publisher; publisher != nil {
+		published.Publisher = publisher.Type
+	}
+	return []*PublishedResult{published}
+}
+
 // Validate is used to check a job for reasonable configuration
 func (e *Execution) Validate() error {
 	var mErr multierror.Error
@@ -236,6 +291,19 @@ type RunCommandResult struct {
 
 	// Runner error
 	ErrorMsg string `json:"ErrorMsg"`
+
+	// Manifest is the compute node's signature of the published results manifest
+	Manifest *ResultManifestSignature `json:"Manifest,omitempty"`
+
+	// ResourceUsage is what the run used of the resources the executor meters
+	ResourceUsage *ExecutionResourceUsage `json:"ResourceUsage,omitempty"`
+}
+
+// ExecutionResourceUsage is what an execution used of the resources that
+// are metered by its executor.
+type ExecutionResourceUsage struct {
+	// Fuel is the fuel used by a metered WASM execution.
+	Fuel uint64 `json:"Fuel,omitempty"`
 }
 
 func NewRunCommandResult() *RunCommandResult {
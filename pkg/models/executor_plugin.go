@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ExecutorPluginState is the state of an executor plugin.
+type ExecutorPluginState string
+
+const (
+	// ExecutorPluginStarting is a plugin that is being launched.
+	ExecutorPluginStarting ExecutorPluginState = "Starting"
+	// ExecutorPluginRunning is a plugin that is running and healthy, and
+	// so provides its engine to jobs.
+	ExecutorPluginRunning ExecutorPluginState = "Running"
+	// ExecutorPluginFailed is a plugin that failed to launch, crashed or
+	// failed its health check, and will be restarted.
+	ExecutorPluginFailed ExecutorPluginState = "Failed"
+)
+
+// ExecutorPluginInfo describes an executor plugin of a compute node.
+type ExecutorPluginInfo struct {
+	// Name is the engine type the plugin provides.
+	Name    string `json:"Name"`
+	Version string `json:"Version,omitempty"`
+	// Path is the path of the plugin's binary.
+	Path string `json:"Path"`
+	// Checksum is the checksum of the plugin's binary, as algorithm:hex.
+	Checksum string              `json:"Checksum,omitempty"`
+	State    ExecutorPluginState `json:"State"`
+	// Restarts is the number of times the plugin has been restarted.
+	Restarts int `json:"Restarts"`
+	// Error is why the plugin last failed.
+	Error string `json:"Error,omitempty"`
+	// StartedAt is when the plugin was last launched.
+	StartedAt time.Time `json:"StartedAt,omitempty"`
+}
@@ -0,0 +1,198 @@
+//go:generate stringer -type=ExecutionHealthStatus -trimprefix=ExecutionHealth -output=health_check_string.go
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// HealthCheckHTTP checks a task is healthy by sending it a GET request.
+	HealthCheckHTTP = "http"
+	// HealthCheckTCP checks a task is healthy by connecting to one of its ports.
+	HealthCheckTCP = "tcp"
+	// HealthCheckExec checks a task is healthy by running a command within it.
+	HealthCheckExec = "exec"
+
+	defaultHealthCheckInterval         = 30
+	defaultHealthCheckTimeout          = 10
+	defaultHealthCheckFailureThreshold = 3
+)
+
+// HealthCheckConfig configures checking the health of a running task. The
+// executions of service and daemon jobs are replaced once their task fails
+// FailureThreshold consecutive checks, as a process can be alive without
+// doing any useful work.
+type HealthCheckConfig struct {
+	// Type is one of http, tcp or exec.
+	Type string `json:"Type"`
+
+	// Port is the port of the task that http and tcp checks connect to.
+	Port int `json:"Port,omitempty"`
+
+	// Path is requested by http checks, which succeed if the response status
+	// is 2xx or 3xx. Defaults to /.
+	Path string `json:"Path,omitempty"`
+
+	// Command is run within the task by exec checks, which succeed if it
+	// exits with status 0.
+	Command []string `json:"Command,omitempty"`
+
+	// Interval is the time between checks in seconds. Defaults to 30.
+	Interval int64 `json:"Interval,omitempty"`
+
+	// Timeout is the time a check can take in seconds. Defaults to 10.
+	Timeout int64 `json:"Timeout,omitempty"`
+
+	// StartPeriod is the time in seconds the task is given to start, during
+	// which failed checks are not counted.
+	StartPeriod int64 `json:"StartPeriod,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks after which
+	// the task is unhealthy. Defaults to 3.
+	FailureThreshold int `json:"FailureThreshold,omitempty"`
+}
+
+// GetInterval returns the time between checks.
+func (h *HealthCheckConfig) GetInterval() time.Duration {
+	if h.Interval == 0 {
+		return defaultHealthCheckInterval * time.Second
+	}
+	return time.Duration(h.Interval) * time.Second
+}
+
+// GetTimeout returns the time a check can take.
+func (h *HealthCheckConfig) GetTimeout() time.Duration {
+	if h.Timeout == 0 {
+		return defaultHealthCheckTimeout * time.Second
+	}
+	return time.Duration(h.Timeout) * time.Second
+}
+
+// GetStartPeriod returns the time the task is given to start.
+func (h *HealthCheckConfig) GetStartPeriod() time.Duration {
+	return time.Duration(h.StartPeriod) * time.Second
+}
+
+// GetFailureThreshold returns the number of consecutive failed checks after
+// which the task is unhealthy.
+func (h *HealthCheckConfig) GetFailureThreshold() int {
+	if h.FailureThreshold == 0 {
+		return defaultHealthCheckFailureThreshold
+	}
+	return h.FailureThreshold
+}
+
+// Copy returns a deep copy of the health check config.
+func (h *HealthCheckConfig) Copy() *HealthCheckConfig {
+	if h == nil {
+		return nil
+	}
+	cpy := *h
+	cpy.Command = slices.Clone(h.Command)
+	return &cpy
+}
+
+// Validate checks the health check config is complete for its type.
+func (h *HealthCheckConfig) Validate() error {
+	if h == nil {
+		return nil
+	}
+	var mErr multierror.Error
+	switch h.Type {
+	case HealthCheckHTTP, HealthCheckTCP:
+		if h.Port <= 0 || h.Port > 65535 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check port: %d", h.Port))
+		}
+		if h.Type == HealthCheckHTTP && h.Path != "" && !strings.HasPrefix(h.Path, "/") {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("health check path %q must start with /", h.Path))
+		}
+	case HealthCheckExec:
+		if len(h.Command) == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("health check command cannot be empty"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check type %q. expected one of %s, %s or %s",
+			h.Type, HealthCheckHTTP, HealthCheckTCP, HealthCheckExec))
+	}
+	if h.Interval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check interval value: %s", h.GetInterval()))
+	}
+	if h.Timeout < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check timeout value: %s", h.GetTimeout()))
+	}
+	if h.StartPeriod < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check start period value: %s", h.GetStartPeriod()))
+	}
+	if h.FailureThreshold < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid health check failure threshold: %d", h.FailureThreshold))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ExecutionHealthStatus is the health of a running execution, as reported by
+// the health checks of its task.
+type ExecutionHealthStatus int
+
+const (
+	// ExecutionHealthUnknown is the status of executions that have not been
+	// checked yet, or whose task has no health check.
+	ExecutionHealthUnknown ExecutionHealthStatus = iota
+	// ExecutionHealthHealthy is the status of executions that passed their
+	// last health check.
+	ExecutionHealthHealthy
+	// ExecutionHealthUnhealthy is the status of executions that failed
+	// FailureThreshold consecutive health checks.
+	ExecutionHealthUnhealthy
+)
+
+func ParseExecutionHealthStatus(s string) (ExecutionHealthStatus, error) {
+	for typ := ExecutionHealthUnknown; typ <= ExecutionHealthUnhealthy; typ++ {
+		if strings.EqualFold(typ.String(), strings.TrimSpace(s)) {
+			return typ, nil
+		}
+	}
+
+	return ExecutionHealthUnknown, fmt.Errorf("invalid execution health status: %s", s)
+}
+
+func (s ExecutionHealthStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *ExecutionHealthStatus) UnmarshalText(text []byte) (err error) {
+	name := string(text)
+	*s, err = ParseExecutionHealthStatus(name)
+	return
+}
+
+// ExecutionHealth is the latest result of the health checks of an execution.
+type ExecutionHealth struct {
+	Status ExecutionHealthStatus `json:"Status"`
+	// Message describes the last failed check, if any.
+	Message string `json:"Message,omitempty"`
+	// ConsecutiveFailures is the number of checks that failed since the
+	// last successful check.
+	ConsecutiveFailures int `json:"ConsecutiveFailures,omitempty"`
+	// UpdateTime is when the status last changed.
+	UpdateTime int64 `json:"UpdateTime"`
+}
+
+// IsUnhealthy returns true if the execution failed its health checks.
+func (h *ExecutionHealth) IsUnhealthy() bool {
+	return h != nil && h.Status == ExecutionHealthUnhealthy
+}
+
+// Copy returns a copy of the execution health.
+func (h *ExecutionHealth) Copy() *ExecutionHealth {
+	if h == nil {
+		return nil
+	}
+	cpy := *h
+	return &cpy
+}
@@ -80,6 +80,11 @@ type Job struct {
 	// Namespace is the namespace this job is running in.
 	Namespace string `json:"Namespace"`
 
+	// Owner is the authenticated principal that submitted this job.
+	// It is set by the server from the caller's credentials and should not be
+	// set directly by the client.
+	Owner string `json:"Owner,omitempty"`
+
 	// Type is the type of job this is, e.g. "daemon" or "batch".
 	Type string `json:"Type"`
 
@@ -266,6 +271,10 @@ func (j *Job) ValidateSubmission() error {
 			outer := fmt.Errorf("task %s validation failed: %v", task.Name, err)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
+		if task.HealthCheck != nil && !j.IsLongRunning() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"task %s validation failed: health checks are only supported by service and daemon jobs", task.Name))
+		}
 	}
 
 	return mErr.ErrorOrNil()
@@ -293,6 +302,10 @@ func (j *Job) SanitizeSubmission() (warnings []string) {
 		warnings = append(warnings, "job modify time is ignored when submitting a job")
 		j.ModifyTime = 0
 	}
+	if j.Owner != "" {
+		warnings = append(warnings, "job owner is ignored when submitting a job")
+		j.Owner = ""
+	}
 	if j.Type == JobTypeBatch || j.Type == JobTypeOps {
 		if j.ID != "" {
 			warnings = append(warnings, "job ID is ignored when submitting a batch job")
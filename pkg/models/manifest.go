@@ -0,0 +1,47 @@
+package models
+
+const (
+	// ResultManifestFilename is the name of the manifest written at the root
+	// of published results.
+	ResultManifestFilename = "bacalhau-manifest.json"
+)
+
+// ResultManifest lists the files that were published for an execution.
+type ResultManifest struct {
+	Files []ManifestFile `json:"Files"`
+}
+
+// ManifestFile describes a single published file.
+type ManifestFile struct {
+	// Path of the file relative to the root of the results, using forward slashes
+	Path string `json:"Path"`
+	// Size of the published file in bytes
+	Size int64 `json:"Size"`
+	// SHA256 is the hex encoded checksum of the published file
+	SHA256 string `json:"SHA256"`
+	// Compression applied to the file, if any
+	Compression string `json:"Compression,omitempty"`
+}
+
+// ResultManifestSignature attests that a results manifest was produced by a
+// compute node. It is recorded on the execution, separately from the
+// published results, so that downloaded results can be verified against it.
+type ResultManifestSignature struct {
+	// NodeID of the compute node that signed the manifest
+	NodeID string `json:"NodeID"`
+	// SHA256 is the hex encoded checksum of the manifest file
+	SHA256 string `json:"SHA256"`
+	// Signature is the base64 encoded signature of the manifest file
+	Signature string `json:"Signature"`
+	// PublicKey is the base64 encoded libp2p public key of the node
+	PublicKey string `json:"PublicKey"`
+}
+
+// Copy returns a copy of the signature
+func (s *ResultManifestSignature) Copy() *ResultManifestSignature {
+	if s == nil {
+		return nil
+	}
+	cpy := *s
+	return &cpy
+}
@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	httppublisher "github.com/bacalhau-project/bacalhau/pkg/publisher/http"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/inline"
 	ipfs_storage "github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/jobresult"
 	localdirectory "github.com/bacalhau-project/bacalhau/pkg/storage/local_directory"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/storage/url/urldownload"
@@ -198,6 +202,65 @@ func FromLegacyStorageSpec(legacy model.StorageSpec) (*models.SpecConfig, error)
 				ChecksumSHA256: legacy.S3.ChecksumSHA256,
 			}.ToMap(),
 		}
+	case model.StorageSourceAzure:
+		if legacy.Azure == nil {
+			return nil, errors.New("invalid legacy storage spec - missing Azure details")
+		}
+
+		res = &models.SpecConfig{
+			Type: models.StorageSourceAzure,
+			Params: azurehelper.SourceSpec{
+				Account:   legacy.Azure.Account,
+				Container: legacy.Azure.Container,
+				Key:       legacy.Azure.Key,
+				Filter:    legacy.Azure.Filter,
+				Endpoint:  legacy.Azure.Endpoint,
+				ETag:      legacy.Azure.ETag,
+			}.ToMap(),
+		}
+	case model.StorageSourceGCS:
+		if legacy.GCS == nil {
+			return nil, errors.New("invalid legacy storage spec - missing GCS details")
+		}
+
+		res = &models.SpecConfig{
+			Type: models.StorageSourceGCS,
+			Params: gcshelper.SourceSpec{
+				Bucket:     legacy.GCS.Bucket,
+				Key:        legacy.GCS.Key,
+				Filter:     legacy.GCS.Filter,
+				Endpoint:   legacy.GCS.Endpoint,
+				Generation: legacy.GCS.Generation,
+			}.ToMap(),
+		}
+	case model.StorageSourceHTTP:
+		if legacy.HTTP == nil {
+			return nil, errors.New("invalid legacy storage spec - missing HTTP details")
+		}
+
+		res = &models.SpecConfig{
+			Type: models.StorageSourceHTTP,
+			Params: httppublisher.ResultSpec{
+				URL:        legacy.HTTP.URL,
+				Files:      legacy.HTTP.Files,
+				Secret:     legacy.HTTP.Secret,
+				AuthHeader: legacy.HTTP.AuthHeader,
+			}.ToMap(),
+		}
+	case model.StorageSourceJobResult:
+		if legacy.JobResult == nil {
+			return nil, errors.New("invalid legacy storage spec - missing job result details")
+		}
+
+		res = &models.SpecConfig{
+			Type: models.StorageSourceJobResult,
+			Params: jobresult.Source{
+				JobID:       legacy.JobResult.JobID,
+				ExecutionID: legacy.JobResult.ExecutionID,
+				Publisher:   legacy.JobResult.Publisher,
+				Path:        legacy.JobResult.Path,
+			}.ToMap(),
+		}
 	default:
 		return nil, fmt.Errorf("unhandled storage spec: %s", legacy.StorageSource)
 	}
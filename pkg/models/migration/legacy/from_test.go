@@ -153,6 +153,15 @@ func (s *LegacyFromSuite) TestLegacyStorageSpec() {
 			expected:    nil,
 			expectError: true,
 		},
+		{
+			name: "http_err",
+			arg: model.StorageSpec{
+				StorageSource: model.StorageSourceHTTP,
+				URL:           "https://example.com/results/",
+			},
+			expected:    nil,
+			expectError: true,
+		},
 		{
 			name: "invalid",
 			arg: model.StorageSpec{
@@ -175,3 +184,24 @@ func (s *LegacyFromSuite) TestLegacyStorageSpec() {
 		})
 	}
 }
+
+func (s *LegacyFromSuite) TestHTTPResultRoundTrip() {
+	// results published per file keep their files and authentication
+	result := &models.SpecConfig{
+		Type: models.StorageSourceHTTP,
+		Params: map[string]interface{}{
+			"URL":        "https://example.com/results/",
+			"Files":      []string{"stdout", "outputs/data.csv"},
+			"Secret":     "gateway",
+			"AuthHeader": "X-Api-Key",
+		},
+	}
+	spec, err := legacy.ToLegacyStorageSpec(result)
+	s.Require().NoError(err)
+	s.Equal(model.StorageSourceHTTP, spec.StorageSource)
+	s.Equal([]string{"stdout", "outputs/data.csv"}, spec.HTTP.Files)
+
+	config, err := legacy.FromLegacyStorageSpec(spec)
+	s.Require().NoError(err)
+	s.Equal(result, config)
+}
@@ -5,8 +5,12 @@ import (
 	"strings"
 	"time"
 
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
+	httppublisher "github.com/bacalhau-project/bacalhau/pkg/publisher/http"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/jobresult"
 )
 
 func ToLegacyJob(job *models.Job) (*model.Job, error) {
@@ -137,6 +141,20 @@ func ToLegacyStorageSpec(storage *models.SpecConfig) (model.StorageSpec, error)
 			StorageSource: model.StorageSourceURLDownload,
 			URL:           storage.Params["URL"].(string),
 		}, nil
+	case models.StorageSourceHTTP:
+		source, err := httppublisher.DecodeResultSpec(storage)
+		if err != nil {
+			return model.StorageSpec{}, err
+		}
+		return model.StorageSpec{
+			StorageSource: model.StorageSourceHTTP,
+			HTTP: &model.HTTPStorageSpec{
+				URL:        source.URL,
+				Files:      source.Files,
+				Secret:     source.Secret,
+				AuthHeader: source.AuthHeader,
+			},
+		}, nil
 	case models.StorageSourceRepoClone:
 		return model.StorageSpec{
 			StorageSource: model.StorageSourceRepoClone,
@@ -147,6 +165,12 @@ func ToLegacyStorageSpec(storage *models.SpecConfig) (model.StorageSpec, error)
 			StorageSource: model.StorageSourceRepoCloneLFS,
 			Repo:          storage.Params["Repo"].(string),
 		}, nil
+	case models.StorageSourceGit:
+		// legacy specs have no notion of refs, commits or sparse paths
+		return model.StorageSpec{
+			StorageSource: model.StorageSourceRepoClone,
+			Repo:          storage.Params["Repo"].(string),
+		}, nil
 	case models.StorageSourceInline:
 		return model.StorageSpec{
 			StorageSource: model.StorageSourceInline,
@@ -176,6 +200,51 @@ func ToLegacyStorageSpec(storage *models.SpecConfig) (model.StorageSpec, error)
 			StorageSource: model.StorageSourceS3,
 			S3:            s3Spec,
 		}, nil
+	case models.StorageSourceAzure:
+		source, err := azurehelper.DecodeSourceSpec(storage)
+		if err != nil {
+			return model.StorageSpec{}, err
+		}
+		return model.StorageSpec{
+			StorageSource: model.StorageSourceAzure,
+			Azure: &model.AzureStorageSpec{
+				Account:   source.Account,
+				Container: source.Container,
+				Key:       source.Key,
+				Filter:    source.Filter,
+				Endpoint:  source.Endpoint,
+				ETag:      source.ETag,
+			},
+		}, nil
+	case models.StorageSourceGCS:
+		source, err := gcshelper.DecodeSourceSpec(storage)
+		if err != nil {
+			return model.StorageSpec{}, err
+		}
+		return model.StorageSpec{
+			StorageSource: model.StorageSourceGCS,
+			GCS: &model.GCSStorageSpec{
+				Bucket:     source.Bucket,
+				Key:        source.Key,
+				Filter:     source.Filter,
+				Endpoint:   source.Endpoint,
+				Generation: source.Generation,
+			},
+		}, nil
+	case models.StorageSourceJobResult:
+		source, err := jobresult.DecodeSpec(storage)
+		if err != nil {
+			return model.StorageSpec{}, err
+		}
+		return model.StorageSpec{
+			StorageSource: model.StorageSourceJobResult,
+			JobResult: &model.JobResultStorageSpec{
+				JobID:       source.JobID,
+				ExecutionID: source.ExecutionID,
+				Publisher:   source.Publisher,
+				Path:        source.Path,
+			},
+		}, nil
 	default:
 		return model.StorageSpec{}, fmt.Errorf("unhandled storage source type: %s", storage.Type)
 	}
@@ -2,6 +2,7 @@
 package models
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
@@ -54,6 +55,16 @@ const (
 
 var domainRegex = regexp.MustCompile(`\b([a-z0-9]+(-[a-z0-9]+)*\.)+[a-z]{2,}\b`)
 
+// portNameRegex matches port names that can be used as a label of a service
+// lookup name.
+var portNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+const (
+	// PortProtocolTCP is the protocol of ports that do not specify one.
+	PortProtocolTCP = "tcp"
+	PortProtocolUDP = "udp"
+)
+
 func ParseNetwork(s string) (Network, error) {
 	for typ := NetworkNone; typ <= NetworkHTTP; typ++ {
 		if strings.EqualFold(typ.String(), strings.TrimSpace(s)) {
@@ -77,6 +88,46 @@ func (n *Network) UnmarshalText(text []byte) (err error) {
 type NetworkConfig struct {
 	Type    Network  `json:"Type"`
 	Domains []string `json:"Domains,omitempty"`
+	// Ports are the ports of the task that are published on the compute
+	// node, so that they can be reached by clients and other jobs.
+	Ports []*PortConfig `json:"Ports,omitempty"`
+}
+
+// PortConfig is a named port of a task that is published on a host port
+// allocated by the compute node.
+type PortConfig struct {
+	// Name identifies the port in the endpoints of the execution, and in
+	// service lookups.
+	Name string `json:"Name"`
+	// Target is the port the task listens on.
+	Target int `json:"Target"`
+	// Protocol is either tcp or udp. Defaults to tcp.
+	Protocol string `json:"Protocol,omitempty"`
+}
+
+// Copy returns a deep copy of the port config.
+func (p *PortConfig) Copy() *PortConfig {
+	if p == nil {
+		return nil
+	}
+	cpy := *p
+	return &cpy
+}
+
+// Validate checks the port config is valid.
+func (p *PortConfig) Validate() (err error) {
+	if !portNameRegex.MatchString(p.Name) {
+		err = multierr.Append(err, fmt.Errorf("invalid port name %q. "+
+			"port names must only contain lowercase letters, digits and hyphens", p.Name))
+	}
+	if p.Target <= 0 || p.Target > 65535 {
+		err = multierr.Append(err, fmt.Errorf("invalid target port %d for port %q", p.Target, p.Name))
+	}
+	if p.Protocol != PortProtocolTCP && p.Protocol != PortProtocolUDP {
+		err = multierr.Append(err, fmt.Errorf("invalid protocol %q for port %q. expected %s or %s",
+			p.Protocol, p.Name, PortProtocolTCP, PortProtocolUDP))
+	}
+	return err
 }
 
 // Disabled returns whether network connections should be completely disabled according
@@ -98,16 +149,43 @@ func (n *NetworkConfig) Normalize() {
 	for i, domain := range n.Domains {
 		n.Domains[i] = strings.TrimSpace(strings.ToLower(domain))
 	}
+	for _, port := range n.Ports {
+		if port == nil {
+			continue
+		}
+		port.Name = strings.TrimSpace(port.Name)
+		port.Protocol = strings.TrimSpace(strings.ToLower(port.Protocol))
+		if port.Protocol == "" {
+			port.Protocol = PortProtocolTCP
+		}
+	}
 }
 
 func (n *NetworkConfig) Copy() *NetworkConfig {
 	if n == nil {
 		return nil
 	}
-	return &NetworkConfig{
+	cpy := &NetworkConfig{
 		Type:    n.Type,
 		Domains: slices.Clone(n.Domains),
 	}
+	if n.Ports != nil {
+		cpy.Ports = CopySlice(n.Ports)
+	}
+	return cpy
+}
+
+// Port returns the port with the given name, or nil if there is none.
+func (n *NetworkConfig) Port(name string) *PortConfig {
+	if n == nil {
+		return nil
+	}
+	for _, port := range n.Ports {
+		if port != nil && port.Name == name {
+			return port
+		}
+	}
+	return nil
 }
 
 // Validate returns an error if any of the fields do not pass validation, or nil
@@ -127,6 +205,22 @@ func (n *NetworkConfig) Validate() (err error) {
 		err = multierr.Append(err, fmt.Errorf("invalid domain %q", domain))
 	}
 
+	if len(n.Ports) > 0 && n.Type != NetworkFull {
+		err = multierr.Append(err, fmt.Errorf("ports can only be published with %s networking", NetworkFull))
+	}
+	seenPorts := make(map[string]bool)
+	for _, port := range n.Ports {
+		if port == nil {
+			err = multierr.Append(err, errors.New("port cannot be nil"))
+			continue
+		}
+		err = multierr.Append(err, port.Validate())
+		if seenPorts[port.Name] {
+			err = multierr.Append(err, fmt.Errorf("port %q is defined more than once", port.Name))
+		}
+		seenPorts[port.Name] = true
+	}
+
 	return
 }
 
@@ -160,6 +254,30 @@ func (n *NetworkConfig) DomainSet() []string {
 	return domains
 }
 
+// AllowsHost returns whether the network config allows connections to the
+// host. Hosts are only restricted with HTTP networking, where they have to
+// match one of the domains in the same way as the Docker HTTP gateway: a
+// domain starting with a dot also matches all of its subdomains.
+func (n *NetworkConfig) AllowsHost(host string) bool {
+	switch n.Type {
+	case NetworkFull:
+		return true
+	case NetworkHTTP:
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if host == "" || strings.HasPrefix(host, ".") {
+			return false
+		}
+		for _, domain := range n.Domains {
+			if matchDomain(domain, host) == 0 {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
 func matchDomain(left, right string) (diff int) {
 	const wildcard = ""
 	lefts := strings.Split(strings.ToLower(strings.Trim(left, " ")), ".")
@@ -228,6 +346,11 @@ func (b *NetworkConfigBuilder) Domains(domains ...string) *NetworkConfigBuilder
 	return b
 }
 
+func (b *NetworkConfigBuilder) Ports(ports ...*PortConfig) *NetworkConfigBuilder {
+	b.network.Ports = ports
+	return b
+}
+
 func (b *NetworkConfigBuilder) Build() (*NetworkConfig, error) {
 	b.network.Normalize()
 	return b.network, b.network.Validate()
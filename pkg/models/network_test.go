@@ -0,0 +1,78 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkConfig_AllowsHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		network NetworkConfig
+		host    string
+		allowed bool
+	}{
+		{
+			name:    "none-allows-nothing",
+			network: NetworkConfig{Type: NetworkNone, Domains: []string{"example.com"}},
+			host:    "example.com",
+			allowed: false,
+		},
+		{
+			name:    "full-allows-anything",
+			network: NetworkConfig{Type: NetworkFull},
+			host:    "example.com",
+			allowed: true,
+		},
+		{
+			name:    "http-allows-domain",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{"example.com"}},
+			host:    "Example.com.",
+			allowed: true,
+		},
+		{
+			name:    "http-denies-subdomain",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{"example.com"}},
+			host:    "api.example.com",
+			allowed: false,
+		},
+		{
+			name:    "http-wildcard-allows-subdomain",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{".example.com"}},
+			host:    "api.example.com",
+			allowed: true,
+		},
+		{
+			name:    "http-wildcard-allows-domain",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{".example.com"}},
+			host:    "example.com",
+			allowed: true,
+		},
+		{
+			name:    "http-denies-other-domain",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{".example.com"}},
+			host:    "example.org",
+			allowed: false,
+		},
+		{
+			name:    "http-allows-ip",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{"192.168.0.1"}},
+			host:    "192.168.0.1",
+			allowed: true,
+		},
+		{
+			name:    "http-denies-empty-host",
+			network: NetworkConfig{Type: NetworkHTTP, Domains: []string{"example.com"}},
+			host:    "",
+			allowed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.network.AllowsHost(tt.host))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/peer"
 	"golang.org/x/exp/maps"
@@ -83,6 +84,23 @@ type NodeInfo struct {
 	Labels          map[string]string `json:"Labels"`
 	ComputeNodeInfo *ComputeNodeInfo  `json:"ComputeNodeInfo,omitempty" yaml:",omitempty"`
 	BacalhauVersion BuildVersionInfo  `json:"BacalhauVersion"`
+	// PublicKey is the base64 encoded libp2p public key that the node signs
+	// the results manifests of its executions with. Orchestrators pin the key
+	// a node first presents, and report the pinned key.
+	PublicKey string `json:"PublicKey,omitempty" yaml:",omitempty"`
+
+	// Membership is the admission state of the node, as recorded by the
+	// orchestrator. It is not set by the node itself.
+	Membership NodeMembershipState `json:"Membership,omitempty" yaml:",omitempty"`
+	// Scheduling tells whether the orchestrator can place new work on the
+	// node, or is moving work away from it. It is not set by the node itself.
+	Scheduling NodeSchedulingState `json:"Scheduling,omitempty" yaml:",omitempty"`
+	// Liveness, LastSeen and LivenessHistory are tracked by node registries
+	// that keep nodes after they stop announcing themselves. They are not set
+	// by the node itself.
+	Liveness        NodeLiveness             `json:"Liveness,omitempty" yaml:",omitempty"`
+	LastSeen        time.Time                `json:"LastSeen,omitempty" yaml:",omitempty"`
+	LivenessHistory []NodeLivenessTransition `json:"LivenessHistory,omitempty" yaml:",omitempty"`
 }
 
 // ID returns the node ID
@@ -100,6 +118,29 @@ func (n NodeInfo) IsComputeNode() bool {
 	return n.NodeType == NodeTypeCompute
 }
 
+// IsAdmitted returns true if the node is allowed to receive work. Nodes
+// without a membership state were not subject to admission and are admitted.
+func (n NodeInfo) IsAdmitted() bool {
+	return n.Membership == nodeMembershipUndefined || n.Membership == NodeMembershipApproved
+}
+
+// IsConnected returns true if executions running on the node are expected to
+// make progress. Nodes without a liveness state are tracked by stores that
+// forget nodes once they stop announcing themselves, and are connected.
+func (n NodeInfo) IsConnected() bool {
+	return n.Liveness == nodeLivenessUndefined ||
+		n.Liveness == NodeLivenessHealthy ||
+		n.Liveness == NodeLivenessSuspect
+}
+
+// IsSchedulable returns true if new work can be placed on the node, which
+// requires the node to be admitted, not cordoned or drained, and not suspected
+// of having gone away.
+func (n NodeInfo) IsSchedulable() bool {
+	return n.IsAdmitted() && n.Scheduling == NodeSchedulable &&
+		(n.Liveness == nodeLivenessUndefined || n.Liveness == NodeLivenessHealthy)
+}
+
 type ComputeNodeInfo struct {
 	ExecutionEngines   []string  `json:"ExecutionEngines"`
 	Publishers         []string  `json:"Publishers"`
@@ -109,4 +150,22 @@ type ComputeNodeInfo struct {
 	MaxJobRequirements Resources `json:"MaxJobRequirements"`
 	RunningExecutions  int       `json:"RunningExecutions"`
 	EnqueuedExecutions int       `json:"EnqueuedExecutions"`
+	// CachedImages are the most recently used docker images held in the
+	// node's image cache, which it can run without pulling them first.
+	CachedImages []string `json:"CachedImages,omitempty"`
+}
+
+// CachedImage is a docker image held in a compute node's image cache.
+type CachedImage struct {
+	// Image is the normalized reference the image was pulled by.
+	Image string `json:"Image"`
+	// ID is the ID of the image in the local docker daemon.
+	ID string `json:"ID"`
+	// Size is the disk space used by the image, in bytes.
+	Size int64 `json:"Size"`
+	// Pinned images were pulled by an operator and are never pruned.
+	Pinned   bool      `json:"Pinned"`
+	PulledAt time.Time `json:"PulledAt"`
+	// LastUsed is when a job last asked for the image.
+	LastUsed time.Time `json:"LastUsed"`
 }
@@ -0,0 +1,56 @@
+//go:generate stringer -type=NodeLiveness -trimprefix=NodeLiveness -output=node_liveness_string.go
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NodeLiveness is how recently the orchestrator heard from a node, as tracked
+// by a node registry that keeps nodes after they stop announcing themselves.
+type NodeLiveness int
+
+const (
+	nodeLivenessUndefined NodeLiveness = iota
+	// NodeLivenessHealthy is the state of a node that announced itself
+	// recently.
+	NodeLivenessHealthy
+	// NodeLivenessSuspect is the state of a node that missed a few
+	// announcements. It is not sent new work, but the executions running on
+	// it are kept.
+	NodeLivenessSuspect
+	// NodeLivenessDisconnected is the state of a node that has not been heard
+	// from for long enough that the executions running on it are lost.
+	NodeLivenessDisconnected
+	// NodeLivenessGone is the state of a node that has been disconnected for
+	// long enough that it is not expected to come back.
+	NodeLivenessGone
+)
+
+func ParseNodeLiveness(s string) (NodeLiveness, error) {
+	for typ := NodeLivenessHealthy; typ <= NodeLivenessGone; typ++ {
+		if strings.EqualFold(typ.String(), strings.TrimSpace(s)) {
+			return typ, nil
+		}
+	}
+
+	return nodeLivenessUndefined, fmt.Errorf("invalid node liveness: %s", s)
+}
+
+func (s NodeLiveness) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *NodeLiveness) UnmarshalText(text []byte) (err error) {
+	name := string(text)
+	*s, err = ParseNodeLiveness(name)
+	return
+}
+
+// NodeLivenessTransition records a change in the liveness of a node.
+type NodeLivenessTransition struct {
+	From NodeLiveness `json:"From"`
+	To   NodeLiveness `json:"To"`
+	Time time.Time    `json:"Time"`
+}
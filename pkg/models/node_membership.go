@@ -0,0 +1,82 @@
+//go:generate stringer -type=NodeMembershipState -trimprefix=NodeMembership -output=node_membership_string.go
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NodeMembershipState is the admission state of a node in the network. Only
+// approved compute nodes are sent work by the orchestrator.
+type NodeMembershipState int
+
+const (
+	nodeMembershipUndefined NodeMembershipState = iota
+	// NodeMembershipPending is the state of a node that has connected but has
+	// not been approved yet.
+	NodeMembershipPending
+	// NodeMembershipApproved is the state of a node that can be sent work.
+	NodeMembershipApproved
+	// NodeMembershipRejected is the state of a pending node that an operator
+	// refused to admit.
+	NodeMembershipRejected
+	// NodeMembershipRevoked is the state of a previously approved node that
+	// is no longer allowed to receive work.
+	NodeMembershipRevoked
+)
+
+func ParseNodeMembershipState(s string) (NodeMembershipState, error) {
+	for typ := NodeMembershipPending; typ <= NodeMembershipRevoked; typ++ {
+		if strings.EqualFold(typ.String(), strings.TrimSpace(s)) {
+			return typ, nil
+		}
+	}
+
+	return nodeMembershipUndefined, fmt.Errorf("invalid node membership state: %s", s)
+}
+
+func (s NodeMembershipState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *NodeMembershipState) UnmarshalText(text []byte) (err error) {
+	name := string(text)
+	*s, err = ParseNodeMembershipState(name)
+	return
+}
+
+// NodeMembership is the admission record the orchestrator keeps for a node.
+type NodeMembership struct {
+	NodeID     string              `json:"NodeID"`
+	State      NodeMembershipState `json:"State"`
+	Scheduling NodeSchedulingState `json:"Scheduling,omitempty"`
+	// DrainDeadline is when executions still running on a draining node are
+	// stopped so that the node can be marked as drained.
+	DrainDeadline time.Time `json:"DrainDeadline,omitempty"`
+	// PublicKey is the public key the node first presented, which it must
+	// keep presenting to stay in the network.
+	PublicKey string `json:"PublicKey,omitempty"`
+	// NonCompute is set when the node was approved because it announced
+	// itself as a node that does not run work. The node is evaluated again if
+	// it later announces itself as a compute node.
+	NonCompute bool      `json:"NonCompute,omitempty"`
+	UpdatedAt  time.Time `json:"UpdatedAt"`
+}
+
+// JoinToken is a short-lived, single-use token that admits a compute node
+// without manual approval. Only a hash of the secret is kept by the
+// orchestrator.
+type JoinToken struct {
+	// ID is a non-secret identifier that can be used to refer to the token.
+	ID string `json:"ID"`
+	// Hash is the hex encoded SHA-256 hash of the token secret.
+	Hash      string    `json:"Hash"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	ExpiresAt time.Time `json:"ExpiresAt"`
+}
+
+// IsExpired returns true if the token can no longer be used at the given time.
+func (t JoinToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,46 @@
+//go:generate stringer -type=NodeSchedulingState -trimprefix=Node -output=node_scheduling_string.go
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeSchedulingState tells whether the orchestrator can place work on an
+// admitted node. Operators cordon or drain nodes before taking them down for
+// maintenance.
+type NodeSchedulingState int
+
+const (
+	// NodeSchedulable is the state of a node that can be sent new work.
+	NodeSchedulable NodeSchedulingState = iota
+	// NodeCordoned is the state of a node that is not sent new work, while
+	// the executions already running on it are left alone.
+	NodeCordoned
+	// NodeDraining is the state of a cordoned node whose service and daemon
+	// executions are moved to other nodes, while batch and ops executions
+	// are given until a deadline to complete.
+	NodeDraining
+	// NodeDrained is the state of a node that has no executions left on it.
+	NodeDrained
+)
+
+func ParseNodeSchedulingState(s string) (NodeSchedulingState, error) {
+	for typ := NodeSchedulable; typ <= NodeDrained; typ++ {
+		if strings.EqualFold(typ.String(), strings.TrimSpace(s)) {
+			return typ, nil
+		}
+	}
+
+	return NodeSchedulable, fmt.Errorf("invalid node scheduling state: %s", s)
+}
+
+func (s NodeSchedulingState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *NodeSchedulingState) UnmarshalText(text []byte) (err error) {
+	name := string(text)
+	*s, err = ParseNodeSchedulingState(name)
+	return
+}
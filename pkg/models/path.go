@@ -2,10 +2,25 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
 
-	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
+)
+
+const (
+	// CompressionNone publishes files as they are
+	CompressionNone = "none"
+	// CompressionGzip compresses each published file with gzip
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses each published file with zstd
+	CompressionZstd = "zstd"
 )
 
 type ResultPath struct {
@@ -13,6 +28,17 @@ type ResultPath struct {
 	Name string `json:"Name"`
 	// The path to the file/dir
 	Path string `json:"Path"`
+	// Include lists glob patterns, relative to the path, of the files to
+	// publish. All files are published if empty.
+	Include []string `json:"Include,omitempty"`
+	// Exclude lists glob patterns, relative to the path, of the files not to
+	// publish. Exclusions take precedence over inclusions.
+	Exclude []string `json:"Exclude,omitempty"`
+	// MaxFileSize is the largest file that will be published, as a
+	// github.com/dustin/go-humanize string. Larger files are dropped.
+	MaxFileSize string `json:"MaxFileSize,omitempty"`
+	// Compression applied to each published file. One of none, gzip or zstd.
+	Compression string `json:"Compression,omitempty"`
 }
 
 // Normalize normalizes the path to a canonical form
@@ -22,6 +48,14 @@ func (p *ResultPath) Normalize() {
 	}
 	p.Name = strings.TrimSpace(p.Name)
 	p.Path = strings.TrimSpace(p.Path)
+	p.MaxFileSize = strings.TrimSpace(p.MaxFileSize)
+	p.Compression = strings.ToLower(strings.TrimSpace(p.Compression))
+	if p.Include == nil {
+		p.Include = []string{}
+	}
+	if p.Exclude == nil {
+		p.Exclude = []string{}
+	}
 }
 
 // Copy returns a copy of the path
@@ -30,8 +64,12 @@ func (p *ResultPath) Copy() *ResultPath {
 		return nil
 	}
 	return &ResultPath{
-		Name: p.Name,
-		Path: p.Path,
+		Name:        p.Name,
+		Path:        p.Path,
+		Include:     slices.Clone(p.Include),
+		Exclude:     slices.Clone(p.Exclude),
+		MaxFileSize: p.MaxFileSize,
+		Compression: p.Compression,
 	}
 }
 
@@ -46,6 +84,66 @@ func (p *ResultPath) Validate() error {
 	}
 	if validate.IsBlank(p.Name) {
 		mErr.Errors = append(mErr.Errors, errors.New("resultpath name is blank"))
+	} else if !p.HasLocalName() {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("resultpath name %q must be a relative path within the results", p.Name))
+	}
+	for _, pattern := range append(slices.Clone(p.Include), p.Exclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid glob pattern: %q", pattern))
+		}
+	}
+	if _, err := p.MaxFileSizeBytes(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	switch p.Compression {
+	case "", CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unsupported compression %q", p.Compression))
 	}
 	return mErr.ErrorOrNil()
 }
+
+// HasLocalName returns true if the name of the result path is a relative path
+// within the results directory, and not the results directory itself.
+func (p *ResultPath) HasLocalName() bool {
+	return filepath.IsLocal(p.Name) && filepath.Clean(p.Name) != "."
+}
+
+// MaxFileSizeBytes returns the parsed MaxFileSize, or zero if there is no limit.
+func (p *ResultPath) MaxFileSizeBytes() (uint64, error) {
+	if p.MaxFileSize == "" {
+		return 0, nil
+	}
+	size, err := humanize.ParseBytes(p.MaxFileSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid max file size %q: %w", p.MaxFileSize, err)
+	}
+	return size, nil
+}
+
+// Publishes returns true if the file at the given slash-separated path,
+// relative to the result path, passes the include and exclude patterns.
+func (p *ResultPath) Publishes(rel string) bool {
+	if p.Excludes(rel) {
+		return false
+	}
+	if len(p.Include) == 0 {
+		return true
+	}
+	return matchesAny(p.Include, rel)
+}
+
+// Excludes returns true if the given slash-separated path, relative to the
+// result path, matches one of the exclude patterns.
+func (p *ResultPath) Excludes(rel string) bool {
+	return matchesAny(p.Exclude, rel)
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if match, _ := doublestar.Match(pattern, rel); match {
+			return true
+		}
+	}
+	return false
+}
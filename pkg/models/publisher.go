@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+)
+
+// PublisherConfig is one of the additional publishers a task's results are
+// published with.
+type PublisherConfig struct {
+	SpecConfig
+
+	// Optional publishers do not fail the execution when they fail to
+	// publish, or when the compute node doesn't support them.
+	Optional bool `json:"Optional,omitempty"`
+}
+
+// NewPublisherConfig returns a required publisher config for the given spec.
+func NewPublisherConfig(spec *SpecConfig) *PublisherConfig {
+	p := &PublisherConfig{}
+	if spec != nil {
+		p.SpecConfig = *spec.Copy()
+	}
+	return p
+}
+
+func (p *PublisherConfig) Normalize() {
+	if p == nil {
+		return
+	}
+	p.SpecConfig.Normalize()
+}
+
+func (p *PublisherConfig) Copy() *PublisherConfig {
+	if p == nil {
+		return nil
+	}
+	return &PublisherConfig{
+		SpecConfig: *p.SpecConfig.Copy(),
+		Optional:   p.Optional,
+	}
+}
+
+func (p *PublisherConfig) Validate() error {
+	if p == nil {
+		return errors.New("nil publisher config")
+	}
+	return p.SpecConfig.Validate()
+}
+
+// Spec returns the publisher's spec config.
+func (p *PublisherConfig) Spec() *SpecConfig {
+	return &p.SpecConfig
+}
+
+// PublishedResult is the result of one of the publishers of a task.
+type PublishedResult struct {
+	// Publisher is the type of publisher that produced the result
+	Publisher string `json:"Publisher"`
+
+	// Result describes where the published results are stored
+	Result *SpecConfig `json:"Result"`
+}
+
+func (r *PublishedResult) Copy() *PublishedResult {
+	if r == nil {
+		return nil
+	}
+	return &PublishedResult{
+		Publisher: r.Publisher,
+		Result:    r.Result.Copy(),
+	}
+}
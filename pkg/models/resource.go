@@ -21,6 +21,8 @@ type ResourcesConfig struct {
 	// Memory github.com/dustin/go-humanize string
 	Disk string `json:"Disk,omitempty"`
 	GPU  string `json:"GPU,omitempty"`
+	// GPUSelector constrains which of the node's GPUs can be allocated
+	GPUSelector *GPUSelectorConfig `json:"GPUSelector,omitempty"`
 }
 
 // Normalize normalizes the resources
@@ -40,6 +42,11 @@ func (r *ResourcesConfig) Normalize() {
 	r.Memory = sanitizeResourceString(r.Memory)
 	r.Disk = sanitizeResourceString(r.Disk)
 	r.GPU = sanitizeResourceString(r.GPU)
+	if r.GPUSelector != nil {
+		r.GPUSelector.Vendor = strings.TrimSpace(r.GPUSelector.Vendor)
+		r.GPUSelector.Model = strings.TrimSpace(r.GPUSelector.Model)
+		r.GPUSelector.MinMemory = sanitizeResourceString(r.GPUSelector.MinMemory)
+	}
 }
 
 // Copy returns a deep copy of the resources
@@ -49,6 +56,10 @@ func (r *ResourcesConfig) Copy() *ResourcesConfig {
 	}
 	newR := new(ResourcesConfig)
 	*newR = *r
+	if r.GPUSelector != nil {
+		selector := *r.GPUSelector
+		newR.GPUSelector = &selector
+	}
 	return newR
 }
 
@@ -101,10 +112,58 @@ func (r *ResourcesConfig) ToResources() (*Resources, error) {
 		}
 		res.GPU = gpu
 	}
+	if r.GPUSelector != nil {
+		selector, err := r.GPUSelector.ToGPUSelector()
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+		res.GPUSelector = selector
+	}
 
 	return res, mErr.ErrorOrNil()
 }
 
+// GPUSelectorConfig selects the GPUs that can be allocated to a task by their
+// vendor, model and memory.
+type GPUSelectorConfig struct {
+	// Vendor of the GPUs, e.g. NVIDIA, AMD or Intel
+	Vendor string `json:"Vendor,omitempty"`
+	// Model is matched case-insensitively against part of the GPU model name, e.g. A100
+	Model string `json:"Model,omitempty"`
+	// MinMemory github.com/dustin/go-humanize string of the minimum GPU memory
+	MinMemory string `json:"MinMemory,omitempty"`
+	// Shared allows the GPUs to be time-sliced with other tasks on nodes that share GPUs
+	Shared bool `json:"Shared,omitempty"`
+}
+
+// ToGPUSelector converts the GPU selector config to a GPU selector
+func (c *GPUSelectorConfig) ToGPUSelector() (*GPUSelector, error) {
+	if c == nil {
+		return nil, nil
+	}
+	var mErr multierror.Error
+	selector := &GPUSelector{
+		Model:  c.Model,
+		Shared: c.Shared,
+	}
+	if c.Vendor != "" {
+		vendor, err := ParseGPUVendor(c.Vendor)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+		selector.Vendor = vendor
+	}
+	if c.MinMemory != "" {
+		mem, err := humanize.ParseBytes(c.MinMemory)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid GPU memory value: %s", c.MinMemory))
+		}
+		// round up so that GPUs with less memory than asked for are never selected
+		selector.MinMemory = (mem + mebibyte - 1) / mebibyte
+	}
+	return selector, mErr.ErrorOrNil()
+}
+
 type ResourcesConfigBuilder struct {
 	resources *ResourcesConfig
 }
@@ -133,6 +192,11 @@ func (r *ResourcesConfigBuilder) GPU(gpu string) *ResourcesConfigBuilder {
 	return r
 }
 
+func (r *ResourcesConfigBuilder) GPUSelector(selector *GPUSelectorConfig) *ResourcesConfigBuilder {
+	r.resources.GPUSelector = selector
+	return r
+}
+
 func (r *ResourcesConfigBuilder) Build() (*ResourcesConfig, error) {
 	r.resources.Normalize()
 	return r.resources, r.resources.Validate()
@@ -155,6 +219,23 @@ const (
 	GPUVendorIntel  GPUVendor = "Intel"
 )
 
+const mebibyte = 1024 * 1024
+
+// ParseGPUVendor returns the vendor with the name, ignoring case. AMD GPUs can
+// be named AMD, ATI or AMD/ATI.
+func ParseGPUVendor(name string) (GPUVendor, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "nvidia":
+		return GPUVendorNvidia, nil
+	case "amd", "ati", "amd/ati":
+		return GPUVendorAMDATI, nil
+	case "intel":
+		return GPUVendorIntel, nil
+	default:
+		return "", fmt.Errorf("invalid GPU vendor: %s", name)
+	}
+}
+
 type GPU struct {
 	// Self-reported index of the device in the system
 	Index uint64
@@ -169,6 +250,69 @@ type GPU struct {
 	PCIAddress string
 }
 
+// GPUSelector selects the GPUs that can be allocated to a task. Empty fields
+// match any GPU.
+type GPUSelector struct {
+	// Vendor of the GPUs
+	Vendor GPUVendor `json:"Vendor,omitempty"`
+	// Model is matched case-insensitively against part of the GPU model name
+	Model string `json:"Model,omitempty"`
+	// Minimum GPU memory in mebibytes (MiB)
+	MinMemory uint64 `json:"MinMemory,omitempty"`
+	// Shared allows the GPUs to be time-sliced with other tasks on nodes that share GPUs
+	Shared bool `json:"Shared,omitempty"`
+}
+
+// Copy returns a copy of the selector
+func (s *GPUSelector) Copy() *GPUSelector {
+	if s == nil {
+		return nil
+	}
+	newS := *s
+	return &newS
+}
+
+// IsEmpty returns true if the selector doesn't constrain which GPUs are selected
+func (s *GPUSelector) IsEmpty() bool {
+	return s == nil || (s.Vendor == "" && s.Model == "" && s.MinMemory == 0)
+}
+
+// Matches returns true if the GPU is selected
+func (s *GPUSelector) Matches(gpu GPU) bool {
+	if s == nil {
+		return true
+	}
+	if s.Vendor != "" && s.Vendor != gpu.Vendor {
+		return false
+	}
+	if s.Model != "" && !strings.Contains(strings.ToLower(gpu.Name), strings.ToLower(s.Model)) {
+		return false
+	}
+	return gpu.Memory >= s.MinMemory
+}
+
+// Filter returns the GPUs that are selected
+func (s *GPUSelector) Filter(gpus []GPU) []GPU {
+	return lo.Filter(gpus, func(gpu GPU, _ int) bool { return s.Matches(gpu) })
+}
+
+func (s *GPUSelector) String() string {
+	var parts []string
+	if s.Vendor != "" {
+		parts = append(parts, fmt.Sprintf("Vendor: %s", s.Vendor))
+	}
+	if s.Model != "" {
+		parts = append(parts, fmt.Sprintf("Model: %s", s.Model))
+	}
+	if s.MinMemory != 0 {
+		parts = append(parts, fmt.Sprintf("MinMemory: %d MiB", s.MinMemory))
+	}
+	if s.Shared {
+		parts = append(parts, "Shared")
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
 type Resources struct {
 	// CPU units
 	CPU float64 `json:"CPU,omitempty"`
@@ -180,6 +324,8 @@ type Resources struct {
 	GPU uint64 `json:"GPU,omitempty"`
 	// GPU details
 	GPUs []GPU `json:"GPUs,omitempty"`
+	// GPUSelector constrains which GPUs can be allocated
+	GPUSelector *GPUSelector `json:"GPUSelector,omitempty"`
 }
 
 // Copy returns a deep copy of the resources
@@ -189,6 +335,7 @@ func (r *Resources) Copy() *Resources {
 	}
 	newR := new(Resources)
 	*newR = *r
+	newR.GPUSelector = r.GPUSelector.Copy()
 	return newR
 }
 
@@ -209,6 +356,9 @@ func (r *Resources) Validate() error {
 		// But the number should always be at least the length of the GPUs array
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("%d GPUs specified but have details for %d", r.GPU, len(r.GPUs)))
 	}
+	if r.GPUSelector != nil && r.GPU == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("GPU selector specified without requesting any GPUs"))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -230,17 +380,25 @@ func (r *Resources) Merge(other Resources) *Resources {
 	if len(newR.GPUs) <= 0 {
 		newR.GPUs = other.GPUs
 	}
+	if newR.GPUSelector == nil {
+		newR.GPUSelector = other.GPUSelector.Copy()
+	}
 	return newR
 }
 
-// Add returns the sum of the resources
+// Add returns the sum of the resources, keeping the GPU selector of either
 func (r *Resources) Add(other Resources) *Resources {
+	selector := r.GPUSelector
+	if selector == nil {
+		selector = other.GPUSelector
+	}
 	return &Resources{
-		CPU:    r.CPU + other.CPU,
-		Memory: r.Memory + other.Memory,
-		Disk:   r.Disk + other.Disk,
-		GPU:    r.GPU + other.GPU,
-		GPUs:   append(r.GPUs, other.GPUs...),
+		CPU:         r.CPU + other.CPU,
+		Memory:      r.Memory + other.Memory,
+		Disk:        r.Disk + other.Disk,
+		GPU:         r.GPU + other.GPU,
+		GPUs:        append(r.GPUs, other.GPUs...),
+		GPUSelector: selector.Copy(),
 	}
 }
 
@@ -278,8 +436,20 @@ func (r *Resources) LessThan(other Resources) bool {
 	return r.CPU < other.CPU && r.Memory < other.Memory && r.Disk < other.Disk && r.GPU < other.GPU
 }
 
+// LessThanEq returns true if the resources fit within the other resources,
+// including having enough GPUs that match the GPU selector.
 func (r *Resources) LessThanEq(other Resources) bool {
-	return r.CPU <= other.CPU && r.Memory <= other.Memory && r.Disk <= other.Disk && r.GPU <= other.GPU
+	return r.CPU <= other.CPU && r.Memory <= other.Memory && r.Disk <= other.Disk && r.GPU <= other.GPU &&
+		r.HasMatchingGPUs(other.GPUs)
+}
+
+// HasMatchingGPUs returns true if enough of the GPUs match the GPU selector.
+// GPUs without details never match a selector.
+func (r *Resources) HasMatchingGPUs(gpus []GPU) bool {
+	if r.GPU == 0 || r.GPUSelector.IsEmpty() {
+		return true
+	}
+	return uint64(len(r.GPUSelector.Filter(gpus))) >= r.GPU
 }
 
 func (r *Resources) Max(other Resources) *Resources {
@@ -308,6 +478,9 @@ func (r *Resources) IsZero() bool {
 func (r *Resources) String() string {
 	mem := humanize.Bytes(r.Memory)
 	disk := humanize.Bytes(r.Disk)
+	if r.GPUSelector != nil {
+		return fmt.Sprintf("{CPU: %f, Memory: %s, Disk: %s, GPU: %d, GPUSelector: %s}", r.CPU, mem, disk, r.GPU, r.GPUSelector)
+	}
 	return fmt.Sprintf("{CPU: %f, Memory: %s, Disk: %s, GPU: %d}", r.CPU, mem, disk, r.GPU)
 }
 
@@ -30,3 +30,29 @@ func TestResourceBytes(t *testing.T) {
 		require.Equal(t, p.exp, actual.Disk)
 	}
 }
+
+func TestGPUSelector(t *testing.T) {
+	cfg, err := NewResourcesConfigBuilder().
+		GPU("1").
+		GPUSelector(&GPUSelectorConfig{Vendor: "AMD", Model: " Radeon ", MinMemory: "16 GB", Shared: true}).
+		Build()
+	require.NoError(t, err)
+	resources, err := cfg.ToResources()
+	require.NoError(t, err)
+	require.Equal(t, &GPUSelector{Vendor: GPUVendorAMDATI, Model: "Radeon", MinMemory: 15259, Shared: true}, resources.GPUSelector)
+
+	gpus := []GPU{
+		{Index: 0, Name: "Radeon Pro VII", Vendor: GPUVendorAMDATI, Memory: 16384},
+		{Index: 1, Name: "Radeon RX 6400", Vendor: GPUVendorAMDATI, Memory: 4096},
+		{Index: 0, Name: "Tesla T4", Vendor: GPUVendorNvidia, Memory: 15360},
+	}
+	require.Equal(t, gpus[:1], resources.GPUSelector.Filter(gpus))
+	require.True(t, resources.LessThanEq(Resources{GPU: 3, GPUs: gpus}))
+	require.False(t, resources.LessThanEq(Resources{GPU: 3, GPUs: gpus[1:]}))
+	require.False(t, resources.LessThanEq(Resources{GPU: 3}))
+
+	_, err = NewResourcesConfigBuilder().GPU("1").GPUSelector(&GPUSelectorConfig{Vendor: "3dfx"}).Build()
+	require.Error(t, err)
+	_, err = NewResourcesConfigBuilder().GPUSelector(&GPUSelectorConfig{Vendor: "nvidia"}).Build()
+	require.Error(t, err)
+}
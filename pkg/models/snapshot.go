@@ -0,0 +1,203 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/exp/slices"
+)
+
+// LatestSnapshot selects the most recent snapshot of an execution when
+// requesting snapshot results.
+const LatestSnapshot = -1
+
+// MinSnapshotInterval is the minimum time between snapshots of an execution
+// in seconds. Snapshots taken when files are closed are delayed to respect it.
+const MinSnapshotInterval = 30
+
+// MaxSnapshots is the maximum number of snapshots recorded for an execution.
+// Compute nodes publish a full snapshot every MaxSnapshots versions, which
+// replaces the snapshots recorded before it.
+const MaxSnapshots = 50
+
+// SnapshotConfig enables publishing a task's results while it is still
+// running. Long running jobs never complete, so snapshots are the only way
+// their output files get published.
+type SnapshotConfig struct {
+	// Interval is the time between snapshots in seconds.
+	// Zero disables periodic snapshots.
+	Interval int64 `json:"Interval,omitempty"`
+
+	// OnFileClose takes a snapshot whenever a file that was open for writing
+	// in one of the snapshot paths is closed.
+	OnFileClose bool `json:"OnFileClose,omitempty"`
+
+	// Paths are the names of the result paths to snapshot.
+	// All of the task's result paths are snapshot if empty.
+	Paths []string `json:"Paths,omitempty"`
+}
+
+// Enabled returns true if snapshots should be taken.
+func (s *SnapshotConfig) Enabled() bool {
+	return s != nil && (s.Interval > 0 || s.OnFileClose)
+}
+
+// GetInterval returns the time between snapshots.
+func (s *SnapshotConfig) GetInterval() time.Duration {
+	return time.Duration(s.Interval) * time.Second
+}
+
+// Includes returns true if the result path with the given name is snapshot.
+func (s *SnapshotConfig) Includes(name string) bool {
+	return len(s.Paths) == 0 || slices.Contains(s.Paths, name)
+}
+
+// Copy returns a deep copy of the snapshot config.
+func (s *SnapshotConfig) Copy() *SnapshotConfig {
+	if s == nil {
+		return nil
+	}
+	return &SnapshotConfig{
+		Interval:    s.Interval,
+		OnFileClose: s.OnFileClose,
+		Paths:       slices.Clone(s.Paths),
+	}
+}
+
+// Validate checks the snapshot config against the result paths of its task.
+func (s *SnapshotConfig) Validate(resultPaths []*ResultPath) error {
+	if s == nil {
+		return nil
+	}
+	var mErr multierror.Error
+	if s.Interval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid snapshot interval value: %s", s.GetInterval()))
+	} else if s.Interval > 0 && s.Interval < MinSnapshotInterval {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshot interval %s is less than the minimum of %s",
+			s.GetInterval(), time.Duration(MinSnapshotInterval)*time.Second))
+	}
+	if !s.Enabled() {
+		return mErr.ErrorOrNil()
+	}
+	if len(resultPaths) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("result paths must be set if snapshots are enabled"))
+	}
+	for _, name := range s.Paths {
+		if !slices.ContainsFunc(resultPaths, func(p *ResultPath) bool { return p.Name == name }) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshot path %s is not a result path", name))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ResultSnapshot is a versioned set of results published while an execution
+// was running. Each snapshot only holds the files that changed since the
+// previous one, so snapshots must be applied in order of their versions to
+// reconstruct the results at a given version.
+type ResultSnapshot struct {
+	// Version of the snapshot, starting at 1 for the first snapshot of an execution
+	Version int `json:"Version"`
+
+	// ExecutionID of the execution the snapshot was taken from
+	ExecutionID string `json:"ExecutionID"`
+
+	// CreateTime is the time the snapshot was taken
+	CreateTime int64 `json:"CreateTime"`
+
+	// Results holds the result of each of the task's publishers
+	Results []*PublishedResult `json:"Results"`
+
+	// Removed lists the paths, relative to the root of the results, of the
+	// files that were removed since the previous snapshot
+	Removed []string `json:"Removed,omitempty"`
+
+	// Full is true if the snapshot holds all the files of the results rather
+	// than the changes since the previous snapshot, so that the snapshots
+	// before it are no longer needed to reconstruct the results
+	Full bool `json:"Full,omitempty"`
+
+	// Manifest is the signature of the snapshot's manifest, if signed
+	Manifest *ResultManifestSignature `json:"Manifest,omitempty"`
+}
+
+// Copy returns a deep copy of the snapshot.
+func (s *ResultSnapshot) Copy() *ResultSnapshot {
+	if s == nil {
+		return nil
+	}
+	cpy := *s
+	cpy.Results = CopySlice(s.Results)
+	cpy.Removed = slices.Clone(s.Removed)
+	cpy.Manifest = s.Manifest.Copy()
+	return &cpy
+}
+
+// Validate checks that the snapshot has a version, and that the removed paths
+// are relative paths within the results.
+func (s *ResultSnapshot) Validate() error {
+	var mErr multierror.Error
+	if s.Version <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid snapshot version: %d", s.Version))
+	}
+	for _, removed := range s.Removed {
+		if !IsLocalResultPath(removed) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid removed path %q", removed))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// AppendSnapshot records a snapshot in the snapshots of an execution, which
+// are kept in order of their versions. The snapshot replaces a snapshot with
+// the same version, and snapshots taken before the latest full snapshot are
+// discarded. It fails if more than MaxSnapshots snapshots would be recorded.
+func AppendSnapshot(snapshots []*ResultSnapshot, snapshot *ResultSnapshot) ([]*ResultSnapshot, error) {
+	result := make([]*ResultSnapshot, 0, len(snapshots)+1)
+	for _, existing := range snapshots {
+		if existing.Version != snapshot.Version {
+			result = append(result, existing)
+		}
+	}
+	result = append(result, snapshot)
+
+	var latestFull *ResultSnapshot
+	for _, existing := range result {
+		if existing.Full && (latestFull == nil || existing.CreateTime > latestFull.CreateTime) {
+			latestFull = existing
+		}
+	}
+	if latestFull != nil {
+		kept := result[:0]
+		for _, existing := range result {
+			if existing.CreateTime >= latestFull.CreateTime {
+				kept = append(kept, existing)
+			}
+		}
+		result = kept
+		if !slices.Contains(result, snapshot) {
+			return nil, fmt.Errorf("snapshot %d was taken before full snapshot %d", snapshot.Version, latestFull.Version)
+		}
+	}
+	if len(result) > MaxSnapshots {
+		return nil, fmt.Errorf("execution has more than %d snapshots since its latest full snapshot", MaxSnapshots)
+	}
+	slices.SortFunc(result, func(a, b *ResultSnapshot) bool {
+		return a.Version < b.Version
+	})
+	return result, nil
+}
+
+// IsLocalResultPath returns true if the slash separated path names a file or
+// directory within the results, and not the root of the results itself.
+func IsLocalResultPath(path string) bool {
+	local := filepath.FromSlash(path)
+	return filepath.IsLocal(local) && filepath.Clean(local) != "."
+}
+
+// GetCreateTime returns the time the snapshot was taken.
+func (s *ResultSnapshot) GetCreateTime() time.Time {
+	return time.Unix(0, s.CreateTime).UTC()
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
 )
@@ -19,6 +20,10 @@ type Task struct {
 
 	Publisher *SpecConfig `json:"Publisher"`
 
+	// Publishers is a list of additional publishers that the task's results
+	// are published with, in parallel with Publisher.
+	Publishers []*PublisherConfig `json:"Publishers,omitempty"`
+
 	// Map of environment variables to be used by the driver
 	Env map[string]string `json:"Env,omitempty"`
 
@@ -38,6 +43,16 @@ type Task struct {
 	Network *NetworkConfig `json:"Network,omitempty"`
 
 	Timeouts *TimeoutConfig `json:"Timeouts,omitempty"`
+
+	// Snapshots configures publishing the task's results while it is running.
+	Snapshots *SnapshotConfig `json:"Snapshots,omitempty"`
+
+	// Checkpoint configures checkpointing the task, so that it resumes from
+	// its latest checkpoint when it is rescheduled.
+	Checkpoint *CheckpointConfig `json:"Checkpoint,omitempty"`
+
+	// HealthCheck configures checking the health of the task while it is running.
+	HealthCheck *HealthCheckConfig `json:"HealthCheck,omitempty"`
 }
 
 func (t *Task) MetricAttributes() []attribute.KeyValue {
@@ -62,6 +77,9 @@ func (t *Task) Normalize() {
 	if t.ResultPaths == nil {
 		t.ResultPaths = make([]*ResultPath, 0)
 	}
+	if t.Publishers == nil {
+		t.Publishers = make([]*PublisherConfig, 0)
+	}
 	if t.ResourcesConfig == nil {
 		t.ResourcesConfig = &ResourcesConfig{}
 	}
@@ -80,6 +98,7 @@ func (t *Task) Normalize() {
 	t.ResourcesConfig.Normalize()
 	NormalizeSlice(t.InputSources)
 	NormalizeSlice(t.ResultPaths)
+	NormalizeSlice(t.Publishers)
 	t.Network.Normalize()
 	t.ResourcesConfig.Normalize()
 }
@@ -92,6 +111,7 @@ func (t *Task) Copy() *Task {
 	*nt = *t
 	nt.Engine = t.Engine.Copy()
 	nt.Publisher = t.Publisher.Copy()
+	nt.Publishers = CopySlice(t.Publishers)
 	nt.ResourcesConfig = t.ResourcesConfig.Copy()
 	nt.InputSources = CopySlice(t.InputSources)
 	nt.ResultPaths = CopySlice(t.ResultPaths)
@@ -99,6 +119,9 @@ func (t *Task) Copy() *Task {
 	nt.Env = maps.Clone(t.Env)
 	nt.Network = t.Network.Copy()
 	nt.Timeouts = t.Timeouts.Copy()
+	nt.Snapshots = t.Snapshots.Copy()
+	nt.Checkpoint = t.Checkpoint.Copy()
+	nt.HealthCheck = t.HealthCheck.Copy()
 	return nt
 }
 
@@ -131,15 +154,35 @@ func (t *Task) ValidateSubmission() error {
 	if err := t.Publisher.ValidateAllowBlank(); err != nil {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("publisher validation failed: %v", err))
 	}
+	if err := ValidateSlice(t.Publishers); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("publisher validation failed: %v", err))
+	}
 	if err := ValidateSlice(t.InputSources); err != nil {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("artifact validation failed: %v", err))
 	}
 	if err := ValidateSlice(t.ResultPaths); err != nil {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("output validation failed: %v", err))
 	}
-	if len(t.ResultPaths) > 0 && t.Publisher.IsEmpty() {
+	if len(t.ResultPaths) > 0 && len(t.AllPublishers()) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("publisher must be set if result paths are set"))
 	}
+	if err := t.Snapshots.Validate(t.ResultPaths); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshots validation failed: %v", err))
+	}
+	if err := t.Checkpoint.Validate(t.ResultPaths, t.AllPublishers()); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("checkpoint validation failed: %v", err))
+	}
+	if t.Network != nil {
+		if err := t.Network.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("network validation failed: %v", err))
+		}
+	}
+	if err := t.HealthCheck.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("health check validation failed: %v", err))
+	}
+	if t.HealthCheck != nil && t.HealthCheck.Type != HealthCheckExec && (t.Network == nil || t.Network.Type == NetworkNone) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("%s health checks require the task to have network access", t.HealthCheck.Type))
+	}
 
 	seenInputAliases := make(map[string]bool)
 	for _, input := range t.InputSources {
@@ -152,6 +195,33 @@ func (t *Task) ValidateSubmission() error {
 	return mErr.ErrorOrNil()
 }
 
+// AllPublishers returns the task's Publisher, if set, followed by its
+// additional Publishers.
+func (t *Task) AllPublishers() []*PublisherConfig {
+	publishers := make([]*PublisherConfig, 0, len(t.Publishers)+1)
+	if !t.Publisher.IsEmpty() {
+		publishers = append(publishers, NewPublisherConfig(t.Publisher))
+	}
+	for _, p := range t.Publishers {
+		if p != nil && !p.IsEmpty() {
+			publishers = append(publishers, p)
+		}
+	}
+	return publishers
+}
+
+// RequiredPublisherTypes returns the types of the publishers that must be
+// supported to run the task.
+func (t *Task) RequiredPublisherTypes() []string {
+	var types []string
+	for _, p := range t.AllPublishers() {
+		if !p.Optional && !slices.Contains(types, p.Type) {
+			types = append(types, p.Type)
+		}
+	}
+	return types
+}
+
 // ToBuilder returns a new task builder with the same values as the task
 func (t *Task) ToBuilder() *TaskBuilder {
 	return NewTaskBuilderFromTask(t)
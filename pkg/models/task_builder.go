@@ -41,6 +41,11 @@ func (b *TaskBuilder) Publisher(publisher *SpecConfig) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Publishers(publishers ...*PublisherConfig) *TaskBuilder {
+	b.task.Publishers = publishers
+	return b
+}
+
 func (b *TaskBuilder) ResourcesConfig(resourcesConfig *ResourcesConfig) *TaskBuilder {
 	b.task.ResourcesConfig = resourcesConfig
 	return b
@@ -66,6 +71,21 @@ func (b *TaskBuilder) Timeouts(timeouts *TimeoutConfig) *TaskBuilder {
 	return b
 }
 
+func (b *TaskBuilder) Snapshots(snapshots *SnapshotConfig) *TaskBuilder {
+	b.task.Snapshots = snapshots
+	return b
+}
+
+func (b *TaskBuilder) HealthCheck(healthCheck *HealthCheckConfig) *TaskBuilder {
+	b.task.HealthCheck = healthCheck
+	return b
+}
+
+func (b *TaskBuilder) Checkpoint(checkpoint *CheckpointConfig) *TaskBuilder {
+	b.task.Checkpoint = checkpoint
+	return b
+}
+
 func (b *TaskBuilder) Build() (*Task, error) {
 	b.task.Normalize()
 	return b.task, b.task.Validate()
@@ -0,0 +1,320 @@
+//go:build unit || !integration
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskTestSuite struct {
+	suite.Suite
+}
+
+func TestTaskTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskTestSuite))
+}
+
+func (s *TaskTestSuite) task() *Task {
+	task := &Task{
+		Name:   "task",
+		Engine: NewSpecConfig("docker"),
+	}
+	task.Normalize()
+	return task
+}
+
+func (s *TaskTestSuite) TestAllPublishers() {
+	task := s.task()
+	s.Empty(task.AllPublishers())
+	s.Empty(task.RequiredPublisherTypes())
+
+	task.Publisher = NewSpecConfig(PublisherLocal)
+	task.Publishers = []*PublisherConfig{
+		{SpecConfig: *NewSpecConfig(PublisherS3).WithParam("Bucket", "b")},
+		{SpecConfig: *NewSpecConfig(PublisherIPFS), Optional: true},
+		{},
+	}
+
+	publishers := task.AllPublishers()
+	s.Require().Len(publishers, 3)
+	s.Equal(PublisherLocal, publishers[0].Type)
+	s.False(publishers[0].Optional)
+	s.Equal(PublisherS3, publishers[1].Type)
+	s.Equal(PublisherIPFS, publishers[2].Type)
+	s.Equal([]string{PublisherLocal, PublisherS3}, task.RequiredPublisherTypes())
+}
+
+func (s *TaskTestSuite) TestPublishersWithoutPublisher() {
+	task := s.task()
+	task.Publishers = []*PublisherConfig{{SpecConfig: *NewSpecConfig(PublisherS3)}}
+	task.ResultPaths = []*ResultPath{{Name: "outputs", Path: "/outputs"}}
+	s.NoError(task.ValidateSubmission())
+	s.Equal([]string{PublisherS3}, task.RequiredPublisherTypes())
+}
+
+func (s *TaskTestSuite) TestValidatePublishers() {
+	task := s.task()
+	task.ResultPaths = []*ResultPath{{Name: "outputs", Path: "/outputs"}}
+	s.ErrorContains(task.ValidateSubmission(), "publisher must be set")
+
+	task.Publishers = []*PublisherConfig{{SpecConfig: SpecConfig{Params: map[string]interface{}{"a": "b"}}}}
+	s.ErrorContains(task.ValidateSubmission(), "publisher validation failed")
+}
+
+func (s *TaskTestSuite) TestCopyPublishers() {
+	task := s.task()
+	task.Publishers = []*PublisherConfig{{SpecConfig: *NewSpecConfig(PublisherS3).WithParam("Key", "a"), Optional: true}}
+
+	cpy := task.Copy()
+	s.Equal(task.Publishers, cpy.Publishers)
+	cpy.Publishers[0].Params["Key"] = "b"
+	s.Equal("a", task.Publishers[0].Params["Key"])
+}
+
+func (s *TaskTestSuite) TestPublisherConfigJSON() {
+	var config PublisherConfig
+	s.Require().NoError(json.Unmarshal([]byte(`{"Type":"s3","Params":{"Bucket":"b"},"Optional":true}`), &config))
+	s.Equal(PublisherS3, config.Type)
+	s.Equal("b", config.Params["Bucket"])
+	s.True(config.Optional)
+}
+
+func (s *TaskTestSuite) TestResultPathValidate() {
+	valid := &ResultPath{Name: "outputs", Path: "/outputs", Include: []string{"**/*.csv"}, MaxFileSize: "10MB",
+		Compression: CompressionZstd}
+	s.NoError(valid.Validate())
+
+	s.Error((&ResultPath{Name: "outputs", Path: "/outputs", Exclude: []string{"[a-"}}).Validate())
+	s.Error((&ResultPath{Name: "outputs", Path: "/outputs", MaxFileSize: "lots"}).Validate())
+	s.Error((&ResultPath{Name: "outputs", Path: "/outputs", Compression: "brotli"}).Validate())
+	s.ErrorContains((&ResultPath{Name: "../../var/lib", Path: "/outputs"}).Validate(), "relative path within the results")
+	s.ErrorContains((&ResultPath{Name: ".", Path: "/outputs"}).Validate(), "relative path within the results")
+	s.ErrorContains((&ResultPath{Name: "/outputs", Path: "/outputs"}).Validate(), "relative path within the results")
+}
+
+func (s *TaskTestSuite) TestResultPathPublishes() {
+	path := &ResultPath{Include: []string{"**/*.csv"}, Exclude: []string{"tmp/**"}}
+	s.True(path.Publishes("data.csv"))
+	s.True(path.Publishes("nested/data.csv"))
+	s.False(path.Publishes("tmp/data.csv"))
+	s.False(path.Publishes("data.json"))
+	s.True((&ResultPath{}).Publishes("anything"))
+}
+
+func (s *TaskTestSuite) TestValidateSnapshots() {
+	task := s.task()
+	task.Publisher = NewSpecConfig(PublisherS3)
+	task.Snapshots = &SnapshotConfig{Interval: 60}
+	s.ErrorContains(task.ValidateSubmission(), "result paths must be set")
+
+	task.ResultPaths = []*ResultPath{{Name: "outputs", Path: "/outputs"}}
+	s.NoError(task.ValidateSubmission())
+
+	task.Snapshots.Paths = []string{"logs"}
+	s.ErrorContains(task.ValidateSubmission(), "snapshot path logs is not a result path")
+
+	task.Snapshots = &SnapshotConfig{Interval: -1}
+	s.ErrorContains(task.ValidateSubmission(), "invalid snapshot interval")
+
+	task.Snapshots = &SnapshotConfig{Interval: 1}
+	s.ErrorContains(task.ValidateSubmission(), "less than the minimum")
+}
+
+func (s *TaskTestSuite) TestValidateResultSnapshot() {
+	snapshot := &ResultSnapshot{Version: 1, Removed: []string{"outputs/data.csv"}}
+	s.NoError(snapshot.Validate())
+
+	snapshot.Removed = []string{"../../etc/passwd"}
+	s.ErrorContains(snapshot.Validate(), "invalid removed path")
+
+	snapshot.Removed = []string{"."}
+	s.ErrorContains(snapshot.Validate(), "invalid removed path")
+
+	s.ErrorContains((&ResultSnapshot{}).Validate(), "invalid snapshot version")
+}
+
+func (s *TaskTestSuite) TestAppendSnapshot() {
+	var snapshots []*ResultSnapshot
+	var err error
+	for version := 1; version <= MaxSnapshots; version++ {
+		snapshot := &ResultSnapshot{Version: version, CreateTime: int64(version), Full: version == 1}
+		snapshots, err = AppendSnapshot(snapshots, snapshot)
+		s.Require().NoError(err)
+	}
+	s.Len(snapshots, MaxSnapshots)
+
+	_, err = AppendSnapshot(snapshots, &ResultSnapshot{Version: MaxSnapshots + 1, CreateTime: MaxSnapshots + 1})
+	s.ErrorContains(err, "more than")
+
+	full := &ResultSnapshot{Version: MaxSnapshots + 1, CreateTime: MaxSnapshots + 1, Full: true}
+	snapshots, err = AppendSnapshot(snapshots, full)
+	s.Require().NoError(err)
+	s.Equal([]*ResultSnapshot{full}, snapshots)
+
+	// snapshots that arrive after a newer full snapshot are not recorded
+	_, err = AppendSnapshot(snapshots, &ResultSnapshot{Version: MaxSnapshots, CreateTime: MaxSnapshots})
+	s.ErrorContains(err, "taken before full snapshot")
+
+	// a restarted node starts again from version 1 with a full snapshot
+	restarted := &ResultSnapshot{Version: 1, CreateTime: MaxSnapshots + 2, Full: true}
+	snapshots, err = AppendSnapshot(snapshots, restarted)
+	s.Require().NoError(err)
+	s.Equal([]*ResultSnapshot{restarted}, snapshots)
+}
+
+func (s *TaskTestSuite) TestValidateCheckpoint() {
+	task := s.task()
+	task.Checkpoint = &CheckpointConfig{Path: "/checkpoint"}
+	s.ErrorContains(task.ValidateSubmission(), "publisher must be set")
+
+	task.Publisher = NewSpecConfig(PublisherS3)
+	s.NoError(task.ValidateSubmission())
+
+	task.Checkpoint = &CheckpointConfig{Path: "checkpoint"}
+	s.ErrorContains(task.ValidateSubmission(), "checkpoint path must be absolute")
+
+	task.Checkpoint = &CheckpointConfig{Path: "/checkpoint", Interval: -1}
+	s.ErrorContains(task.ValidateSubmission(), "invalid checkpoint interval")
+
+	task.Checkpoint = &CheckpointConfig{Path: "/outputs/"}
+	task.ResultPaths = []*ResultPath{{Name: "outputs", Path: "/outputs"}}
+	s.ErrorContains(task.ValidateSubmission(), "checkpoint path /outputs/ is also a result path")
+
+	task.Checkpoint = &CheckpointConfig{Path: "/checkpoint"}
+	task.ResultPaths = []*ResultPath{{Name: CheckpointResultPathName, Path: "/outputs"}}
+	s.ErrorContains(task.ValidateSubmission(), "is reserved for checkpoints")
+}
+
+func (s *TaskTestSuite) TestCheckpoint() {
+	var config *CheckpointConfig
+	s.False(config.Enabled())
+	s.Nil(config.Copy())
+
+	config = &CheckpointConfig{Path: "/checkpoint"}
+	s.True(config.Enabled())
+	s.Equal(DefaultCheckpointInterval, config.GetInterval())
+	s.Equal(90*time.Second, (&CheckpointConfig{Path: "/checkpoint", Interval: 90}).GetInterval())
+
+	checkpoint := &Checkpoint{
+		Version: 2,
+		Results: []*PublishedResult{
+			{Publisher: PublisherS3, Result: &SpecConfig{}},
+			{Publisher: PublisherIPFS, Result: NewSpecConfig(StorageSourceIPFS).WithParam("CID", "Qm")},
+		},
+	}
+	input := checkpoint.InputSource(config)
+	s.Require().NotNil(input)
+	s.Equal(CheckpointInputAlias, input.Alias)
+	s.Equal("/checkpoint", input.Target)
+	s.Equal("Qm", input.Source.Params["CID"])
+	s.Nil((&Checkpoint{}).InputSource(config))
+
+	execution := &Execution{}
+	s.Nil(execution.LatestCheckpoint())
+	execution.Checkpoints = execution.AddCheckpoint(checkpoint)
+	execution.Checkpoints = execution.AddCheckpoint(&Checkpoint{Version: 1})
+	execution.Checkpoints = execution.AddCheckpoint(&Checkpoint{Version: 2})
+	s.Require().Len(execution.Checkpoints, 2)
+	s.Equal(1, execution.Checkpoints[0].Version)
+	s.Equal(execution.Checkpoints[1], execution.LatestCheckpoint())
+	s.Empty(execution.LatestCheckpoint().Results)
+}
+
+func (s *TaskTestSuite) TestSnapshotConfig() {
+	var config *SnapshotConfig
+	s.False(config.Enabled())
+	s.False((&SnapshotConfig{Paths: []string{"outputs"}}).Enabled())
+	s.True((&SnapshotConfig{OnFileClose: true}).Enabled())
+
+	config = &SnapshotConfig{Interval: 30, Paths: []string{"outputs"}}
+	s.True(config.Enabled())
+	s.True(config.Includes("outputs"))
+	s.False(config.Includes("logs"))
+	s.True((&SnapshotConfig{}).Includes("logs"))
+
+	task := s.task()
+	task.Snapshots = config
+	cpy := task.Copy()
+	s.Equal(config, cpy.Snapshots)
+	cpy.Snapshots.Paths[0] = "logs"
+	s.Equal("outputs", config.Paths[0])
+}
+
+func (s *TaskTestSuite) TestValidateHealthCheck() {
+	task := s.task()
+	task.HealthCheck = &HealthCheckConfig{Type: HealthCheckHTTP, Port: 8080, Path: "/healthz"}
+	s.ErrorContains(task.ValidateSubmission(), "http health checks require the task to have network access")
+
+	task.Network = &NetworkConfig{Type: NetworkFull}
+	s.NoError(task.ValidateSubmission())
+
+	task.HealthCheck = &HealthCheckConfig{Type: HealthCheckTCP}
+	s.ErrorContains(task.ValidateSubmission(), "invalid health check port")
+
+	task.HealthCheck = &HealthCheckConfig{Type: HealthCheckExec}
+	s.ErrorContains(task.ValidateSubmission(), "health check command cannot be empty")
+
+	task.HealthCheck = &HealthCheckConfig{Type: "grpc"}
+	s.ErrorContains(task.ValidateSubmission(), "invalid health check type")
+
+	task.HealthCheck = &HealthCheckConfig{Type: HealthCheckExec, Command: []string{"true"}, Interval: -1}
+	s.ErrorContains(task.ValidateSubmission(), "invalid health check interval")
+}
+
+func (s *TaskTestSuite) TestHealthCheckConfig() {
+	config := &HealthCheckConfig{Type: HealthCheckExec, Command: []string{"pg_isready"}}
+	s.Equal(30*time.Second, config.GetInterval())
+	s.Equal(10*time.Second, config.GetTimeout())
+	s.Equal(3, config.GetFailureThreshold())
+
+	config.Interval, config.Timeout, config.FailureThreshold = 5, 1, 1
+	s.Equal(5*time.Second, config.GetInterval())
+	s.Equal(time.Second, config.GetTimeout())
+	s.Equal(1, config.GetFailureThreshold())
+
+	task := s.task()
+	task.HealthCheck = config
+	cpy := task.Copy()
+	s.Equal(config, cpy.HealthCheck)
+	cpy.HealthCheck.Command[0] = "true"
+	s.Equal("pg_isready", config.Command[0])
+}
+
+func (s *TaskTestSuite) TestValidatePorts() {
+	task := s.task()
+	task.Network = &NetworkConfig{Type: NetworkHTTP, Domains: []string{"example.com"}, Ports: []*PortConfig{
+		{Name: "http", Target: 8080},
+	}}
+	task.Network.Normalize()
+	s.Equal(PortProtocolTCP, task.Network.Ports[0].Protocol)
+	s.ErrorContains(task.ValidateSubmission(), "ports can only be published with Full networking")
+
+	task.Network.Type = NetworkFull
+	s.NoError(task.ValidateSubmission())
+
+	task.Network.Ports = append(task.Network.Ports, &PortConfig{Name: "http", Target: 8081, Protocol: PortProtocolTCP})
+	s.ErrorContains(task.ValidateSubmission(), `port "http" is defined more than once`)
+
+	task.Network.Ports = []*PortConfig{{Name: "Web_UI", Target: 0, Protocol: "sctp"}}
+	err := task.ValidateSubmission()
+	s.ErrorContains(err, `invalid port name "Web_UI"`)
+	s.ErrorContains(err, "invalid target port 0")
+	s.ErrorContains(err, `invalid protocol "sctp"`)
+}
+
+func (s *TaskTestSuite) TestParseServiceName() {
+	service, err := ParseServiceName("_http._tcp.web.v2")
+	s.Require().NoError(err)
+	s.Equal(ServiceName{Port: "http", Protocol: PortProtocolTCP, Job: "web.v2"}, service)
+	s.Equal("_http._tcp.web.v2", service.String())
+
+	_, err = ParseServiceName("http.tcp.web")
+	s.ErrorContains(err, "invalid service name")
+
+	_, err = ParseServiceName("_http._sctp.web")
+	s.ErrorContains(err, `invalid protocol "sctp"`)
+}
@@ -2,6 +2,7 @@ package nats
 
 import (
 	"context"
+	"crypto/tls"
 
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/nats-io/nats.go"
@@ -10,6 +11,8 @@ import (
 type ClientManagerParams struct {
 	Name    string
 	Servers string
+	// TLSConfig is used to secure the connection to the servers, if set.
+	TLSConfig *tls.Config
 }
 
 type ClientManager struct {
@@ -18,7 +21,11 @@ type ClientManager struct {
 
 // NewClientManager is a helper function to create a NATS client connection with a given name and servers string
 func NewClientManager(ctx context.Context, params ClientManagerParams) (*ClientManager, error) {
-	nc, err := nats.Connect(params.Servers, nats.Name(params.Name))
+	opts := []nats.Option{nats.Name(params.Name)}
+	if params.TLSConfig != nil {
+		opts = append(opts, nats.Secure(params.TLSConfig))
+	}
+	nc, err := nats.Connect(params.Servers, opts...)
 	if err != nil {
 		return nil, err
 	}
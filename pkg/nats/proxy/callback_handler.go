@@ -57,6 +57,14 @@ func (h *CallbackHandler) handle(msg *nats.Msg) {
 		processCallback(ctx, msg, h.callback.OnBidComplete)
 	case OnRunComplete:
 		processCallback(ctx, msg, h.callback.OnRunComplete)
+	case OnSnapshotComplete:
+		processCallback(ctx, msg, h.callback.OnSnapshotComplete)
+	case OnCheckpointComplete:
+		processCallback(ctx, msg, h.callback.OnCheckpointComplete)
+	case OnHealthUpdate:
+		processCallback(ctx, msg, h.callback.OnHealthUpdate)
+	case OnEndpointsAllocated:
+		processCallback(ctx, msg, h.callback.OnEndpointsAllocated)
 	case OnCancelComplete:
 		processCallback(ctx, msg, h.callback.OnCancelComplete)
 	case OnComputeFailure:
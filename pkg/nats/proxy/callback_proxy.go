@@ -38,6 +38,22 @@ func (p *CallbackProxy) OnRunComplete(ctx context.Context, result compute.RunRes
 	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnRunComplete, result)
 }
 
+func (p *CallbackProxy) OnSnapshotComplete(ctx context.Context, result compute.SnapshotResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnSnapshotComplete, result)
+}
+
+func (p *CallbackProxy) OnHealthUpdate(ctx context.Context, result compute.HealthResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnHealthUpdate, result)
+}
+
+func (p *CallbackProxy) OnEndpointsAllocated(ctx context.Context, result compute.EndpointsResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnEndpointsAllocated, result)
+}
+
+func (p *CallbackProxy) OnCheckpointComplete(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnCheckpointComplete, result)
+}
+
 func (p *CallbackProxy) OnCancelComplete(ctx context.Context, result compute.CancelResult) {
 	proxyCallbackRequest(ctx, p.conn, result.RoutingMetadata.TargetPeerID, OnCancelComplete, result)
 }
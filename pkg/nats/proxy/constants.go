@@ -5,6 +5,9 @@ import "fmt"
 const (
 	ComputeEndpointSubjectPrefix = "node.compute"
 	CallbackSubjectPrefix        = "node.orchestrator"
+	// NodeJoinSubject is not addressed to a node, as compute nodes present
+	// their join token to whichever orchestrator is listening.
+	NodeJoinSubject = "node.join"
 
 	AskForBid       = "AskForBid/v1"
 	BidAccepted     = "BidAccepted/v1"
@@ -12,10 +15,16 @@ const (
 	CancelExecution = "CancelExecution/v1"
 	ExecutionLogs   = "ExecutionLogs/v1"
 
-	OnBidComplete    = "OnBidComplete/v1"
-	OnRunComplete    = "OnRunComplete/v1"
-	OnCancelComplete = "OnCancelComplete/v1"
-	OnComputeFailure = "OnComputeFailure/v1"
+	OnBidComplete        = "OnBidComplete/v1"
+	OnRunComplete        = "OnRunComplete/v1"
+	OnSnapshotComplete   = "OnSnapshotComplete/v1"
+	OnCheckpointComplete = "OnCheckpointComplete/v1"
+	OnHealthUpdate       = "OnHealthUpdate/v1"
+	OnEndpointsAllocated = "OnEndpointsAllocated/v1"
+	OnCancelComplete     = "OnCancelComplete/v1"
+	OnComputeFailure     = "OnComputeFailure/v1"
+
+	Join = "Join/v1"
 )
 
 func computeEndpointPublishSubject(nodeID string, method string) string {
@@ -33,3 +42,7 @@ func callbackPublishSubject(nodeID string, method string) string {
 func callbackSubscribeSubject(nodeID string) string {
 	return fmt.Sprintf("%s.%s.>", CallbackSubjectPrefix, nodeID)
 }
+
+func nodeJoinSubject() string {
+	return fmt.Sprintf("%s.%s", NodeJoinSubject, Join)
+}
@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+// nodeJoinQueue makes sure each join request is handled by a single
+// orchestrator, as join tokens can only be used once.
+const nodeJoinQueue = "orchestrators"
+
+type JoinProxyParams struct {
+	Conn *nats.Conn
+}
+
+// JoinProxy presents the join token of a compute node to the orchestrators.
+type JoinProxy struct {
+	conn *nats.Conn
+}
+
+func NewJoinProxy(params JoinProxyParams) *JoinProxy {
+	return &JoinProxy{conn: params.Conn}
+}
+
+func (p *JoinProxy) Join(ctx context.Context, request routing.JoinRequest) (routing.JoinResponse, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return routing.JoinResponse{}, fmt.Errorf("%T: failed to marshal request: %w", request, err)
+	}
+	res, err := p.conn.RequestWithContext(ctx, nodeJoinSubject(), data)
+	if err != nil {
+		return routing.JoinResponse{}, fmt.Errorf("%T: failed to send request to orchestrators: %w", request, err)
+	}
+
+	result := new(concurrency.AsyncResult[routing.JoinResponse])
+	if err = json.Unmarshal(res.Data, result); err != nil {
+		return routing.JoinResponse{}, fmt.Errorf("%T: failed to decode response: %w", request, err)
+	}
+	return result.ValueOrError()
+}
+
+type JoinHandlerParams struct {
+	Conn   *nats.Conn
+	Joiner routing.NodeJoiner
+}
+
+// NewJoinHandler handles the join requests of compute nodes with the
+// orchestrator's node joiner.
+func NewJoinHandler(params JoinHandlerParams) error {
+	subject := nodeJoinSubject()
+	_, err := params.Conn.QueueSubscribe(subject, nodeJoinQueue, func(m *nats.Msg) {
+		processAndRespond(context.Background(), params.Conn, m, params.Joiner.Join)
+	})
+	if err != nil {
+		return err
+	}
+	log.Debug().Msgf("JoinHandler subscribed to %s", subject)
+	return nil
+}
+
+// compile-time interface check
+var _ routing.NodeJoiner = (*JoinProxy)(nil)
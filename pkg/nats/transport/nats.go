@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/tlsutil"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/validate"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -36,6 +37,14 @@ type NATSTransportConfig struct {
 	// user part of their Orchestrator URL.
 	AuthSecret string
 
+	// TLS files used for mutual TLS between nodes. Requester nodes serve
+	// with the certificate and require clients to present a certificate
+	// signed by the CA. Compute nodes present the certificate and verify
+	// requesters against the CA. Certificates are reloaded when they change.
+	TLSCACertFile string
+	TLSCertFile   string
+	TLSKeyFile    string
+
 	// Cluster config for requester nodes to connect with each other
 	ClusterName              string
 	ClusterPort              int
@@ -65,9 +74,21 @@ func (c *NATSTransportConfig) Validate() error {
 			mErr = multierror.Append(mErr, errors.New("missing orchestrators"))
 		}
 	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		mErr = multierror.Append(mErr, errors.New("TLS certificate and key must be provided together"))
+	}
+	if c.IsRequesterNode && c.TLSCACertFile != "" && c.TLSCertFile == "" {
+		mErr = multierror.Append(mErr, errors.New("TLS certificate is required to verify compute node certificates"))
+	}
 	return mErr.ErrorOrNil()
 }
 
+// TLSEnabled returns true if the transport should use mutual TLS.
+func (c *NATSTransportConfig) TLSEnabled() bool {
+	return c.TLSCACertFile != "" || c.TLSCertFile != ""
+}
+
 type NATSTransport struct {
 	nodeID            string
 	natsServer        *nats_helper.ServerManager
@@ -76,6 +97,7 @@ type NATSTransport struct {
 	callbackProxy     compute.Callback
 	nodeInfoPubSub    pubsub.PubSub[models.NodeInfo]
 	nodeInfoDecorator models.NodeInfoDecorator
+	nodeJoiner        routing.NodeJoiner
 }
 
 func NewNATSTransport(ctx context.Context,
@@ -86,6 +108,16 @@ func NewNATSTransport(ctx context.Context,
 		return nil, fmt.Errorf("error validating nats transport config. %w", err)
 	}
 
+	var certReloader *tlsutil.CertificateReloader
+	if config.TLSEnabled() {
+		var err error
+		certReloader, err = tlsutil.NewCertificateReloader(config.TLSCertFile, config.TLSKeyFile, config.TLSCACertFile)
+		if err != nil {
+			return nil, err
+		}
+		certReloader.Start(ctx, tlsutil.DefaultReloadInterval)
+	}
+
 	var sm *nats_helper.ServerManager
 	if config.IsRequesterNode {
 		// create nats server with servers acting as its cluster peers
@@ -106,6 +138,13 @@ func NewNATSTransport(ctx context.Context,
 				Advertise: config.ClusterAdvertisedAddress,
 			},
 		}
+		if certReloader != nil {
+			serverOps.TLSConfig, err = certReloader.ServerConfig(config.TLSCACertFile != "")
+			if err != nil {
+				return nil, err
+			}
+			serverOps.TLSVerify = config.TLSCACertFile != ""
+		}
 		log.Debug().Msgf("Creating NATS server with options: %+v", serverOps)
 		sm, err = nats_helper.NewServerManager(ctx, nats_helper.ServerManagerParams{
 			Options: serverOps,
@@ -127,10 +166,14 @@ func NewNATSTransport(ctx context.Context,
 
 	// create nats client
 	log.Debug().Msgf("Creating NATS client with servers: %s", strings.Join(config.Orchestrators, ","))
-	nc, err := nats_helper.NewClientManager(ctx, nats_helper.ClientManagerParams{
+	clientParams := nats_helper.ClientManagerParams{
 		Name:    config.NodeID,
 		Servers: strings.Join(config.Orchestrators, ","),
-	})
+	}
+	if certReloader != nil {
+		clientParams.TLSConfig = certReloader.ClientConfig()
+	}
+	nc, err := nats_helper.NewClientManager(ctx, clientParams)
 	if err != nil {
 		return nil, err
 	}
@@ -176,6 +219,7 @@ func NewNATSTransport(ctx context.Context,
 		callbackProxy:     computeCallback,
 		nodeInfoPubSub:    nodeInfoPubSub,
 		nodeInfoDecorator: models.NoopNodeInfoDecorator{},
+		nodeJoiner:        proxy.NewJoinProxy(proxy.JoinProxyParams{Conn: nc.Client}),
 	}, nil
 }
 
@@ -199,6 +243,14 @@ func (t *NATSTransport) RegisterComputeEndpoint(endpoint compute.Endpoint) error
 	return err
 }
 
+// RegisterNodeJoiner registers the node joiner of an orchestrator with the transport layer.
+func (t *NATSTransport) RegisterNodeJoiner(joiner routing.NodeJoiner) error {
+	return proxy.NewJoinHandler(proxy.JoinHandlerParams{
+		Conn:   t.natsClient.Client,
+		Joiner: joiner,
+	})
+}
+
 // ComputeProxy returns the compute proxy.
 func (t *NATSTransport) ComputeProxy() compute.Endpoint {
 	return t.computeProxy
@@ -214,6 +266,11 @@ func (t *NATSTransport) NodeInfoPubSub() pubsub.PubSub[models.NodeInfo] {
 	return t.nodeInfoPubSub
 }
 
+// NodeJoiner returns the proxy that presents join tokens to orchestrators.
+func (t *NATSTransport) NodeJoiner() routing.NodeJoiner {
+	return t.nodeJoiner
+}
+
 // NodeInfoDecorator returns the node info decorator.
 func (t *NATSTransport) NodeInfoDecorator() models.NodeInfoDecorator {
 	return t.nodeInfoDecorator
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"time"
+
+	"github.com/dustin/go-humanize"
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy"
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/resource"
@@ -12,9 +15,14 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity/disk"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/logstream"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/scratch"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/sensors"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
 	"github.com/bacalhau-project/bacalhau/pkg/executor"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/docker/imagecache"
 	executor_util "github.com/bacalhau-project/bacalhau/pkg/executor/util"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
@@ -25,7 +33,9 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/storage"
 	repo_storage "github.com/bacalhau-project/bacalhau/pkg/storage/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
+	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/rs/zerolog/log"
 )
 
 type Compute struct {
@@ -73,15 +83,38 @@ func NewComputeNode(
 	// executor/backend
 	runningCapacityTracker := capacity.NewLocalTracker(capacity.LocalTrackerParams{
 		MaxCapacity: config.TotalResourceLimits,
+		GPUSharing:  config.GPUSharing,
 	})
 	enqueuedCapacityTracker := capacity.NewLocalTracker(capacity.LocalTrackerParams{
 		MaxCapacity: config.QueueResourceLimits,
+		GPUSharing:  config.GPUSharing,
 	})
 
 	resultsPath, err := compute.NewResultsPath()
 	if err != nil {
 		return nil, err
 	}
+	manifestSigner, err := newManifestSigner(ctx, nodeID, host)
+	if err != nil {
+		return nil, err
+	}
+	ports, err := compute.NewPortAllocator(compute.PortAllocatorParams{
+		Start:   config.Ports.Start,
+		End:     config.Ports.End,
+		Address: config.Ports.Address,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scratchVolumes, err := scratch.NewManager(scratch.ManagerParams{
+		Type:      config.ScratchVolumes.Type,
+		Directory: config.ScratchVolumes.Directory,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	baseExecutor := compute.NewBaseExecutor(compute.BaseExecutorParams{
 		ID:                     nodeID,
 		Callback:               computeCallback,
@@ -92,6 +125,9 @@ func NewComputeNode(
 		Publishers:             publishers,
 		FailureInjectionConfig: config.FailureInjectionConfig,
 		ResultsPath:            *resultsPath,
+		ManifestSigner:         manifestSigner,
+		Ports:                  ports,
+		ScratchVolumes:         scratchVolumes,
 	})
 
 	bufferRunner := compute.NewExecutorBuffer(compute.ExecutorBufferParams{
@@ -143,9 +179,9 @@ func NewComputeNode(
 			semantic.NewStatelessJobStrategy(semantic.StatelessJobStrategyParams{
 				RejectStatelessJobs: config.JobSelectionPolicy.RejectStatelessJobs,
 			}),
-			semantic.NewProviderInstalledStrategy(
+			semantic.NewProviderInstalledArrayStrategy(
 				publishers,
-				func(j *models.Job) string { return j.Task().Publisher.Type },
+				func(j *models.Job) []string { return j.Task().RequiredPublisherTypes() },
 			),
 			semantic.NewStorageInstalledBidStrategy(storages),
 			semantic.NewInputLocalityStrategy(semantic.InputLocalityStrategyParams{
@@ -183,6 +219,22 @@ func NewComputeNode(
 		Buffer:         config.LogStreamBufferSize,
 	})
 
+	// docker image cache
+	imageCache, err := newImageCache(ctx, nodeID, config.ImageCache, fsRepo)
+	if err != nil {
+		return nil, err
+	}
+	var computeImageCache compute.ImageCache
+	if imageCache != nil {
+		computeImageCache = imageCache
+		imageCacheCtx, cancel := context.WithCancel(ctx)
+		cleanupManager.RegisterCallback(func() error {
+			cancel()
+			return nil
+		})
+		imageCache.Start(imageCacheCtx)
+	}
+
 	// node info
 	nodeInfoDecorator := compute.NewNodeInfoDecorator(compute.NodeInfoDecoratorParams{
 		Executors:          executors,
@@ -191,6 +243,8 @@ func NewComputeNode(
 		CapacityTracker:    runningCapacityTracker,
 		ExecutorBuffer:     bufferRunner,
 		MaxJobRequirements: config.JobResourceLimits,
+		ImageCache:         computeImageCache,
+		PublicKey:          manifestSignerPublicKey(manifestSigner),
 	})
 
 	bidStrat := bidstrategy.NewChainedBidStrategy(semanticBidStrat, resourceBidStrat)
@@ -213,6 +267,7 @@ func NewComputeNode(
 		Bidder:          bidder,
 		Executor:        bufferRunner,
 		LogServer:       logserver,
+		ImageCache:      computeImageCache,
 	})
 
 	// register debug info providers for the /debug endpoint
@@ -221,7 +276,7 @@ func NewComputeNode(
 		sensors.NewCompletedJobs(executionStore),
 	}
 
-	startup := compute.NewStartup(executionStore, bufferRunner)
+	startup := compute.NewStartup(executionStore, bufferRunner, scratchVolumes)
 	startupErr := startup.Execute(ctx)
 	if startupErr != nil {
 		return nil, fmt.Errorf("failed to execute compute node startup tasks: %s", startupErr)
@@ -233,6 +288,7 @@ func NewComputeNode(
 		Bidder:             bidder,
 		Store:              executionStore,
 		DebugInfoProviders: debugInfoProviders,
+		ImageCache:         imageCache,
 	})
 
 	// A single cleanup function to make sure the order of closing dependencies is correct
@@ -266,3 +322,76 @@ func NewComputeNode(
 func (c *Compute) cleanup(ctx context.Context) {
 	c.cleanupFunc(ctx)
 }
+
+// newImageCache returns the cache of the docker images pulled for jobs, or
+// nil if it is disabled or docker is not available on the node.
+func newImageCache(
+	ctx context.Context, nodeID string, cfg types.ImageCacheConfig, fsRepo *repo.FsRepo) (*imagecache.Cache, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	client, err := docker.NewDockerClient()
+	if err != nil || !client.IsInstalled(ctx) {
+		log.Ctx(ctx).Debug().Err(err).Msg("docker is not available, images pulled for jobs are not cached")
+		if client != nil {
+			_ = client.Close()
+		}
+		return nil, nil
+	}
+
+	var diskBudget uint64
+	if cfg.DiskBudget != "" {
+		if diskBudget, err = humanize.ParseBytes(cfg.DiskBudget); err != nil {
+			return nil, fmt.Errorf("invalid image cache disk budget %q: %w", cfg.DiskBudget, err)
+		}
+	}
+	var statePath string
+	if fsRepo != nil {
+		if statePath, err = fsRepo.ImageCacheStatePath(nodeID); err != nil {
+			return nil, err
+		}
+	}
+	credentials, err := docker.NewCredentialsResolverFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return imagecache.New(imagecache.Params{
+		Client:         client,
+		Credentials:    credentials,
+		DiskBudget:     diskBudget,
+		PruneFrequency: time.Duration(cfg.PruneFrequency),
+		AutoPull:       cfg.AutoPull,
+		Images:         cfg.Images,
+		Advertised:     cfg.Advertised,
+		StatePath:      statePath,
+	})
+}
+
+// manifestSignerPublicKey returns the public key of the signer, if results are signed.
+func manifestSignerPublicKey(signer *compute.ManifestSigner) string {
+	if signer == nil {
+		return ""
+	}
+	return signer.PublicKey()
+}
+
+// newManifestSigner returns a signer using the node's libp2p key, which the
+// node ID is derived from. Results are published unsigned if the key is not
+// available.
+func newManifestSigner(ctx context.Context, nodeID string, host host.Host) (*compute.ManifestSigner, error) {
+	var key crypto.PrivKey
+	if host != nil {
+		key = host.Peerstore().PrivKey(host.ID())
+	} else {
+		var err error
+		if key, err = config.GetLibp2pPrivKey(); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("node key is not available, results manifests will not be signed")
+			return nil, nil
+		}
+	}
+	if key == nil {
+		log.Ctx(ctx).Warn().Msg("node key is not available, results manifests will not be signed")
+		return nil, nil
+	}
+	return compute.NewManifestSigner(nodeID, key)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute/capacity"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/docker"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 )
@@ -49,6 +50,8 @@ type ComputeConfigParams struct {
 	DefaultJobResourceLimits     models.Resources
 	PhysicalResourcesProvider    capacity.Provider
 	IgnorePhysicalResourceLimits bool
+	// GPUSharing is how many tasks can time-slice each GPU
+	GPUSharing uint64
 
 	// Timeout config
 	JobNegotiationTimeout      time.Duration
@@ -73,6 +76,14 @@ type ComputeConfigParams struct {
 	BidResourceStrategy bidstrategy.ResourceBidStrategy
 
 	LocalPublisher types.LocalPublisherConfig
+
+	ImageCache types.ImageCacheConfig
+
+	Ports types.PortsConfig
+
+	ScratchVolumes types.ScratchVolumesConfig
+
+	ContainerRuntime types.ContainerRuntimeConfig
 }
 
 type ComputeConfig struct {
@@ -82,6 +93,8 @@ type ComputeConfig struct {
 	JobResourceLimits            models.Resources
 	DefaultJobResourceLimits     models.Resources
 	IgnorePhysicalResourceLimits bool
+	// GPUSharing is how many tasks can time-slice each GPU
+	GPUSharing uint64
 
 	// JobNegotiationTimeout default timeout value to hold a bid for a job
 	JobNegotiationTimeout time.Duration
@@ -117,6 +130,18 @@ type ComputeConfig struct {
 	ExecutionStore store.ExecutionStore
 
 	LocalPublisher types.LocalPublisherConfig
+
+	// ImageCache configures the cache of docker images pulled for jobs.
+	ImageCache types.ImageCacheConfig
+
+	// Ports configures the host ports allocated to the ports published by tasks.
+	Ports types.PortsConfig
+
+	// ScratchVolumes configures the scratch volumes created for executions.
+	ScratchVolumes types.ScratchVolumesConfig
+
+	// ContainerRuntime configures the container runtime that runs docker jobs.
+	ContainerRuntime types.ContainerRuntimeConfig
 }
 
 func NewComputeConfigWithDefaults() (ComputeConfig, error) {
@@ -143,6 +168,19 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 	if params.LocalPublisher.Address == "" {
 		params.LocalPublisher.Address = DefaultComputeConfig.LocalPublisher.Address
 	}
+	if params.Ports.Start == 0 && params.Ports.End == 0 {
+		params.Ports.Start = DefaultComputeConfig.Ports.Start
+		params.Ports.End = DefaultComputeConfig.Ports.End
+	}
+	if params.Ports.Address == "" {
+		params.Ports.Address = DefaultComputeConfig.Ports.Address
+	}
+	if params.ScratchVolumes.Type == "" {
+		params.ScratchVolumes.Type = DefaultComputeConfig.ScratchVolumes.Type
+	}
+	if params.ScratchVolumes.Directory == "" {
+		params.ScratchVolumes.Directory = DefaultComputeConfig.ScratchVolumes.Directory
+	}
 	if params.LocalPublisher.Directory == "" {
 		params.LocalPublisher.Directory = DefaultComputeConfig.LocalPublisher.Directory
 		if err := os.MkdirAll(params.LocalPublisher.Directory, localPublishFolderPerm); err != nil {
@@ -185,6 +223,7 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 		JobResourceLimits:            *jobResourceLimits,
 		DefaultJobResourceLimits:     *defaultJobResourceLimits,
 		IgnorePhysicalResourceLimits: params.IgnorePhysicalResourceLimits,
+		GPUSharing:                   params.GPUSharing,
 
 		JobNegotiationTimeout:      params.JobNegotiationTimeout,
 		MinJobExecutionTimeout:     params.MinJobExecutionTimeout,
@@ -201,6 +240,10 @@ func NewComputeConfigWith(params ComputeConfigParams) (ComputeConfig, error) {
 		BidSemanticStrategy:          params.BidSemanticStrategy,
 		BidResourceStrategy:          params.BidResourceStrategy,
 		LocalPublisher:               params.LocalPublisher,
+		ImageCache:                   params.ImageCache,
+		Ports:                        params.Ports,
+		ScratchVolumes:               params.ScratchVolumes,
+		ContainerRuntime:             params.ContainerRuntime,
 	}
 
 	if err := validateConfig(config, physicalResources); err != nil {
@@ -237,5 +280,14 @@ func validateConfig(config ComputeConfig, physicalResources models.Resources) er
 				config.DefaultJobResourceLimits, config.JobResourceLimits))
 	}
 
+	if config.Ports.Start <= 0 || config.Ports.End > 65535 || config.Ports.Start > config.Ports.End {
+		errors = multierror.Append(errors,
+			fmt.Errorf("invalid port range %d-%d", config.Ports.Start, config.Ports.End))
+	}
+
+	if err := docker.ValidateRuntimeConfig(config.ContainerRuntime); err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
 	return errors.ErrorOrNil()
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/bacalhau-project/bacalhau/pkg/bidstrategy/semantic"
 	compute_system "github.com/bacalhau-project/bacalhau/pkg/compute/capacity/system"
+	"github.com/bacalhau-project/bacalhau/pkg/compute/scratch"
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
@@ -33,6 +34,15 @@ var DefaultComputeConfig = ComputeConfigParams{
 	LocalPublisher: types.LocalPublisherConfig{
 		Directory: path.Join(config.GetStoragePath(), "bacalhau-local-publisher"),
 	},
+	Ports: types.PortsConfig{
+		Start:   30000,
+		End:     32767,
+		Address: "private",
+	},
+	ScratchVolumes: types.ScratchVolumesConfig{
+		Type:      scratch.TypeAuto,
+		Directory: path.Join(config.GetStoragePath(), "bacalhau-scratch"),
+	},
 }
 
 var DefaultRequesterConfig = RequesterConfigParams{
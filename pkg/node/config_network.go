@@ -32,6 +32,15 @@ type NetworkConfig struct {
 	// user part of their Orchestrator URL.
 	AuthSecret string
 
+	// JoinToken is presented by compute nodes to be admitted by orchestrators
+	// that require manual node approval.
+	JoinToken string
+
+	// TLS files used for mutual TLS between orchestrator and compute nodes
+	TLSCACertFile string
+	TLSCertFile   string
+	TLSKeyFile    string
+
 	// NATS config for requester nodes to connect with each other
 	ClusterName              string
 	ClusterPort              int
@@ -48,6 +48,10 @@ type RequesterConfigParams struct {
 	S3PreSignedURLExpiration time.Duration
 
 	DefaultPublisher string
+
+	// ManualNodeApproval requires compute nodes to present a join token, or
+	// to be approved by an operator, before they are sent work.
+	ManualNodeApproval bool
 }
 
 type RequesterConfig struct {
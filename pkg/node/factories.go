@@ -73,13 +73,17 @@ func NewStandardExecutorsFactory() ExecutorsFactory {
 				ctx,
 				nodeConfig.CleanupManager,
 				executor_util.StandardExecutorOptions{
-					DockerID: fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					DockerID:    fmt.Sprintf("bacalhau-%s", nodeConfig.NodeID),
+					PluginsPath: config.GetExecutorPluginsPath(),
 				},
 			)
 			if err != nil {
 				return nil, err
 			}
-			return provider.NewConfiguredProvider(pr, nodeConfig.DisabledFeatures.Engines), err
+			return &executor_util.ExecutorProviderWithPlugins{
+				ExecutorProvider:   provider.NewConfiguredProvider[executor.Executor](pr, nodeConfig.DisabledFeatures.Engines),
+				PluginInfoProvider: pr,
+			}, nil
 		})
 }
 
@@ -112,7 +116,10 @@ func NewPluginExecutorFactory() ExecutorsFactory {
 			if err != nil {
 				return nil, err
 			}
-			return provider.NewConfiguredProvider(pr, nodeConfig.DisabledFeatures.Engines), err
+			return &executor_util.ExecutorProviderWithPlugins{
+				ExecutorProvider:   provider.NewConfiguredProvider[executor.Executor](pr, nodeConfig.DisabledFeatures.Engines),
+				PluginInfoProvider: pr,
+			}, nil
 		})
 }
 
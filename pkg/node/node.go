@@ -2,6 +2,7 @@ package node
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,12 +10,15 @@ import (
 	"github.com/imdario/mergo"
 	"github.com/labstack/echo/v4"
 	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authz"
 	pkgconfig "github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/policy"
 	libp2p_transport "github.com/bacalhau-project/bacalhau/pkg/libp2p/transport"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
@@ -27,6 +31,8 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/shared"
 	"github.com/bacalhau-project/bacalhau/pkg/repo"
 	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
+	routing_boltdb "github.com/bacalhau-project/bacalhau/pkg/routing/boltdb"
 	"github.com/bacalhau-project/bacalhau/pkg/routing/inmemory"
 	"github.com/bacalhau-project/bacalhau/pkg/system"
 	"github.com/bacalhau-project/bacalhau/pkg/transport"
@@ -50,6 +56,8 @@ type NodeConfig struct {
 	RequesterAutoCertCache      string
 	RequesterTLSCertificateFile string
 	RequesterTLSKeyFile         string
+	RequesterTLSClientCAFile    string
+	RequesterRequireClientCert  bool
 	DisabledFeatures            FeatureConfig
 	ComputeConfig               ComputeConfig
 	RequesterNodeConfig         RequesterConfig
@@ -159,6 +167,20 @@ func NewNode(
 		return nil, err
 	}
 
+	// the job store is only created with the requester node below, but no
+	// requests are authorized before the API server starts
+	var jobStore jobstore.Store
+	jobOwners := func(ctx context.Context, jobID string) (string, error) {
+		if jobStore == nil {
+			return "", nil
+		}
+		job, err := jobStore.GetJob(ctx, jobID)
+		if errors.As(err, &jobstore.ErrJobNotFound{}) {
+			return "", nil
+		}
+		return job.Owner, err
+	}
+
 	serverVersion := version.Get()
 	// public http api server
 	serverParams := publicapi.ServerParams{
@@ -167,7 +189,7 @@ func NewNode(
 		Port:       config.APIPort,
 		HostID:     config.NodeID,
 		Config:     config.APIServerConfig,
-		Authorizer: authz.NewPolicyAuthorizer(authzPolicy, signingKey, config.NodeID),
+		Authorizer: authz.NewPolicyAuthorizer(authzPolicy, signingKey, config.NodeID, jobOwners),
 		Headers: map[string]string{
 			apimodels.HTTPHeaderBacalhauGitVersion: serverVersion.GitVersion,
 			apimodels.HTTPHeaderBacalhauGitCommit:  serverVersion.GitCommit,
@@ -183,6 +205,8 @@ func NewNode(
 		serverParams.AutoCertCache = config.RequesterAutoCertCache
 		serverParams.TLSCertificateFile = config.RequesterTLSCertificateFile
 		serverParams.TLSKeyFile = config.RequesterTLSKeyFile
+		serverParams.TLSClientCAFile = config.RequesterTLSClientCAFile
+		serverParams.RequireClientCert = config.RequesterRequireClientCert
 	}
 
 	apiServer, err := publicapi.NewAPIServer(serverParams)
@@ -191,9 +215,35 @@ func NewNode(
 	}
 
 	// node info store that is used for both discovering compute nodes, as to find addresses of other nodes for routing requests.
-	nodeInfoStore := inmemory.NewNodeInfoStore(inmemory.NodeInfoStoreParams{
-		TTL: config.NodeInfoStoreTTL,
-	})
+	// Requester nodes keep a persistent registry of nodes that tracks their liveness, and decide which compute nodes are admitted.
+	var nodeInfoStore routing.NodeInfoStore
+	var nodeManager *admission.NodeManager
+	var membershipStore *routing_boltdb.MembershipStore
+	var nodeRegistry *routing_boltdb.NodeInfoStore
+	if config.IsRequesterNode {
+		nodeRegistry, err = config.FsRepo.InitNodeInfoStore(ctx, config.NodeID, routing_boltdb.NodeInfoStoreParams{
+			DisconnectedAfter: config.NodeInfoStoreTTL,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		membershipStore, err = config.FsRepo.InitNodeMembershipStore(ctx, config.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		nodeManager = admission.NewNodeManager(admission.NodeManagerParams{
+			NodeID:          config.NodeID,
+			NodeInfoStore:   nodeRegistry,
+			MembershipStore: membershipStore,
+			ManualApproval:  config.RequesterNodeConfig.ManualNodeApproval,
+		})
+		nodeInfoStore = nodeManager
+	} else {
+		nodeInfoStore = inmemory.NewNodeInfoStore(inmemory.NodeInfoStoreParams{
+			TTL: config.NodeInfoStoreTTL,
+		})
+	}
 
 	var transportLayer transport.TransportLayer
 
@@ -203,6 +253,9 @@ func NewNode(
 			Port:                     config.NetworkConfig.Port,
 			AdvertisedAddress:        config.NetworkConfig.AdvertisedAddress,
 			AuthSecret:               config.NetworkConfig.AuthSecret,
+			TLSCACertFile:            config.NetworkConfig.TLSCACertFile,
+			TLSCertFile:              config.NetworkConfig.TLSCertFile,
+			TLSKeyFile:               config.NetworkConfig.TLSKeyFile,
 			Orchestrators:            config.NetworkConfig.Orchestrators,
 			ClusterName:              config.NetworkConfig.ClusterName,
 			ClusterPort:              config.NetworkConfig.ClusterPort,
@@ -229,6 +282,7 @@ func NewNode(
 
 	var requesterNode *Requester
 	var computeNode *Compute
+	var pluginInfoProvider executor.PluginInfoProvider
 	var labelsProvider models.LabelsProvider = &ConfigLabelsProvider{staticLabels: config.Labels}
 
 	// setup requester node
@@ -249,17 +303,22 @@ func NewNode(
 			config.RequesterNodeConfig,
 			storageProviders,
 			authenticators,
-			nodeInfoStore,
+			nodeManager,
 			config.FsRepo,
 			transportLayer.ComputeProxy(),
 		)
 		if err != nil {
 			return nil, err
 		}
+		jobStore = requesterNode.JobStore
 		err = transportLayer.RegisterComputeCallback(requesterNode.localCallback)
 		if err != nil {
 			return nil, err
 		}
+		err = transportLayer.RegisterNodeJoiner(nodeManager)
+		if err != nil {
+			return nil, err
+		}
 		debugInfoProviders = append(debugInfoProviders, requesterNode.debugInfoProviders...)
 	}
 
@@ -276,6 +335,10 @@ func NewNode(
 			return nil, err
 		}
 
+		if plugins, ok := executors.(executor.PluginInfoProvider); ok {
+			pluginInfoProvider = plugins
+		}
+
 		metrics.NodeInfo.Add(ctx, 1,
 			attribute.StringSlice("node_publishers", publishers.Keys(ctx)),
 			attribute.StringSlice("node_engines", executors.Keys(ctx)),
@@ -332,6 +395,7 @@ func NewNode(
 		Router:             apiServer.Router,
 		NodeInfoProvider:   nodeInfoProvider,
 		DebugInfoProviders: debugInfoProviders,
+		PluginInfoProvider: pluginInfoProvider,
 	})
 
 	// node info publisher
@@ -348,6 +412,21 @@ func NewNode(
 		IntervalConfig:   nodeInfoPublisherInterval,
 	})
 
+	// compute nodes present their join token directly to orchestrators until they are admitted
+	joinCtx, stopJoining := context.WithCancel(ctx)
+	if config.IsComputeNode && !config.IsRequesterNode && config.NetworkConfig.JoinToken != "" {
+		go func() {
+			err := routing.Join(joinCtx, routing.JoinParams{
+				Joiner:           transportLayer.NodeJoiner(),
+				NodeInfoProvider: nodeInfoProvider,
+				JoinToken:        config.NetworkConfig.JoinToken,
+			})
+			if err != nil && joinCtx.Err() == nil {
+				log.Ctx(joinCtx).Error().Err(err).Msg("failed to join the network")
+			}
+		}()
+	}
+
 	// Start periodic software update checks.
 	updateCheckCtx, stopUpdateChecks := context.WithCancel(ctx)
 	version.RunUpdateChecker(
@@ -369,10 +448,17 @@ func NewNode(
 			requesterNode.cleanup(ctx)
 		}
 		nodeInfoPublisher.Stop(ctx)
+		stopJoining()
 
 		var errors *multierror.Error
 		errors = multierror.Append(errors, transportLayer.Close(ctx))
 		errors = multierror.Append(errors, apiServer.Shutdown(ctx))
+		if membershipStore != nil {
+			errors = multierror.Append(errors, membershipStore.Close(ctx))
+		}
+		if nodeRegistry != nil {
+			errors = multierror.Append(errors, nodeRegistry.Close(ctx))
+		}
 		cancel()
 		return errors.ErrorOrNil()
 	})
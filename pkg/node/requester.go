@@ -18,7 +18,7 @@ import (
 	auth_endpoint "github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/auth"
 	orchestrator_endpoint "github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/orchestrator"
 	requester_endpoint "github.com/bacalhau-project/bacalhau/pkg/publicapi/endpoint/requester"
-	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
 	"github.com/bacalhau-project/bacalhau/pkg/translation"
 	"github.com/bacalhau-project/bacalhau/pkg/util"
@@ -55,7 +55,7 @@ func NewRequesterNode(
 	requesterConfig RequesterConfig,
 	storageProvider storage.StorageProvider,
 	authnProvider authn.Provider,
-	nodeInfoStore routing.NodeInfoStore,
+	nodeManager *admission.NodeManager,
 	fsRepo *repo.FsRepo,
 	computeProxy compute.Endpoint,
 ) (*Requester, error) {
@@ -76,7 +76,7 @@ func NewRequesterNode(
 	nodeDiscoveryChain := discovery.NewChain(true)
 	nodeDiscoveryChain.Add(
 		discovery.NewStoreNodeDiscoverer(discovery.StoreNodeDiscovererParams{
-			Store: nodeInfoStore,
+			Store: nodeManager,
 		}),
 	)
 
@@ -91,6 +91,8 @@ func NewRequesterNode(
 		ranking.NewMaxUsageNodeRanker(),
 		ranking.NewMinVersionNodeRanker(ranking.MinVersionNodeRankerParams{MinVersion: requesterConfig.MinBacalhauVersion}),
 		ranking.NewPreviousExecutionsNodeRanker(ranking.PreviousExecutionsNodeRankerParams{JobStore: jobStore}),
+		// rankers that prefer nodes without filtering others out
+		ranking.NewCachedImagesNodeRanker(),
 		// arbitrary rankers
 		ranking.NewRandomNodeRanker(ranking.RandomNodeRankerParams{
 			RandomnessRange: requesterConfig.NodeRankRandomnessRange,
@@ -165,9 +167,11 @@ func NewRequesterNode(
 			NodeSelector: nodeSelector,
 		}),
 		models.JobTypeDaemon: scheduler.NewDaemonJobScheduler(scheduler.DaemonJobSchedulerParams{
-			JobStore:     jobStore,
-			Planner:      planners,
-			NodeSelector: nodeSelector,
+			JobStore:         jobStore,
+			Planner:          planners,
+			NodeSelector:     nodeSelector,
+			RetryStrategy:    retryStrategy,
+			EvaluationBroker: evalBroker,
 		}),
 	})
 
@@ -224,6 +228,8 @@ func NewRequesterNode(
 		transformer.NameOptional(),
 		transformer.DefaultsApplier(requesterConfig.JobDefaults),
 		transformer.RequesterInfo(nodeID),
+		transformer.OwnerInfo(),
+		transformer.NewJobResultResolver(jobStore),
 		transformer.NewInlineStoragePinner(storageProvider),
 	}
 
@@ -254,6 +260,13 @@ func NewRequesterNode(
 		Interval: requesterConfig.HousekeepingBackgroundTaskInterval,
 	})
 
+	nodeDrainer := orchestrator.NewNodeDrainer(orchestrator.NodeDrainerParams{
+		NodeManager:      nodeManager,
+		JobStore:         jobStore,
+		EvaluationBroker: evalBroker,
+		Interval:         requesterConfig.HousekeepingBackgroundTaskInterval,
+	})
+
 	// register debug info providers for the /debug endpoint
 	debugInfoProviders := []model.DebugInfoProvider{
 		discovery.NewDebugInfoProvider(nodeDiscoveryChain),
@@ -272,7 +285,9 @@ func NewRequesterNode(
 		Router:       apiServer.Router,
 		Orchestrator: endpointV2,
 		JobStore:     jobStore,
-		NodeStore:    nodeInfoStore,
+		NodeStore:    nodeManager,
+		NodeManager:  nodeManager,
+		NodeDrainer:  nodeDrainer,
 	})
 
 	auth_endpoint.BindEndpoint(ctx, apiServer.Router, authnProvider)
@@ -298,8 +313,9 @@ func NewRequesterNode(
 
 	// A single cleanup function to make sure the order of closing dependencies is correct
 	cleanupFunc := func(ctx context.Context) {
-		// stop the housekeeping background task
+		// stop the housekeeping and node drain background tasks
 		housekeeping.Stop()
+		nodeDrainer.Stop()
 		for _, worker := range workers {
 			worker.Stop()
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
@@ -17,6 +18,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
 	"sigs.k8s.io/yaml"
 )
 
@@ -251,7 +253,9 @@ func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsReques
 		return GetResultsResponse{}, err
 	}
 
-	if job.Type != models.JobTypeBatch && job.Type != models.JobTypeOps {
+	// snapshots are published while executions are running, so they are
+	// available for all job types
+	if request.Snapshot == 0 && job.Type != models.JobTypeBatch && job.Type != models.JobTypeOps {
 		return GetResultsResponse{}, fmt.Errorf("job type %s does not support results", job.Type)
 	}
 
@@ -261,11 +265,21 @@ func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsReques
 	if err != nil {
 		return GetResultsResponse{}, err
 	}
+	if request.Snapshot != 0 {
+		return e.getSnapshots(ctx, request, executions)
+	}
 
 	results := make([]*models.SpecConfig, 0)
+	manifests := make([]*models.ResultManifestSignature, 0)
 	for _, execution := range executions {
-		if execution.ComputeState.StateType == models.ExecutionStateCompleted {
-			result := execution.PublishedResult.Copy()
+		if execution.ComputeState.StateType != models.ExecutionStateCompleted {
+			continue
+		}
+		for _, published := range execution.AllPublishedResults() {
+			if request.Publisher != "" && !strings.EqualFold(published.Publisher, request.Publisher) {
+				continue
+			}
+			result := published.Result.Copy()
 			err = e.resultTransformer.Transform(ctx, result)
 			if err != nil {
 				return GetResultsResponse{}, err
@@ -274,11 +288,164 @@ func (e *BaseEndpoint) GetResults(ctx context.Context, request *GetResultsReques
 			// Only add valid results
 			if result.Type != "" {
 				results = append(results, result)
+				var manifest *models.ResultManifestSignature
+				if execution.RunOutput != nil {
+					manifest = execution.RunOutput.Manifest.Copy()
+				}
+				manifests = append(manifests, manifest)
 			}
 		}
 	}
 
 	return GetResultsResponse{
-		Results: results,
+		Results:   results,
+		Manifests: manifests,
 	}, nil
 }
+
+// GetEndpoints returns the endpoints of the running executions of a job
+func (e *BaseEndpoint) GetEndpoints(ctx context.Context, request *GetEndpointsRequest) (GetEndpointsResponse, error) {
+	job, err := e.store.GetJob(ctx, request.JobID)
+	if err != nil {
+		return GetEndpointsResponse{}, err
+	}
+	endpoints, err := e.jobEndpoints(ctx, job, nil)
+	if err != nil {
+		return GetEndpointsResponse{}, err
+	}
+	return GetEndpointsResponse{Endpoints: endpoints}, nil
+}
+
+// LookupService returns the endpoints of a port of the running executions of
+// a job, which is identified by its name or ID.
+func (e *BaseEndpoint) LookupService(ctx context.Context, request *LookupServiceRequest) (LookupServiceResponse, error) {
+	service, err := models.ParseServiceName(request.Name)
+	if err != nil {
+		return LookupServiceResponse{}, err
+	}
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = models.DefaultNamespace
+	}
+
+	var jobs []models.Job
+	job, err := e.store.GetJob(ctx, service.Job)
+	if err == nil {
+		if job.Namespace == namespace {
+			jobs = append(jobs, job)
+		}
+	} else if !errors.As(err, &jobstore.ErrJobNotFound{}) {
+		return LookupServiceResponse{}, err
+	}
+	if len(jobs) == 0 {
+		inProgress, err := e.store.GetInProgressJobs(ctx)
+		if err != nil {
+			return LookupServiceResponse{}, err
+		}
+		for _, job := range inProgress {
+			if job.Name == service.Job && job.Namespace == namespace {
+				jobs = append(jobs, job)
+			}
+		}
+	}
+
+	endpoints := make([]*models.ServiceEndpoint, 0)
+	for _, job := range jobs {
+		jobEndpoints, err := e.jobEndpoints(ctx, job, func(endpoint *models.ExecutionEndpoint) bool {
+			return endpoint.Name == service.Port && endpoint.Protocol == service.Protocol
+		})
+		if err != nil {
+			return LookupServiceResponse{}, err
+		}
+		endpoints = append(endpoints, jobEndpoints...)
+	}
+	return LookupServiceResponse{Endpoints: endpoints}, nil
+}
+
+// jobEndpoints returns the endpoints of the running executions of a job that
+// match the filter, or all of them if the filter is nil.
+func (e *BaseEndpoint) jobEndpoints(
+	ctx context.Context, job models.Job, filter func(*models.ExecutionEndpoint) bool) ([]*models.ServiceEndpoint, error) {
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID: job.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpoints := make([]*models.ServiceEndpoint, 0)
+	for _, execution := range executions {
+		if execution.IsTerminalState() {
+			continue
+		}
+		for _, endpoint := range execution.Endpoints {
+			if filter != nil && !filter(endpoint) {
+				continue
+			}
+			endpoints = append(endpoints, &models.ServiceEndpoint{
+				JobID:             job.ID,
+				ExecutionID:       execution.ID,
+				NodeID:            execution.NodeID,
+				ExecutionEndpoint: *endpoint,
+			})
+		}
+	}
+	return endpoints, nil
+}
+
+// getSnapshots returns the snapshots of each execution up to the requested
+// version. Executions that have not published the requested version are
+// skipped, as their results could not be reconstructed at that version.
+func (e *BaseEndpoint) getSnapshots(
+	ctx context.Context, request *GetResultsRequest, executions []models.Execution) (GetResultsResponse, error) {
+	snapshots := make([]*models.ResultSnapshot, 0)
+	for i := range executions {
+		execution := &executions[i]
+		if len(execution.Snapshots) == 0 {
+			continue
+		}
+		if request.Snapshot != models.LatestSnapshot &&
+			!slices.ContainsFunc(execution.Snapshots, func(s *models.ResultSnapshot) bool {
+				return s.Version == request.Snapshot
+			}) {
+			continue
+		}
+		for _, snapshot := range execution.Snapshots {
+			if request.Snapshot != models.LatestSnapshot && snapshot.Version > request.Snapshot {
+				continue
+			}
+			result, err := e.snapshotResult(ctx, request, snapshot)
+			if err != nil {
+				return GetResultsResponse{}, err
+			}
+			if result == nil {
+				continue
+			}
+			cpy := snapshot.Copy()
+			cpy.Results = []*models.PublishedResult{result}
+			snapshots = append(snapshots, cpy)
+		}
+	}
+	return GetResultsResponse{Snapshots: snapshots}, nil
+}
+
+// snapshotResult returns the first result of a snapshot that was published
+// by the requested publisher, with its source transformed for downloading.
+func (e *BaseEndpoint) snapshotResult(
+	ctx context.Context, request *GetResultsRequest, snapshot *models.ResultSnapshot) (*models.PublishedResult, error) {
+	for _, published := range snapshot.Results {
+		if published == nil || published.Result == nil {
+			continue
+		}
+		if request.Publisher != "" && !strings.EqualFold(published.Publisher, request.Publisher) {
+			continue
+		}
+		result := published.Result.Copy()
+		if err := e.resultTransformer.Transform(ctx, result); err != nil {
+			return nil, err
+		}
+		if result.Type != "" {
+			return &models.PublishedResult{Publisher: published.Publisher, Result: result}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,222 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
+)
+
+// DefaultDrainTimeout is how long batch and ops executions are given to
+// complete on a draining node if no timeout is requested.
+const DefaultDrainTimeout = time.Hour
+
+type NodeDrainerParams struct {
+	NodeManager      *admission.NodeManager
+	JobStore         jobstore.Store
+	EvaluationBroker EvaluationBroker
+	Interval         time.Duration
+	Clock            clock.Clock
+}
+
+// NodeDrainer moves work away from draining nodes. When a node starts
+// draining, it asks the scheduler to evaluate the jobs running on the node so
+// that service and daemon executions are moved elsewhere. It then checks the
+// node periodically, and marks it as drained once no executions are left or
+// the drain deadline has passed, at which point the remaining executions are
+// evaluated again to be stopped and rescheduled.
+type NodeDrainer struct {
+	nodeManager      *admission.NodeManager
+	jobStore         jobstore.Store
+	evaluationBroker EvaluationBroker
+	interval         time.Duration
+	clock            clock.Clock
+
+	// nodes whose jobs have already been evaluated since they started draining
+	evaluated map[string]struct{}
+	mu        sync.Mutex
+
+	stopChannel chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewNodeDrainer(params NodeDrainerParams) *NodeDrainer {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	d := &NodeDrainer{
+		nodeManager:      params.NodeManager,
+		jobStore:         params.JobStore,
+		evaluationBroker: params.EvaluationBroker,
+		interval:         params.Interval,
+		clock:            params.Clock,
+		evaluated:        make(map[string]struct{}),
+		stopChannel:      make(chan struct{}),
+	}
+
+	go d.drainBackgroundTask()
+	return d
+}
+
+// DrainNode starts draining a node. Executions still running on the node
+// after the timeout are stopped and rescheduled.
+func (d *NodeDrainer) DrainNode(ctx context.Context, nodeID string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	if err := d.nodeManager.DrainNode(ctx, nodeID, d.clock.Now().UTC().Add(timeout)); err != nil {
+		return err
+	}
+	// a node that is drained again, e.g. after being uncordoned, has its jobs
+	// evaluated again even if no tick noticed that it stopped draining
+	d.mu.Lock()
+	delete(d.evaluated, nodeID)
+	d.mu.Unlock()
+	// move long-running executions straight away rather than on the next tick
+	return d.Drain(ctx)
+}
+
+func (d *NodeDrainer) drainBackgroundTask() {
+	ctx := context.Background()
+	ticker := d.clock.Ticker(d.interval)
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.Drain(ctx); err != nil {
+				log.Ctx(ctx).Err(err).Msg("failed to drain nodes")
+			}
+		case <-d.stopChannel:
+			log.Ctx(ctx).Debug().Msg("stopped node drainer task")
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+// Drain makes progress on all draining nodes. Failing to drain a node or to
+// evaluate one of its jobs doesn't stop the others from being drained, and
+// all the errors are returned together.
+func (d *NodeDrainer) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	draining, err := d.nodeManager.DrainingNodes(ctx)
+	if err != nil {
+		return err
+	}
+	// forget nodes that stopped draining, e.g. because they were uncordoned
+	for nodeID := range d.evaluated {
+		if !slices.ContainsFunc(draining, func(membership models.NodeMembership) bool {
+			return membership.NodeID == nodeID
+		}) {
+			delete(d.evaluated, nodeID)
+		}
+	}
+	if len(draining) == 0 {
+		return nil
+	}
+
+	activeJobs, err := d.activeJobsByNode(ctx, draining)
+	if err != nil {
+		return err
+	}
+
+	now := d.clock.Now()
+	var errs error
+	for _, membership := range draining {
+		nodeID := membership.NodeID
+		jobs := activeJobs[nodeID]
+		_, evaluated := d.evaluated[nodeID]
+
+		drained := len(jobs) == 0 || !now.Before(membership.DrainDeadline)
+		switch {
+		case drained:
+			if err = d.nodeManager.MarkDrained(ctx, nodeID); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("failed to mark node %s as drained: %w", nodeID, err))
+				continue
+			}
+			delete(d.evaluated, nodeID)
+		case evaluated:
+			continue
+		}
+
+		var evalErrs error
+		for _, job := range jobs {
+			if err = d.evaluate(ctx, job); err != nil {
+				evalErrs = errors.Join(evalErrs, fmt.Errorf("failed to evaluate job %s on node %s: %w", job.ID, nodeID, err))
+			}
+		}
+		// the jobs of a node that is still draining are evaluated again on the next tick if any of them failed
+		if !drained && evalErrs == nil {
+			d.evaluated[nodeID] = struct{}{}
+		}
+		errs = errors.Join(errs, evalErrs)
+	}
+	return errs
+}
+
+// activeJobsByNode returns the in progress jobs that have non-terminal
+// executions on each of the given nodes.
+func (d *NodeDrainer) activeJobsByNode(
+	ctx context.Context, nodes []models.NodeMembership) (map[string][]models.Job, error) {
+	out := make(map[string][]models.Job)
+	for _, node := range nodes {
+		out[node.NodeID] = nil
+	}
+
+	jobs, err := d.jobStore.GetInProgressJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		executions, err := d.jobStore.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: job.ID})
+		if err != nil {
+			return nil, err
+		}
+		added := make(map[string]struct{})
+		for _, execution := range executions {
+			if _, ok := out[execution.NodeID]; !ok || execution.IsTerminalComputeState() {
+				continue
+			}
+			if _, ok := added[execution.NodeID]; !ok {
+				out[execution.NodeID] = append(out[execution.NodeID], job)
+				added[execution.NodeID] = struct{}{}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (d *NodeDrainer) evaluate(ctx context.Context, job models.Job) error {
+	now := d.clock.Now().UTC().UnixNano()
+	eval := &models.Evaluation{
+		ID:          uuid.NewString(),
+		JobID:       job.ID,
+		TriggeredBy: models.EvalTriggerNodeDrain,
+		Type:        job.Type,
+		Status:      models.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	if err := d.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("failed to save evaluation for draining job %s", job.ID)
+		return err
+	}
+	return d.evaluationBroker.Enqueue(eval)
+}
+
+func (d *NodeDrainer) Stop() {
+	d.stopOnce.Do(func() {
+		d.stopChannel <- struct{}{}
+	})
+}
@@ -0,0 +1,181 @@
+//go:build unit || !integration
+
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/inmemory"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+const drainedNodeID = "QmXaXu9N5GNetatsvwnTfQqNtSeKAD6uCmarbh3LMRYAcF"
+
+type NodeDrainerSuite struct {
+	suite.Suite
+	ctx             context.Context
+	clock           *clock.Mock
+	jobStore        *jobstore.MockStore
+	broker          *MockEvaluationBroker
+	membershipStore *boltdb.MembershipStore
+	nodeManager     *admission.NodeManager
+	drainer         *NodeDrainer
+	job             *models.Job
+}
+
+func TestNodeDrainerSuite(t *testing.T) {
+	suite.Run(t, new(NodeDrainerSuite))
+}
+
+func (s *NodeDrainerSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	ctrl := gomock.NewController(s.T())
+	s.jobStore = jobstore.NewMockStore(ctrl)
+	s.broker = NewMockEvaluationBroker(ctrl)
+
+	var err error
+	s.membershipStore, err = boltdb.NewMembershipStore(filepath.Join(s.T().TempDir(), "nodes.db"))
+	s.Require().NoError(err)
+	s.nodeManager = admission.NewNodeManager(admission.NodeManagerParams{
+		NodeInfoStore:   inmemory.NewNodeInfoStore(inmemory.NodeInfoStoreParams{TTL: time.Hour}),
+		MembershipStore: s.membershipStore,
+		Clock:           s.clock,
+	})
+	s.Require().NoError(s.nodeManager.Add(s.ctx, models.NodeInfo{NodeID: drainedNodeID, NodeType: models.NodeTypeCompute}))
+
+	s.drainer = NewNodeDrainer(NodeDrainerParams{
+		NodeManager:      s.nodeManager,
+		JobStore:         s.jobStore,
+		EvaluationBroker: s.broker,
+		Interval:         time.Hour,
+		Clock:            s.clock,
+	})
+
+	s.job = mock.Job()
+	s.job.Type = models.JobTypeBatch
+}
+
+func (s *NodeDrainerSuite) TearDownTest() {
+	s.drainer.Stop()
+	s.NoError(s.membershipStore.Close(s.ctx))
+}
+
+// mockActiveExecution mocks the job store to return an execution in the given
+// state on the draining node.
+func (s *NodeDrainerSuite) mockActiveExecution(state models.ExecutionStateType) {
+	execution := mock.ExecutionForJob(s.job)
+	execution.NodeID = drainedNodeID
+	execution.ComputeState = models.NewExecutionState(state)
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any()).Return([]models.Job{*s.job}, nil).AnyTimes()
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: s.job.ID}).
+		Return([]models.Execution{*execution}, nil).AnyTimes()
+}
+
+func (s *NodeDrainerSuite) expectEvaluation(times int) {
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, eval models.Evaluation) error {
+			s.Equal(s.job.ID, eval.JobID)
+			s.Equal(models.EvalTriggerNodeDrain, eval.TriggeredBy)
+			return nil
+		}).Times(times)
+	s.broker.EXPECT().Enqueue(gomock.Any()).Times(times)
+}
+
+func (s *NodeDrainerSuite) scheduling() models.NodeSchedulingState {
+	nodeInfo, err := s.nodeManager.Get(s.ctx, drainedNodeID)
+	s.Require().NoError(err)
+	return nodeInfo.Scheduling
+}
+
+func (s *NodeDrainerSuite) TestNodeWithoutExecutionsIsDrained() {
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any()).Return(nil, nil)
+
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.Equal(models.NodeDrained, s.scheduling())
+}
+
+func (s *NodeDrainerSuite) TestJobsAreEvaluatedOnceWhileDraining() {
+	s.mockActiveExecution(models.ExecutionStateBidAccepted)
+	s.expectEvaluation(1)
+
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.Require().NoError(s.drainer.Drain(s.ctx))
+	s.Equal(models.NodeDraining, s.scheduling())
+}
+
+func (s *NodeDrainerSuite) TestJobsAreEvaluatedAgainWhenDrainedAgain() {
+	s.mockActiveExecution(models.ExecutionStateBidAccepted)
+	s.expectEvaluation(2)
+
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.Require().NoError(s.nodeManager.UncordonNode(s.ctx, drainedNodeID))
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.Equal(models.NodeDraining, s.scheduling())
+}
+
+func (s *NodeDrainerSuite) TestNodeIsDrainedAtDeadline() {
+	s.mockActiveExecution(models.ExecutionStateBidAccepted)
+	s.expectEvaluation(2)
+
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.clock.Add(time.Minute)
+	s.Require().NoError(s.drainer.Drain(s.ctx))
+	s.Equal(models.NodeDrained, s.scheduling())
+}
+
+func (s *NodeDrainerSuite) TestTerminalExecutionsAreIgnored() {
+	s.mockActiveExecution(models.ExecutionStateCompleted)
+
+	s.Require().NoError(s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute))
+	s.Equal(models.NodeDrained, s.scheduling())
+}
+
+func (s *NodeDrainerSuite) TestFailedEvaluationDoesNotStopDrain() {
+	other := mock.Job()
+	other.Type = models.JobTypeBatch
+	var executions []models.Execution
+	for _, job := range []*models.Job{s.job, other} {
+		execution := mock.ExecutionForJob(job)
+		execution.NodeID = drainedNodeID
+		execution.ComputeState = models.NewExecutionState(models.ExecutionStateBidAccepted)
+		executions = append(executions, *execution)
+	}
+	s.jobStore.EXPECT().GetInProgressJobs(gomock.Any()).Return([]models.Job{*s.job, *other}, nil).AnyTimes()
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: s.job.ID}).
+		Return(executions[:1], nil).AnyTimes()
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: other.ID}).
+		Return(executions[1:], nil).AnyTimes()
+
+	// the first job fails to be evaluated once, and the other job is still evaluated
+	failed := false
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, eval models.Evaluation) error {
+			if eval.JobID == s.job.ID && !failed {
+				failed = true
+				return errors.New("store unavailable")
+			}
+			return nil
+		}).Times(4)
+	s.broker.EXPECT().Enqueue(gomock.Any()).Times(3)
+
+	err := s.drainer.DrainNode(s.ctx, drainedNodeID, time.Minute)
+	s.ErrorContains(err, "store unavailable")
+	s.Equal(models.NodeDraining, s.scheduling())
+
+	// the jobs of the node are evaluated again as one of them failed
+	s.Require().NoError(s.drainer.Drain(s.ctx))
+	s.Require().NoError(s.drainer.Drain(s.ctx))
+}
@@ -238,6 +238,100 @@ func (s *BatchJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutions
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldRestoreLatestCheckpoint() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	job.Task().Publisher = models.NewSpecConfig(models.PublisherNoop)
+	job.Task().Checkpoint = &models.CheckpointConfig{Path: "/checkpoint"}
+	checkpoint := func(createTime int64, key string) []*models.Checkpoint {
+		return []*models.Checkpoint{{
+			Version:    1,
+			CreateTime: createTime,
+			Results: []*models.PublishedResult{
+				{Publisher: models.PublisherNoop, Result: models.NewSpecConfig(models.StorageSourceS3).WithParam("Key", key)},
+			},
+		}}
+	}
+	executions[execFailed].Checkpoints = checkpoint(1, "failed")
+	executions[execBidAccepted].Checkpoints = checkpoint(2, "lost")
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// the node of the execution with the latest checkpoint is lost
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*mockNodeInfo(s.T(), executions[execCanceled].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos, 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions: []string{
+			executions[execBidAccepted].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1).Do(func(ctx context.Context, plan *models.Plan) {
+		s.Require().Len(plan.NewExecutions, 1)
+		execution := plan.NewExecutions[0]
+		s.Equal(executions[execBidAccepted].ID, execution.PreviousExecution)
+		s.Require().NotNil(execution.CheckpointInput)
+		s.Equal("/checkpoint", execution.CheckpointInput.Target)
+		s.Equal(models.CheckpointInputAlias, execution.CheckpointInput.Alias)
+		s.Equal("lost", execution.CheckpointInput.Source.Params["Key"])
+	})
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldKeepExecutionsOnDrainingNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// batch executions are allowed to complete on a draining node
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*mockNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}
+	nodeInfos[1].Scheduling = models.NodeDraining
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+
+	// empty plan
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMoveExecutionsOffDrainedNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// the drain deadline passed while the BidAccepted execution was still running
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[execAskForBid].NodeID),
+		*mockNodeInfo(s.T(), executions[execBidAccepted].NodeID),
+	}
+	nodeInfos[1].Scheduling = models.NodeDrained
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos[:1], 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions: []string{
+			executions[execBidAccepted].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 func (s *BatchJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsCompleted() {
 	ctx := context.Background()
 	job, executions, evaluation := mockJob()
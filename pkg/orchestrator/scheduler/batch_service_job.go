@@ -77,6 +77,14 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
 	lost.markStopped(execLost, plan)
 
+	// Move executions away from nodes that are being drained
+	nonTerminalExecs, drained := nonTerminalExecs.filterByNodeDrain(nodeInfos, job.Type)
+	drained.markStopped(execDrained, plan)
+
+	// Replace executions that failed their health checks
+	nonTerminalExecs, unhealthy := nonTerminalExecs.filterByHealth()
+	unhealthy.markStopped(execUnhealthy, plan)
+
 	// Calculate remaining job count
 	// Service jobs run until the user stops the job, and would be a bug if an execution is marked completed. So the desired
 	// remaining count equals the count specified in the job spec.
@@ -94,12 +102,13 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 	// create new executions if needed
 	remainingExecutionCount := desiredRemainingCount - execsByApprovalStatus.activeCount()
 	if remainingExecutionCount > 0 {
-		allFailed := existingExecs.filterFailed().union(lost)
+		allFailed := existingExecs.filterFailed().union(lost).union(unhealthy)
 		var placementErr error
 		if len(allFailed) > 0 && !b.retryStrategy.ShouldRetry(ctx, orchestrator.RetryRequest{JobID: job.ID}) {
 			placementErr = fmt.Errorf("exceeded max retries for job %s", job.ID)
 		} else {
-			_, placementErr = b.createMissingExecs(ctx, remainingExecutionCount, &job, plan)
+			_, placementErr = b.createMissingExecs(ctx, remainingExecutionCount, &job, plan,
+				existingExecs.checkpointsToRestore(nonTerminalExecs))
 		}
 		if placementErr != nil {
 			b.handleFailure(nonTerminalExecs, allFailed, plan, placementErr)
@@ -122,8 +131,10 @@ func (b *BatchServiceJobScheduler) Process(ctx context.Context, evaluation *mode
 }
 
 func (b *BatchServiceJobScheduler) createMissingExecs(
-	ctx context.Context, remainingExecutionCount int, job *models.Job, plan *models.Plan) (execSet, error) {
+	ctx context.Context, remainingExecutionCount int, job *models.Job, plan *models.Plan,
+	checkpointed []*models.Execution) (execSet, error) {
 	newExecs := execSet{}
+	checkpoint := job.Task().Checkpoint
 	for i := 0; i < remainingExecutionCount; i++ {
 		execution := &models.Execution{
 			JobID:        job.ID,
@@ -133,6 +144,15 @@ func (b *BatchServiceJobScheduler) createMissingExecs(
 			ComputeState: models.NewExecutionState(models.ExecutionStateNew),
 			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStatePending),
 		}
+		// resume from the latest checkpoint of an execution that did not complete
+		if checkpoint.Enabled() && len(checkpointed) > 0 {
+			previous := checkpointed[0]
+			checkpointed = checkpointed[1:]
+			if input := previous.LatestCheckpoint().InputSource(checkpoint); input != nil {
+				execution.CheckpointInput = input
+				execution.PreviousExecution = previous.ID
+			}
+		}
 		execution.Normalize()
 		newExecs[execution.ID] = execution
 	}
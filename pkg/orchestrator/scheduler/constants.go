@@ -7,6 +7,12 @@ const (
 	// execLost is the status used when an execution is lost
 	execLost = "execution is lost since its node is down"
 
+	// execDrained is the status used when an execution is stopped since its node is being drained
+	execDrained = "execution is stopped since its node is being drained"
+
+	// execUnhealthy is the status used when an execution is replaced since it failed its health checks
+	execUnhealthy = "execution is replaced since it failed its health checks"
+
 	// execRejected is the status used when an execution is rejected
 	execRejected = "execution is rejected in favor of another execution"
 
@@ -3,8 +3,10 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
@@ -12,24 +14,41 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// replacementBaseBackoff and replacementMaxBackoff bound how long the
+	// scheduler waits before replacing an execution that failed its health
+	// checks, which doubles every time the replacement on a node is replaced too.
+	replacementBaseBackoff = 10 * time.Second
+	replacementMaxBackoff  = 5 * time.Minute
+)
+
 // DaemonJobScheduler is a scheduler for batch jobs that run until completion
 type DaemonJobScheduler struct {
-	jobStore     jobstore.Store
-	planner      orchestrator.Planner
-	nodeSelector orchestrator.NodeSelector
+	jobStore           jobstore.Store
+	planner            orchestrator.Planner
+	nodeSelector       orchestrator.NodeSelector
+	retryStrategy      orchestrator.RetryStrategy
+	evaluationBroker   orchestrator.EvaluationBroker
+	replacementBackoff *backoff.Exponential
 }
 
 type DaemonJobSchedulerParams struct {
-	JobStore     jobstore.Store
-	Planner      orchestrator.Planner
-	NodeSelector orchestrator.NodeSelector
+	JobStore      jobstore.Store
+	Planner       orchestrator.Planner
+	NodeSelector  orchestrator.NodeSelector
+	RetryStrategy orchestrator.RetryStrategy
+	// EvaluationBroker enqueues the evaluations that replace unhealthy executions once their backoff has passed.
+	EvaluationBroker orchestrator.EvaluationBroker
 }
 
 func NewDaemonJobScheduler(params DaemonJobSchedulerParams) *DaemonJobScheduler {
 	return &DaemonJobScheduler{
-		jobStore:     params.JobStore,
-		planner:      params.Planner,
-		nodeSelector: params.NodeSelector,
+		jobStore:           params.JobStore,
+		planner:            params.Planner,
+		nodeSelector:       params.NodeSelector,
+		retryStrategy:      params.RetryStrategy,
+		evaluationBroker:   params.EvaluationBroker,
+		replacementBackoff: backoff.NewExponential(replacementBaseBackoff, replacementMaxBackoff),
 	}
 }
 
@@ -68,9 +87,26 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 	}
 
 	// Mark executions that are running on nodes that are not healthy as failed
-	_, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
+	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
 	lost.markStopped(execLost, plan)
 
+	// Move executions away from nodes that are being drained
+	nonTerminalExecs, drained := nonTerminalExecs.filterByNodeDrain(nodeInfos, job.Type)
+	drained.markStopped(execDrained, plan)
+
+	// Replace executions that failed their health checks with new executions on the same nodes
+	nonTerminalExecs, unhealthy := nonTerminalExecs.filterByHealth()
+	if len(unhealthy) > 0 {
+		if !b.retryStrategy.ShouldRetry(ctx, orchestrator.RetryRequest{JobID: job.ID}) {
+			nonTerminalExecs.union(unhealthy).markStopped(jobFailed, plan)
+			plan.MarkJobFailed(fmt.Sprintf("exceeded max retries for job %s", job.ID))
+			return b.planner.Process(ctx, plan)
+		}
+		if err = b.replaceUnhealthyExecs(ctx, &job, plan, existingExecs, unhealthy); err != nil {
+			return err
+		}
+	}
+
 	// Look for new matching nodes and create new executions every time we evaluate the job
 	_, err = b.createMissingExecs(ctx, &job, plan, existingExecs)
 	if err != nil {
@@ -81,6 +117,62 @@ func (b *DaemonJobScheduler) Process(ctx context.Context, evaluation *models.Eva
 	return b.planner.Process(ctx, plan)
 }
 
+// replaceUnhealthyExecs replaces executions that failed their health checks
+// with new executions on the same nodes. Executions that replaced unhealthy
+// executions themselves are only replaced once they ran for the backoff of the
+// number of replacements before them, so that executions failing over and over
+// are not replaced in a tight loop. An evaluation is enqueued to replace them
+// once the earliest backoff passes.
+func (b *DaemonJobScheduler) replaceUnhealthyExecs(
+	ctx context.Context, job *models.Job, plan *models.Plan, existingExecs execSet, unhealthy execSet) error {
+	now := time.Now().UTC()
+	var retryAt time.Time
+	for _, exec := range unhealthy {
+		replaceAt := time.Unix(0, exec.CreateTime).Add(b.replacementBackoff.Duration(replacements(existingExecs, exec)))
+		if now.Before(replaceAt) {
+			if retryAt.IsZero() || replaceAt.Before(retryAt) {
+				retryAt = replaceAt
+			}
+			continue
+		}
+		plan.AppendStoppedExecution(exec, execUnhealthy)
+		plan.AppendExecution(b.newExecution(job, exec.NodeID, exec.ID))
+	}
+	if retryAt.IsZero() {
+		return nil
+	}
+
+	log.Ctx(ctx).Debug().Msgf("backing off replacing unhealthy executions of job %s until %s", job.ID, retryAt)
+	eval := &models.Evaluation{
+		ID:          uuid.NewString(),
+		JobID:       job.ID,
+		TriggeredBy: models.EvalTriggerRetryFailedExec,
+		Type:        job.Type,
+		Status:      models.EvalStatusPending,
+		WaitUntil:   retryAt,
+		CreateTime:  now.UnixNano(),
+		ModifyTime:  now.UnixNano(),
+	}
+	if err := b.jobStore.CreateEvaluation(ctx, *eval); err != nil {
+		return fmt.Errorf("failed to save evaluation to replace unhealthy executions of job %s: %w", job.ID, err)
+	}
+	return b.evaluationBroker.Enqueue(eval)
+}
+
+// replacements returns the number of executions that the execution replaced
+// one after the other.
+func replacements(existingExecs execSet, exec *models.Execution) int {
+	count := 0
+	for id := exec.PreviousExecution; count < len(existingExecs); count++ {
+		previous, ok := existingExecs[id]
+		if !ok {
+			break
+		}
+		id = previous.PreviousExecution
+	}
+	return count
+}
+
 func (b *DaemonJobScheduler) createMissingExecs(
 	ctx context.Context, job *models.Job, plan *models.Plan, existingExecs execSet) (execSet, error) {
 	newExecs := execSet{}
@@ -100,16 +192,7 @@ func (b *DaemonJobScheduler) createMissingExecs(
 			// there is already a healthy execution on this node
 			continue
 		}
-		execution := &models.Execution{
-			JobID:        job.ID,
-			Job:          job,
-			ID:           idgen.ExecutionIDPrefix + uuid.NewString(),
-			Namespace:    job.Namespace,
-			ComputeState: models.NewExecutionState(models.ExecutionStateNew),
-			DesiredState: models.NewExecutionDesiredState(models.ExecutionDesiredStateRunning),
-			NodeID:       node.ID(),
-		}
-		execution.Normalize()
+		execution := b.newExecution(job, node.ID(), "")
 		newExecs[execution.ID] = execution
 	}
 	for _, exec := range newExecs {
@@ -118,5 +201,22 @@ func (b *DaemonJobScheduler) createMissingExecs(
 	return newExecs, nil
 }
 
+// newExecution returns a new execution of the job on a node, replacing the
+// previous execution if any.
+func (b *DaemonJobScheduler) newExecution(job *models.Job, nodeID string, previousExecution string) *models.Execution {
+	execution := &models.Execution{
+		JobID:             job.ID,
+		Job:               job,
+		ID:                idgen.ExecutionIDPrefix + uuid.NewString(),
+		Namespace:         job.Namespace,
+		ComputeState:      models.NewExecutionState(models.ExecutionStateNew),
+		DesiredState:      models.NewExecutionDesiredState(models.ExecutionDesiredStateRunning),
+		NodeID:            nodeID,
+		PreviousExecution: previousExecution,
+	}
+	execution.Normalize()
+	return execution
+}
+
 // compile-time assertion that DaemonJobScheduler satisfies the Scheduler interface
 var _ orchestrator.Scheduler = &DaemonJobScheduler{}
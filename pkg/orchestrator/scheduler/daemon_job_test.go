@@ -5,10 +5,12 @@ package scheduler
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator/retry"
 	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
@@ -20,6 +22,7 @@ type DaemonJobSchedulerTestSuite struct {
 	jobStore     *jobstore.MockStore
 	planner      *orchestrator.MockPlanner
 	nodeSelector *orchestrator.MockNodeSelector
+	evalBroker   *orchestrator.MockEvaluationBroker
 	scheduler    *DaemonJobScheduler
 }
 
@@ -28,11 +31,14 @@ func (s *DaemonJobSchedulerTestSuite) SetupTest() {
 	s.jobStore = jobstore.NewMockStore(ctrl)
 	s.planner = orchestrator.NewMockPlanner(ctrl)
 	s.nodeSelector = orchestrator.NewMockNodeSelector(ctrl)
+	s.evalBroker = orchestrator.NewMockEvaluationBroker(ctrl)
 
 	s.scheduler = NewDaemonJobScheduler(DaemonJobSchedulerParams{
-		JobStore:     s.jobStore,
-		Planner:      s.planner,
-		NodeSelector: s.nodeSelector,
+		JobStore:         s.jobStore,
+		Planner:          s.planner,
+		NodeSelector:     s.nodeSelector,
+		RetryStrategy:    retry.NewFixedStrategy(retry.FixedStrategyParams{ShouldRetry: true}),
+		EvaluationBroker: s.evalBroker,
 	})
 }
 
@@ -113,6 +119,99 @@ func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldMarkLostExecutionsOnUnhe
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldReplaceUnhealthyExecutionsOnTheSameNode() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	executions[0].Health = &models.ExecutionHealth{Status: models.ExecutionHealthUnhealthy}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[0].NodeID),
+		*mockNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job).Return(nodeInfos, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		JobState:                 models.JobStateTypeRunning,
+		Evaluation:               evaluation,
+		NewExecutionsNodes:       []string{executions[0].NodeID},
+		NewExecutionDesiredState: models.ExecutionDesiredStateRunning,
+		StoppedExecutions: []string{
+			executions[0].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldBackOffReplacingRepeatedlyUnhealthyExecutions() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	// the unhealthy execution replaced an execution on the same node that was unhealthy too
+	previous := executions[0]
+	previous.ID = "previous-execution"
+	previous.ComputeState = models.NewExecutionState(models.ExecutionStateCancelled)
+	previous.DesiredState = models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped)
+	executions[0].PreviousExecution = previous.ID
+	executions[0].CreateTime = time.Now().UnixNano()
+	executions[0].Health = &models.ExecutionHealth{Status: models.ExecutionHealthUnhealthy}
+	executions = append(executions, previous)
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[0].NodeID),
+		*mockNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.nodeSelector.EXPECT().AllMatchingNodes(gomock.Any(), job).Return(nodeInfos, nil)
+
+	// the execution is only replaced once it ran for the backoff, by a delayed evaluation
+	replaceAt := time.Unix(0, executions[0].CreateTime).Add(replacementBaseBackoff)
+	s.jobStore.EXPECT().CreateEvaluation(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, eval models.Evaluation) error {
+			s.Equal(job.ID, eval.JobID)
+			s.WithinDuration(replaceAt, eval.WaitUntil, time.Millisecond)
+			return nil
+		})
+	s.evalBroker.EXPECT().Enqueue(gomock.Any()).Return(nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *DaemonJobSchedulerTestSuite) TestProcess_ShouldMarkJobAsFailed_NoRetry() {
+	ctx := context.Background()
+	job, executions, evaluation := mockDaemonJob()
+	executions[0].Health = &models.ExecutionHealth{Status: models.ExecutionHealthUnhealthy}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+	s.scheduler.retryStrategy = retry.NewFixedStrategy(retry.FixedStrategyParams{ShouldRetry: false})
+
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[0].NodeID),
+		*mockNodeInfo(s.T(), executions[1].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation: evaluation,
+		JobState:   models.JobStateTypeFailed,
+		StoppedExecutions: []string{
+			executions[0].ID,
+			executions[1].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 // Even when an execution has failed, we don't mark the job as failed and continue waiting
 // for more nodes that match the job selection to join.
 // This requires a revisit in the future if all or a high percentage of nodes keep failing
@@ -72,6 +72,10 @@ func (b *OpsJobScheduler) Process(ctx context.Context, evaluation *models.Evalua
 	nonTerminalExecs, lost := nonTerminalExecs.filterByNodeHealth(nodeInfos)
 	lost.markStopped(execLost, plan)
 
+	// Move executions away from nodes that are being drained
+	nonTerminalExecs, drained := nonTerminalExecs.filterByNodeDrain(nodeInfos, job.Type)
+	drained.markStopped(execDrained, plan)
+
 	allFailed := existingExecs.filterFailed().union(lost)
 
 	// Look for matching nodes and create new executions if:
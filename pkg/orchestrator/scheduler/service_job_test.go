@@ -238,6 +238,62 @@ func (s *ServiceJobSchedulerTestSuite) TestFailUnhealthyExecs_ShouldMarkExecutio
 	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
 }
 
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldMoveExecutionsOffDrainingNodes() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	// service executions are moved off a draining node straight away
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*mockNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*mockNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}
+	nodeInfos[1].Scheduling = models.NodeDraining
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos[:1], 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions: []string{
+			executions[execServiceBidAccepted1].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
+func (s *ServiceJobSchedulerTestSuite) TestProcess_ShouldReplaceUnhealthyExecutions() {
+	ctx := context.Background()
+	job, executions, evaluation := mockServiceJob()
+	executions[execServiceBidAccepted1].Health = &models.ExecutionHealth{
+		Status:  models.ExecutionHealthUnhealthy,
+		Message: "health check request returned status 503 Service Unavailable",
+	}
+	s.jobStore.EXPECT().GetJob(gomock.Any(), job.ID).Return(*job, nil)
+	s.jobStore.EXPECT().GetExecutions(gomock.Any(), jobstore.GetExecutionsOptions{JobID: job.ID}).Return(executions, nil)
+
+	nodeInfos := []models.NodeInfo{
+		*mockNodeInfo(s.T(), executions[execServiceAskForBid].NodeID),
+		*mockNodeInfo(s.T(), executions[execServiceBidAccepted1].NodeID),
+		*mockNodeInfo(s.T(), executions[execServiceBidAccepted2].NodeID),
+	}
+	s.nodeSelector.EXPECT().AllNodes(gomock.Any()).Return(nodeInfos, nil)
+	s.mockNodeSelection(job, nodeInfos[:1], 1)
+
+	matcher := NewPlanMatcher(s.T(), PlanMatcherParams{
+		Evaluation:         evaluation,
+		NewExecutionsNodes: []string{nodeInfos[0].ID()},
+		StoppedExecutions: []string{
+			executions[execServiceBidAccepted1].ID,
+		},
+	})
+	s.planner.EXPECT().Process(gomock.Any(), matcher).Times(1)
+	s.Require().NoError(s.scheduler.Process(ctx, evaluation))
+}
+
 // It is a bug if a long running execution is completed. The scheduler treat those as failed executions,
 // try to reschedule, or fail the job if can no longer reschedule
 func (s *ServiceJobSchedulerTestSuite) TestProcess_TreatCompletedExecutionsAsFailed() {
@@ -121,12 +121,13 @@ func (set execSet) filterByOverSubscriptions(desiredCount int) (remaining execSe
 	return remaining, overSubscriptions
 }
 
-// filterByNodeHealth partitions executions based on their node's health status.
+// filterByNodeHealth partitions executions based on their node's health status. Executions are lost if their node
+// is no longer known, or if it has been disconnected for long enough.
 func (set execSet) filterByNodeHealth(nodeInfos map[string]*models.NodeInfo) (healthy execSet, lost execSet) {
 	healthy = make(execSet)
 	lost = make(execSet)
 	for _, exec := range set {
-		if _, ok := nodeInfos[exec.NodeID]; !ok {
+		if nodeInfo, ok := nodeInfos[exec.NodeID]; !ok || !nodeInfo.IsConnected() {
 			lost[exec.ID] = exec
 			log.Debug().Msgf("Execution %s is running on node %s which is not healthy", exec.ID, exec.NodeID)
 		} else {
@@ -136,6 +137,46 @@ func (set execSet) filterByNodeHealth(nodeInfos map[string]*models.NodeInfo) (he
 	return healthy, lost
 }
 
+// filterByNodeDrain partitions executions based on whether they must be moved off their node because it is
+// being drained. Service and daemon executions are moved as soon as draining starts, while batch and ops executions
+// are given until the node is drained to complete.
+func (set execSet) filterByNodeDrain(
+	nodeInfos map[string]*models.NodeInfo, jobType string) (remaining execSet, drained execSet) {
+	remaining = make(execSet)
+	drained = make(execSet)
+	for _, exec := range set {
+		nodeInfo, ok := nodeInfos[exec.NodeID]
+		if !ok {
+			remaining[exec.ID] = exec
+			continue
+		}
+		switch {
+		case nodeInfo.Scheduling == models.NodeDrained,
+			nodeInfo.Scheduling == models.NodeDraining && jobType != models.JobTypeBatch && jobType != models.JobTypeOps:
+			drained[exec.ID] = exec
+			log.Debug().Msgf("Execution %s is running on node %s which is being drained", exec.ID, exec.NodeID)
+		default:
+			remaining[exec.ID] = exec
+		}
+	}
+	return remaining, drained
+}
+
+// filterByHealth partitions executions based on whether they failed the health checks of their task.
+func (set execSet) filterByHealth() (remaining execSet, unhealthy execSet) {
+	remaining = make(execSet)
+	unhealthy = make(execSet)
+	for _, exec := range set {
+		if exec.Health.IsUnhealthy() {
+			unhealthy[exec.ID] = exec
+			log.Debug().Msgf("Execution %s failed its health checks: %s", exec.ID, exec.Health.Message)
+		} else {
+			remaining[exec.ID] = exec
+		}
+	}
+	return remaining, unhealthy
+}
+
 // executionsByApprovalStatus represents the different sets of executions based on their approval status.
 type executionsByApprovalStatus struct {
 	running   execSet
@@ -242,3 +283,30 @@ func (set execSet) countByState() map[models.ExecutionStateType]int {
 func (set execSet) countCompleted() int {
 	return set.countByState()[models.ExecutionStateCompleted]
 }
+
+// checkpointsToRestore returns the executions in the set whose checkpoints
+// should be restored by new executions, ordered by their latest checkpoint
+// with the most recent first. These are the executions that did not complete
+// and are no longer active, and that were not already replaced by an active
+// execution or by one that took checkpoints of its own.
+func (set execSet) checkpointsToRestore(active execSet) []*models.Execution {
+	superseded := make(map[string]bool)
+	for _, exec := range set {
+		if exec.PreviousExecution != "" && (active.has(exec.ID) || len(exec.Checkpoints) > 0) {
+			superseded[exec.PreviousExecution] = true
+		}
+	}
+
+	var execs []*models.Execution
+	for _, exec := range set {
+		if exec.LatestCheckpoint() == nil || active.has(exec.ID) || superseded[exec.ID] ||
+			exec.ComputeState.StateType == models.ExecutionStateCompleted {
+			continue
+		}
+		execs = append(execs, exec)
+	}
+	sort.Slice(execs, func(i, j int) bool {
+		return execs[i].LatestCheckpoint().CreateTime > execs[j].LatestCheckpoint().CreateTime
+	})
+	return execs
+}
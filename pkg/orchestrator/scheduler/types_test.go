@@ -188,6 +188,53 @@ func TestExecSet_FilterByNodeHealth(t *testing.T) {
 	assert.ElementsMatch(t, lost.keys(), []string{"exec3"})
 }
 
+func TestExecSet_FilterByNodeHealth_Liveness(t *testing.T) {
+	nodeInfos := map[string]*models.NodeInfo{
+		"node1": {Liveness: models.NodeLivenessHealthy},
+		"node2": {Liveness: models.NodeLivenessSuspect},
+		"node3": {Liveness: models.NodeLivenessDisconnected},
+		"node4": {Liveness: models.NodeLivenessGone},
+	}
+
+	executions := []*models.Execution{
+		{ID: "exec1", NodeID: "node1"},
+		{ID: "exec2", NodeID: "node2"},
+		{ID: "exec3", NodeID: "node3"},
+		{ID: "exec4", NodeID: "node4"},
+	}
+
+	set := execSetFromSlice(executions)
+	healthy, lost := set.filterByNodeHealth(nodeInfos)
+
+	assert.ElementsMatch(t, healthy.keys(), []string{"exec1", "exec2"})
+	assert.ElementsMatch(t, lost.keys(), []string{"exec3", "exec4"})
+}
+
+func TestExecSet_FilterByNodeDrain(t *testing.T) {
+	nodeInfos := map[string]*models.NodeInfo{
+		"node1": {},
+		"node2": {Scheduling: models.NodeCordoned},
+		"node3": {Scheduling: models.NodeDraining},
+		"node4": {Scheduling: models.NodeDrained},
+	}
+
+	executions := []*models.Execution{
+		{ID: "exec1", NodeID: "node1"},
+		{ID: "exec2", NodeID: "node2"},
+		{ID: "exec3", NodeID: "node3"},
+		{ID: "exec4", NodeID: "node4"},
+	}
+
+	set := execSetFromSlice(executions)
+	remaining, drained := set.filterByNodeDrain(nodeInfos, models.JobTypeBatch)
+	assert.ElementsMatch(t, remaining.keys(), []string{"exec1", "exec2", "exec3"})
+	assert.ElementsMatch(t, drained.keys(), []string{"exec4"})
+
+	remaining, drained = set.filterByNodeDrain(nodeInfos, models.JobTypeService)
+	assert.ElementsMatch(t, remaining.keys(), []string{"exec1", "exec2"})
+	assert.ElementsMatch(t, drained.keys(), []string{"exec3", "exec4"})
+}
+
 func TestExecSet_FilterByOverSubscription(t *testing.T) {
 	desiredCount := 3
 	now := time.Now()
@@ -35,8 +35,9 @@ func existingNodeInfos(ctx context.Context,
 		if _, ok := checked[execution.NodeID]; ok {
 			continue
 		}
+		// executions on nodes that are no longer admitted are treated as lost
 		nodeInfo, ok := nodesMap[execution.NodeID]
-		if ok {
+		if ok && nodeInfo.IsAdmitted() {
 			out[execution.NodeID] = nodeInfo
 		}
 		checked[execution.NodeID] = struct{}{}
@@ -0,0 +1,69 @@
+package ranking
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+)
+
+// CachedImagesNodeRanker prefers nodes that already have the docker image of
+// a job cached, and can run it without pulling the image first.
+type CachedImagesNodeRanker struct {
+}
+
+func NewCachedImagesNodeRanker() *CachedImagesNodeRanker {
+	return &CachedImagesNodeRanker{}
+}
+
+// RankNodes ranks nodes based on the docker images they have cached:
+// - Rank 10: Node has the job's image cached.
+// - Rank 0: Node does not have the image cached, or the job does not run in docker.
+func (s *CachedImagesNodeRanker) RankNodes(
+	ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
+	image := jobImage(job)
+	ranks := make([]orchestrator.NodeRank, len(nodes))
+	for i, node := range nodes {
+		rank := orchestrator.RankPossible
+		reason := "image is not cached"
+		if image == "" {
+			reason = "job does not use a docker image"
+		} else if node.ComputeNodeInfo != nil && containsImage(node.ComputeNodeInfo.CachedImages, image) {
+			rank = orchestrator.RankPreferred
+			reason = "image is cached"
+		}
+		ranks[i] = orchestrator.NodeRank{
+			NodeInfo: node,
+			Rank:     rank,
+			Reason:   reason,
+		}
+		log.Ctx(ctx).Trace().Object("Rank", ranks[i]).Msg("Ranked node")
+	}
+	return ranks, nil
+}
+
+// jobImage returns the normalized docker image of a job, or an empty string
+// if the job does not run in docker.
+func jobImage(job models.Job) string {
+	task := job.Task()
+	if task == nil || task.Engine == nil || !task.Engine.IsType(models.EngineDocker) {
+		return ""
+	}
+	spec, err := dockermodels.DecodeSpec(task.Engine)
+	if err != nil {
+		return ""
+	}
+	return dockermodels.NormalizeImage(spec.Image)
+}
+
+func containsImage(images []string, image string) bool {
+	for _, cached := range images {
+		if cached == image {
+			return true
+		}
+	}
+	return false
+}
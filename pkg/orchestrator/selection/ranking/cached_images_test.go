@@ -0,0 +1,65 @@
+//go:build unit || !integration
+
+package ranking
+
+import (
+	"context"
+	"testing"
+
+	dockermodels "github.com/bacalhau-project/bacalhau/pkg/executor/docker/models"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CachedImagesNodeRankerSuite struct {
+	suite.Suite
+	ranker *CachedImagesNodeRanker
+	nodes  []models.NodeInfo
+}
+
+func TestCachedImagesNodeRankerSuite(t *testing.T) {
+	suite.Run(t, new(CachedImagesNodeRankerSuite))
+}
+
+func (s *CachedImagesNodeRankerSuite) SetupTest() {
+	s.ranker = NewCachedImagesNodeRanker()
+	s.nodes = []models.NodeInfo{
+		{
+			NodeID:          "cached",
+			ComputeNodeInfo: &models.ComputeNodeInfo{CachedImages: []string{"docker.io/library/ubuntu:latest"}},
+		},
+		{
+			NodeID:          "other",
+			ComputeNodeInfo: &models.ComputeNodeInfo{CachedImages: []string{"docker.io/library/ubuntu:22.04"}},
+		},
+		{
+			NodeID: "unknown",
+		},
+	}
+}
+
+func (s *CachedImagesNodeRankerSuite) rank(job *models.Job) []orchestrator.NodeRank {
+	ranks, err := s.ranker.RankNodes(context.Background(), *job, s.nodes)
+	s.Require().NoError(err)
+	s.Require().Len(ranks, len(s.nodes))
+	return ranks
+}
+
+func (s *CachedImagesNodeRankerSuite) TestPrefersCachedImage() {
+	job := mock.Job()
+	job.Task().Engine = dockermodels.NewDockerEngineBuilder("ubuntu").Build()
+	ranks := s.rank(job)
+	assertEquals(s.T(), ranks, "cached", orchestrator.RankPreferred)
+	assertEquals(s.T(), ranks, "other", orchestrator.RankPossible)
+	assertEquals(s.T(), ranks, "unknown", orchestrator.RankPossible)
+}
+
+func (s *CachedImagesNodeRankerSuite) TestNonDockerJob() {
+	job := mock.Job()
+	job.Task().Engine = models.NewSpecConfig(models.EngineNoop)
+	for _, rank := range s.rank(job) {
+		s.Equal(orchestrator.RankPossible, rank.Rank)
+	}
+}
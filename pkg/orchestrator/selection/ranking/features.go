@@ -31,11 +31,8 @@ func NewEnginesNodeRanker() *featureNodeRanker {
 func NewPublishersNodeRanker() *featureNodeRanker {
 	return &featureNodeRanker{
 		getJobRequirement: func(j models.Job) []string {
-			// publisher is optional and can be empty
-			if j.Task().Publisher.Type == "" {
-				return []string{}
-			}
-			return []string{j.Task().Publisher.Type}
+			// publishers are optional and can be empty
+			return j.Task().RequiredPublisherTypes()
 		},
 		getNodeProvidedKeys: func(ni models.ComputeNodeInfo) []string { return ni.Publishers },
 	}
@@ -18,7 +18,8 @@ func NewMaxUsageNodeRanker() *MaxUsageNodeRanker {
 
 // RankNodes ranks nodes based on the MaxJobRequirements the compute nodes are accepting:
 // - Rank 10: Node is accepting MaxJobRequirements that are equal or higher than the job requirements.
-// - Rank -1: Node is accepting MaxJobRequirements that are lower than the job requirements.
+// - Rank -1: Node is accepting MaxJobRequirements that are lower than the job requirements, or doesn't have
+// enough GPUs that match the job's GPU selector.
 // - Rank 0: Node MaxJobRequirements are not set, or the node was discovered not through nodeInfoPublisher (e.g. identity protocol)
 func (s *MaxUsageNodeRanker) RankNodes(ctx context.Context, job models.Job, nodes []models.NodeInfo) ([]orchestrator.NodeRank, error) {
 	ranks := make([]orchestrator.NodeRank, len(nodes))
@@ -31,7 +32,15 @@ func (s *MaxUsageNodeRanker) RankNodes(ctx context.Context, job models.Job, node
 		rank := orchestrator.RankPossible
 		reason := "max job resource requirements not set or unknown"
 		if jobResourceUsageSet && node.ComputeNodeInfo != nil {
-			if jobResourceUsage.LessThanEq(node.ComputeNodeInfo.MaxJobRequirements) {
+			if !jobResourceUsage.HasMatchingGPUs(node.ComputeNodeInfo.MaxJobRequirements.GPUs) {
+				rank = orchestrator.RankUnsuitable
+				reason = fmt.Sprintf(
+					"job requires %d GPUs matching %s but node has %d",
+					jobResourceUsage.GPU,
+					jobResourceUsage.GPUSelector.String(),
+					len(jobResourceUsage.GPUSelector.Filter(node.ComputeNodeInfo.MaxJobRequirements.GPUs)),
+				)
+			} else if jobResourceUsage.LessThanEq(node.ComputeNodeInfo.MaxJobRequirements) {
 				rank = orchestrator.RankPreferred
 				reason = "job requires less resources than are available"
 			} else {
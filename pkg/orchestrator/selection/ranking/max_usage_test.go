@@ -114,3 +114,52 @@ func (s *MaxUsageNodeRankerSuite) TestRankNodesUnknownJob() {
 	assertEquals(s.T(), ranks, "med", 0)
 	assertEquals(s.T(), ranks, "large", 0)
 }
+
+func (s *MaxUsageNodeRankerSuite) TestRankNodes_GPUSelector() {
+	nvidiaPeer := models.NodeInfo{
+		NodeID: "nvidia",
+		ComputeNodeInfo: &models.ComputeNodeInfo{MaxJobRequirements: models.Resources{
+			CPU: 1,
+			GPU: 2,
+			GPUs: []models.GPU{
+				{Index: 0, Name: "Tesla T4", Vendor: models.GPUVendorNvidia, Memory: 15360},
+				{Index: 1, Name: "NVIDIA A100-SXM4-80GB", Vendor: models.GPUVendorNvidia, Memory: 81920},
+			},
+		}},
+	}
+	amdPeer := models.NodeInfo{
+		NodeID: "amd",
+		ComputeNodeInfo: &models.ComputeNodeInfo{MaxJobRequirements: models.Resources{
+			CPU:  1,
+			GPU:  1,
+			GPUs: []models.GPU{{Index: 0, Name: "Radeon Pro VII", Vendor: models.GPUVendorAMDATI, Memory: 16384}},
+		}},
+	}
+	unknownPeer := models.NodeInfo{
+		NodeID:          "unknown",
+		ComputeNodeInfo: &models.ComputeNodeInfo{MaxJobRequirements: models.Resources{CPU: 1, GPU: 2}},
+	}
+	nodes := []models.NodeInfo{nvidiaPeer, amdPeer, unknownPeer}
+
+	job := mock.Job()
+	job.Task().ResourcesConfig = &models.ResourcesConfig{GPU: "1", GPUSelector: &models.GPUSelectorConfig{Vendor: "nvidia"}}
+	ranks, err := s.MaxUsageNodeRanker.RankNodes(context.Background(), *job, nodes)
+	s.NoError(err)
+	assertEquals(s.T(), ranks, "nvidia", 10)
+	assertEquals(s.T(), ranks, "amd", -1)
+	assertEquals(s.T(), ranks, "unknown", -1)
+
+	job.Task().ResourcesConfig = &models.ResourcesConfig{GPU: "1", GPUSelector: &models.GPUSelectorConfig{MinMemory: "16GiB"}}
+	ranks, err = s.MaxUsageNodeRanker.RankNodes(context.Background(), *job, nodes)
+	s.NoError(err)
+	assertEquals(s.T(), ranks, "nvidia", 10)
+	assertEquals(s.T(), ranks, "amd", 10)
+	assertEquals(s.T(), ranks, "unknown", -1)
+
+	job.Task().ResourcesConfig = &models.ResourcesConfig{GPU: "2", GPUSelector: &models.GPUSelectorConfig{Model: "a100"}}
+	ranks, err = s.MaxUsageNodeRanker.RankNodes(context.Background(), *job, nodes)
+	s.NoError(err)
+	assertEquals(s.T(), ranks, "nvidia", -1)
+	assertEquals(s.T(), ranks, "amd", -1)
+	assertEquals(s.T(), ranks, "unknown", -1)
+}
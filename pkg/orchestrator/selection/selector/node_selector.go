@@ -70,7 +70,7 @@ func (n NodeSelector) rankAndFilterNodes(ctx context.Context, job *models.Job) (
 	}
 
 	nodeIDs := lo.Filter(listed, func(nodeInfo models.NodeInfo, index int) bool {
-		return nodeInfo.NodeType == models.NodeTypeCompute
+		return nodeInfo.NodeType == models.NodeTypeCompute && nodeInfo.IsSchedulable()
 	})
 
 	rankedNodes, err := n.nodeRanker.RankNodes(ctx, *job, nodeIDs)
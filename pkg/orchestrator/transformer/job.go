@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/util/idgen"
 )
@@ -45,6 +46,16 @@ func RequesterInfo(requesterNodeID string) JobTransformer {
 	return JobFn(f)
 }
 
+// OwnerInfo is a transformer that sets the job owner to the authenticated
+// principal that submitted the job, if there is one.
+func OwnerInfo() JobTransformer {
+	f := func(ctx context.Context, job *models.Job) error {
+		job.Owner = authz.PrincipalFromContext(ctx)
+		return nil
+	}
+	return JobFn(f)
+}
+
 // NameOptional is a transformer that sets the job name to the job ID if it is empty.
 func NameOptional() JobTransformer {
 	f := func(ctx context.Context, job *models.Job) error {
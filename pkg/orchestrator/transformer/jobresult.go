@@ -0,0 +1,130 @@
+package transformer
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/ipfs"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/jobresult"
+)
+
+// NewJobResultResolver returns a job transformer that replaces input sources
+// referencing the results of another job with the storage source the results
+// were published to. The referenced job must have completed, and must be in
+// the same namespace as the submitted job.
+func NewJobResultResolver(store jobstore.Store) JobTransformer {
+	f := func(ctx context.Context, job *models.Job) error {
+		for _, task := range job.Tasks {
+			for _, input := range task.InputSources {
+				if input == nil || !input.Source.IsType(models.StorageSourceJobResult) {
+					continue
+				}
+				source, err := resolveJobResult(ctx, store, job.Namespace, input.Source)
+				if err != nil {
+					return err
+				}
+				input.Source = source
+			}
+		}
+		return nil
+	}
+	return JobFn(f)
+}
+
+func resolveJobResult(
+	ctx context.Context, store jobstore.Store, namespace string, spec *models.SpecConfig) (*models.SpecConfig, error) {
+	source, err := jobresult.DecodeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := store.GetJob(ctx, source.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve results of job %s: %w", source.JobID, err)
+	}
+	// jobs in other namespaces are reported as missing so that their
+	// existence is not revealed to callers that cannot read them
+	if job.Namespace != namespace {
+		return nil, fmt.Errorf("failed to resolve results of job %s: %w", source.JobID, bacerrors.NewJobNotFound(source.JobID))
+	}
+	if job.State.StateType != models.JobStateTypeCompleted {
+		return nil, fmt.Errorf("results of job %s are not available as the job is %s", job.ID, job.State.StateType)
+	}
+
+	execution, err := completedExecution(ctx, store, job.ID, source.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, published := range execution.AllPublishedResults() {
+		if published == nil || published.Result.IsEmpty() {
+			continue
+		}
+		if source.Publisher != "" && !strings.EqualFold(published.Publisher, source.Publisher) {
+			continue
+		}
+		return resultAtPath(published.Result.Copy(), source.Path)
+	}
+	if source.Publisher != "" {
+		return nil, fmt.Errorf("execution %s of job %s has no results published by %s", execution.ID, job.ID, source.Publisher)
+	}
+	return nil, fmt.Errorf("execution %s of job %s has no published results", execution.ID, job.ID)
+}
+
+// completedExecution returns the completed execution of a job with the given
+// ID or ID prefix, or the only completed execution if executionID is empty.
+func completedExecution(
+	ctx context.Context, store jobstore.Store, jobID, executionID string) (*models.Execution, error) {
+	executions, err := store.GetExecutions(ctx, jobstore.GetExecutionsOptions{
+		JobID:      jobID,
+		IncludeJob: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var completed []*models.Execution
+	for i := range executions {
+		execution := &executions[i]
+		if execution.ComputeState.StateType != models.ExecutionStateCompleted {
+			continue
+		}
+		if executionID == "" || strings.HasPrefix(execution.ID, executionID) {
+			completed = append(completed, execution)
+		}
+	}
+
+	switch {
+	case len(completed) == 1:
+		return completed[0], nil
+	case len(completed) == 0 && executionID != "":
+		return nil, fmt.Errorf("job %s has no completed execution %s", jobID, executionID)
+	case len(completed) == 0:
+		return nil, fmt.Errorf("job %s has no completed executions", jobID)
+	default:
+		return nil, fmt.Errorf("job %s has %d completed executions. select one of them with ExecutionID", jobID, len(completed))
+	}
+}
+
+// resultAtPath narrows a published result down to a path within it.
+func resultAtPath(result *models.SpecConfig, resultPath string) (*models.SpecConfig, error) {
+	if resultPath == "" {
+		return result, nil
+	}
+	if !result.IsType(models.StorageSourceIPFS) {
+		return nil, fmt.Errorf("a path within the results is only supported for results published to IPFS, not %s",
+			result.Type)
+	}
+	source, err := ipfs.DecodeSpec(result)
+	if err != nil {
+		return nil, err
+	}
+	source.CID = path.Join(source.CID, path.Clean(resultPath))
+	result.Params = source.ToMap()
+	return result, nil
+}
@@ -0,0 +1,136 @@
+//go:build unit || !integration
+
+package transformer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bacalhau-project/bacalhau/pkg/bacerrors"
+	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/jobresult"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type JobResultResolverSuite struct {
+	suite.Suite
+	ctx        context.Context
+	mockStore  *jobstore.MockStore
+	resolver   JobTransformer
+	producer   models.Job
+	executions []models.Execution
+}
+
+func TestJobResultResolverSuite(t *testing.T) {
+	suite.Run(t, new(JobResultResolverSuite))
+}
+
+func (s *JobResultResolverSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockStore = jobstore.NewMockStore(gomock.NewController(s.T()))
+	s.resolver = NewJobResultResolver(s.mockStore)
+
+	s.producer = *mock.Job()
+	s.producer.State = models.NewJobState(models.JobStateTypeCompleted)
+	execution := mock.ExecutionForJob(&s.producer)
+	execution.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	execution.PublishedResults = []*models.PublishedResult{
+		{Publisher: models.PublisherS3, Result: models.NewSpecConfig(models.StorageSourceS3).
+			WithParam("Bucket", "b").WithParam("Key", "results.tar.gz")},
+		{Publisher: models.PublisherIPFS, Result: models.NewSpecConfig(models.StorageSourceIPFS).
+			WithParam("CID", "QmResults")},
+	}
+	s.executions = []models.Execution{*execution}
+
+	s.mockStore.EXPECT().GetJob(gomock.Any(), s.producer.ID).Return(s.producer, nil).AnyTimes()
+	s.mockStore.EXPECT().GetExecutions(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, jobstore.GetExecutionsOptions) ([]models.Execution, error) {
+			return s.executions, nil
+		}).AnyTimes()
+}
+
+// consumer returns a job that uses the producer's results as its input.
+func (s *JobResultResolverSuite) consumer(source jobresult.Source) *models.Job {
+	job := mock.Job()
+	job.Task().InputSources = []*models.InputSource{{
+		Source: &models.SpecConfig{Type: models.StorageSourceJobResult, Params: source.ToMap()},
+		Target: "/inputs",
+	}}
+	return job
+}
+
+func (s *JobResultResolverSuite) resolve(source jobresult.Source) (*models.SpecConfig, error) {
+	job := s.consumer(source)
+	err := s.resolver.Transform(s.ctx, job)
+	return job.Task().InputSources[0].Source, err
+}
+
+func (s *JobResultResolverSuite) TestResolvesFirstPublisher() {
+	source, err := s.resolve(jobresult.Source{JobID: s.producer.ID})
+	s.Require().NoError(err)
+	s.Equal(models.StorageSourceS3, source.Type)
+	s.Equal("results.tar.gz", source.Params["Key"])
+}
+
+func (s *JobResultResolverSuite) TestResolvesPublisherAndPath() {
+	source, err := s.resolve(jobresult.Source{JobID: s.producer.ID, Publisher: "IPFS", Path: "outputs/data.csv"})
+	s.Require().NoError(err)
+	s.Equal(models.StorageSourceIPFS, source.Type)
+	s.Equal("QmResults/outputs/data.csv", source.Params["CID"])
+
+	// the result recorded on the producer's execution is left untouched
+	s.Equal("QmResults", s.executions[0].PublishedResults[1].Result.Params["CID"])
+}
+
+func (s *JobResultResolverSuite) TestPathRequiresIPFS() {
+	_, err := s.resolve(jobresult.Source{JobID: s.producer.ID, Publisher: models.PublisherS3, Path: "outputs"})
+	s.ErrorContains(err, "only supported for results published to IPFS")
+}
+
+func (s *JobResultResolverSuite) TestUnknownPublisher() {
+	_, err := s.resolve(jobresult.Source{JobID: s.producer.ID, Publisher: models.PublisherLocal})
+	s.ErrorContains(err, "no results published by local")
+}
+
+func (s *JobResultResolverSuite) TestIncompleteJob() {
+	s.producer.State = models.NewJobState(models.JobStateTypeRunning)
+	store := jobstore.NewMockStore(gomock.NewController(s.T()))
+	store.EXPECT().GetJob(gomock.Any(), s.producer.ID).Return(s.producer, nil)
+	job := s.consumer(jobresult.Source{JobID: s.producer.ID})
+	s.ErrorContains(NewJobResultResolver(store).Transform(s.ctx, job), "not available")
+}
+
+func (s *JobResultResolverSuite) TestOtherNamespace() {
+	job := s.consumer(jobresult.Source{JobID: s.producer.ID})
+	job.Namespace = "other"
+	err := s.resolver.Transform(s.ctx, job)
+	s.ErrorAs(err, new(*bacerrors.JobNotFound))
+}
+
+func (s *JobResultResolverSuite) TestSelectsExecution() {
+	second := mock.ExecutionForJob(&s.producer)
+	second.ComputeState = models.NewExecutionState(models.ExecutionStateCompleted)
+	second.PublishedResults = []*models.PublishedResult{
+		{Publisher: models.PublisherIPFS, Result: models.NewSpecConfig(models.StorageSourceIPFS).
+			WithParam("CID", "QmSecond")},
+	}
+	s.executions = append(s.executions, *second)
+
+	_, err := s.resolve(jobresult.Source{JobID: s.producer.ID})
+	s.ErrorContains(err, "2 completed executions")
+
+	source, err := s.resolve(jobresult.Source{JobID: s.producer.ID, ExecutionID: second.ID[:10]})
+	s.Require().NoError(err)
+	s.Equal("QmSecond", source.Params["CID"])
+}
+
+func (s *JobResultResolverSuite) TestInvalidSource() {
+	_, err := s.resolve(jobresult.Source{})
+	s.ErrorContains(err, "job id cannot be empty")
+
+	_, err = s.resolve(jobresult.Source{JobID: s.producer.ID, Path: "../secrets"})
+	s.ErrorContains(err, "path must be relative")
+}
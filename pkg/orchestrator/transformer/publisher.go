@@ -10,7 +10,7 @@ func DefaultPublisher(publisherConfig *models.SpecConfig) JobTransformer {
 	f := func(ctx context.Context, job *models.Job) error {
 		for i := range job.Tasks {
 			task := job.Tasks[i]
-			if task.Publisher == nil || task.Publisher.Type == "" {
+			if len(task.AllPublishers()) == 0 {
 				task.Publisher = publisherConfig
 			}
 		}
@@ -39,10 +39,45 @@ type ReadLogsResponse struct {
 
 type GetResultsRequest struct {
 	JobID string
+	// Publisher restricts the results to those of the given publisher type.
+	Publisher string
+	// Snapshot requests the snapshots of running executions up to the given
+	// version, or up to the latest version if set to models.LatestSnapshot,
+	// instead of the results of completed executions.
+	Snapshot int
 }
 
 type GetResultsResponse struct {
 	Results []*models.SpecConfig
+	// Manifests holds the signature of the manifest of each result, in the
+	// same order as Results. Entries are nil for unsigned results.
+	Manifests []*models.ResultManifestSignature
+	// Snapshots holds the requested snapshots, ordered by execution and then
+	// by version. Each snapshot only holds the result of a single publisher.
+	Snapshots []*models.ResultSnapshot
+}
+
+type GetEndpointsRequest struct {
+	JobID string
+}
+
+type GetEndpointsResponse struct {
+	// Endpoints holds the endpoints of the running executions of the job.
+	Endpoints []*models.ServiceEndpoint
+}
+
+type LookupServiceRequest struct {
+	// Name is the service to look up in the form _<port>._<protocol>.<job>,
+	// where job is the name or ID of a job.
+	Name string
+	// Namespace is the namespace of the job. Defaults to the default namespace.
+	Namespace string
+}
+
+type LookupServiceResponse struct {
+	// Endpoints holds the endpoints of the service's port of the running
+	// executions of the job.
+	Endpoints []*models.ServiceEndpoint
 }
 
 // NodeRank represents a node and its rank. The higher the rank, the more preferable a node is to execute the job.
@@ -30,3 +30,13 @@ type GetAgentNodeResponse struct {
 	BaseGetResponse
 	*models.NodeInfo
 }
+
+// GetAgentPluginsRequest is the request to list the executor plugins of the agent.
+type GetAgentPluginsRequest struct {
+	BaseGetRequest
+}
+
+type GetAgentPluginsResponse struct {
+	BaseGetResponse
+	Plugins []*models.ExecutorPluginInfo
+}
@@ -0,0 +1,40 @@
+package apimodels
+
+import (
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type ListImagesRequest struct {
+	BaseGetRequest
+}
+
+type ListImagesResponse struct {
+	BaseGetResponse
+	// Images are the cached images, most recently used first.
+	Images []models.CachedImage
+}
+
+type PullImageRequest struct {
+	BasePutRequest
+	Image string `json:"Image" validate:"required"`
+	// Pin the image so that it is never pruned.
+	Pin bool `json:"Pin"`
+}
+
+type PullImageResponse struct {
+	BasePutResponse
+	Image models.CachedImage
+}
+
+type PruneImagesRequest struct {
+	BasePutRequest
+	// DiskBudget is the disk space, e.g. 10Gb, the cached images can use
+	// once pruned. The node's configured budget is used if it is empty.
+	DiskBudget string `json:"DiskBudget"`
+}
+
+type PruneImagesResponse struct {
+	BasePutResponse
+	// Removed are the images that were removed from the cache.
+	Removed []models.CachedImage
+}
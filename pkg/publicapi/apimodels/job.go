@@ -85,12 +85,23 @@ func (r *GetJobResponse) Normalize() {
 type ListJobsRequest struct {
 	BaseListRequest
 	Labels []labels.Requirement `query:"-"` // don't auto bind as it requires special handling
+	// Owner restricts the results to jobs submitted by the given principal.
+	Owner string `query:"owner"`
+	// Mine restricts the results to jobs submitted by the calling principal.
+	Mine bool `query:"mine" validate:"excluded_with=Owner"`
 }
 
 // ToHTTPRequest is used to convert the request to an HTTP request
 func (o *ListJobsRequest) ToHTTPRequest() *HTTPRequest {
 	r := o.BaseListRequest.ToHTTPRequest()
 
+	if o.Owner != "" {
+		r.Params.Set("owner", o.Owner)
+	}
+	if o.Mine {
+		r.Params.Set("mine", "true")
+	}
+
 	for _, v := range o.Labels {
 		r.Params.Add("labels", v.String())
 	}
@@ -156,11 +167,71 @@ type ListJobExecutionsResponse struct {
 type ListJobResultsRequest struct {
 	BaseListRequest
 	JobID string `query:"-"`
+	// Publisher restricts the results to those published by the given
+	// publisher type.
+	Publisher string `query:"publisher"`
+	// Snapshot requests the snapshots of running executions up to the given
+	// version, or up to the latest version if set to models.LatestSnapshot.
+	Snapshot int `query:"snapshot"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *ListJobResultsRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseListRequest.ToHTTPRequest()
+	if o.Publisher != "" {
+		r.Params.Set("publisher", o.Publisher)
+	}
+	if o.Snapshot != 0 {
+		r.Params.Set("snapshot", strconv.Itoa(o.Snapshot))
+	}
+	return r
 }
 
 type ListJobResultsResponse struct {
 	BaseListResponse
 	Results []*models.SpecConfig
+	// Manifests holds the signature of the manifest of each result, in the
+	// same order as Results. Entries are nil for unsigned results.
+	Manifests []*models.ResultManifestSignature `json:",omitempty"`
+	// Snapshots holds the requested snapshots, each with the result of a
+	// single publisher. Snapshots must be applied in order of their versions.
+	Snapshots []*models.ResultSnapshot `json:",omitempty"`
+}
+
+type GetJobEndpointsRequest struct {
+	BaseGetRequest
+	JobID string `query:"-"`
+}
+
+type GetJobEndpointsResponse struct {
+	BaseGetResponse
+	// Endpoints holds the endpoints of the running executions of the job.
+	Endpoints []*models.ServiceEndpoint
+}
+
+type LookupServiceRequest struct {
+	BaseGetRequest
+	// Name is the service to look up in the form _<port>._<protocol>.<job>,
+	// where job is the name or ID of a job.
+	Name string `query:"-"`
+	// Namespace is the namespace of the job. Defaults to the default namespace.
+	Namespace string `query:"namespace"`
+}
+
+// ToHTTPRequest is used to convert the request to an HTTP request
+func (o *LookupServiceRequest) ToHTTPRequest() *HTTPRequest {
+	r := o.BaseGetRequest.ToHTTPRequest()
+	if o.Namespace != "" {
+		r.Params.Set("namespace", o.Namespace)
+	}
+	return r
+}
+
+type LookupServiceResponse struct {
+	BaseGetResponse
+	// Endpoints holds the endpoints of the service's port of the running
+	// executions of the job.
+	Endpoints []*models.ServiceEndpoint
 }
 
 type StopJobRequest struct {
@@ -1,6 +1,8 @@
 package apimodels
 
 import (
+	"time"
+
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -34,3 +36,46 @@ type ListNodesResponse struct {
 	BaseListResponse
 	Nodes []*models.NodeInfo
 }
+
+// NodeAction is an operation that changes the state of a node.
+type NodeAction string
+
+const (
+	NodeActionApprove  NodeAction = "approve"
+	NodeActionReject   NodeAction = "reject"
+	NodeActionRevoke   NodeAction = "revoke"
+	NodeActionCordon   NodeAction = "cordon"
+	NodeActionUncordon NodeAction = "uncordon"
+	NodeActionDrain    NodeAction = "drain"
+)
+
+type PutNodeRequest struct {
+	BasePutRequest
+	NodeID string     `json:"-"`
+	Action NodeAction `json:"Action" validate:"required"`
+	// DrainTimeout is how long batch and ops executions are given to
+	// complete when draining the node. The orchestrator's default is used if
+	// it is zero.
+	DrainTimeout time.Duration `json:"DrainTimeout,omitempty"`
+}
+
+type PutNodeResponse struct {
+	BasePutResponse
+	Node *models.NodeInfo
+}
+
+type CreateJoinTokenRequest struct {
+	BasePutRequest
+	// TTL is how long the token is valid for. The orchestrator's default is
+	// used if it is zero.
+	TTL time.Duration `json:"TTL"`
+}
+
+type CreateJoinTokenResponse struct {
+	BasePutResponse
+	// Token is the secret to pass to the compute node. It is only returned
+	// once and cannot be retrieved again.
+	Token     string    `json:"Token"`
+	TokenID   string    `json:"TokenID"`
+	ExpiresAt time.Time `json:"ExpiresAt"`
+}
@@ -39,6 +39,9 @@ type LegacyTLSSupport struct {
 	UseTLS   bool
 	CACert   string
 	Insecure bool
+
+	ClientCert string
+	ClientKey  string
 }
 
 // NewAPIClient returns a new client for a node's API server against v1 APIs
@@ -70,8 +73,9 @@ func NewAPIClient(tlsinfo LegacyTLSSupport, host string, port uint16, path ...st
 	}
 }
 
-// getTLSTransport builds a http.Transport from the TLS options
-func getTLSTransport(config LegacyTLSSupport) *http.Transport {
+// getTLSTransport builds a http.Transport from the TLS options. If the client
+// certificate cannot be loaded, every request fails with the reason.
+func getTLSTransport(config LegacyTLSSupport) http.RoundTripper {
 	tr := &http.Transport{}
 
 	if !config.UseTLS {
@@ -99,9 +103,30 @@ func getTLSTransport(config LegacyTLSSupport) *http.Transport {
 			MinVersion:         tls.VersionTLS12,
 		}
 	}
+
+	if config.ClientCert != "" {
+		clientCert, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return failingTransport{err: fmt.Errorf("unable to load client certificate %s: %w", config.ClientCert, err)}
+		}
+
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tr.TLSClientConfig.Certificates = []tls.Certificate{clientCert}
+	}
 	return tr
 }
 
+// failingTransport fails every request with err.
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
 func (apiClient *APIClient) doGet(ctx context.Context, api string, resData any) error {
 	ctx, span := system.NewSpan(ctx, system.GetTracer(), "pkg/publicapi.Client.Get")
 	defer span.End()
@@ -88,8 +88,6 @@ func (c *Client) put(ctx context.Context, endpoint string, in apimodels.PutReque
 }
 
 // post is used to do a POST request against an endpoint
-//
-//nolint:unused
 func (c *Client) post(ctx context.Context, endpoint string, in apimodels.PutRequest, out apimodels.PutResponse) error {
 	return c.write(ctx, http.MethodPost, endpoint, in, out)
 }
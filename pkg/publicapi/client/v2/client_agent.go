@@ -35,3 +35,10 @@ func (c *Agent) Node(ctx context.Context, req *apimodels.GetAgentNodeRequest) (*
 	err := c.client.get(ctx, "/api/v1/agent/node", req, &res)
 	return &res, err
 }
+
+// Plugins is used to list the executor plugins of the agent.
+func (c *Agent) Plugins(ctx context.Context, req *apimodels.GetAgentPluginsRequest) (*apimodels.GetAgentPluginsResponse, error) {
+	var res apimodels.GetAgentPluginsResponse
+	err := c.client.get(ctx, "/api/v1/agent/plugins", req, &res)
+	return &res, err
+}
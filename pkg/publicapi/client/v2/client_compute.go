@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+const computeImagesPath = "/api/v1/compute/images"
+
+type Compute struct {
+	client *Client
+}
+
+// Compute returns a handle on the endpoints of a compute node, which must be
+// the node the client is connected to.
+func (c *Client) Compute() *Compute {
+	return &Compute{client: c}
+}
+
+// ListImages is used to list the docker images cached on the compute node.
+func (c *Compute) ListImages(ctx context.Context, r *apimodels.ListImagesRequest) (*apimodels.ListImagesResponse, error) {
+	var resp apimodels.ListImagesResponse
+	if err := c.client.get(ctx, computeImagesPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PullImage is used to pull a docker image into the compute node's image cache.
+func (c *Compute) PullImage(ctx context.Context, r *apimodels.PullImageRequest) (*apimodels.PullImageResponse, error) {
+	var resp apimodels.PullImageResponse
+	if err := c.client.post(ctx, computeImagesPath, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PruneImages is used to remove the least recently used docker images from
+// the compute node's image cache.
+func (c *Compute) PruneImages(ctx context.Context, r *apimodels.PruneImagesRequest) (*apimodels.PruneImagesResponse, error) {
+	var resp apimodels.PruneImagesResponse
+	if err := c.client.post(ctx, computeImagesPath+"/prune", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -2,13 +2,17 @@ package client
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
 )
 
-const jobsPath = "/api/v1/orchestrator/jobs"
+const (
+	jobsPath     = "/api/v1/orchestrator/jobs"
+	servicesPath = "/api/v1/orchestrator/services"
+)
 
 type Jobs struct {
 	client *Client
@@ -74,6 +78,25 @@ func (j *Jobs) Results(ctx context.Context, r *apimodels.ListJobResultsRequest)
 	return &resp, nil
 }
 
+// Endpoints returns the endpoints of the running executions of a job.
+func (j *Jobs) Endpoints(ctx context.Context, r *apimodels.GetJobEndpointsRequest) (*apimodels.GetJobEndpointsResponse, error) {
+	var resp apimodels.GetJobEndpointsResponse
+	if err := j.client.get(ctx, jobsPath+"/"+r.JobID+"/endpoints", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// LookupService returns the endpoints of a port of the running executions
+// of a job, given a service name in the form _<port>._<protocol>.<job>.
+func (j *Jobs) LookupService(ctx context.Context, r *apimodels.LookupServiceRequest) (*apimodels.LookupServiceResponse, error) {
+	var resp apimodels.LookupServiceResponse
+	if err := j.client.get(ctx, servicesPath+"/"+url.PathEscape(r.Name), r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Stop is used to stop a job by ID.
 func (j *Jobs) Stop(ctx context.Context, r *apimodels.StopJobRequest) (*apimodels.StopJobResponse, error) {
 	var resp apimodels.StopJobResponse
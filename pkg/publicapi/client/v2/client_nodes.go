@@ -34,3 +34,22 @@ func (c *Nodes) List(ctx context.Context, r *apimodels.ListNodesRequest) (*apimo
 	}
 	return &resp, nil
 }
+
+// Put is used to perform an action, such as approving a node, on a node.
+func (c *Nodes) Put(ctx context.Context, r *apimodels.PutNodeRequest) (*apimodels.PutNodeResponse, error) {
+	var resp apimodels.PutNodeResponse
+	if err := c.client.put(ctx, nodesPath+"/"+r.NodeID, r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateJoinToken is used to create a single-use token that admits a compute node.
+func (c *Nodes) CreateJoinToken(ctx context.Context, r *apimodels.CreateJoinTokenRequest) (
+	*apimodels.CreateJoinTokenResponse, error) {
+	var resp apimodels.CreateJoinTokenResponse
+	if err := c.client.post(ctx, nodesPath+"/tokens", r, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -60,6 +60,11 @@ type TLSConfig struct {
 	// CACert specifies the location of a self-signed CA certificate
 	// file
 	CACert string
+	// ClientCert specifies the location of a client certificate file to
+	// present to servers that require mutual TLS
+	ClientCert string
+	// ClientKey specifies the location of the key matching ClientCert
+	ClientKey string
 }
 
 // OptionFn is a function that can be used to configure the client.
@@ -88,6 +93,15 @@ func WithInsecureTLS(insecure bool) OptionFn {
 	}
 }
 
+// WithClientCertificate specifies the location of a client certificate and key
+// to present when the server requires mutual TLS
+func WithClientCertificate(cert, key string) OptionFn {
+	return func(o *Config) {
+		o.TLS.ClientCert = cert
+		o.TLS.ClientKey = key
+	}
+}
+
 // WithNamespace sets the default namespace to use for all requests.
 func WithNamespace(namespace string) OptionFn {
 	return func(o *Config) {
@@ -145,8 +159,9 @@ func resolveHTTPClient(config *Config) {
 	config.HTTPClient = defaultHTTPClient(config)
 }
 
-// getTLSTransport builds a http.Transport from the TLS options
-func getTLSTransport(config *Config) *http.Transport {
+// getTLSTransport builds a http.Transport from the TLS options. If the client
+// certificate cannot be loaded, every request fails with the reason.
+func getTLSTransport(config *Config) http.RoundTripper {
 	tr := &http.Transport{}
 
 	if !config.TLS.UseTLS {
@@ -174,9 +189,30 @@ func getTLSTransport(config *Config) *http.Transport {
 			MinVersion:         tls.VersionTLS12,
 		}
 	}
+
+	if config.TLS.ClientCert != "" {
+		clientCert, err := tls.LoadX509KeyPair(config.TLS.ClientCert, config.TLS.ClientKey)
+		if err != nil {
+			return failingTransport{err: fmt.Errorf("unable to load client certificate %s: %w", config.TLS.ClientCert, err)}
+		}
+
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tr.TLSClientConfig.Certificates = []tls.Certificate{clientCert}
+	}
 	return tr
 }
 
+// failingTransport fails every request with err.
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
 // defaultHTTPClient is the default client to use if none is provided.
 func defaultHTTPClient(config *Config) *http.Client {
 	tr := getTLSTransport(config)
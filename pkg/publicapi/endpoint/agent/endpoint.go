@@ -3,6 +3,7 @@ package agent
 import (
 	"net/http"
 
+	"github.com/bacalhau-project/bacalhau/pkg/executor"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
@@ -16,12 +17,15 @@ type EndpointParams struct {
 	Router             *echo.Echo
 	NodeInfoProvider   models.NodeInfoProvider
 	DebugInfoProviders []model.DebugInfoProvider
+	// PluginInfoProvider is optional, and is nil if the node has no executor plugins.
+	PluginInfoProvider executor.PluginInfoProvider
 }
 
 type Endpoint struct {
 	router             *echo.Echo
 	nodeInfoProvider   models.NodeInfoProvider
 	debugInfoProviders []model.DebugInfoProvider
+	pluginInfoProvider executor.PluginInfoProvider
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
@@ -29,6 +33,7 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 		router:             params.Router,
 		nodeInfoProvider:   params.NodeInfoProvider,
 		debugInfoProviders: params.DebugInfoProviders,
+		pluginInfoProvider: params.PluginInfoProvider,
 	}
 
 	// JSON group
@@ -38,6 +43,7 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 	g.GET("/version", e.version)
 	g.GET("/node", e.node)
 	g.GET("/debug", e.debug)
+	g.GET("/plugins", e.plugins)
 	return e
 }
 
@@ -86,6 +92,25 @@ func (e *Endpoint) node(c echo.Context) error {
 	})
 }
 
+// plugins godoc
+//
+//	@ID			agent/plugins
+//	@Summary	Returns the executor plugins of the node.
+//	@Tags		Ops
+//	@Produce	json
+//	@Success	200	{object}	apimodels.GetAgentPluginsResponse
+//	@Failure	500	{object}	string
+//	@Router		/api/v1/agent/plugins [get]
+func (e *Endpoint) plugins(c echo.Context) error {
+	plugins := []*models.ExecutorPluginInfo{}
+	if e.pluginInfoProvider != nil {
+		plugins = e.pluginInfoProvider.Plugins(c.Request().Context())
+	}
+	return c.JSON(http.StatusOK, apimodels.GetAgentPluginsResponse{
+		Plugins: plugins,
+	})
+}
+
 // debug godoc
 //
 //	@ID			agent/debug
@@ -3,6 +3,7 @@ package compute
 import (
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/compute/store"
+	"github.com/bacalhau-project/bacalhau/pkg/executor/docker/imagecache"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
 	"github.com/labstack/echo/v4"
@@ -13,6 +14,8 @@ type EndpointParams struct {
 	Bidder             compute.Bidder
 	Store              store.ExecutionStore
 	DebugInfoProviders []model.DebugInfoProvider
+	// ImageCache is optional, and is nil if images are not cached.
+	ImageCache *imagecache.Cache
 }
 
 type Endpoint struct {
@@ -20,6 +23,7 @@ type Endpoint struct {
 	bidder             compute.Bidder
 	store              store.ExecutionStore
 	debugInfoProviders []model.DebugInfoProvider
+	imageCache         *imagecache.Cache
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
@@ -28,11 +32,15 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 		bidder:             params.Bidder,
 		store:              params.Store,
 		debugInfoProviders: params.DebugInfoProviders,
+		imageCache:         params.ImageCache,
 	}
 
 	g := e.router.Group("/api/v1/compute")
 	g.Use(middleware.SetContentType(echo.MIMEApplicationJSON))
 	g.POST("/debug", e.debug)
 	g.POST("/approve", e.approve)
+	g.GET("/images", e.listImages)
+	g.POST("/images", e.pullImage)
+	g.POST("/images/prune", e.pruneImages)
 	return e
 }
@@ -0,0 +1,105 @@
+package compute
+
+import (
+	"net/http"
+
+	"github.com/dustin/go-humanize"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+)
+
+// listImages godoc
+//
+//	@ID			compute/images/list
+//	@Summary	Lists the docker images cached on this compute node.
+//	@Tags		Compute Node
+//	@Produce	json
+//	@Success	200	{object}	apimodels.ListImagesResponse
+//	@Failure	404	{object}	string
+//	@Router		/api/v1/compute/images [get]
+func (s *Endpoint) listImages(c echo.Context) error {
+	if s.imageCache == nil {
+		return errImageCacheDisabled
+	}
+	return c.JSON(http.StatusOK, apimodels.ListImagesResponse{
+		Images: s.imageCache.List(),
+	})
+}
+
+// pullImage godoc
+//
+//	@ID			compute/images/pull
+//	@Summary	Pulls a docker image into the image cache of this compute node.
+//	@Tags		Compute Node
+//	@Accept		json
+//	@Produce	json
+//	@Param		pullImageRequest	body		apimodels.PullImageRequest	true	"image to pull"
+//	@Success	200					{object}	apimodels.PullImageResponse
+//	@Failure	400					{object}	string
+//	@Failure	404					{object}	string
+//	@Failure	500					{object}	string
+//	@Router		/api/v1/compute/images [post]
+func (s *Endpoint) pullImage(c echo.Context) error {
+	if s.imageCache == nil {
+		return errImageCacheDisabled
+	}
+	var args apimodels.PullImageRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	image, err := s.imageCache.Pull(c.Request().Context(), args.Image, args.Pin)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.PullImageResponse{
+		Image: image,
+	})
+}
+
+// pruneImages godoc
+//
+//	@ID			compute/images/prune
+//	@Summary	Removes the least recently used docker images from the image cache of this compute node.
+//	@Tags		Compute Node
+//	@Accept		json
+//	@Produce	json
+//	@Param		pruneImagesRequest	body		apimodels.PruneImagesRequest	true	"disk budget to prune to"
+//	@Success	200					{object}	apimodels.PruneImagesResponse
+//	@Failure	400					{object}	string
+//	@Failure	404					{object}	string
+//	@Failure	500					{object}	string
+//	@Router		/api/v1/compute/images/prune [post]
+func (s *Endpoint) pruneImages(c echo.Context) error {
+	if s.imageCache == nil {
+		return errImageCacheDisabled
+	}
+	var args apimodels.PruneImagesRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	budget := s.imageCache.DiskBudget()
+	if args.DiskBudget != "" {
+		var err error
+		if budget, err = humanize.ParseBytes(args.DiskBudget); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid disk budget: "+err.Error())
+		}
+	} else if budget == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "the node has no disk budget for cached images, a disk budget is required")
+	}
+
+	removed, err := s.imageCache.Prune(c.Request().Context(), budget)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.PruneImagesResponse{
+		Removed: removed,
+	})
+}
+
+var errImageCacheDisabled = echo.NewHTTPError(http.StatusNotFound, "docker images are not cached on this node")
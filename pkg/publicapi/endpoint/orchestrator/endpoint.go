@@ -5,6 +5,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
 	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
 	"github.com/labstack/echo/v4"
 	echo_middleware "github.com/labstack/echo/v4/middleware"
 )
@@ -14,6 +15,8 @@ type EndpointParams struct {
 	Orchestrator *orchestrator.BaseEndpoint
 	JobStore     jobstore.Store
 	NodeStore    routing.NodeInfoStore
+	NodeManager  *admission.NodeManager
+	NodeDrainer  *orchestrator.NodeDrainer
 }
 
 type Endpoint struct {
@@ -21,6 +24,8 @@ type Endpoint struct {
 	orchestrator *orchestrator.BaseEndpoint
 	store        jobstore.Store
 	nodeStore    routing.NodeInfoStore
+	nodeManager  *admission.NodeManager
+	nodeDrainer  *orchestrator.NodeDrainer
 }
 
 func NewEndpoint(params EndpointParams) *Endpoint {
@@ -29,6 +34,8 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 		orchestrator: params.Orchestrator,
 		store:        params.JobStore,
 		nodeStore:    params.NodeStore,
+		nodeManager:  params.NodeManager,
+		nodeDrainer:  params.NodeDrainer,
 	}
 
 	// JSON group
@@ -44,7 +51,11 @@ func NewEndpoint(params EndpointParams) *Endpoint {
 	g.GET("/jobs/:id/executions", e.jobExecutions)
 	g.GET("/jobs/:id/results", e.jobResults)
 	g.GET("/jobs/:id/logs", e.logs)
+	g.GET("/jobs/:id/endpoints", e.jobEndpoints)
+	g.GET("/services/:name", e.lookupService)
 	g.GET("/nodes", e.listNodes)
 	g.GET("/nodes/:id", e.getNode)
+	g.PUT("/nodes/:id", e.updateNode)
+	g.POST("/nodes/tokens", e.createJoinToken)
 	return e
 }
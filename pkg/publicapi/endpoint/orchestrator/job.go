@@ -3,6 +3,7 @@ package orchestrator
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/bacalhau-project/bacalhau/pkg/lib/concurrency"
@@ -11,6 +12,7 @@ import (
 	"github.com/samber/lo"
 	"golang.org/x/exp/slices"
 
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/orchestrator"
@@ -135,6 +137,8 @@ func (e *Endpoint) getJob(c echo.Context) error {
 // @Param			next_token	query	string	false	"Token to get the next page of jobs"
 // @Param			reverse	query	bool	false		"Reverse the order of the jobs"
 // @Param			order_by	query	string	false	"Order the jobs by the given field"
+// @Param			owner	query	string	false		"Only return jobs submitted by the given owner"
+// @Param			mine	query	bool	false		"Only return jobs submitted by the caller"
 // @Success		200	{object}	apimodels.ListJobsResponse
 // @Failure		400	{object}	string
 // @Failure		500	{object}	string
@@ -173,8 +177,17 @@ func (e *Endpoint) listJobs(c echo.Context) error {
 		return err
 	}
 
+	owner := args.Owner
+	if args.Mine {
+		owner = authz.PrincipalFromContext(ctx)
+		if owner == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "cannot list own jobs without an authenticated principal")
+		}
+	}
+
 	query := jobstore.JobQuery{
 		Namespace:   args.Namespace,
+		Owner:       owner,
 		Limit:       args.Limit,
 		Offset:      offset,
 		SortBy:      args.OrderBy,
@@ -393,6 +406,8 @@ func (e *Endpoint) jobExecutions(c echo.Context) error {
 // @Param			next_token	query	string	false	"Token to get the next page of results"
 // @Param			reverse	query	bool	false		"Reverse the order of the results"
 // @Param			order_by	query	string	false	"Order the results by the given field"
+// @Param			publisher	query	string	false	"Only return results published by the given publisher type"
+// @Param			snapshot	query	int	false	"Return snapshots up to the given version instead of results, or -1 for the latest version"
 // @Success		200	{object}	apimodels.ListJobResultsResponse
 // @Failure		400	{object}	string
 // @Failure		500	{object}	string
@@ -409,14 +424,91 @@ func (e *Endpoint) jobResults(c echo.Context) error {
 	}
 
 	resp, err := e.orchestrator.GetResults(ctx, &orchestrator.GetResultsRequest{
-		JobID: jobID,
+		JobID:     jobID,
+		Publisher: args.Publisher,
+		Snapshot:  args.Snapshot,
 	})
 	if err != nil {
 		return err
 	}
 
 	return publicapi.UnescapedJSON(c, http.StatusOK, &apimodels.ListJobResultsResponse{
-		Results: resp.Results,
+		Results:   resp.Results,
+		Manifests: resp.Manifests,
+		Snapshots: resp.Snapshots,
+	})
+}
+
+// godoc for Orchestrator JobEndpoints
+//
+// @ID			orchestrator/jobEndpoints
+// @Summary		Returns the endpoints of a job.
+// @Description	Returns the addresses the published ports of the running executions of a job can be reached on.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			id	path	string	true	"ID to get the job endpoints for"
+// @Success		200	{object}	apimodels.GetJobEndpointsResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/jobs/{id}/endpoints [get]
+func (e *Endpoint) jobEndpoints(c echo.Context) error {
+	ctx := c.Request().Context()
+	jobID := c.Param("id")
+	var args apimodels.GetJobEndpointsRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := e.orchestrator.GetEndpoints(ctx, &orchestrator.GetEndpointsRequest{
+		JobID: jobID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.GetJobEndpointsResponse{
+		Endpoints: resp.Endpoints,
+	})
+}
+
+// godoc for Orchestrator LookupService
+//
+// @ID			orchestrator/lookupService
+// @Summary		Looks up the endpoints of a service.
+// @Description	Returns the endpoints of a port of the running executions of a job, given a service name
+// @Description	in the form _<port>._<protocol>.<job>, where job is the name or ID of the job.
+// @Tags			Orchestrator
+// @Accept		json
+// @Produce		json
+// @Param			name	path	string	true	"Service name in the form _<port>._<protocol>.<job>"
+// @Param			namespace	query	string	false	"Namespace of the job"
+// @Success		200	{object}	apimodels.LookupServiceResponse
+// @Failure		400	{object}	string
+// @Failure		500	{object}	string
+// @Router			/api/v1/orchestrator/services/{name} [get]
+func (e *Endpoint) lookupService(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.LookupServiceRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	name, err := url.PathUnescape(c.Param("name"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if _, err = models.ParseServiceName(name); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	resp, err := e.orchestrator.LookupService(ctx, &orchestrator.LookupServiceRequest{
+		Name:      name,
+		Namespace: args.Namespace,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, &apimodels.LookupServiceResponse{
+		Endpoints: resp.Endpoints,
 	})
 }
 
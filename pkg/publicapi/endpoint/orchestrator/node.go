@@ -1,10 +1,15 @@
 package orchestrator
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/apimodels"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/admission"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/labels"
@@ -97,3 +102,86 @@ func (e *Endpoint) listNodes(c echo.Context) error {
 		Nodes: res,
 	})
 }
+
+func (e *Endpoint) updateNode(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.PutNodeRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+
+	node, err := e.nodeStore.GetByPrefix(ctx, c.Param("id"))
+	if err != nil {
+		var errNotFound routing.ErrNodeNotFound
+		if errors.As(err, &errNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+
+	var action func(context.Context, string) error
+	switch args.Action {
+	case apimodels.NodeActionApprove:
+		action = e.nodeManager.ApproveNode
+	case apimodels.NodeActionReject:
+		action = e.nodeManager.RejectNode
+	case apimodels.NodeActionRevoke:
+		action = e.nodeManager.RevokeNode
+	case apimodels.NodeActionCordon:
+		action = e.nodeManager.CordonNode
+	case apimodels.NodeActionUncordon:
+		action = e.nodeManager.UncordonNode
+	case apimodels.NodeActionDrain:
+		if args.DrainTimeout < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "drain timeout must not be negative")
+		}
+		action = func(ctx context.Context, nodeID string) error {
+			return e.nodeDrainer.DrainNode(ctx, nodeID, args.DrainTimeout)
+		}
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unsupported node action: %s", args.Action))
+	}
+
+	if err = action(ctx, node.ID()); err != nil {
+		var errTransition admission.ErrInvalidTransition
+		if errors.As(err, &errTransition) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+
+	node, err = e.nodeStore.Get(ctx, node.ID())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.PutNodeResponse{
+		Node: &node,
+	})
+}
+
+func (e *Endpoint) createJoinToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	var args apimodels.CreateJoinTokenRequest
+	if err := c.Bind(&args); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err := c.Validate(&args); err != nil {
+		return err
+	}
+	if args.TTL < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "token TTL must not be negative")
+	}
+
+	secret, token, err := e.nodeManager.CreateJoinToken(ctx, args.TTL)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, apimodels.CreateJoinTokenResponse{
+		Token:     secret,
+		TokenID:   token.ID,
+		ExpiresAt: token.ExpiresAt,
+	})
+}
@@ -8,7 +8,8 @@ import (
 )
 
 // Authorize only allows the HTTP request to continue if the passed authorizer
-// permits the request.
+// permits the request. Any principal established by the authorizer is attached
+// to the request context for use by handlers.
 func Authorize(authorizer authz.Authorizer) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -17,6 +18,8 @@ func Authorize(authorizer authz.Authorizer) echo.MiddlewareFunc {
 			} else if !result.Approved {
 				return echo.NewHTTPError(http.StatusForbidden, "unauthorized. "+result.Reason)
 			} else {
+				req := c.Request()
+				c.SetRequest(req.WithContext(authz.ContextWithPrincipal(req.Context(), result.Principal)))
 				return next(c)
 			}
 		}
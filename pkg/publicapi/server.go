@@ -12,6 +12,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/bacalhau-project/bacalhau/pkg/authz"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/tlsutil"
 
 	"github.com/bacalhau-project/bacalhau/pkg/logger"
 	"github.com/bacalhau-project/bacalhau/pkg/publicapi/middleware"
@@ -36,6 +37,8 @@ type ServerParams struct {
 	AutoCertCache      string
 	TLSCertificateFile string
 	TLSKeyFile         string
+	TLSClientCAFile    string
+	RequireClientCert  bool
 	Config             Config
 	Authorizer         authz.Authorizer
 	Headers            map[string]string
@@ -50,9 +53,10 @@ type Server struct {
 	TLSCertificateFile string
 	TLSKeyFile         string
 
-	httpServer http.Server
-	config     Config
-	useTLS     bool
+	httpServer   http.Server
+	config       Config
+	useTLS       bool
+	certReloader *tlsutil.CertificateReloader
 }
 
 //nolint:funlen
@@ -135,6 +139,9 @@ func NewAPIServer(params ServerParams) (*Server, error) {
 	)
 
 	var tlsConfig *tls.Config
+	if params.AutoCertDomain != "" && (params.TLSClientCAFile != "" || params.RequireClientCert) {
+		return nil, fmt.Errorf("client certificates cannot be verified with an automatic certificate")
+	}
 	if params.AutoCertDomain != "" {
 		log.Ctx(context.TODO()).Debug().Msgf("Setting up auto-cert for %s", params.AutoCertDomain)
 
@@ -150,8 +157,23 @@ func NewAPIServer(params ServerParams) (*Server, error) {
 		}
 
 		server.useTLS = true
-	} else {
-		server.useTLS = params.TLSCertificateFile != "" && params.TLSKeyFile != ""
+	} else if params.TLSCertificateFile != "" && params.TLSKeyFile != "" {
+		// Certificates are served through a reloader so that they can be
+		// rotated on disk without restarting the node. If a client CA is
+		// configured then clients can authenticate using certificates.
+		server.certReloader, err = tlsutil.NewCertificateReloader(
+			params.TLSCertificateFile, params.TLSKeyFile, params.TLSClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig, err = server.certReloader.ServerConfig(params.RequireClientCert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+		server.useTLS = true
+	} else if params.TLSClientCAFile != "" || params.RequireClientCert {
+		return nil, fmt.Errorf("a TLS certificate and key are required to verify client certificates")
 	}
 
 	server.TLSCertificateFile = params.TLSCertificateFile
@@ -214,11 +236,17 @@ func (apiServer *Server) ListenAndServe(ctx context.Context) error {
 	log.Ctx(ctx).Debug().Msgf(
 		"API server listening for host %s on %s...", apiServer.Address, listener.Addr().String())
 
+	if apiServer.certReloader != nil {
+		apiServer.certReloader.Start(ctx, tlsutil.DefaultReloadInterval)
+	}
+
 	go func() {
 		var err error
 
 		if apiServer.useTLS {
-			err = apiServer.httpServer.ServeTLS(listener, apiServer.TLSCertificateFile, apiServer.TLSKeyFile)
+			// certificates are provided by the TLS config, either through
+			// autocert or the certificate reloader
+			err = apiServer.httpServer.ServeTLS(listener, "", "")
 		} else {
 			err = apiServer.httpServer.Serve(listener)
 		}
@@ -134,6 +134,17 @@ func (s *APIServerTestSuite) TestShutdownNotRunning() {
 	s.Require().NoError(s.server.Shutdown(context.Background()))
 }
 
+func (s *APIServerTestSuite) TestRequireClientCertWithoutCA() {
+	_, err := NewAPIServer(ServerParams{
+		Router:            echo.New(),
+		Address:           "localhost",
+		Config:            *NewConfig(),
+		Authorizer:        authz.AlwaysAllow,
+		RequireClientCert: true,
+	})
+	s.Require().Error(err)
+}
+
 // validateResponse validates the response from the server
 func (s *APIServerTestSuite) validateResponse(resp *http.Response, expectedStatusCode int, expectedBody string) {
 	s.Require().NotNil(resp)
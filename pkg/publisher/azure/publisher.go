@@ -0,0 +1,94 @@
+package azure
+
+import (
+	"context"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+type PublisherParams struct {
+	LocalDir       string
+	ClientProvider *azurehelper.ClientProvider
+}
+
+// Compile-time check that publisher implements the correct interface:
+var _ publisher.Publisher = (*Publisher)(nil)
+
+// Publisher uploads results as a tar.gz archive to Azure Blob Storage.
+type Publisher struct {
+	localDir       string
+	clientProvider *azurehelper.ClientProvider
+}
+
+func NewPublisher(params PublisherParams) *Publisher {
+	return &Publisher{
+		localDir:       params.LocalDir,
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled returns true if Azure storage credentials are configured.
+func (p *Publisher) IsInstalled(_ context.Context) (bool, error) {
+	return p.clientProvider.IsInstalled(), nil
+}
+
+// ValidateJob validates the job spec and returns an error if the job is invalid.
+func (p *Publisher) ValidateJob(_ context.Context, j models.Job) error {
+	_, err := azurehelper.DecodePublisherSpec(j.Task().Publisher)
+	return err
+}
+
+func (p *Publisher) PublishResult(
+	ctx context.Context,
+	execution *models.Execution,
+	resultPath string,
+) (models.SpecConfig, error) {
+	spec, err := azurehelper.DecodePublisherSpec(execution.Job.Task().Publisher)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	client, err := p.clientProvider.GetClient(spec.Account, spec.Endpoint)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	key := publisher.ParsePublishedKey(spec.Key, execution, true)
+
+	archive, err := os.CreateTemp(p.localDir, "bacalhau-archive-*.tar.gz")
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	defer os.Remove(archive.Name()) //nolint:errcheck
+	defer closer.CloseWithLogOnError("archive", archive)
+
+	if err = gzip.Compress(resultPath, archive); err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	res, err := client.UploadFile(ctx, spec.Container, key, archive, nil)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Uploaded az://%s/%s", spec.Container, key)
+
+	source := azurehelper.SourceSpec{
+		Account:   spec.Account,
+		Container: spec.Container,
+		Key:       key,
+		Endpoint:  spec.Endpoint,
+	}
+	if res.ETag != nil {
+		source.ETag = string(*res.ETag)
+	}
+	return models.SpecConfig{
+		Type:   models.StorageSourceAzure,
+		Params: source.ToMap(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package gcs
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+type PublisherParams struct {
+	LocalDir       string
+	ClientProvider *gcshelper.ClientProvider
+}
+
+// Compile-time check that publisher implements the correct interface:
+var _ publisher.Publisher = (*Publisher)(nil)
+
+// Publisher uploads results as a tar.gz archive to Google Cloud Storage.
+type Publisher struct {
+	localDir       string
+	clientProvider *gcshelper.ClientProvider
+}
+
+func NewPublisher(params PublisherParams) *Publisher {
+	return &Publisher{
+		localDir:       params.LocalDir,
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled returns true if Google application default credentials are
+// available, or a storage emulator is configured.
+func (p *Publisher) IsInstalled(ctx context.Context) (bool, error) {
+	return p.clientProvider.IsInstalled(ctx), nil
+}
+
+// ValidateJob validates the job spec and returns an error if the job is invalid.
+func (p *Publisher) ValidateJob(_ context.Context, j models.Job) error {
+	_, err := gcshelper.DecodePublisherSpec(j.Task().Publisher)
+	return err
+}
+
+func (p *Publisher) PublishResult(
+	ctx context.Context,
+	execution *models.Execution,
+	resultPath string,
+) (models.SpecConfig, error) {
+	spec, err := gcshelper.DecodePublisherSpec(execution.Job.Task().Publisher)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	client, err := p.clientProvider.GetClient(ctx, spec.Endpoint)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	key := publisher.ParsePublishedKey(spec.Key, execution, true)
+
+	archive, err := os.CreateTemp(p.localDir, "bacalhau-archive-*.tar.gz")
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+	defer os.Remove(archive.Name()) //nolint:errcheck
+	defer closer.CloseWithLogOnError("archive", archive)
+
+	if err = gzip.Compress(resultPath, archive); err != nil {
+		return models.SpecConfig{}, err
+	}
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	writer := client.Bucket(spec.Bucket).Object(key).NewWriter(ctx)
+	writer.ContentType = "application/gzip"
+	if _, err = io.Copy(writer, archive); err != nil {
+		_ = writer.Close()
+		return models.SpecConfig{}, err
+	}
+	if err = writer.Close(); err != nil {
+		return models.SpecConfig{}, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Uploaded gs://%s/%s", spec.Bucket, key)
+
+	return models.SpecConfig{
+		Type: models.StorageSourceGCS,
+		Params: gcshelper.SourceSpec{
+			Bucket:     spec.Bucket,
+			Key:        key,
+			Endpoint:   spec.Endpoint,
+			Generation: writer.Attrs().Generation,
+		}.ToMap(),
+	}, nil
+}
@@ -0,0 +1,419 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+const (
+	DefaultMaxRetries  = 5
+	defaultBaseBackoff = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+
+	methodMkcol = "MKCOL"
+)
+
+// committedRange matches the Range header returned by servers for a partially
+// uploaded object, e.g. "bytes=0-1048575".
+var committedRange = regexp.MustCompile(`^bytes=0-(\d+)$`)
+
+type PublisherParams struct {
+	// LocalDir is where result archives are staged before upload.
+	LocalDir string
+	Secrets  secrets.Resolver
+	// SecretsPolicy sets the secrets jobs can reference, and the URLs they can be sent to.
+	SecretsPolicy *secrets.Policy
+	// HTTPClient is copied so that redirects are never followed.
+	HTTPClient *http.Client
+	// MaxRetries is the number of times a failed request is retried.
+	MaxRetries int
+	Backoff    backoff.Backoff
+}
+
+// Publisher uploads results to an HTTP server or WebDAV share.
+type Publisher struct {
+	localDir   string
+	secrets    secrets.Resolver
+	policy     *secrets.Policy
+	client     *http.Client
+	maxRetries int
+	backoff    backoff.Backoff
+}
+
+// Compile-time check that publisher implements the correct interface:
+var _ publisher.Publisher = (*Publisher)(nil)
+
+func NewPublisher(params PublisherParams) *Publisher {
+	p := &Publisher{
+		localDir:   params.LocalDir,
+		secrets:    params.Secrets,
+		policy:     params.SecretsPolicy,
+		client:     params.HTTPClient,
+		maxRetries: params.MaxRetries,
+		backoff:    params.Backoff,
+	}
+	if p.secrets == nil {
+		p.secrets = secrets.NewEnvResolver()
+	}
+	if p.client == nil {
+		p.client = http.DefaultClient
+	}
+	// redirects would forward the job's credentials to another URL
+	client := *p.client
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	p.client = &client
+	if p.maxRetries == 0 {
+		p.maxRetries = DefaultMaxRetries
+	}
+	if p.backoff == nil {
+		p.backoff = backoff.NewExponential(defaultBaseBackoff, defaultMaxBackoff)
+	}
+	return p
+}
+
+// IsInstalled returns true as the publisher only needs network access.
+func (p *Publisher) IsInstalled(context.Context) (bool, error) {
+	return true, nil
+}
+
+// ValidateJob validates the job spec and returns an error if the job is invalid.
+func (p *Publisher) ValidateJob(_ context.Context, j models.Job) error {
+	_, err := DecodePublisherSpec(j.Task().Publisher)
+	return err
+}
+
+func (p *Publisher) PublishResult(
+	ctx context.Context,
+	execution *models.Execution,
+	resultPath string,
+) (models.SpecConfig, error) {
+	spec, err := DecodePublisherSpec(execution.Job.Task().Publisher)
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	header := http.Header{}
+	authHeader := spec.AuthHeader
+	if authHeader == "" {
+		authHeader = DefaultAuthHeader
+	}
+	result := ResultSpec{
+		URL:        ParsePublishedURL(spec.URL, execution, !spec.PerFile),
+		Secret:     spec.Secret,
+		AuthHeader: spec.AuthHeader,
+	}
+	if spec.Secret != "" {
+		value, err := secrets.ForNamespace(p.secrets, p.policy, execution.Job.Namespace).
+			ResolveFor(ctx, spec.Secret, result.URL)
+		if err != nil {
+			return models.SpecConfig{}, err
+		}
+		header.Set(authHeader, value)
+	}
+
+	u := &uploader{Publisher: p, spec: spec, header: header}
+	if spec.PerFile {
+		result.Files, err = u.uploadDirectory(ctx, resultPath, result.URL)
+	} else {
+		err = u.uploadArchive(ctx, resultPath, result.URL)
+	}
+	if err != nil {
+		return models.SpecConfig{}, err
+	}
+
+	log.Ctx(ctx).Debug().Str("url", result.URL).Int("files", len(result.Files)).Msg("Published results over HTTP")
+	return models.SpecConfig{
+		Type:   models.StorageSourceHTTP,
+		Params: result.ToMap(),
+	}, nil
+}
+
+// uploader holds the state of a single PublishResult call.
+type uploader struct {
+	*Publisher
+	spec   PublisherSpec
+	header http.Header
+}
+
+func (u *uploader) uploadArchive(ctx context.Context, resultPath, target string) error {
+	archive, err := os.CreateTemp(u.localDir, "bacalhau-archive-*.tar.gz")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive.Name()) //nolint:errcheck
+	defer closer.CloseWithLogOnError("archive", archive)
+
+	if err = gzip.Compress(resultPath, archive); err != nil {
+		return err
+	}
+	return u.uploadFile(ctx, archive.Name(), target)
+}
+
+// uploadDirectory uploads each regular file under resultPath and returns
+// their paths relative to the base URL.
+func (u *uploader) uploadDirectory(ctx context.Context, resultPath, base string) ([]string, error) {
+	webdav := u.spec.UploadMethod() == MethodWebDAV
+	if webdav {
+		if err := u.mkcol(ctx, base); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	err := filepath.WalkDir(resultPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(resultPath, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if webdav {
+				return u.mkcol(ctx, joinURL(base, rel))
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err = u.uploadFile(ctx, path, joinURL(base, rel)); err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+func (u *uploader) uploadFile(ctx context.Context, path, target string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError("file", file)
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if u.spec.ChunkSize > 0 && info.Size() > u.spec.ChunkSize {
+		return u.uploadChunked(ctx, file, info.Size(), target)
+	}
+
+	method := u.spec.UploadMethod()
+	if method == MethodWebDAV {
+		method = http.MethodPut
+	}
+	return u.retry(ctx, target, func() error {
+		_, err := u.do(ctx, method, target, io.NewSectionReader(file, 0, info.Size()), info.Size(), nil)
+		return err
+	})
+}
+
+// uploadChunked sends the file in ChunkSize pieces with Content-Range
+// headers. Servers acknowledge a partial upload with 308 Permanent Redirect
+// and a Range header of the bytes committed so far, and only the final chunk
+// with a success status. After a failure, the upload is resumed from the
+// offset the server reports. Attempts are only reset once the upload gets
+// further than it ever did, so a server that never commits anything fails
+// the upload after the retry limit.
+func (u *uploader) uploadChunked(ctx context.Context, file io.ReaderAt, size int64, target string) error {
+	var offset, furthest int64
+	for attempt := 0; offset < size; {
+		end := offset + u.spec.ChunkSize
+		if end > size {
+			end = size
+		}
+		header := http.Header{}
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, end-1, size))
+
+		res, err := u.do(ctx, http.MethodPut, target, io.NewSectionReader(file, offset, end-offset), end-offset, header)
+		if err == nil {
+			committed, err := committedOffset(res, end, size)
+			if err != nil {
+				return fmt.Errorf("failed to upload %s: %w", target, err)
+			}
+			if committed > furthest {
+				attempt = 0
+				furthest = committed
+			}
+			if committed > offset {
+				offset = committed
+				continue
+			}
+			offset = committed
+			if attempt >= u.maxRetries {
+				return fmt.Errorf("failed to upload %s: server committed none of bytes %d-%d", target, offset, end-1)
+			}
+		} else if !isRetryable(err) || attempt >= u.maxRetries {
+			return fmt.Errorf("failed to upload %s: %w", target, err)
+		}
+		attempt++
+		log.Ctx(ctx).Debug().Err(err).Str("url", target).Int64("offset", offset).Msg("Chunk upload failed, resuming")
+		u.backoff.Backoff(ctx, attempt)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if res == nil {
+			if offset, err = u.queryOffset(ctx, target, size, offset); err != nil {
+				return fmt.Errorf("failed to upload %s: %w", target, err)
+			}
+		}
+	}
+	return nil
+}
+
+// queryOffset asks the server how much of an interrupted upload it has
+// committed, falling back to the given offset if it cannot tell.
+func (u *uploader) queryOffset(ctx context.Context, target string, size, fallback int64) (int64, error) {
+	header := http.Header{}
+	header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	res, err := u.do(ctx, http.MethodPut, target, http.NoBody, 0, header)
+	if err != nil {
+		return fallback, nil
+	}
+	if res.StatusCode != http.StatusPermanentRedirect {
+		// a server that does not understand the status query has replaced
+		// the object with the empty request body
+		return 0, fmt.Errorf("server does not support resumable uploads: status query returned %d", res.StatusCode)
+	}
+	return committedOffset(res, 0, size)
+}
+
+// committedOffset returns the offset to continue uploading from given a
+// successful response to a request that sent the bytes up to sent. A 308
+// reports the committed range, and any other success is only expected once
+// the final chunk was sent. Servers that accept a partial chunk with a
+// success status ignore Content-Range and would corrupt the object.
+func committedOffset(res *http.Response, sent, size int64) (int64, error) {
+	if res.StatusCode != http.StatusPermanentRedirect {
+		if sent != size {
+			return 0, fmt.Errorf("server does not support resumable uploads: partial upload returned %d", res.StatusCode)
+		}
+		return size, nil
+	}
+	rng := res.Header.Get("Range")
+	if rng == "" {
+		return 0, nil
+	}
+	m := committedRange.FindStringSubmatch(rng)
+	if m == nil {
+		return 0, fmt.Errorf("invalid committed range %q", rng)
+	}
+	last, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || last+1 > size || (sent > 0 && last+1 > sent) {
+		return 0, fmt.Errorf("invalid committed range %q", rng)
+	}
+	return last + 1, nil
+}
+
+// mkcol creates a WebDAV collection, accepting one that already exists.
+func (u *uploader) mkcol(ctx context.Context, target string) error {
+	if !strings.HasSuffix(target, "/") {
+		target += "/"
+	}
+	return u.retry(ctx, target, func() error {
+		_, err := u.do(ctx, methodMkcol, target, http.NoBody, 0, nil)
+		var status *statusError
+		if errors.As(err, &status) && status.code == http.StatusMethodNotAllowed {
+			// 405 is returned for collections that already exist
+			return nil
+		}
+		return err
+	})
+}
+
+func (u *uploader) retry(ctx context.Context, target string, f func() error) error {
+	var err error
+	for attempt := 0; attempt <= u.maxRetries; attempt++ {
+		u.backoff.Backoff(ctx, attempt)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err = f(); err == nil || !isRetryable(err) {
+			break
+		}
+		log.Ctx(ctx).Debug().Err(err).Str("url", target).Int("attempt", attempt).Msg("Upload failed, retrying")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", target, err)
+	}
+	return nil
+}
+
+func (u *uploader) do(
+	ctx context.Context, method, target string, body io.Reader, length int64, header http.Header,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = length
+	for k, v := range u.header {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.DrainAndCloseWithLogOnError(ctx, "http response", res.Body)
+
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices ||
+		res.StatusCode == http.StatusPermanentRedirect && header.Get("Content-Range") != "" {
+		return res, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10)) //nolint:gomnd // 1KB max
+	return nil, &statusError{code: res.StatusCode, method: method, body: strings.TrimSpace(string(msg))}
+}
+
+type statusError struct {
+	code   int
+	method string
+	body   string
+}
+
+func (e *statusError) Error() string {
+	if e.body == "" {
+		return fmt.Sprintf("%s failed with status code %d", e.method, e.code)
+	}
+	return fmt.Sprintf("%s failed with status code %d: %s", e.method, e.code, e.body)
+}
+
+// isRetryable returns true for network errors and for status codes that may
+// succeed on a later attempt.
+func isRetryable(err error) bool {
+	var status *statusError
+	if !errors.As(err, &status) {
+		return true
+	}
+	return status.code >= http.StatusInternalServerError ||
+		status.code == http.StatusRequestTimeout ||
+		status.code == http.StatusTooManyRequests
+}
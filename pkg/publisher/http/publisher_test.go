@@ -0,0 +1,350 @@
+//go:build unit || !integration
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/backoff"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/test/mock"
+)
+
+var (
+	contentRange = regexp.MustCompile(`^bytes (\d+)-(\d+)/(\d+)$`)
+	rangeQuery   = regexp.MustCompile(`^bytes \*/(\d+)$`)
+)
+
+// objectServer is a minimal HTTP object store that understands WebDAV
+// collections and Content-Range resumable uploads.
+type objectServer struct {
+	mu          sync.Mutex
+	objects     map[string][]byte
+	partial     map[string][]byte
+	collections map[string]bool
+	methods     []string
+	auth        []string
+	// failures is the number of upcoming uploads that fail with a 503
+	failures int
+}
+
+func newObjectServer() *objectServer {
+	return &objectServer{
+		objects:     make(map[string][]byte),
+		partial:     make(map[string][]byte),
+		collections: make(map[string]bool),
+	}
+}
+
+func (o *objectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.methods = append(o.methods, r.Method)
+	o.auth = append(o.auth, r.Header.Get("Authorization"))
+
+	body, _ := io.ReadAll(r.Body)
+	switch r.Method {
+	case "MKCOL":
+		if o.collections[r.URL.Path] {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		o.collections[r.URL.Path] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		data, ok := o.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodPut, http.MethodPost:
+		if o.failures > 0 && len(body) > 0 {
+			o.failures--
+			// accept half of the chunk before failing, like an interrupted upload
+			if rng := r.Header.Get("Content-Range"); rng != "" {
+				o.partial[r.URL.Path] = append(o.partial[r.URL.Path], body[:len(body)/2]...)
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rng := r.Header.Get("Content-Range")
+		if rng == "" {
+			o.objects[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		o.putRange(w, r.URL.Path, rng, body)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (o *objectServer) putRange(w http.ResponseWriter, path, rng string, body []byte) {
+	committed := o.partial[path]
+	var total int
+	if m := rangeQuery.FindStringSubmatch(rng); m != nil {
+		total, _ = strconv.Atoi(m[1])
+	} else if m := contentRange.FindStringSubmatch(rng); m != nil {
+		start, _ := strconv.Atoi(m[1])
+		total, _ = strconv.Atoi(m[3])
+		if start > len(committed) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		committed = append(committed[:start], body...)
+		o.partial[path] = committed
+	} else {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if len(committed) == total {
+		o.objects[path] = committed
+		delete(o.partial, path)
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	if len(committed) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(committed)-1))
+	}
+	w.WriteHeader(http.StatusPermanentRedirect)
+}
+
+type PublisherSuite struct {
+	suite.Suite
+	server    *objectServer
+	url       string
+	resultDir string
+	publisher *Publisher
+}
+
+func TestPublisherSuite(t *testing.T) {
+	suite.Run(t, new(PublisherSuite))
+}
+
+func (s *PublisherSuite) SetupTest() {
+	s.server = newObjectServer()
+	ts := httptest.NewServer(s.server)
+	s.T().Cleanup(ts.Close)
+	s.url = ts.URL
+
+	s.resultDir = s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(s.resultDir, "stdout"), []byte("hello"), 0o600))
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.resultDir, "outputs", "nested dir"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.resultDir, "outputs", "nested dir", "data.bin"),
+		bytes.Repeat([]byte("0123456789"), 100), 0o600))
+
+	policy, err := secrets.NewPolicy([]types.SecretConfig{
+		{Name: "gateway", Shared: true, URLs: []string{s.url + "/results/"}},
+	})
+	s.Require().NoError(err)
+	s.publisher = NewPublisher(PublisherParams{
+		LocalDir:      s.T().TempDir(),
+		Secrets:       secrets.StaticResolver{"gateway": "Bearer token"},
+		SecretsPolicy: policy,
+		Backoff:       backoff.NewNoop(),
+	})
+}
+
+func (s *PublisherSuite) publish(spec PublisherSpec) (ResultSpec, error) {
+	job := mock.Job()
+	job.Task().Publisher = &models.SpecConfig{Type: models.PublisherHTTP, Params: spec.ToMap()}
+	execution := mock.ExecutionForJob(job)
+	execution.NodeID = "node-1"
+
+	s.Require().NoError(s.publisher.ValidateJob(context.Background(), *job))
+	config, err := s.publisher.PublishResult(context.Background(), execution, s.resultDir)
+	if err != nil {
+		return ResultSpec{}, err
+	}
+	return DecodeResultSpec(&config)
+}
+
+func (s *PublisherSuite) TestArchive() {
+	result, err := s.publish(PublisherSpec{URL: s.url + "/results/{nodeID}/out", Secret: "gateway"})
+	s.Require().NoError(err)
+	s.Equal(s.url+"/results/node-1/out.tar.gz", result.URL)
+	s.Empty(result.Files)
+	s.Equal("gateway", result.Secret)
+
+	archive := filepath.Join(s.T().TempDir(), "out.tar.gz")
+	s.Require().NoError(os.WriteFile(archive, s.server.objects["/results/node-1/out.tar.gz"], 0o600))
+	extracted := s.T().TempDir()
+	s.Require().NoError(gzip.Decompress(archive, extracted))
+	s.FileExists(filepath.Join(extracted, "outputs", "nested dir", "data.bin"))
+	s.Equal([]string{"Bearer token"}, s.server.auth)
+}
+
+func (s *PublisherSuite) TestPost() {
+	_, err := s.publish(PublisherSpec{URL: s.url + "/upload", Method: "post"})
+	s.Require().NoError(err)
+	s.Equal([]string{http.MethodPost}, s.server.methods)
+	s.Contains(s.server.objects, "/upload.tar.gz")
+}
+
+func (s *PublisherSuite) TestWebDAVPerFile() {
+	// the base collection already exists
+	s.server.collections["/dav/"] = true
+
+	result, err := s.publish(PublisherSpec{URL: s.url + "/dav", Method: MethodWebDAV, PerFile: true})
+	s.Require().NoError(err)
+	s.Equal(s.url+"/dav/", result.URL)
+	s.ElementsMatch([]string{"stdout", "outputs/nested dir/data.bin"}, result.Files)
+	s.True(s.server.collections["/dav/outputs/"])
+	s.True(s.server.collections["/dav/outputs/nested dir/"])
+	s.Equal([]byte("hello"), s.server.objects["/dav/stdout"])
+	s.Len(s.server.objects["/dav/outputs/nested dir/data.bin"], 1000)
+}
+
+func (s *PublisherSuite) TestRetry() {
+	s.server.failures = 2
+	_, err := s.publish(PublisherSpec{URL: s.url + "/retry"})
+	s.Require().NoError(err)
+	s.Len(s.server.methods, 3)
+}
+
+func (s *PublisherSuite) TestRetriesExhausted() {
+	s.server.failures = DefaultMaxRetries + 1
+	_, err := s.publish(PublisherSpec{URL: s.url + "/retry"})
+	s.ErrorContains(err, "503")
+}
+
+func (s *PublisherSuite) TestClientErrorIsNotRetried() {
+	var requests int
+	s.publisher.client = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusForbidden, Body: http.NoBody}, nil
+	})}
+	_, err := s.publish(PublisherSpec{URL: s.url + "/forbidden"})
+	s.ErrorContains(err, "403")
+	s.Equal(1, requests)
+}
+
+func (s *PublisherSuite) TestChunkedResume() {
+	s.server.failures = 2
+	result, err := s.publish(PublisherSpec{URL: s.url + "/chunked", PerFile: true, ChunkSize: 300})
+	s.Require().NoError(err)
+	s.ElementsMatch([]string{"stdout", "outputs/nested dir/data.bin"}, result.Files)
+	s.Equal(bytes.Repeat([]byte("0123456789"), 100), s.server.objects["/chunked/outputs/nested dir/data.bin"])
+	s.Empty(s.server.partial)
+}
+
+func (s *PublisherSuite) TestChunkedWithoutProgress() {
+	// a server that never reports a committed range fails the upload after the retry limit
+	var requests int
+	s.publisher.client = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusPermanentRedirect, Header: http.Header{}, Body: http.NoBody}, nil
+	})}
+	_, err := s.publish(PublisherSpec{URL: s.url + "/chunked", PerFile: true, ChunkSize: 300})
+	s.ErrorContains(err, "committed none")
+	s.Equal(DefaultMaxRetries+1, requests)
+}
+
+func (s *PublisherSuite) TestChunkedNotSupported() {
+	// a server that accepts a partial chunk with a success status ignores Content-Range
+	var requests int
+	s.publisher.client = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+	_, err := s.publish(PublisherSpec{URL: s.url + "/chunked", PerFile: true, ChunkSize: 300})
+	s.ErrorContains(err, "does not support resumable uploads")
+	s.Equal(1, requests)
+}
+
+func (s *PublisherSuite) TestChunkedStatusQueryNotSupported() {
+	// after a lost response, a success reply to the status query means the
+	// server stored the empty body instead of reporting progress
+	var ranges []string
+	s.publisher.client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		ranges = append(ranges, r.Header.Get("Content-Range"))
+		if len(ranges) == 1 {
+			return nil, errors.New("connection reset")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})}
+	_, err := s.publish(PublisherSpec{URL: s.url + "/chunked", PerFile: true, ChunkSize: 300})
+	s.ErrorContains(err, "does not support resumable uploads")
+	s.Len(ranges, 2)
+	s.Equal("bytes */1000", ranges[1])
+}
+
+func (s *PublisherSuite) TestMissingSecret() {
+	_, err := s.publish(PublisherSpec{URL: s.url + "/results", Secret: "unknown"})
+	s.Error(err)
+}
+
+func (s *PublisherSuite) TestSecretOnlySentToItsURLs() {
+	_, err := s.publish(PublisherSpec{URL: s.url + "/other", Secret: "gateway"})
+	s.ErrorContains(err, "cannot be sent to")
+	_, err = s.publish(PublisherSpec{URL: s.url + "/results-other", Secret: "gateway"})
+	s.ErrorContains(err, "cannot be sent to")
+	s.Empty(s.server.auth)
+}
+
+func (s *PublisherSuite) TestRedirectIsNotFollowed() {
+	redirect := httptest.NewServer(http.RedirectHandler(s.url+"/results/out", http.StatusTemporaryRedirect))
+	s.T().Cleanup(redirect.Close)
+	_, err := s.publish(PublisherSpec{URL: redirect.URL + "/out"})
+	s.ErrorContains(err, "307")
+	s.Empty(s.server.methods)
+}
+
+func (s *PublisherSuite) TestValidate() {
+	for _, tc := range []struct {
+		name  string
+		spec  PublisherSpec
+		valid bool
+	}{
+		{name: "put", spec: PublisherSpec{URL: "https://example.com/{jobID}"}, valid: true},
+		{name: "webdav", spec: PublisherSpec{URL: "https://example.com/dav", Method: "webdav", ChunkSize: 1024}, valid: true},
+		{name: "empty url", spec: PublisherSpec{}},
+		{name: "bad scheme", spec: PublisherSpec{URL: "ftp://example.com/x"}},
+		{name: "bad method", spec: PublisherSpec{URL: "https://example.com/x", Method: "PATCH"}},
+		{name: "chunked post", spec: PublisherSpec{URL: "https://example.com/x", Method: "POST", ChunkSize: 10}},
+	} {
+		s.Run(tc.name, func() {
+			err := tc.spec.Validate()
+			if tc.valid {
+				s.NoError(err)
+			} else {
+				s.Error(err)
+			}
+		})
+	}
+}
+
+func (s *PublisherSuite) TestDecodeWeaklyTyped() {
+	spec, err := DecodePublisherSpec(&models.SpecConfig{
+		Type:   models.PublisherHTTP,
+		Params: map[string]interface{}{"URL": "https://example.com/x", "perfile": "true", "chunksize": "1024"},
+	})
+	s.Require().NoError(err)
+	s.True(spec.PerFile)
+	s.Equal(int64(1024), spec.ChunkSize)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// MethodPut uploads each object with an HTTP PUT.
+	MethodPut = http.MethodPut
+	// MethodPost uploads each object with an HTTP POST.
+	MethodPost = http.MethodPost
+	// MethodWebDAV uploads each object with a PUT, creating any missing
+	// collections with MKCOL first.
+	MethodWebDAV = "WEBDAV"
+
+	// DefaultAuthHeader is the header that carries the secret's value.
+	DefaultAuthHeader = "Authorization"
+
+	archiveExtension = ".tar.gz"
+)
+
+// PublisherSpec configures where and how results are uploaded.
+type PublisherSpec struct {
+	// URL is the destination of the upload. It can contain the {jobID},
+	// {executionID}, {nodeID}, {date} and {time} placeholders. When
+	// publishing an archive, .tar.gz is appended if missing; when
+	// publishing per file, the URL is the prefix each file is uploaded under.
+	URL string
+	// Method is one of PUT (the default), POST or WEBDAV.
+	Method string
+	// PerFile uploads each result file individually rather than a single
+	// tarball of the result directory.
+	PerFile bool
+	// Secret is the name of a node secret whose value is sent in AuthHeader.
+	Secret string
+	// AuthHeader is the header carrying the secret. Defaults to Authorization.
+	AuthHeader string
+	// ChunkSize enables resumable uploads for PUT and WEBDAV: objects larger
+	// than ChunkSize bytes are sent in chunks using Content-Range, and an
+	// interrupted upload resumes from the offset the server reports.
+	ChunkSize int64
+}
+
+func (c PublisherSpec) Validate() error {
+	var errs error
+	if c.URL == "" {
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. url cannot be empty"))
+	} else if u, err := url.Parse(c.URL); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. invalid url: %w", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. url must begin with http or https"))
+	}
+	switch strings.ToUpper(c.Method) {
+	case "", MethodPut, MethodPost, MethodWebDAV:
+	default:
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. unsupported method %q", c.Method))
+	}
+	if c.ChunkSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. chunk size cannot be negative"))
+	}
+	if c.ChunkSize > 0 && strings.EqualFold(c.Method, MethodPost) {
+		errs = errors.Join(errs, fmt.Errorf("invalid http publisher params. chunked uploads are not supported with POST"))
+	}
+	return errs
+}
+
+func (c PublisherSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+// UploadMethod returns the normalised upload method.
+func (c PublisherSpec) UploadMethod() string {
+	if c.Method == "" {
+		return MethodPut
+	}
+	return strings.ToUpper(c.Method)
+}
+
+func DecodePublisherSpec(spec *models.SpecConfig) (PublisherSpec, error) {
+	if !spec.IsType(models.PublisherHTTP) {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher type. expected %s, but received: %s",
+			models.PublisherHTTP, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return PublisherSpec{}, fmt.Errorf("invalid publisher params. cannot be nil")
+	}
+
+	// weakly typed so that options passed as strings on the command line
+	// decode into the boolean and numeric fields
+	var c PublisherSpec
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{WeaklyTypedInput: true, Result: &c})
+	if err != nil {
+		return c, err
+	}
+	if err = decoder.Decode(spec.Params); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// ResultSpec describes results published over HTTP, so that they can be
+// downloaded again.
+type ResultSpec struct {
+	// URL is the location of the archive, or the prefix of the files when
+	// Files is set.
+	URL string
+	// Files lists the published files relative to URL, if results were
+	// published per file.
+	Files []string
+	// Secret and AuthHeader carry over the publisher's authentication so the
+	// client can present its own value of the same secret.
+	Secret     string
+	AuthHeader string
+}
+
+func (c ResultSpec) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("invalid http result params. url cannot be empty")
+	}
+	for _, f := range c.Files {
+		if f == "" || path.IsAbs(f) || strings.HasPrefix(path.Clean(f), "..") {
+			return fmt.Errorf("invalid http result params. invalid file %q", f)
+		}
+	}
+	return nil
+}
+
+func (c ResultSpec) ToMap() map[string]interface{} {
+	return structs.Map(c)
+}
+
+// FileURL returns the URL of a file published per file.
+func (c ResultSpec) FileURL(file string) string {
+	return joinURL(c.URL, file)
+}
+
+func DecodeResultSpec(spec *models.SpecConfig) (ResultSpec, error) {
+	if !spec.IsType(models.StorageSourceHTTP) {
+		return ResultSpec{}, fmt.Errorf("invalid storage source type. expected %s, but received: %s",
+			models.StorageSourceHTTP, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return ResultSpec{}, fmt.Errorf("invalid http result params. cannot be nil")
+	}
+
+	var c ResultSpec
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// ParsePublishedURL substitutes the execution's details into the URL
+// template.
+func ParsePublishedURL(rawURL string, execution *models.Execution, archive bool) string {
+	if archive && !strings.HasSuffix(rawURL, archiveExtension) {
+		rawURL += archiveExtension
+	}
+	if !archive && !strings.HasSuffix(rawURL, "/") {
+		rawURL += "/"
+	}
+
+	now := time.Now()
+	return strings.NewReplacer(
+		"{nodeID}", url.PathEscape(execution.NodeID),
+		"{executionID}", url.PathEscape(execution.ID),
+		"{jobID}", url.PathEscape(execution.JobID),
+		"{date}", now.Format("20060102"),
+		"{time}", now.Format("150405"),
+	).Replace(rawURL)
+}
+
+// joinURL appends a slash separated relative path to a base URL, escaping
+// each segment.
+func joinURL(base, rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,56 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// ParsePublishedKey expands the {nodeID}, {executionID}, {jobID}, {date} and
+// {time} placeholders of an object key, and makes sure archives end with
+// .tar.gz and directories with a "/".
+func ParsePublishedKey(key string, execution *models.Execution, archive bool) string {
+	if archive && !strings.HasSuffix(key, ".tar.gz") {
+		key = key + ".tar.gz"
+	}
+	if !archive && !strings.HasSuffix(key, "/") {
+		key = key + "/"
+	}
+
+	key = strings.ReplaceAll(key, "{nodeID}", execution.NodeID)
+	key = strings.ReplaceAll(key, "{executionID}", execution.ID)
+	key = strings.ReplaceAll(key, "{jobID}", execution.JobID)
+	key = strings.ReplaceAll(key, "{date}", time.Now().Format("20060102"))
+	key = strings.ReplaceAll(key, "{time}", time.Now().Format("150405"))
+	return key
+}
+
+// SnapshotKey returns the key a snapshot of the results is published at. It is
+// the key of the final results with the snapshot version appended, before any
+// archive extension or trailing "/", so that snapshots do not overwrite each
+// other or the final results.
+func SnapshotKey(key string, version int) string {
+	return appendToKey(key, fmt.Sprintf("-snapshot-%d", version))
+}
+
+// CheckpointKey returns the key a checkpoint of a running execution is
+// published at, which is the key of the final results with the checkpoint
+// version appended in the same way as SnapshotKey.
+func CheckpointKey(key string, version int) string {
+	return appendToKey(key, fmt.Sprintf("-checkpoint-%d", version))
+}
+
+// appendToKey appends a suffix to a key, before any archive extension or
+// trailing "/".
+func appendToKey(key, suffix string) string {
+	switch {
+	case strings.HasSuffix(key, ".tar.gz"):
+		return strings.TrimSuffix(key, ".tar.gz") + suffix + ".tar.gz"
+	case strings.HasSuffix(key, "/"):
+		return strings.TrimSuffix(key, "/") + suffix + "/"
+	default:
+		return key + suffix
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/gzip"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
 	s3helper "github.com/bacalhau-project/bacalhau/pkg/s3"
@@ -66,7 +67,7 @@ func (publisher *Publisher) PublishResult(
 	defer targetFile.Close()
 	defer os.Remove(targetFile.Name())
 
-	err = archiveDirectory(resultPath, targetFile)
+	err = gzip.Compress(resultPath, targetFile)
 	if err != nil {
 		return models.SpecConfig{}, err
 	}
@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"os"
 
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
 	"github.com/bacalhau-project/bacalhau/pkg/config"
 	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
 	ipfsClient "github.com/bacalhau-project/bacalhau/pkg/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/lib/provider"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/publisher/gcs"
+	httppublisher "github.com/bacalhau-project/bacalhau/pkg/publisher/http"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/ipfs"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/local"
 	"github.com/bacalhau-project/bacalhau/pkg/publisher/noop"
@@ -37,6 +43,21 @@ func NewPublisherProvider(
 		return nil, err
 	}
 
+	httpPublisher, err := configureHTTPPublisher(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	azurePublisher, err := configureAzurePublisher(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	gcsPublisher, err := configureGCSPublisher(cm)
+	if err != nil {
+		return nil, err
+	}
+
 	localPublisher := local.NewLocalPublisher(ctx, localConfig.Directory, localConfig.Address, localConfig.Port)
 
 	return provider.NewMappedProvider(map[string]publisher.Publisher{
@@ -44,6 +65,9 @@ func NewPublisherProvider(
 		models.PublisherIPFS:  tracing.Wrap(ipfsPublisher),
 		models.PublisherS3:    tracing.Wrap(s3Publisher),
 		models.PublisherLocal: tracing.Wrap(localPublisher),
+		models.PublisherHTTP:  tracing.Wrap(httpPublisher),
+		models.PublisherAzure: tracing.Wrap(azurePublisher),
+		models.PublisherGCS:   tracing.Wrap(gcsPublisher),
 	}), nil
 }
 
@@ -73,6 +97,68 @@ func configureS3Publisher(cm *system.CleanupManager) (*s3.Publisher, error) {
 	}), nil
 }
 
+func configureHTTPPublisher(cm *system.CleanupManager) (*httppublisher.Publisher, error) {
+	dir, err := os.MkdirTemp(config.GetStoragePath(), "bacalhau-http-publisher")
+	if err != nil {
+		return nil, err
+	}
+
+	cm.RegisterCallback(func() error {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("unable to clean up HTTP publisher directory: %w", err)
+		}
+		return nil
+	})
+
+	secretsPolicy, err := secrets.NewPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return httppublisher.NewPublisher(httppublisher.PublisherParams{
+		LocalDir:      dir,
+		Secrets:       secrets.NewEnvResolver(),
+		SecretsPolicy: secretsPolicy,
+	}), nil
+}
+
+func configureAzurePublisher(cm *system.CleanupManager) (*azure.Publisher, error) {
+	dir, err := os.MkdirTemp(config.GetStoragePath(), "bacalhau-azure-publisher")
+	if err != nil {
+		return nil, err
+	}
+
+	cm.RegisterCallback(func() error {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("unable to clean up Azure publisher directory: %w", err)
+		}
+		return nil
+	})
+
+	return azure.NewPublisher(azure.PublisherParams{
+		LocalDir:       dir,
+		ClientProvider: azurehelper.NewClientProvider(azurehelper.DefaultClientProviderParams()),
+	}), nil
+}
+
+func configureGCSPublisher(cm *system.CleanupManager) (*gcs.Publisher, error) {
+	dir, err := os.MkdirTemp(config.GetStoragePath(), "bacalhau-gcs-publisher")
+	if err != nil {
+		return nil, err
+	}
+
+	cm.RegisterCallback(func() error {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("unable to clean up GCS publisher directory: %w", err)
+		}
+		return nil
+	})
+
+	return gcs.NewPublisher(gcs.PublisherParams{
+		LocalDir:       dir,
+		ClientProvider: gcshelper.NewClientProvider(gcshelper.DefaultClientProviderParams()),
+	}), nil
+}
+
 func NewNoopPublishers(
 	_ context.Context,
 	_ *system.CleanupManager,
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ImageCacheStatePath must be called after Init and returns the file the
+// compute node's docker image cache is persisted to, next to its execution
+// store, for example:
+// `~/.bacalhau/Qmd1BEyR4RsLdYTEym1YxxaeXFdwWCMANYN7XCcpPYbTRs-compute/image_cache.json`
+func (fsr *FsRepo) ImageCacheStatePath(prefix string) (string, error) {
+	if exists, err := fsr.Exists(); err != nil {
+		return "", fmt.Errorf("failed to check if repo exists: %w", err)
+	} else if !exists {
+		return "", fmt.Errorf("repo is uninitialized")
+	}
+
+	directory := filepath.Join(fsr.path, fmt.Sprintf("%s-compute", prefix))
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+	return filepath.Join(directory, "image_cache.json"), nil
+}
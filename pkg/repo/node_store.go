@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+
+	routing_boltdb "github.com/bacalhau-project/bacalhau/pkg/routing/boltdb"
+)
+
+// InitNodeMembershipStore must be called after Init and creates the store that
+// persists the admission state of nodes for the requester node. The database
+// is created next to the requester's job store, for example:
+// `~/.bacalhau/Qmd1BEyR4RsLdYTEym1YxxaeXFdwWCMANYN7XCcpPYbTRs-requester/nodes.db`
+func (fsr *FsRepo) InitNodeMembershipStore(ctx context.Context, prefix string) (*routing_boltdb.MembershipStore, error) {
+	path, err := fsr.requesterDatabasePath(prefix, "nodes.db")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create NodeMembershipStore: %w", err)
+	}
+
+	log.Ctx(ctx).Debug().Str("Path", path).Msg("creating boltdb backed node membership store")
+	return routing_boltdb.NewMembershipStore(path)
+}
+
+// InitNodeInfoStore must be called after Init and creates the registry that
+// persists the info and liveness of nodes for the requester node, for example:
+// `~/.bacalhau/Qmd1BEyR4RsLdYTEym1YxxaeXFdwWCMANYN7XCcpPYbTRs-requester/node_registry.db`
+func (fsr *FsRepo) InitNodeInfoStore(
+	ctx context.Context, prefix string, params routing_boltdb.NodeInfoStoreParams) (*routing_boltdb.NodeInfoStore, error) {
+	path, err := fsr.requesterDatabasePath(prefix, "node_registry.db")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create NodeInfoStore: %w", err)
+	}
+
+	log.Ctx(ctx).Debug().Str("Path", path).Msg("creating boltdb backed node info store")
+	return routing_boltdb.NewNodeInfoStore(path, params)
+}
+
+func (fsr *FsRepo) requesterDatabasePath(prefix, name string) (string, error) {
+	if exists, err := fsr.Exists(); err != nil {
+		return "", fmt.Errorf("failed to check if repo exists: %w", err)
+	} else if !exists {
+		return "", fmt.Errorf("repo is uninitialized")
+	}
+
+	directory := filepath.Join(fsr.path, fmt.Sprintf("%s-requester", prefix))
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+	return filepath.Join(directory, name), nil
+}
@@ -2,14 +2,18 @@ package requester
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slices"
 
+	"github.com/bacalhau-project/bacalhau/pkg/authz"
 	"github.com/bacalhau-project/bacalhau/pkg/compute"
 	"github.com/bacalhau-project/bacalhau/pkg/jobstore"
 	"github.com/bacalhau-project/bacalhau/pkg/model"
@@ -120,6 +124,7 @@ func (e *BaseEndpoint) SubmitJob(ctx context.Context, data model.JobCreatePayloa
 	if err != nil {
 		return nil, err
 	}
+	job.Owner = authz.PrincipalFromContext(ctx)
 
 	for _, transform := range e.postTransforms {
 		_, err = transform(ctx, job)
@@ -291,10 +296,11 @@ func (e *BaseEndpoint) OnRunComplete(ctx context.Context, result compute.RunResu
 			},
 		},
 		NewValues: models.Execution{
-			PublishedResult: result.PublishResult,
-			RunOutput:       result.RunCommandResult,
-			ComputeState:    models.NewExecutionState(models.ExecutionStateCompleted),
-			DesiredState:    models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution completed"),
+			PublishedResult:  result.PublishResult,
+			PublishedResults: result.PublishResults,
+			RunOutput:        result.RunCommandResult,
+			ComputeState:     models.NewExecutionState(models.ExecutionStateCompleted),
+			DesiredState:     models.NewExecutionDesiredState(models.ExecutionDesiredStateStopped).WithMessage("execution completed"),
 		},
 	}
 
@@ -317,6 +323,162 @@ func (e *BaseEndpoint) OnRunComplete(ctx context.Context, result compute.RunResu
 	e.enqueueEvaluation(ctx, result.JobID, "OnRunComplete")
 }
 
+// OnSnapshotComplete records a snapshot of the results of a running execution.
+// Snapshots are kept in order of their versions, and a snapshot replaces any
+// previously recorded snapshot with the same version. Updates that conflict
+// with a concurrent update of the execution are retried.
+func (e *BaseEndpoint) OnSnapshotComplete(ctx context.Context, result compute.SnapshotResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received SnapshotComplete for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Snapshot == nil {
+		return
+	}
+	if err := result.Snapshot.Validate(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnSnapshotComplete] invalid snapshot of execution %s", result.ExecutionID)
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := e.recordSnapshot(ctx, result)
+		if err == nil {
+			return
+		}
+		var conflict jobstore.ErrInvalidExecutionVersion
+		if !errors.As(err, &conflict) || attempt >= snapshotUpdateAttempts {
+			log.Ctx(ctx).Error().Err(err).Msgf("[OnSnapshotComplete] failed to update execution")
+			return
+		}
+	}
+}
+
+// snapshotUpdateAttempts is the number of times recording a snapshot is
+// attempted when the execution is updated concurrently.
+const snapshotUpdateAttempts = 5
+
+func (e *BaseEndpoint) recordSnapshot(ctx context.Context, result compute.SnapshotResult) error {
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: result.JobID})
+	if err != nil {
+		return fmt.Errorf("failed to get executions of job %s: %w", result.JobID, err)
+	}
+	index := slices.IndexFunc(executions, func(execution models.Execution) bool {
+		return execution.ID == result.ExecutionID
+	})
+	if index < 0 {
+		return fmt.Errorf("execution %s not found", result.ExecutionID)
+	}
+	execution := executions[index]
+	if execution.NodeID != result.SourcePeerID {
+		return fmt.Errorf("execution %s is not assigned to node %s", result.ExecutionID, result.SourcePeerID)
+	}
+
+	snapshots, err := models.AppendSnapshot(execution.Snapshots, result.Snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to record snapshot of execution %s: %w", result.ExecutionID, err)
+	}
+
+	return e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedRevision: execution.Revision,
+		},
+		NewValues: models.Execution{
+			Snapshots: snapshots,
+		},
+		Comment: fmt.Sprintf("published snapshot %d", result.Snapshot.Version),
+	})
+}
+
+// OnCheckpointComplete records a checkpoint of a running execution, so that
+// the checkpoint can be restored if the execution is rescheduled.
+func (e *BaseEndpoint) OnCheckpointComplete(ctx context.Context, result compute.CheckpointResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received CheckpointComplete for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Checkpoint == nil {
+		return
+	}
+
+	executions, err := e.store.GetExecutions(ctx, jobstore.GetExecutionsOptions{JobID: result.JobID})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnCheckpointComplete] failed to get executions of job %s", result.JobID)
+		return
+	}
+	index := slices.IndexFunc(executions, func(execution models.Execution) bool {
+		return execution.ID == result.ExecutionID
+	})
+	if index < 0 {
+		log.Ctx(ctx).Error().Msgf("[OnCheckpointComplete] execution %s not found", result.ExecutionID)
+		return
+	}
+	execution := executions[index]
+
+	err = e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedRevision: execution.Revision,
+		},
+		NewValues: models.Execution{
+			Checkpoints: execution.AddCheckpoint(result.Checkpoint),
+		},
+		Comment: fmt.Sprintf("published checkpoint %d", result.Checkpoint.Version),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnCheckpointComplete] failed to update execution")
+	}
+}
+
+// OnHealthUpdate records a change in the health of a running execution, and
+// enqueues an evaluation so that the scheduler can replace unhealthy executions.
+func (e *BaseEndpoint) OnHealthUpdate(ctx context.Context, result compute.HealthResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received HealthUpdate for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if result.Health == nil {
+		return
+	}
+
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{models.ExecutionStateBidAccepted},
+		},
+		NewValues: models.Execution{
+			Health: result.Health,
+		},
+		Comment: fmt.Sprintf("execution is %s", strings.ToLower(result.Health.Status.String())),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnHealthUpdate] failed to update execution")
+		return
+	}
+
+	if result.Health.IsUnhealthy() {
+		e.enqueueEvaluation(ctx, result.JobID, "OnHealthUpdate")
+	}
+}
+
+// OnEndpointsAllocated records the endpoints the published ports of a
+// running execution can be reached on.
+func (e *BaseEndpoint) OnEndpointsAllocated(ctx context.Context, result compute.EndpointsResult) {
+	log.Ctx(ctx).Debug().Msgf("Requester node %s received EndpointsAllocated for execution: %s from %s",
+		e.id, result.ExecutionID, result.SourcePeerID)
+	if len(result.Endpoints) == 0 {
+		return
+	}
+
+	err := e.store.UpdateExecution(ctx, jobstore.UpdateExecutionRequest{
+		ExecutionID: result.ExecutionID,
+		Condition: jobstore.UpdateExecutionCondition{
+			ExpectedStates: []models.ExecutionStateType{models.ExecutionStateBidAccepted},
+		},
+		NewValues: models.Execution{
+			Endpoints: result.Endpoints,
+		},
+		Comment: fmt.Sprintf("%d ports published", len(result.Endpoints)),
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msgf("[OnEndpointsAllocated] failed to update execution")
+	}
+}
+
 func (e *BaseEndpoint) OnCancelComplete(ctx context.Context, result compute.CancelResult) {
 	log.Ctx(ctx).Debug().Msgf("Requester node %s received CancelComplete for execution: %s from %s",
 		e.id, result.ExecutionID, result.SourcePeerID)
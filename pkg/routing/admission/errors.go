@@ -0,0 +1,35 @@
+package admission
+
+import (
+	"fmt"
+)
+
+// ErrInvalidTransition is returned when a node cannot be moved to the
+// requested membership or scheduling state from its current state.
+type ErrInvalidTransition struct {
+	nodeID string
+	from   fmt.Stringer
+	to     fmt.Stringer
+}
+
+func NewErrInvalidTransition(nodeID string, from, to fmt.Stringer) ErrInvalidTransition {
+	return ErrInvalidTransition{nodeID: nodeID, from: from, to: to}
+}
+
+func (e ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("node %s cannot be moved from %s to %s", e.nodeID, e.from, e.to)
+}
+
+// ErrPublicKeyMismatch is returned when a node presents a different public key
+// than the one pinned when it first joined.
+type ErrPublicKeyMismatch struct {
+	nodeID string
+}
+
+func NewErrPublicKeyMismatch(nodeID string) ErrPublicKeyMismatch {
+	return ErrPublicKeyMismatch{nodeID: nodeID}
+}
+
+func (e ErrPublicKeyMismatch) Error() string {
+	return fmt.Sprintf("node %s presented a different public key than the one it joined with", e.nodeID)
+}
@@ -0,0 +1,383 @@
+package admission
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+const (
+	// DefaultJoinTokenTTL is how long join tokens are valid for if no TTL is
+	// requested.
+	DefaultJoinTokenTTL = time.Hour
+
+	joinTokenSecretBytes = 32
+	joinTokenIDLength    = 8
+)
+
+type NodeManagerParams struct {
+	// NodeID is the ID of the local node, which is always admitted.
+	NodeID          string
+	NodeInfoStore   routing.NodeInfoStore
+	MembershipStore routing.NodeMembershipStore
+	// ManualApproval requires compute nodes to present a join token or to be
+	// approved by an operator before they are sent work. If false, nodes are
+	// approved when they first connect.
+	ManualApproval bool
+	Clock          clock.Clock
+}
+
+// NodeManager admits nodes into the network. It wraps a routing.NodeInfoStore
+// so that every node info announced by a node is stored along with the
+// membership state of that node, and offers operations to manage that state.
+//
+// Memberships are kept in a routing.NodeMembershipStore, which outlives the
+// node infos themselves, so that a rejected or revoked node that reconnects is
+// not admitted again.
+type NodeManager struct {
+	routing.NodeInfoStore
+	nodeID          string
+	membershipStore routing.NodeMembershipStore
+	manualApproval  bool
+	clock           clock.Clock
+}
+
+func NewNodeManager(params NodeManagerParams) *NodeManager {
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	return &NodeManager{
+		NodeInfoStore:   params.NodeInfoStore,
+		nodeID:          params.NodeID,
+		membershipStore: params.MembershipStore,
+		manualApproval:  params.ManualApproval,
+		clock:           params.Clock,
+	}
+}
+
+// Add resolves the membership of the node before adding its info to the
+// underlying store.
+func (m *NodeManager) Add(ctx context.Context, nodeInfo models.NodeInfo) error {
+	membership, err := m.resolveMembership(ctx, nodeInfo, "")
+	if err != nil {
+		return err
+	}
+	applyMembership(&nodeInfo, membership)
+	return m.NodeInfoStore.Add(ctx, nodeInfo)
+}
+
+// Join admits a pending compute node that presented a join token directly to
+// the orchestrator, and adds its info to the underlying store. The token is
+// never stored.
+func (m *NodeManager) Join(ctx context.Context, request routing.JoinRequest) (routing.JoinResponse, error) {
+	nodeInfo := request.NodeInfo
+	membership, err := m.resolveMembership(ctx, nodeInfo, request.JoinToken)
+	if err != nil {
+		return routing.JoinResponse{}, err
+	}
+	applyMembership(&nodeInfo, membership)
+	if err = m.NodeInfoStore.Add(ctx, nodeInfo); err != nil {
+		return routing.JoinResponse{}, err
+	}
+
+	switch membership.State {
+	case models.NodeMembershipApproved:
+		return routing.JoinResponse{Accepted: true}, nil
+	case models.NodeMembershipPending:
+		return routing.JoinResponse{Reason: "join token is invalid, expired or already used"}, nil
+	default:
+		return routing.JoinResponse{Reason: fmt.Sprintf("node membership is %s", membership.State)}, nil
+	}
+}
+
+func (m *NodeManager) resolveMembership(ctx context.Context, nodeInfo models.NodeInfo, joinToken string) (
+	models.NodeMembership, error) {
+	nodeID := nodeInfo.ID()
+	membership, err := m.membershipStore.GetMembership(ctx, nodeID)
+	var errNotFound routing.ErrMembershipNotFound
+	if err != nil && !errors.As(err, &errNotFound) {
+		return membership, err
+	}
+
+	// the key a node first presents is pinned, so that no other node can take over its ID
+	if membership.PublicKey != "" && nodeInfo.PublicKey != membership.PublicKey {
+		log.Ctx(ctx).Warn().Str("NodeID", nodeID).Msg("node presented a different public key than the one it is known by")
+		return membership, NewErrPublicKeyMismatch(nodeID)
+	}
+	changed := false
+	if membership.PublicKey == "" && nodeInfo.PublicKey != "" {
+		membership.PublicKey = nodeInfo.PublicKey
+		changed = true
+	}
+
+	// the node type is only what the node reports about itself, so a node
+	// approved as a non-compute node is evaluated again once it announces
+	// itself as a compute node
+	if err != nil || membership.State == models.NodeMembershipPending ||
+		(membership.State == models.NodeMembershipApproved && membership.NonCompute && nodeInfo.IsComputeNode()) {
+		state := m.admissionState(ctx, nodeInfo, joinToken)
+		if state != membership.State {
+			if state == models.NodeMembershipPending {
+				log.Ctx(ctx).Info().Str("NodeID", nodeID).Msg("node is pending approval")
+			}
+			membership.State = state
+			changed = true
+		}
+		nonCompute := state == models.NodeMembershipApproved && m.requiresApproval(nodeID) && !nodeInfo.IsComputeNode()
+		if nonCompute != membership.NonCompute {
+			membership.NonCompute = nonCompute
+			changed = true
+		}
+	}
+
+	if !changed {
+		return membership, nil
+	}
+	membership.NodeID = nodeID
+	membership.UpdatedAt = m.clock.Now().UTC()
+	return membership, m.membershipStore.PutMembership(ctx, membership)
+}
+
+// admissionState returns the membership state of a node that is not admitted
+// yet.
+func (m *NodeManager) admissionState(ctx context.Context, nodeInfo models.NodeInfo, joinToken string) models.NodeMembershipState {
+	nodeID := nodeInfo.ID()
+	switch {
+	case !m.requiresApproval(nodeID), !nodeInfo.IsComputeNode():
+		return models.NodeMembershipApproved
+	case joinToken != "":
+		if err := m.consumeJoinToken(ctx, joinToken); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("NodeID", nodeID).Msg("node presented an invalid join token")
+			return models.NodeMembershipPending
+		}
+		log.Ctx(ctx).Info().Str("NodeID", nodeID).Msg("node admitted with a join token")
+		return models.NodeMembershipApproved
+	}
+	return models.NodeMembershipPending
+}
+
+// requiresApproval returns true if the node needs a join token or an
+// operator's approval to be admitted as a compute node.
+func (m *NodeManager) requiresApproval(nodeID string) bool {
+	return m.manualApproval && nodeID != m.nodeID
+}
+
+func (m *NodeManager) consumeJoinToken(ctx context.Context, secret string) error {
+	token, err := m.membershipStore.ConsumeJoinToken(ctx, hashJoinToken(secret))
+	if err != nil {
+		return err
+	}
+	if token.IsExpired(m.clock.Now()) {
+		return routing.ErrJoinTokenExpired
+	}
+	return nil
+}
+
+// CreateJoinToken creates a new single-use join token that is valid for the
+// given duration. It returns the token secret, which must be passed to the
+// compute node, and the token record that is stored.
+func (m *NodeManager) CreateJoinToken(ctx context.Context, ttl time.Duration) (string, models.JoinToken, error) {
+	if ttl <= 0 {
+		ttl = DefaultJoinTokenTTL
+	}
+
+	secretBytes := make([]byte, joinTokenSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", models.JoinToken{}, fmt.Errorf("failed to generate join token: %w", err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	now := m.clock.Now().UTC()
+	hash := hashJoinToken(secret)
+	token := models.JoinToken{
+		ID:        hash[:joinTokenIDLength],
+		Hash:      hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := m.membershipStore.AddJoinToken(ctx, token); err != nil {
+		return "", models.JoinToken{}, err
+	}
+	return secret, token, nil
+}
+
+// ApproveNode allows a pending, rejected or revoked node to receive work.
+func (m *NodeManager) ApproveNode(ctx context.Context, nodeID string) error {
+	return m.transition(ctx, nodeID, models.NodeMembershipApproved,
+		models.NodeMembershipPending, models.NodeMembershipRejected, models.NodeMembershipRevoked)
+}
+
+// RejectNode refuses to admit a pending node.
+func (m *NodeManager) RejectNode(ctx context.Context, nodeID string) error {
+	return m.transition(ctx, nodeID, models.NodeMembershipRejected, models.NodeMembershipPending)
+}
+
+// RevokeNode stops an approved node from receiving any more work.
+func (m *NodeManager) RevokeNode(ctx context.Context, nodeID string) error {
+	return m.transition(ctx, nodeID, models.NodeMembershipRevoked, models.NodeMembershipApproved)
+}
+
+func (m *NodeManager) transition(
+	ctx context.Context, nodeID string, to models.NodeMembershipState, from ...models.NodeMembershipState) error {
+	if nodeID == m.nodeID {
+		return fmt.Errorf("cannot change the membership of the orchestrator node %s", nodeID)
+	}
+
+	return m.update(ctx, nodeID, func(membership *models.NodeMembership) error {
+		if !slices.Contains(from, membership.State) {
+			return NewErrInvalidTransition(nodeID, membership.State, to)
+		}
+		membership.State = to
+		membership.NonCompute = false
+		log.Ctx(ctx).Info().Str("NodeID", nodeID).Stringer("Membership", to).Msg("node membership changed")
+		return nil
+	})
+}
+
+// CordonNode stops new work from being placed on a node. Executions already
+// running on the node are not affected.
+func (m *NodeManager) CordonNode(ctx context.Context, nodeID string) error {
+	return m.transitionScheduling(ctx, nodeID, models.NodeCordoned, time.Time{}, models.NodeSchedulable)
+}
+
+// UncordonNode allows new work to be placed on a cordoned, draining or
+// drained node again.
+func (m *NodeManager) UncordonNode(ctx context.Context, nodeID string) error {
+	return m.transitionScheduling(ctx, nodeID, models.NodeSchedulable, time.Time{},
+		models.NodeCordoned, models.NodeDraining, models.NodeDrained)
+}
+
+// DrainNode cordons a node and moves work away from it. Executions that are
+// still running on the node at the deadline are stopped.
+func (m *NodeManager) DrainNode(ctx context.Context, nodeID string, deadline time.Time) error {
+	return m.transitionScheduling(ctx, nodeID, models.NodeDraining, deadline,
+		models.NodeSchedulable, models.NodeCordoned)
+}
+
+// MarkDrained records that a draining node has no executions left.
+func (m *NodeManager) MarkDrained(ctx context.Context, nodeID string) error {
+	return m.transitionScheduling(ctx, nodeID, models.NodeDrained, time.Time{}, models.NodeDraining)
+}
+
+// DrainingNodes returns the memberships of the nodes that are being drained.
+func (m *NodeManager) DrainingNodes(ctx context.Context) ([]models.NodeMembership, error) {
+	memberships, err := m.membershipStore.ListMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var draining []models.NodeMembership
+	for _, membership := range memberships {
+		if membership.Scheduling == models.NodeDraining {
+			draining = append(draining, membership)
+		}
+	}
+	return draining, nil
+}
+
+func (m *NodeManager) transitionScheduling(ctx context.Context, nodeID string,
+	to models.NodeSchedulingState, deadline time.Time, from ...models.NodeSchedulingState) error {
+	return m.update(ctx, nodeID, func(membership *models.NodeMembership) error {
+		if !slices.Contains(from, membership.Scheduling) {
+			return NewErrInvalidTransition(nodeID, membership.Scheduling, to)
+		}
+		membership.Scheduling = to
+		membership.DrainDeadline = deadline
+		log.Ctx(ctx).Info().Str("NodeID", nodeID).Stringer("Scheduling", to).Msg("node scheduling changed")
+		return nil
+	})
+}
+
+// update applies a change to the membership of a node. The change is applied
+// to the node info when it is read, so it takes effect immediately rather than
+// on the next announcement from the node.
+func (m *NodeManager) update(
+	ctx context.Context, nodeID string, mutate func(membership *models.NodeMembership) error) error {
+	membership, err := m.membershipStore.GetMembership(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	if err = mutate(&membership); err != nil {
+		return err
+	}
+	membership.UpdatedAt = m.clock.Now().UTC()
+	return m.membershipStore.PutMembership(ctx, membership)
+}
+
+// Get returns the node info with its current membership.
+func (m *NodeManager) Get(ctx context.Context, nodeID string) (models.NodeInfo, error) {
+	nodeInfo, err := m.NodeInfoStore.Get(ctx, nodeID)
+	if err != nil {
+		return nodeInfo, err
+	}
+	return m.withMembership(ctx, nodeInfo)
+}
+
+// GetByPrefix returns the node info with its current membership.
+func (m *NodeManager) GetByPrefix(ctx context.Context, prefix string) (models.NodeInfo, error) {
+	nodeInfo, err := m.NodeInfoStore.GetByPrefix(ctx, prefix)
+	if err != nil {
+		return nodeInfo, err
+	}
+	return m.withMembership(ctx, nodeInfo)
+}
+
+// List returns the node infos with their current memberships.
+func (m *NodeManager) List(ctx context.Context) ([]models.NodeInfo, error) {
+	nodeInfos, err := m.NodeInfoStore.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := m.membershipStore.ListMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byNodeID := make(map[string]models.NodeMembership, len(memberships))
+	for _, membership := range memberships {
+		byNodeID[membership.NodeID] = membership
+	}
+	for i := range nodeInfos {
+		if membership, ok := byNodeID[nodeInfos[i].ID()]; ok {
+			applyMembership(&nodeInfos[i], membership)
+		}
+	}
+	return nodeInfos, nil
+}
+
+func (m *NodeManager) withMembership(ctx context.Context, nodeInfo models.NodeInfo) (models.NodeInfo, error) {
+	membership, err := m.membershipStore.GetMembership(ctx, nodeInfo.ID())
+	if err != nil {
+		var errNotFound routing.ErrMembershipNotFound
+		if errors.As(err, &errNotFound) {
+			return nodeInfo, nil
+		}
+		return nodeInfo, err
+	}
+	applyMembership(&nodeInfo, membership)
+	return nodeInfo, nil
+}
+
+func applyMembership(nodeInfo *models.NodeInfo, membership models.NodeMembership) {
+	nodeInfo.Membership = membership.State
+	nodeInfo.Scheduling = membership.Scheduling
+	nodeInfo.PublicKey = membership.PublicKey
+}
+
+func hashJoinToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// compile time check that we implement the interface
+var _ routing.NodeInfoStore = (*NodeManager)(nil)
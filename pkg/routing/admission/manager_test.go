@@ -0,0 +1,250 @@
+//go:build unit || !integration
+
+package admission
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/boltdb"
+	"github.com/bacalhau-project/bacalhau/pkg/routing/inmemory"
+)
+
+const (
+	orchestratorID = "QmdZQ7ZbhnvWY1J12XYKGHApJ6aufKyLNSvf8jZBrBaAVL"
+	computeID      = "QmXaXu9N5GNetatsvwnTfQqNtSeKAD6uCmarbh3LMRYAcF"
+)
+
+type NodeManagerSuite struct {
+	suite.Suite
+	ctx             context.Context
+	clock           *clock.Mock
+	membershipStore *boltdb.MembershipStore
+	manager         *NodeManager
+}
+
+func TestNodeManagerSuite(t *testing.T) {
+	suite.Run(t, new(NodeManagerSuite))
+}
+
+func (s *NodeManagerSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Now())
+
+	var err error
+	s.membershipStore, err = boltdb.NewMembershipStore(filepath.Join(s.T().TempDir(), "nodes.db"))
+	s.Require().NoError(err)
+	s.manager = s.newManager(true)
+}
+
+func (s *NodeManagerSuite) TearDownTest() {
+	s.NoError(s.membershipStore.Close(s.ctx))
+}
+
+func (s *NodeManagerSuite) newManager(manualApproval bool) *NodeManager {
+	return NewNodeManager(NodeManagerParams{
+		NodeID:          orchestratorID,
+		NodeInfoStore:   inmemory.NewNodeInfoStore(inmemory.NodeInfoStoreParams{TTL: time.Hour}),
+		MembershipStore: s.membershipStore,
+		ManualApproval:  manualApproval,
+		Clock:           s.clock,
+	})
+}
+
+// addComputeNode announces the compute node, or has it join with the token if one is given.
+func (s *NodeManagerSuite) addComputeNode(joinToken string) models.NodeInfo {
+	info := models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeCompute}
+	if joinToken == "" {
+		s.Require().NoError(s.manager.Add(s.ctx, info))
+	} else {
+		_, err := s.manager.Join(s.ctx, routing.JoinRequest{NodeInfo: info, JoinToken: joinToken})
+		s.Require().NoError(err)
+	}
+	nodeInfo, err := s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	return nodeInfo
+}
+
+func (s *NodeManagerSuite) TestNodesAreApprovedWithoutManualApproval() {
+	s.manager = s.newManager(false)
+	s.Equal(models.NodeMembershipApproved, s.addComputeNode("").Membership)
+}
+
+func (s *NodeManagerSuite) TestOrchestratorIsAlwaysApproved() {
+	s.Require().NoError(s.manager.Add(s.ctx, models.NodeInfo{NodeID: orchestratorID, NodeType: models.NodeTypeCompute}))
+	nodeInfo, err := s.manager.Get(s.ctx, orchestratorID)
+	s.Require().NoError(err)
+	s.Equal(models.NodeMembershipApproved, nodeInfo.Membership)
+	s.Error(s.manager.RevokeNode(s.ctx, orchestratorID))
+}
+
+func (s *NodeManagerSuite) TestNodeIsPendingUntilApproved() {
+	nodeInfo := s.addComputeNode("")
+	s.Equal(models.NodeMembershipPending, nodeInfo.Membership)
+	s.False(nodeInfo.IsAdmitted())
+
+	s.Require().NoError(s.manager.ApproveNode(s.ctx, computeID))
+	nodeInfo, err := s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	s.Equal(models.NodeMembershipApproved, nodeInfo.Membership)
+
+	// the approval is kept when the node announces itself again
+	s.Equal(models.NodeMembershipApproved, s.addComputeNode("").Membership)
+}
+
+func (s *NodeManagerSuite) TestNonComputeApprovalIsReevaluated() {
+	s.Require().NoError(s.manager.Add(s.ctx, models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeRequester}))
+	nodeInfo, err := s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	s.Equal(models.NodeMembershipApproved, nodeInfo.Membership)
+
+	// the approval does not carry over once the node announces itself as a compute node
+	s.Equal(models.NodeMembershipPending, s.addComputeNode("").Membership)
+
+	s.Require().NoError(s.manager.ApproveNode(s.ctx, computeID))
+	s.Require().NoError(s.manager.Add(s.ctx, models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeRequester}))
+	s.Equal(models.NodeMembershipApproved, s.addComputeNode("").Membership)
+}
+
+func (s *NodeManagerSuite) TestPublicKeyIsPinned() {
+	s.manager = s.newManager(false)
+	info := models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeCompute, PublicKey: "key-1"}
+	s.Require().NoError(s.manager.Add(s.ctx, info))
+
+	// the registry reports the pinned key
+	nodeInfo, err := s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	s.Equal("key-1", nodeInfo.PublicKey)
+
+	// another node cannot take over the node ID with a different key
+	info.PublicKey = "key-2"
+	s.ErrorAs(s.manager.Add(s.ctx, info), &ErrPublicKeyMismatch{})
+	_, err = s.manager.Join(s.ctx, routing.JoinRequest{NodeInfo: info})
+	s.ErrorAs(err, &ErrPublicKeyMismatch{})
+
+	nodeInfo, err = s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	s.Equal("key-1", nodeInfo.PublicKey)
+}
+
+func (s *NodeManagerSuite) TestJoinTokenAdmitsNodeOnce() {
+	secret, token, err := s.manager.CreateJoinToken(s.ctx, time.Minute)
+	s.Require().NoError(err)
+	s.Equal(s.clock.Now().UTC().Add(time.Minute), token.ExpiresAt)
+
+	// the node announces itself before it joins
+	s.Equal(models.NodeMembershipPending, s.addComputeNode("").Membership)
+
+	response, err := s.manager.Join(s.ctx, routing.JoinRequest{
+		NodeInfo:  models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeCompute},
+		JoinToken: secret,
+	})
+	s.Require().NoError(err)
+	s.True(response.Accepted)
+	s.Equal(models.NodeMembershipApproved, s.addComputeNode("").Membership)
+
+	_, err = s.membershipStore.ConsumeJoinToken(s.ctx, hashJoinToken(secret))
+	s.ErrorIs(err, routing.ErrJoinTokenNotFound)
+}
+
+func (s *NodeManagerSuite) TestJoinWithUnknownTokenIsRefused() {
+	response, err := s.manager.Join(s.ctx, routing.JoinRequest{
+		NodeInfo:  models.NodeInfo{NodeID: computeID, NodeType: models.NodeTypeCompute},
+		JoinToken: "unknown",
+	})
+	s.Require().NoError(err)
+	s.False(response.Accepted)
+	s.NotEmpty(response.Reason)
+}
+
+func (s *NodeManagerSuite) TestExpiredJoinTokenIsRejected() {
+	secret, _, err := s.manager.CreateJoinToken(s.ctx, time.Minute)
+	s.Require().NoError(err)
+	s.clock.Add(2 * time.Minute)
+
+	s.Equal(models.NodeMembershipPending, s.addComputeNode(secret).Membership)
+}
+
+func (s *NodeManagerSuite) TestRejectedNodeIsNotAdmittedByJoinToken() {
+	s.addComputeNode("")
+	s.Require().NoError(s.manager.RejectNode(s.ctx, computeID))
+
+	secret, _, err := s.manager.CreateJoinToken(s.ctx, time.Minute)
+	s.Require().NoError(err)
+	s.Equal(models.NodeMembershipRejected, s.addComputeNode(secret).Membership)
+}
+
+func (s *NodeManagerSuite) TestRevokedMembershipSurvivesRestart() {
+	s.manager = s.newManager(false)
+	s.addComputeNode("")
+	s.Require().NoError(s.manager.RevokeNode(s.ctx, computeID))
+
+	// a new manager with an empty node info store still knows the node is revoked
+	s.manager = s.newManager(false)
+	s.Equal(models.NodeMembershipRevoked, s.addComputeNode("").Membership)
+}
+
+func (s *NodeManagerSuite) TestInvalidTransitions() {
+	s.addComputeNode("")
+	s.IsType(ErrInvalidTransition{}, s.manager.RevokeNode(s.ctx, computeID))
+
+	s.Require().NoError(s.manager.ApproveNode(s.ctx, computeID))
+	s.IsType(ErrInvalidTransition{}, s.manager.ApproveNode(s.ctx, computeID))
+	s.IsType(ErrInvalidTransition{}, s.manager.RejectNode(s.ctx, computeID))
+
+	s.IsType(routing.ErrMembershipNotFound{}, s.manager.ApproveNode(s.ctx, "unknown"))
+}
+
+func (s *NodeManagerSuite) TestCordonAndUncordon() {
+	s.manager = s.newManager(false)
+	s.True(s.addComputeNode("").IsSchedulable())
+
+	s.Require().NoError(s.manager.CordonNode(s.ctx, computeID))
+	nodeInfo, err := s.manager.Get(s.ctx, computeID)
+	s.Require().NoError(err)
+	s.Equal(models.NodeCordoned, nodeInfo.Scheduling)
+	s.False(nodeInfo.IsSchedulable())
+	s.IsType(ErrInvalidTransition{}, s.manager.CordonNode(s.ctx, computeID))
+
+	// the node stays cordoned when it announces itself again
+	s.Equal(models.NodeCordoned, s.addComputeNode("").Scheduling)
+
+	s.Require().NoError(s.manager.UncordonNode(s.ctx, computeID))
+	s.True(s.addComputeNode("").IsSchedulable())
+	s.IsType(ErrInvalidTransition{}, s.manager.UncordonNode(s.ctx, computeID))
+}
+
+func (s *NodeManagerSuite) TestDrain() {
+	s.manager = s.newManager(false)
+	s.addComputeNode("")
+	s.IsType(ErrInvalidTransition{}, s.manager.MarkDrained(s.ctx, computeID))
+
+	deadline := s.clock.Now().UTC().Add(time.Minute)
+	s.Require().NoError(s.manager.DrainNode(s.ctx, computeID, deadline))
+	s.Equal(models.NodeDraining, s.addComputeNode("").Scheduling)
+
+	draining, err := s.manager.DrainingNodes(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(draining, 1)
+	s.Equal(computeID, draining[0].NodeID)
+	s.True(deadline.Equal(draining[0].DrainDeadline))
+
+	s.Require().NoError(s.manager.MarkDrained(s.ctx, computeID))
+	s.Equal(models.NodeDrained, s.addComputeNode("").Scheduling)
+	s.IsType(ErrInvalidTransition{}, s.manager.DrainNode(s.ctx, computeID, deadline))
+
+	draining, err = s.manager.DrainingNodes(s.ctx)
+	s.Require().NoError(err)
+	s.Empty(draining)
+
+	s.Require().NoError(s.manager.UncordonNode(s.ctx, computeID))
+	s.True(s.addComputeNode("").IsSchedulable())
+}
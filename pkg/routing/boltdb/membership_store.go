@@ -0,0 +1,161 @@
+package boltdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	BucketMemberships = "memberships"
+	BucketJoinTokens  = "join_tokens"
+
+	defaultDatabasePermissions = 0600
+)
+
+// MembershipStore is a routing.NodeMembershipStore backed by a boltdb
+// database on disk, so that the admission state of nodes survives restarts of
+// the orchestrator.
+//
+// The schema (<key> {json-value}) looks like the following:
+//
+// memberships
+//
+//	|--> <node-id> -> {models.NodeMembership}
+//
+// join_tokens
+//
+//	|--> <token-hash> -> {models.JoinToken}
+type MembershipStore struct {
+	database   *bolt.DB
+	marshaller marshaller.Marshaller
+}
+
+// NewMembershipStore opens, or creates, the database at the given path.
+func NewMembershipStore(dbPath string) (*MembershipStore, error) {
+	database, err := bolt.Open(dbPath, defaultDatabasePermissions, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node membership database at %s: %w", dbPath, err)
+	}
+
+	err = database.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{BucketMemberships, BucketJoinTokens} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating database structure: %w", err)
+	}
+
+	return &MembershipStore{
+		database:   database,
+		marshaller: marshaller.NewJSONMarshaller(),
+	}, nil
+}
+
+// GetMembership implements routing.NodeMembershipStore
+func (s *MembershipStore) GetMembership(ctx context.Context, nodeID string) (models.NodeMembership, error) {
+	var membership models.NodeMembership
+	err := s.database.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketMemberships)).Get([]byte(nodeID))
+		if data == nil {
+			return routing.NewErrMembershipNotFound(nodeID)
+		}
+		return s.marshaller.Unmarshal(data, &membership)
+	})
+	return membership, err
+}
+
+// PutMembership implements routing.NodeMembershipStore
+func (s *MembershipStore) PutMembership(ctx context.Context, membership models.NodeMembership) error {
+	data, err := s.marshaller.Marshal(membership)
+	if err != nil {
+		return err
+	}
+	return s.database.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketMemberships)).Put([]byte(membership.NodeID), data)
+	})
+}
+
+// ListMemberships implements routing.NodeMembershipStore
+func (s *MembershipStore) ListMemberships(ctx context.Context) ([]models.NodeMembership, error) {
+	var memberships []models.NodeMembership
+	err := s.database.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketMemberships)).ForEach(func(_, data []byte) error {
+			var membership models.NodeMembership
+			if err := s.marshaller.Unmarshal(data, &membership); err != nil {
+				return err
+			}
+			memberships = append(memberships, membership)
+			return nil
+		})
+	})
+	return memberships, err
+}
+
+// AddJoinToken implements routing.NodeMembershipStore. Tokens that expired
+// before the new token was created are removed at the same time.
+func (s *MembershipStore) AddJoinToken(ctx context.Context, token models.JoinToken) error {
+	data, err := s.marshaller.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketJoinTokens))
+
+		var expired [][]byte
+		err := bucket.ForEach(func(key, data []byte) error {
+			var existing models.JoinToken
+			if err := s.marshaller.Unmarshal(data, &existing); err != nil {
+				return err
+			}
+			if existing.IsExpired(token.CreatedAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err = bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return bucket.Put([]byte(token.Hash), data)
+	})
+}
+
+// ConsumeJoinToken implements routing.NodeMembershipStore
+func (s *MembershipStore) ConsumeJoinToken(ctx context.Context, hash string) (models.JoinToken, error) {
+	var token models.JoinToken
+	err := s.database.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketJoinTokens))
+		data := bucket.Get([]byte(hash))
+		if data == nil {
+			return routing.ErrJoinTokenNotFound
+		}
+		if err := s.marshaller.Unmarshal(data, &token); err != nil {
+			return err
+		}
+		return bucket.Delete([]byte(hash))
+	})
+	return token, err
+}
+
+// Close closes the underlying database.
+func (s *MembershipStore) Close(ctx context.Context) error {
+	return s.database.Close()
+}
+
+// compile time check that we implement the interface
+var _ routing.NodeMembershipStore = (*MembershipStore)(nil)
@@ -0,0 +1,105 @@
+//go:build unit || !integration
+
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+type MembershipStoreSuite struct {
+	suite.Suite
+	ctx    context.Context
+	dbPath string
+	store  *MembershipStore
+}
+
+func (s *MembershipStoreSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.dbPath = filepath.Join(s.T().TempDir(), "nodes.db")
+
+	var err error
+	s.store, err = NewMembershipStore(s.dbPath)
+	s.Require().NoError(err)
+}
+
+func (s *MembershipStoreSuite) TearDownTest() {
+	s.NoError(s.store.Close(s.ctx))
+}
+
+func TestMembershipStoreSuite(t *testing.T) {
+	suite.Run(t, new(MembershipStoreSuite))
+}
+
+func (s *MembershipStoreSuite) TestPutAndGetMembership() {
+	membership := models.NodeMembership{
+		NodeID:    "QmdZQ7ZbhnvWY1J12XYKGHApJ6aufKyLNSvf8jZBrBaAVL",
+		State:     models.NodeMembershipPending,
+		UpdatedAt: time.Now().UTC(),
+	}
+	s.Require().NoError(s.store.PutMembership(s.ctx, membership))
+
+	res, err := s.store.GetMembership(s.ctx, membership.NodeID)
+	s.Require().NoError(err)
+	s.Equal(membership.State, res.State)
+	s.True(membership.UpdatedAt.Equal(res.UpdatedAt))
+
+	membership.State = models.NodeMembershipApproved
+	s.Require().NoError(s.store.PutMembership(s.ctx, membership))
+	memberships, err := s.store.ListMemberships(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(memberships, 1)
+	s.Equal(models.NodeMembershipApproved, memberships[0].State)
+}
+
+func (s *MembershipStoreSuite) TestGetMembershipNotFound() {
+	_, err := s.store.GetMembership(s.ctx, "unknown")
+	s.IsType(routing.ErrMembershipNotFound{}, err)
+}
+
+func (s *MembershipStoreSuite) TestMembershipsArePersisted() {
+	membership := models.NodeMembership{NodeID: "QmXaXu9N", State: models.NodeMembershipRevoked}
+	s.Require().NoError(s.store.PutMembership(s.ctx, membership))
+	s.Require().NoError(s.store.Close(s.ctx))
+
+	var err error
+	s.store, err = NewMembershipStore(s.dbPath)
+	s.Require().NoError(err)
+	res, err := s.store.GetMembership(s.ctx, membership.NodeID)
+	s.Require().NoError(err)
+	s.Equal(models.NodeMembershipRevoked, res.State)
+}
+
+func (s *MembershipStoreSuite) TestJoinTokensAreSingleUse() {
+	now := time.Now().UTC()
+	token := models.JoinToken{ID: "abc", Hash: "abcdef", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	s.Require().NoError(s.store.AddJoinToken(s.ctx, token))
+
+	res, err := s.store.ConsumeJoinToken(s.ctx, token.Hash)
+	s.Require().NoError(err)
+	s.Equal(token.ID, res.ID)
+
+	_, err = s.store.ConsumeJoinToken(s.ctx, token.Hash)
+	s.ErrorIs(err, routing.ErrJoinTokenNotFound)
+}
+
+func (s *MembershipStoreSuite) TestAddJoinTokenRemovesExpiredTokens() {
+	now := time.Now().UTC()
+	expired := models.JoinToken{ID: "old", Hash: "old", CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	s.Require().NoError(s.store.AddJoinToken(s.ctx, expired))
+
+	token := models.JoinToken{ID: "new", Hash: "new", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	s.Require().NoError(s.store.AddJoinToken(s.ctx, token))
+
+	_, err := s.store.ConsumeJoinToken(s.ctx, expired.Hash)
+	s.ErrorIs(err, routing.ErrJoinTokenNotFound)
+	_, err = s.store.ConsumeJoinToken(s.ctx, token.Hash)
+	s.NoError(err)
+}
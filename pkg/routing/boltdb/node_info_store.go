@@ -0,0 +1,319 @@
+package boltdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog/log"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/bacalhau-project/bacalhau/pkg/lib/marshaller"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/math"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+const (
+	BucketNodes = "nodes"
+
+	DefaultSuspectAfter          = 90 * time.Second
+	DefaultDisconnectedAfter     = 10 * time.Minute
+	DefaultGoneAfter             = 24 * time.Hour
+	DefaultLivenessCheckInterval = 15 * time.Second
+
+	// maxLivenessHistory is the number of liveness transitions kept for each
+	// node, with older transitions being dropped first.
+	maxLivenessHistory = 10
+)
+
+type NodeInfoStoreParams struct {
+	// SuspectAfter is how long after it was last seen a node becomes suspect.
+	SuspectAfter time.Duration
+	// DisconnectedAfter is how long after it was last seen a node becomes
+	// disconnected.
+	DisconnectedAfter time.Duration
+	// GoneAfter is how long after it was last seen a node is considered gone.
+	GoneAfter time.Duration
+	// LivenessCheckInterval is how often the liveness of nodes is updated.
+	LivenessCheckInterval time.Duration
+	Clock                 clock.Clock
+}
+
+// NodeInfoStore is a routing.NodeInfoStore backed by a boltdb database on
+// disk. Unlike the in-memory store, nodes are not forgotten when they stop
+// announcing themselves. Instead, their liveness moves from healthy to
+// suspect, disconnected and finally gone based on when they were last seen,
+// and every transition is recorded in the node info.
+//
+// The schema (<key> {json-value}) looks like the following:
+//
+// nodes
+//
+//	|--> <node-id> -> {models.NodeInfo}
+type NodeInfoStore struct {
+	database   *bolt.DB
+	marshaller marshaller.Marshaller
+	clock      clock.Clock
+
+	suspectAfter      time.Duration
+	disconnectedAfter time.Duration
+	goneAfter         time.Duration
+
+	stopChannel chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewNodeInfoStore opens, or creates, the database at the given path and
+// starts tracking the liveness of the nodes in it.
+func NewNodeInfoStore(dbPath string, params NodeInfoStoreParams) (*NodeInfoStore, error) {
+	if params.DisconnectedAfter <= 0 {
+		params.DisconnectedAfter = DefaultDisconnectedAfter
+	}
+	// keep the default thresholds consistent with a custom disconnect time
+	if params.SuspectAfter <= 0 {
+		params.SuspectAfter = math.Min(DefaultSuspectAfter, params.DisconnectedAfter/2)
+	}
+	if params.GoneAfter <= 0 {
+		params.GoneAfter = math.Max(DefaultGoneAfter, 2*params.DisconnectedAfter)
+	}
+	if params.LivenessCheckInterval <= 0 {
+		params.LivenessCheckInterval = DefaultLivenessCheckInterval
+	}
+	if params.Clock == nil {
+		params.Clock = clock.New()
+	}
+	if params.SuspectAfter >= params.DisconnectedAfter || params.DisconnectedAfter >= params.GoneAfter {
+		return nil, fmt.Errorf("node liveness thresholds must increase: suspect after %s, disconnected after %s, gone after %s",
+			params.SuspectAfter, params.DisconnectedAfter, params.GoneAfter)
+	}
+
+	database, err := bolt.Open(dbPath, defaultDatabasePermissions, &bolt.Options{Timeout: 2 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open node registry database at %s: %w", dbPath, err)
+	}
+	err = database.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketNodes))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating database structure: %w", err)
+	}
+
+	s := &NodeInfoStore{
+		database:          database,
+		marshaller:        marshaller.NewJSONMarshaller(),
+		clock:             params.Clock,
+		suspectAfter:      params.SuspectAfter,
+		disconnectedAfter: params.DisconnectedAfter,
+		goneAfter:         params.GoneAfter,
+		stopChannel:       make(chan struct{}),
+	}
+	go s.livenessBackgroundTask(params.LivenessCheckInterval)
+	return s, nil
+}
+
+// Add implements routing.NodeInfoStore. Adding a node info records that the
+// node has just been seen, and so is healthy.
+func (s *NodeInfoStore) Add(ctx context.Context, nodeInfo models.NodeInfo) error {
+	now := s.clock.Now().UTC()
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketNodes))
+		nodeID := nodeInfo.ID()
+
+		nodeInfo.LastSeen = now
+		nodeInfo.Liveness = models.NodeLivenessHealthy
+		nodeInfo.LivenessHistory = nil
+		if data := bucket.Get([]byte(nodeID)); data != nil {
+			var existing models.NodeInfo
+			if err := s.marshaller.Unmarshal(data, &existing); err != nil {
+				return err
+			}
+			nodeInfo.Liveness = existing.Liveness
+			nodeInfo.LivenessHistory = existing.LivenessHistory
+			if existing.Liveness != models.NodeLivenessHealthy {
+				log.Ctx(ctx).Info().Str("NodeID", nodeID).Stringer("From", existing.Liveness).
+					Msg("node is healthy again")
+				recordTransition(&nodeInfo, models.NodeLivenessHealthy, now)
+			}
+		}
+
+		log.Ctx(ctx).Trace().Msgf("Added node info %+v", nodeInfo)
+		return s.put(bucket, nodeInfo)
+	})
+}
+
+// Get implements routing.NodeInfoStore
+func (s *NodeInfoStore) Get(ctx context.Context, nodeID string) (models.NodeInfo, error) {
+	var nodeInfo models.NodeInfo
+	err := s.database.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketNodes)).Get([]byte(nodeID))
+		if data == nil {
+			return routing.NewErrNodeNotFound(nodeID)
+		}
+		return s.marshaller.Unmarshal(data, &nodeInfo)
+	})
+	return nodeInfo, err
+}
+
+// GetByPrefix implements routing.NodeInfoStore
+func (s *NodeInfoStore) GetByPrefix(ctx context.Context, prefix string) (models.NodeInfo, error) {
+	var nodeInfo models.NodeInfo
+	err := s.database.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketNodes))
+		// we found a node with the exact ID
+		if data := bucket.Get([]byte(prefix)); data != nil {
+			return s.marshaller.Unmarshal(data, &nodeInfo)
+		}
+
+		var nodeIDsWithPrefix []string
+		var found []byte
+		cursor := bucket.Cursor()
+		for key, data := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, data = cursor.Next() {
+			nodeIDsWithPrefix = append(nodeIDsWithPrefix, string(key))
+			found = data
+		}
+		if len(nodeIDsWithPrefix) == 0 {
+			return routing.NewErrNodeNotFound(prefix)
+		}
+		if len(nodeIDsWithPrefix) > 1 {
+			return routing.NewErrMultipleNodesFound(prefix, nodeIDsWithPrefix)
+		}
+		return s.marshaller.Unmarshal(found, &nodeInfo)
+	})
+	return nodeInfo, err
+}
+
+// FindPeer implements routing.NodeInfoStore
+func (s *NodeInfoStore) FindPeer(ctx context.Context, peerID peer.ID) (peer.AddrInfo, error) {
+	nodeInfo, err := s.Get(ctx, peerID.String())
+	if err != nil || nodeInfo.PeerInfo == nil || len(nodeInfo.PeerInfo.Addrs) == 0 {
+		return peer.AddrInfo{}, nil
+	}
+	return *nodeInfo.PeerInfo, nil
+}
+
+// List implements routing.NodeInfoStore
+func (s *NodeInfoStore) List(ctx context.Context) ([]models.NodeInfo, error) {
+	var nodeInfos []models.NodeInfo
+	err := s.database.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketNodes)).ForEach(func(_, data []byte) error {
+			var nodeInfo models.NodeInfo
+			if err := s.marshaller.Unmarshal(data, &nodeInfo); err != nil {
+				return err
+			}
+			nodeInfos = append(nodeInfos, nodeInfo)
+			return nil
+		})
+	})
+	return nodeInfos, err
+}
+
+// Delete implements routing.NodeInfoStore
+func (s *NodeInfoStore) Delete(ctx context.Context, nodeID string) error {
+	return s.database.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketNodes)).Delete([]byte(nodeID))
+	})
+}
+
+func (s *NodeInfoStore) livenessBackgroundTask(interval time.Duration) {
+	ctx := context.Background()
+	ticker := s.clock.Ticker(interval)
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.updateLiveness(ctx); err != nil {
+				log.Ctx(ctx).Err(err).Msg("failed to update node liveness")
+			}
+		case <-s.stopChannel:
+			log.Ctx(ctx).Debug().Msg("stopped node liveness task")
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+// updateLiveness moves nodes that have not been seen recently to the
+// liveness state matching how long ago they were last seen.
+func (s *NodeInfoStore) updateLiveness(ctx context.Context) error {
+	now := s.clock.Now().UTC()
+	return s.database.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketNodes))
+		var changed []models.NodeInfo
+		err := bucket.ForEach(func(_, data []byte) error {
+			var nodeInfo models.NodeInfo
+			if err := s.marshaller.Unmarshal(data, &nodeInfo); err != nil {
+				return err
+			}
+			liveness := s.liveness(now.Sub(nodeInfo.LastSeen))
+			if liveness != nodeInfo.Liveness {
+				log.Ctx(ctx).Info().Str("NodeID", nodeInfo.ID()).Stringer("From", nodeInfo.Liveness).
+					Stringer("To", liveness).Msg("node liveness changed")
+				recordTransition(&nodeInfo, liveness, now)
+				changed = append(changed, nodeInfo)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// buckets must not be modified while iterating over them
+		for _, nodeInfo := range changed {
+			if err = s.put(bucket, nodeInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *NodeInfoStore) liveness(sinceLastSeen time.Duration) models.NodeLiveness {
+	switch {
+	case sinceLastSeen >= s.goneAfter:
+		return models.NodeLivenessGone
+	case sinceLastSeen >= s.disconnectedAfter:
+		return models.NodeLivenessDisconnected
+	case sinceLastSeen >= s.suspectAfter:
+		return models.NodeLivenessSuspect
+	default:
+		return models.NodeLivenessHealthy
+	}
+}
+
+func (s *NodeInfoStore) put(bucket *bolt.Bucket, nodeInfo models.NodeInfo) error {
+	data, err := s.marshaller.Marshal(nodeInfo)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(nodeInfo.ID()), data)
+}
+
+// recordTransition moves the node info to a new liveness state, keeping a
+// bounded history of transitions.
+func recordTransition(nodeInfo *models.NodeInfo, to models.NodeLiveness, now time.Time) {
+	nodeInfo.LivenessHistory = append(nodeInfo.LivenessHistory, models.NodeLivenessTransition{
+		From: nodeInfo.Liveness,
+		To:   to,
+		Time: now,
+	})
+	nodeInfo.Liveness = to
+	if len(nodeInfo.LivenessHistory) > maxLivenessHistory {
+		nodeInfo.LivenessHistory = nodeInfo.LivenessHistory[len(nodeInfo.LivenessHistory)-maxLivenessHistory:]
+	}
+}
+
+// Close stops tracking the liveness of nodes and closes the underlying
+// database.
+func (s *NodeInfoStore) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		s.stopChannel <- struct{}{}
+	})
+	return s.database.Close()
+}
+
+// compile time check that we implement the interface
+var _ routing.NodeInfoStore = (*NodeInfoStore)(nil)
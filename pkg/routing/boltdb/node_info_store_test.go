@@ -0,0 +1,175 @@
+//go:build unit || !integration
+
+package boltdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+const (
+	nodeID1 = "QmdZQ7ZbhnvWY1J12XYKGHApJ6aufKyLNSvf8jZBrBaAVL"
+	nodeID2 = "QmXaXu9N5GNetatsvwnTfQqNtSeKAD6uCmarbh3LMRYAcF"
+	nodeID3 = "QmXaXu9N5GNetatsvwnTfQqNtSeKAD6uCmarbh3LMRYAcG"
+)
+
+type NodeInfoStoreSuite struct {
+	suite.Suite
+	ctx    context.Context
+	clock  *clock.Mock
+	dbPath string
+	store  *NodeInfoStore
+}
+
+func (s *NodeInfoStoreSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.clock = clock.NewMock()
+	s.clock.Set(time.Now())
+	s.dbPath = filepath.Join(s.T().TempDir(), "node_registry.db")
+	s.store = s.newStore()
+}
+
+func (s *NodeInfoStoreSuite) newStore() *NodeInfoStore {
+	store, err := NewNodeInfoStore(s.dbPath, NodeInfoStoreParams{
+		SuspectAfter:      time.Minute,
+		DisconnectedAfter: 5 * time.Minute,
+		GoneAfter:         time.Hour,
+		// updated explicitly by the tests
+		LivenessCheckInterval: 24 * time.Hour,
+		Clock:                 s.clock,
+	})
+	s.Require().NoError(err)
+	return store
+}
+
+func (s *NodeInfoStoreSuite) TearDownTest() {
+	s.NoError(s.store.Close(s.ctx))
+}
+
+func TestNodeInfoStoreSuite(t *testing.T) {
+	suite.Run(t, new(NodeInfoStoreSuite))
+}
+
+func (s *NodeInfoStoreSuite) addNode(nodeID string) {
+	s.Require().NoError(s.store.Add(s.ctx, models.NodeInfo{NodeID: nodeID, NodeType: models.NodeTypeCompute}))
+}
+
+func (s *NodeInfoStoreSuite) get(nodeID string) models.NodeInfo {
+	nodeInfo, err := s.store.Get(s.ctx, nodeID)
+	s.Require().NoError(err)
+	return nodeInfo
+}
+
+func (s *NodeInfoStoreSuite) TestAddAndGet() {
+	s.addNode(nodeID1)
+	nodeInfo := s.get(nodeID1)
+	s.Equal(models.NodeLivenessHealthy, nodeInfo.Liveness)
+	s.Equal(s.clock.Now().UTC(), nodeInfo.LastSeen.UTC())
+	s.Empty(nodeInfo.LivenessHistory)
+
+	_, err := s.store.Get(s.ctx, nodeID2)
+	s.IsType(routing.ErrNodeNotFound{}, err)
+}
+
+func (s *NodeInfoStoreSuite) TestGetByPrefix() {
+	s.addNode(nodeID1)
+	s.addNode(nodeID2)
+	s.addNode(nodeID3)
+
+	nodeInfo, err := s.store.GetByPrefix(s.ctx, "QmdZQ7")
+	s.Require().NoError(err)
+	s.Equal(nodeID1, nodeInfo.ID())
+
+	nodeInfo, err = s.store.GetByPrefix(s.ctx, nodeID2)
+	s.Require().NoError(err)
+	s.Equal(nodeID2, nodeInfo.ID())
+
+	_, err = s.store.GetByPrefix(s.ctx, "QmXaXu9N")
+	s.IsType(routing.ErrMultipleNodesFound{}, err)
+
+	_, err = s.store.GetByPrefix(s.ctx, "QmZ")
+	s.IsType(routing.ErrNodeNotFound{}, err)
+}
+
+func (s *NodeInfoStoreSuite) TestListAndDelete() {
+	s.addNode(nodeID1)
+	s.addNode(nodeID2)
+	nodeInfos, err := s.store.List(s.ctx)
+	s.Require().NoError(err)
+	s.Len(nodeInfos, 2)
+
+	s.Require().NoError(s.store.Delete(s.ctx, nodeID1))
+	nodeInfos, err = s.store.List(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(nodeInfos, 1)
+	s.Equal(nodeID2, nodeInfos[0].ID())
+}
+
+func (s *NodeInfoStoreSuite) TestLivenessTransitions() {
+	s.addNode(nodeID1)
+
+	for _, step := range []struct {
+		advance  time.Duration
+		liveness models.NodeLiveness
+	}{
+		{advance: 30 * time.Second, liveness: models.NodeLivenessHealthy},
+		{advance: time.Minute, liveness: models.NodeLivenessSuspect},
+		{advance: 5 * time.Minute, liveness: models.NodeLivenessDisconnected},
+		{advance: time.Hour, liveness: models.NodeLivenessGone},
+	} {
+		s.clock.Add(step.advance)
+		s.Require().NoError(s.store.updateLiveness(s.ctx))
+		s.Equal(step.liveness, s.get(nodeID1).Liveness)
+	}
+
+	// the node announcing itself again makes it healthy
+	s.addNode(nodeID1)
+	nodeInfo := s.get(nodeID1)
+	s.Equal(models.NodeLivenessHealthy, nodeInfo.Liveness)
+
+	var transitions []models.NodeLiveness
+	for _, transition := range nodeInfo.LivenessHistory {
+		transitions = append(transitions, transition.To)
+	}
+	s.Equal([]models.NodeLiveness{
+		models.NodeLivenessSuspect,
+		models.NodeLivenessDisconnected,
+		models.NodeLivenessGone,
+		models.NodeLivenessHealthy,
+	}, transitions)
+	s.Equal(models.NodeLivenessGone, nodeInfo.LivenessHistory[3].From)
+}
+
+func (s *NodeInfoStoreSuite) TestLivenessHistoryIsBounded() {
+	s.addNode(nodeID1)
+	for i := 0; i < maxLivenessHistory; i++ {
+		s.clock.Add(time.Minute)
+		s.Require().NoError(s.store.updateLiveness(s.ctx))
+		s.addNode(nodeID1)
+	}
+	s.Len(s.get(nodeID1).LivenessHistory, maxLivenessHistory)
+}
+
+func (s *NodeInfoStoreSuite) TestNodesSurviveRestart() {
+	s.addNode(nodeID1)
+	s.Require().NoError(s.store.Close(s.ctx))
+
+	s.store = s.newStore()
+	s.Equal(models.NodeLivenessHealthy, s.get(nodeID1).Liveness)
+}
+
+func (s *NodeInfoStoreSuite) TestThresholdsMustIncrease() {
+	_, err := NewNodeInfoStore(filepath.Join(s.T().TempDir(), "invalid.db"), NodeInfoStoreParams{
+		SuspectAfter:      time.Hour,
+		DisconnectedAfter: time.Minute,
+	})
+	s.Error(err)
+}
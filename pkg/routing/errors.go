@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"errors"
 	"fmt"
 )
 
@@ -33,3 +34,22 @@ func NewErrMultipleNodesFound(nodeIDPrefix string, matchingNodeIDs []string) Err
 func (e ErrMultipleNodesFound) Error() string {
 	return fmt.Errorf("multiple nodes found for nodeID prefix: %s, matching nodeIDs: %v", e.nodeIDPrefix, e.matchingNodeIDs).Error()
 }
+
+// ErrMembershipNotFound is returned when no membership was recorded for a node id
+type ErrMembershipNotFound struct {
+	nodeID string
+}
+
+func NewErrMembershipNotFound(nodeID string) ErrMembershipNotFound {
+	return ErrMembershipNotFound{nodeID: nodeID}
+}
+
+func (e ErrMembershipNotFound) Error() string {
+	return fmt.Errorf("membership not found for nodeID: %s", e.nodeID).Error()
+}
+
+// ErrJoinTokenNotFound is returned when a join token does not exist or has already been used
+var ErrJoinTokenNotFound = errors.New("join token not found or already used")
+
+// ErrJoinTokenExpired is returned when a join token is used after it has expired
+var ErrJoinTokenExpired = errors.New("join token has expired")
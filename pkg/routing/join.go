@@ -0,0 +1,52 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+// DefaultJoinRetryInterval is how long compute nodes wait before presenting
+// their join token again when no orchestrator could be reached.
+const DefaultJoinRetryInterval = 10 * time.Second
+
+type JoinParams struct {
+	Joiner           NodeJoiner
+	NodeInfoProvider models.NodeInfoProvider
+	JoinToken        string
+	RetryInterval    time.Duration
+}
+
+// Join presents the join token of a compute node directly to orchestrators
+// until the node is admitted, so that the token is never gossiped with the
+// node info. It retries while no orchestrator can be reached, and gives up
+// if the token is refused, as join tokens can only be used once.
+func Join(ctx context.Context, params JoinParams) error {
+	if params.RetryInterval == 0 {
+		params.RetryInterval = DefaultJoinRetryInterval
+	}
+	for {
+		response, err := params.Joiner.Join(ctx, JoinRequest{
+			NodeInfo:  params.NodeInfoProvider.GetNodeInfo(ctx),
+			JoinToken: params.JoinToken,
+		})
+		if err == nil {
+			if !response.Accepted {
+				return fmt.Errorf("join token was refused: %s", response.Reason)
+			}
+			log.Ctx(ctx).Info().Msg("node was admitted by the orchestrator")
+			return nil
+		}
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to present join token, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(params.RetryInterval):
+		}
+	}
+}
@@ -21,3 +21,38 @@ type NodeInfoStore interface {
 	// Delete deletes a node info from the repo.
 	Delete(ctx context.Context, nodeID string) error
 }
+
+// NodeMembershipStore persists the admission state of nodes, and the join
+// tokens that can be used to admit them.
+type NodeMembershipStore interface {
+	// GetMembership returns the membership of the given node ID.
+	GetMembership(ctx context.Context, nodeID string) (models.NodeMembership, error)
+	// PutMembership adds or updates the membership of a node.
+	PutMembership(ctx context.Context, membership models.NodeMembership) error
+	// ListMemberships returns the memberships of all known nodes.
+	ListMemberships(ctx context.Context) ([]models.NodeMembership, error)
+	// AddJoinToken stores a new join token.
+	AddJoinToken(ctx context.Context, token models.JoinToken) error
+	// ConsumeJoinToken removes and returns the join token with the given
+	// hash, so that it cannot be used again. It returns ErrJoinTokenNotFound
+	// if there is no such token.
+	ConsumeJoinToken(ctx context.Context, hash string) (models.JoinToken, error)
+}
+
+// JoinRequest is sent by a compute node directly to orchestrators to be
+// admitted with a join token.
+type JoinRequest struct {
+	NodeInfo  models.NodeInfo
+	JoinToken string
+}
+
+// JoinResponse tells a compute node whether it was admitted, and why not.
+type JoinResponse struct {
+	Accepted bool
+	Reason   string
+}
+
+// NodeJoiner admits compute nodes that present a join token.
+type NodeJoiner interface {
+	Join(ctx context.Context, request JoinRequest) (JoinResponse, error)
+}
@@ -0,0 +1,225 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/rs/zerolog/log"
+
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+/*
+Storage provider that supports fetching content from Azure Blob Storage, or
+an emulator such as Azurite.
+
+The storage provider supports downloading:
+- a single blob: az://myContainer/dir/file-001.txt
+- a directory and all its content: az://myContainer/dir/
+- a prefix and all blobs matching the prefix: az://myContainer/dir/file-*
+*/
+
+type blobSummary struct {
+	name  string
+	eTag  *azcore.ETag
+	size  int64
+	isDir bool
+}
+
+type StorageProviderParams struct {
+	ClientProvider *azurehelper.ClientProvider
+}
+
+type StorageProvider struct {
+	clientProvider *azurehelper.ClientProvider
+}
+
+func NewStorage(params StorageProviderParams) *StorageProvider {
+	return &StorageProvider{
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled checks if Azure storage credentials are configured through
+// the AZURE_STORAGE_* environment variables.
+func (s *StorageProvider) IsInstalled(_ context.Context) (bool, error) {
+	return s.clientProvider.IsInstalled(), nil
+}
+
+// HasStorageLocally checks if the requested content is hosted locally.
+func (s *StorageProvider) HasStorageLocally(_ context.Context, _ models.InputSource) (bool, error) {
+	return false, nil
+}
+
+func (s *StorageProvider) GetVolumeSize(ctx context.Context, volume models.InputSource) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.GetVolumeSizeRequestTimeout())
+	defer cancel()
+
+	source, err := azurehelper.DecodeSourceSpec(volume.Source)
+	if err != nil {
+		return 0, err
+	}
+
+	client, err := s.clientProvider.GetClient(source.Account, source.Endpoint)
+	if err != nil {
+		return 0, err
+	}
+	blobs, err := s.explodeKey(ctx, client, source)
+	if err != nil {
+		return 0, err
+	}
+	size := uint64(0)
+	for _, b := range blobs {
+		size += uint64(b.size)
+	}
+	return size, nil
+}
+
+func (s *StorageProvider) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	storageSpec models.InputSource) (storage.StorageVolume, error) {
+	source, err := azurehelper.DecodeSourceSpec(storageSpec.Source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Preparing storage for az://%s/%s", source.Container, source.Key)
+
+	outputDir, err := os.MkdirTemp(storageDirectory, "azure-input-*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	client, err := s.clientProvider.GetClient(source.Account, source.Endpoint)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	blobs, err := s.explodeKey(ctx, client, source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	prefixTokens := strings.Split(sanitizeKey(source.Key), "/")
+	for _, b := range blobs {
+		if err = s.downloadBlob(ctx, client, source, b, outputDir, prefixTokens); err != nil {
+			return storage.StorageVolume{}, err
+		}
+	}
+
+	return storage.StorageVolume{
+		Type:   storage.StorageVolumeConnectorBind,
+		Source: outputDir,
+		Target: storageSpec.Target,
+	}, nil
+}
+
+// downloadBlob downloads a single blob to local disk
+func (s *StorageProvider) downloadBlob(ctx context.Context,
+	client *azblob.Client,
+	source azurehelper.SourceSpec,
+	b blobSummary,
+	parentDir string,
+	prefixTokens []string) error {
+	outputPath := filepath.Join(parentDir, storage.TrimKeyPrefix(b.name, prefixTokens))
+	if b.isDir {
+		return os.MkdirAll(outputPath, models.DownloadFolderPerm)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), models.DownloadFolderPerm); err != nil {
+		return err
+	}
+
+	outputFile, err := os.OpenFile(outputPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, models.DownloadFilePerm)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(outputPath, outputFile)
+
+	log.Ctx(ctx).Debug().Msgf("Downloading az://%s/%s to %s.", source.Container, b.name, outputFile.Name())
+	_, err = client.DownloadFile(ctx, source.Container, b.name, outputFile, &azblob.DownloadFileOptions{
+		AccessConditions: &blob.AccessConditions{
+			ModifiedAccessConditions: &blob.ModifiedAccessConditions{IfMatch: b.eTag},
+		},
+	})
+	return err
+}
+
+func (s *StorageProvider) CleanupStorage(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+	return os.RemoveAll(volume.Source)
+}
+
+func (s *StorageProvider) Upload(_ context.Context, _ string) (models.SpecConfig, error) {
+	return models.SpecConfig{}, fmt.Errorf("not implemented")
+}
+
+// explodeKey returns the blobs a source refers to. Keys that do not end with
+// "/" or "*" refer to a single blob, otherwise all blobs with the key as
+// prefix are returned, optionally filtered by a regex on the rest of their name.
+func (s *StorageProvider) explodeKey(
+	ctx context.Context, client *azblob.Client, source azurehelper.SourceSpec) ([]blobSummary, error) {
+	if source.Key != "" && !strings.HasSuffix(source.Key, "*") && !strings.HasSuffix(source.Key, "/") {
+		props, err := client.ServiceClient().NewContainerClient(source.Container).NewBlobClient(source.Key).
+			GetProperties(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if source.ETag != "" && (props.ETag == nil || string(*props.ETag) != source.ETag) {
+			return nil, fmt.Errorf("etag mismatch for az://%s/%s, expected %s", source.Container, source.Key, source.ETag)
+		}
+		var size int64
+		if props.ContentLength != nil {
+			size = *props.ContentLength
+		}
+		return []blobSummary{{name: source.Key, eTag: props.ETag, size: size}}, nil
+	}
+
+	regex, err := regexp.Compile(source.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	prefix := sanitizeKey(source.Key)
+	res := make([]blobSummary, 0)
+	pager := client.NewListBlobsFlatPager(source.Container, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			name := *item.Name
+			if source.Filter != "" && !regex.MatchString(strings.TrimPrefix(name, prefix)) {
+				continue
+			}
+			summary := blobSummary{name: name, isDir: strings.HasSuffix(name, "/")}
+			if item.Properties != nil {
+				summary.eTag = item.Properties.ETag
+				if item.Properties.ContentLength != nil {
+					summary.size = *item.Properties.ContentLength
+				}
+			}
+			res = append(res, summary)
+		}
+	}
+	return res, nil
+}
+
+func sanitizeKey(key string) string {
+	return strings.TrimSuffix(strings.TrimSpace(key), "*")
+}
+
+// Compile time interface check:
+var _ storage.Storage = (*StorageProvider)(nil)
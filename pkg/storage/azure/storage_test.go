@@ -0,0 +1,105 @@
+//go:build integration || !unit
+
+package azure_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	azurehelper "github.com/bacalhau-project/bacalhau/pkg/azure"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/azure"
+)
+
+// StorageTestSuite runs against Azurite or a real storage account configured
+// through AZURE_STORAGE_CONNECTION_STRING.
+type StorageTestSuite struct {
+	suite.Suite
+	clientProvider *azurehelper.ClientProvider
+	storage        *azure.StorageProvider
+	container      string
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
+
+func (s *StorageTestSuite) SetupSuite() {
+	if os.Getenv(azurehelper.EnvConnectionString) == "" {
+		s.T().Skip("No " + azurehelper.EnvConnectionString + " found")
+	}
+	s.clientProvider = azurehelper.NewClientProvider(azurehelper.DefaultClientProviderParams())
+	s.storage = azure.NewStorage(azure.StorageProviderParams{ClientProvider: s.clientProvider})
+	s.container = "bacalhau-test-" + strings.ToLower(uuid.NewString()[:8])
+
+	client, err := s.clientProvider.GetClient("", "")
+	s.Require().NoError(err)
+	ctx := context.Background()
+	_, err = client.CreateContainer(ctx, s.container, nil)
+	s.Require().NoError(err)
+	for _, name := range []string{"set1/a.csv", "set1/b.txt", "set1/nested/c.csv", "set2/d.csv"} {
+		_, err = client.UploadBuffer(ctx, s.container, name, []byte(name), nil)
+		s.Require().NoError(err)
+	}
+}
+
+func (s *StorageTestSuite) TearDownSuite() {
+	if s.clientProvider == nil {
+		return
+	}
+	client, err := s.clientProvider.GetClient("", "")
+	s.Require().NoError(err)
+	_, _ = client.DeleteContainer(context.Background(), s.container, nil)
+}
+
+func (s *StorageTestSuite) prepare(source azurehelper.SourceSpec) string {
+	volume, err := s.storage.PrepareStorage(context.Background(), s.T().TempDir(), models.InputSource{
+		Source: &models.SpecConfig{Type: models.StorageSourceAzure, Params: source.ToMap()},
+		Target: "/inputs",
+	})
+	s.Require().NoError(err)
+	s.Equal("/inputs", volume.Target)
+	return volume.Source
+}
+
+func (s *StorageTestSuite) TestPrepareSingleBlob() {
+	dir := s.prepare(azurehelper.SourceSpec{Container: s.container, Key: "set1/a.csv"})
+	content, err := os.ReadFile(filepath.Join(dir, "a.csv"))
+	s.Require().NoError(err)
+	s.Equal("set1/a.csv", string(content))
+}
+
+func (s *StorageTestSuite) TestPreparePrefixWithFilter() {
+	dir := s.prepare(azurehelper.SourceSpec{Container: s.container, Key: "set1/", Filter: `.*\.csv$`})
+	s.FileExists(filepath.Join(dir, "a.csv"))
+	s.FileExists(filepath.Join(dir, "nested", "c.csv"))
+	s.NoFileExists(filepath.Join(dir, "b.txt"))
+	s.NoFileExists(filepath.Join(dir, "d.csv"))
+}
+
+func (s *StorageTestSuite) TestGetVolumeSize() {
+	size, err := s.storage.GetVolumeSize(context.Background(), models.InputSource{
+		Source: &models.SpecConfig{
+			Type:   models.StorageSourceAzure,
+			Params: azurehelper.SourceSpec{Container: s.container, Key: "set2/"}.ToMap(),
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(uint64(len("set2/d.csv")), size)
+}
+
+func (s *StorageTestSuite) TestMismatchedETag() {
+	_, err := s.storage.PrepareStorage(context.Background(), s.T().TempDir(), models.InputSource{
+		Source: &models.SpecConfig{
+			Type:   models.StorageSourceAzure,
+			Params: azurehelper.SourceSpec{Container: s.container, Key: "set1/a.csv", ETag: "0x0"}.ToMap(),
+		},
+	})
+	s.Require().Error(err)
+}
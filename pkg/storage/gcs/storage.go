@@ -0,0 +1,229 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gcstorage "cloud.google.com/go/storage"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/api/iterator"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/util/closer"
+)
+
+/*
+Storage provider that supports fetching content from Google Cloud Storage, or
+an emulator such as fake-gcs-server.
+
+The storage provider supports downloading:
+- a single object: gs://myBucket/dir/file-001.txt
+- a directory and all its content: gs://myBucket/dir/
+- a prefix and all objects matching the prefix: gs://myBucket/dir/file-*
+*/
+
+type objectSummary struct {
+	name       string
+	generation int64
+	size       int64
+	isDir      bool
+}
+
+type StorageProviderParams struct {
+	ClientProvider *gcshelper.ClientProvider
+}
+
+type StorageProvider struct {
+	clientProvider *gcshelper.ClientProvider
+}
+
+func NewStorage(params StorageProviderParams) *StorageProvider {
+	return &StorageProvider{
+		clientProvider: params.ClientProvider,
+	}
+}
+
+// IsInstalled checks if Google application default credentials are
+// available, or a storage emulator is configured.
+func (s *StorageProvider) IsInstalled(ctx context.Context) (bool, error) {
+	return s.clientProvider.IsInstalled(ctx), nil
+}
+
+// HasStorageLocally checks if the requested content is hosted locally.
+func (s *StorageProvider) HasStorageLocally(_ context.Context, _ models.InputSource) (bool, error) {
+	return false, nil
+}
+
+func (s *StorageProvider) GetVolumeSize(ctx context.Context, volume models.InputSource) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.GetVolumeSizeRequestTimeout())
+	defer cancel()
+
+	source, err := gcshelper.DecodeSourceSpec(volume.Source)
+	if err != nil {
+		return 0, err
+	}
+
+	client, err := s.clientProvider.GetClient(ctx, source.Endpoint)
+	if err != nil {
+		return 0, err
+	}
+	objects, err := s.explodeKey(ctx, client, source)
+	if err != nil {
+		return 0, err
+	}
+	size := uint64(0)
+	for _, object := range objects {
+		size += uint64(object.size)
+	}
+	return size, nil
+}
+
+func (s *StorageProvider) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	storageSpec models.InputSource) (storage.StorageVolume, error) {
+	source, err := gcshelper.DecodeSourceSpec(storageSpec.Source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	log.Ctx(ctx).Debug().Msgf("Preparing storage for gs://%s/%s", source.Bucket, source.Key)
+
+	outputDir, err := os.MkdirTemp(storageDirectory, "gcs-input-*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	client, err := s.clientProvider.GetClient(ctx, source.Endpoint)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	objects, err := s.explodeKey(ctx, client, source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	prefixTokens := strings.Split(sanitizeKey(source.Key), "/")
+	for _, object := range objects {
+		if err = s.downloadObject(ctx, client, source, object, outputDir, prefixTokens); err != nil {
+			return storage.StorageVolume{}, err
+		}
+	}
+
+	return storage.StorageVolume{
+		Type:   storage.StorageVolumeConnectorBind,
+		Source: outputDir,
+		Target: storageSpec.Target,
+	}, nil
+}
+
+// downloadObject downloads a single object to local disk
+func (s *StorageProvider) downloadObject(ctx context.Context,
+	client *gcstorage.Client,
+	source gcshelper.SourceSpec,
+	object objectSummary,
+	parentDir string,
+	prefixTokens []string) error {
+	outputPath := filepath.Join(parentDir, storage.TrimKeyPrefix(object.name, prefixTokens))
+	if object.isDir {
+		return os.MkdirAll(outputPath, models.DownloadFolderPerm)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), models.DownloadFolderPerm); err != nil {
+		return err
+	}
+	log.Ctx(ctx).Debug().Msgf("Downloading gs://%s/%s generation:%d to %s.",
+		source.Bucket, object.name, object.generation, outputPath)
+	return DownloadObject(ctx, client, source.Bucket, object.name, object.generation, outputPath)
+}
+
+// DownloadObject downloads an object to the given path. A non-zero
+// generation pins the download to that version of the object.
+func DownloadObject(ctx context.Context, client *gcstorage.Client, bucket, name string, generation int64, path string) error {
+	handle := client.Bucket(bucket).Object(name)
+	if generation != 0 {
+		handle = handle.Generation(generation)
+	}
+	reader, err := handle.NewReader(ctx)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(name, reader)
+
+	outputFile, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, models.DownloadFilePerm)
+	if err != nil {
+		return err
+	}
+	defer closer.CloseWithLogOnError(path, outputFile)
+
+	_, err = io.Copy(outputFile, reader)
+	return err
+}
+
+func (s *StorageProvider) CleanupStorage(_ context.Context, _ models.InputSource, volume storage.StorageVolume) error {
+	return os.RemoveAll(volume.Source)
+}
+
+func (s *StorageProvider) Upload(_ context.Context, _ string) (models.SpecConfig, error) {
+	return models.SpecConfig{}, fmt.Errorf("not implemented")
+}
+
+// explodeKey returns the objects a source refers to. Keys that do not end
+// with "/" or "*" refer to a single object, otherwise all objects with the key
+// as prefix are returned, optionally filtered by a regex on the rest of their name.
+func (s *StorageProvider) explodeKey(
+	ctx context.Context, client *gcstorage.Client, source gcshelper.SourceSpec) ([]objectSummary, error) {
+	bucket := client.Bucket(source.Bucket)
+	if source.Key != "" && !strings.HasSuffix(source.Key, "*") && !strings.HasSuffix(source.Key, "/") {
+		handle := bucket.Object(source.Key)
+		if source.Generation != 0 {
+			handle = handle.Generation(source.Generation)
+		}
+		attrs, err := handle.Attrs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []objectSummary{{name: attrs.Name, generation: attrs.Generation, size: attrs.Size}}, nil
+	}
+
+	regex, err := regexp.Compile(source.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex pattern: %w", err)
+	}
+
+	prefix := sanitizeKey(source.Key)
+	res := make([]objectSummary, 0)
+	it := bucket.Objects(ctx, &gcstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if source.Filter != "" && !regex.MatchString(strings.TrimPrefix(attrs.Name, prefix)) {
+			continue
+		}
+		res = append(res, objectSummary{
+			name:       attrs.Name,
+			generation: attrs.Generation,
+			size:       attrs.Size,
+			isDir:      strings.HasSuffix(attrs.Name, "/"),
+		})
+	}
+	return res, nil
+}
+
+func sanitizeKey(key string) string {
+	return strings.TrimSuffix(strings.TrimSpace(key), "*")
+}
+
+// Compile time interface check:
+var _ storage.Storage = (*StorageProvider)(nil)
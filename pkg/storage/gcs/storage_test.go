@@ -0,0 +1,107 @@
+//go:build integration || !unit
+
+package gcs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/api/iterator"
+
+	gcshelper "github.com/bacalhau-project/bacalhau/pkg/gcs"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/gcs"
+)
+
+// StorageTestSuite runs against a storage emulator such as fake-gcs-server
+// configured through STORAGE_EMULATOR_HOST.
+type StorageTestSuite struct {
+	suite.Suite
+	clientProvider *gcshelper.ClientProvider
+	storage        *gcs.StorageProvider
+	bucket         string
+}
+
+func TestStorageTestSuite(t *testing.T) {
+	suite.Run(t, new(StorageTestSuite))
+}
+
+func (s *StorageTestSuite) SetupSuite() {
+	if os.Getenv(gcshelper.EnvEmulatorHost) == "" {
+		s.T().Skip("No " + gcshelper.EnvEmulatorHost + " found")
+	}
+	ctx := context.Background()
+	s.clientProvider = gcshelper.NewClientProvider(gcshelper.DefaultClientProviderParams())
+	s.storage = gcs.NewStorage(gcs.StorageProviderParams{ClientProvider: s.clientProvider})
+	s.bucket = "bacalhau-test-" + strings.ToLower(uuid.NewString()[:8])
+
+	client, err := s.clientProvider.GetClient(ctx, "")
+	s.Require().NoError(err)
+	s.Require().NoError(client.Bucket(s.bucket).Create(ctx, "test-project", nil))
+	for _, name := range []string{"set1/a.csv", "set1/b.txt", "set1/nested/c.csv", "set2/d.csv"} {
+		writer := client.Bucket(s.bucket).Object(name).NewWriter(ctx)
+		_, err = writer.Write([]byte(name))
+		s.Require().NoError(err)
+		s.Require().NoError(writer.Close())
+	}
+}
+
+func (s *StorageTestSuite) TearDownSuite() {
+	if s.clientProvider == nil {
+		return
+	}
+	ctx := context.Background()
+	client, err := s.clientProvider.GetClient(ctx, "")
+	s.Require().NoError(err)
+	it := client.Bucket(s.bucket).Objects(ctx, nil)
+	for {
+		attrs, err := it.Next()
+		if err != nil {
+			s.Require().ErrorIs(err, iterator.Done)
+			break
+		}
+		_ = client.Bucket(s.bucket).Object(attrs.Name).Delete(ctx)
+	}
+	_ = client.Bucket(s.bucket).Delete(ctx)
+}
+
+func (s *StorageTestSuite) prepare(source gcshelper.SourceSpec) string {
+	volume, err := s.storage.PrepareStorage(context.Background(), s.T().TempDir(), models.InputSource{
+		Source: &models.SpecConfig{Type: models.StorageSourceGCS, Params: source.ToMap()},
+		Target: "/inputs",
+	})
+	s.Require().NoError(err)
+	s.Equal("/inputs", volume.Target)
+	return volume.Source
+}
+
+func (s *StorageTestSuite) TestPrepareSingleObject() {
+	dir := s.prepare(gcshelper.SourceSpec{Bucket: s.bucket, Key: "set1/a.csv"})
+	content, err := os.ReadFile(filepath.Join(dir, "a.csv"))
+	s.Require().NoError(err)
+	s.Equal("set1/a.csv", string(content))
+}
+
+func (s *StorageTestSuite) TestPreparePrefixWithFilter() {
+	dir := s.prepare(gcshelper.SourceSpec{Bucket: s.bucket, Key: "set1/", Filter: `.*\.csv$`})
+	s.FileExists(filepath.Join(dir, "a.csv"))
+	s.FileExists(filepath.Join(dir, "nested", "c.csv"))
+	s.NoFileExists(filepath.Join(dir, "b.txt"))
+	s.NoFileExists(filepath.Join(dir, "d.csv"))
+}
+
+func (s *StorageTestSuite) TestGetVolumeSize() {
+	size, err := s.storage.GetVolumeSize(context.Background(), models.InputSource{
+		Source: &models.SpecConfig{
+			Type:   models.StorageSourceGCS,
+			Params: gcshelper.SourceSpec{Bucket: s.bucket, Key: "set2/"}.ToMap(),
+		},
+	})
+	s.Require().NoError(err)
+	s.Equal(uint64(len("set2/d.csv")), size)
+}
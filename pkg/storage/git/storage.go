@@ -0,0 +1,607 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/bacalhau-project/bacalhau/pkg/storage"
+	"github.com/bacalhau-project/bacalhau/pkg/storage/util"
+)
+
+const (
+	gitBinary = "git"
+
+	// DefaultMaxRepositorySize is the largest repository fetched if no size is configured.
+	DefaultMaxRepositorySize uint64 = 1 << 30
+	// DefaultMaxCacheSize is how much of the cache is kept if no size is configured.
+	DefaultMaxCacheSize uint64 = 10 << 30
+	// DefaultFetchTimeout is how long fetching a repository can take if no timeout is configured.
+	DefaultFetchTimeout = 10 * time.Minute
+
+	// sizeCheckInterval is how often the size of a repository being fetched is checked.
+	sizeCheckInterval = time.Second
+)
+
+// inheritedEnv are the variables of the node's environment that git is run
+// with. Everything else, including the node's git configuration, ssh agent
+// and credential helpers, is left out.
+var inheritedEnv = []string{
+	"PATH", "HOME", "TMPDIR",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+}
+
+type StorageProviderParams struct {
+	// CacheDir is where bare mirrors of fetched repositories are kept so
+	// that later jobs using the same repository only fetch what changed.
+	CacheDir string
+	// Secrets resolves the credentials referenced by a source's Secret.
+	Secrets secrets.Resolver
+	// SecretsPolicy sets the secrets jobs can reference, and the repositories they can be sent to.
+	SecretsPolicy *secrets.Policy
+	// SSHKeyFile is the only key used to fetch ssh repositories, if set.
+	SSHKeyFile string
+	// SSHKnownHostsFile is the known hosts file used for ssh repositories, if set.
+	SSHKnownHostsFile string
+	// MaxRepositorySize is the largest repository in bytes that is fetched.
+	MaxRepositorySize uint64
+	// MaxCacheSize is how many bytes of mirrors are kept in CacheDir.
+	MaxCacheSize uint64
+	// FetchTimeout is how long fetching a repository can take.
+	FetchTimeout time.Duration
+}
+
+// StorageProvider checks out git repositories directly onto the compute
+// node, without going through IPFS.
+type StorageProvider struct {
+	cacheDir     string
+	secrets      secrets.Resolver
+	policy       *secrets.Policy
+	sshCommand   string
+	maxRepoSize  uint64
+	maxCacheSize uint64
+	fetchTimeout time.Duration
+
+	mu      sync.Mutex
+	mirrors map[string]*sync.Mutex
+}
+
+func NewStorageProvider(params StorageProviderParams) (*StorageProvider, error) {
+	if params.CacheDir == "" {
+		return nil, fmt.Errorf("git storage requires a cache directory")
+	}
+	if err := os.MkdirAll(params.CacheDir, util.OS_USER_RWX); err != nil {
+		return nil, fmt.Errorf("failed to create git cache directory: %w", err)
+	}
+	// remove clones left behind by a node that stopped while fetching
+	if leftovers, err := filepath.Glob(filepath.Join(params.CacheDir, "clone-*")); err == nil {
+		for _, leftover := range leftovers {
+			_ = os.RemoveAll(leftover)
+		}
+	}
+	sshCommand, err := sshCommand(params.SSHKeyFile, params.SSHKnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+	resolver := params.Secrets
+	if resolver == nil {
+		resolver = secrets.NewEnvResolver()
+	}
+	sp := &StorageProvider{
+		cacheDir:     params.CacheDir,
+		secrets:      resolver,
+		policy:       params.SecretsPolicy,
+		sshCommand:   sshCommand,
+		maxRepoSize:  params.MaxRepositorySize,
+		maxCacheSize: params.MaxCacheSize,
+		fetchTimeout: params.FetchTimeout,
+		mirrors:      make(map[string]*sync.Mutex),
+	}
+	if sp.maxRepoSize == 0 {
+		sp.maxRepoSize = DefaultMaxRepositorySize
+	}
+	if sp.maxCacheSize == 0 {
+		sp.maxCacheSize = DefaultMaxCacheSize
+	}
+	if sp.fetchTimeout == 0 {
+		sp.fetchTimeout = DefaultFetchTimeout
+	}
+	log.Debug().Str("cacheDir", params.CacheDir).Msg("Git storage driver created")
+	return sp, nil
+}
+
+// sshCommand returns the command git runs ssh with. It ignores the ssh
+// configuration and agent of the user running the node, only offers the
+// configured key, and never prompts for unknown host keys or passwords.
+func sshCommand(keyFile, knownHostsFile string) (string, error) {
+	args := []string{"ssh", "-F", "/dev/null",
+		"-o", "IdentitiesOnly=yes", "-o", "IdentityAgent=none",
+		"-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=yes"}
+	if keyFile == "" {
+		args = append(args, "-o", "IdentityFile=none")
+	} else {
+		if _, err := os.Stat(keyFile); err != nil {
+			return "", fmt.Errorf("invalid git ssh key file: %w", err)
+		}
+		args = append(args, "-i", shellQuote(keyFile))
+	}
+	if knownHostsFile != "" {
+		if _, err := os.Stat(knownHostsFile); err != nil {
+			return "", fmt.Errorf("invalid git ssh known hosts file: %w", err)
+		}
+		args = append(args, "-o", shellQuote("UserKnownHostsFile="+knownHostsFile))
+	}
+	return strings.Join(args, " "), nil
+}
+
+// shellQuote quotes s for the shell git runs GIT_SSH_COMMAND with.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (sp *StorageProvider) IsInstalled(context.Context) (bool, error) {
+	if _, err := exec.LookPath(gitBinary); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// HasStorageLocally returns true if the repository is already mirrored on
+// this node and, when a commit is pinned, the mirror already contains it.
+func (sp *StorageProvider) HasStorageLocally(ctx context.Context, spec models.InputSource) (bool, error) {
+	source, err := DecodeSpec(spec.Source)
+	if err != nil {
+		return false, err
+	}
+	mirror := sp.mirrorPath(ctx, source)
+	if _, err = os.Stat(mirror); err != nil {
+		return false, nil
+	}
+	if source.Commit == "" {
+		return true, nil
+	}
+	return hasCommit(ctx, mirror, source.Commit), nil
+}
+
+// GetVolumeSize returns the size of the repository's mirror as an estimate
+// of the space the checkout will need. The mirror is fetched if it is not
+// cached yet, which also warms the cache for PrepareStorage. The fetch is
+// bounded by the volume size request timeout as well as the fetch timeout and
+// the largest repository size.
+func (sp *StorageProvider) GetVolumeSize(ctx context.Context, spec models.InputSource) (uint64, error) {
+	source, err := DecodeSpec(spec.Source)
+	if err != nil {
+		return 0, err
+	}
+	if timeout := config.GetVolumeSizeRequestTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	mirror, unlock := sp.lockMirror(ctx, source)
+	defer unlock()
+	if _, err = os.Stat(mirror); err != nil {
+		if err = sp.syncMirror(ctx, source, mirror); err != nil {
+			return 0, err
+		}
+	}
+	return dirSize(mirror)
+}
+
+func (sp *StorageProvider) PrepareStorage(
+	ctx context.Context,
+	storageDirectory string,
+	storageSpec models.InputSource) (storage.StorageVolume, error) {
+	source, err := DecodeSpec(storageSpec.Source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	// hold the mirror until it is checked out so it cannot be evicted
+	mirror, unlock := sp.lockMirror(ctx, source)
+	defer unlock()
+	if err = sp.syncMirror(ctx, source, mirror); err != nil {
+		return storage.StorageVolume{}, err
+	}
+	commit, err := resolveCommit(ctx, mirror, source)
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+
+	outputPath, err := os.MkdirTemp(storageDirectory, "*")
+	if err != nil {
+		return storage.StorageVolume{}, err
+	}
+	if err = checkout(ctx, mirror, outputPath, commit, source); err != nil {
+		_ = os.RemoveAll(outputPath)
+		return storage.StorageVolume{}, err
+	}
+
+	log.Ctx(ctx).Debug().
+		Str("repo", source.Repo).
+		Str("commit", commit).
+		Str("path", outputPath).
+		Msg("Checked out git repository")
+
+	return storage.StorageVolume{
+		Type:     storage.StorageVolumeConnectorBind,
+		ReadOnly: true,
+		Source:   outputPath,
+		Target:   storageSpec.Target,
+	}, nil
+}
+
+func (sp *StorageProvider) CleanupStorage(
+	ctx context.Context,
+	_ models.InputSource,
+	volume storage.StorageVolume,
+) error {
+	log.Ctx(ctx).Debug().Str("ResultPath", volume.Source).Msg("Cleaning up")
+	return os.RemoveAll(volume.Source)
+}
+
+func (sp *StorageProvider) Upload(context.Context, string) (models.SpecConfig, error) {
+	return models.SpecConfig{}, fmt.Errorf("not implemented")
+}
+
+// mirrorPath returns where the bare mirror of the source's repository is
+// cached. The secret and the namespace it is resolved in are part of the key
+// so that a repository fetched with one set of credentials is never served to
+// a job that references another.
+func (sp *StorageProvider) mirrorPath(ctx context.Context, source Source) string {
+	key := source.Repo
+	if source.Secret != "" {
+		key += "\x00" + secrets.NamespaceFromContext(ctx) + "\x00" + source.Secret
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(sp.cacheDir, hex.EncodeToString(sum[:])+".git")
+}
+
+func (sp *StorageProvider) mirrorLock(mirror string) *sync.Mutex {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	lock, ok := sp.mirrors[mirror]
+	if !ok {
+		lock = &sync.Mutex{}
+		sp.mirrors[mirror] = lock
+	}
+	return lock
+}
+
+// lockMirror locks the source's mirror, returning its path and a function
+// that unlocks it.
+func (sp *StorageProvider) lockMirror(ctx context.Context, source Source) (string, func()) {
+	mirror := sp.mirrorPath(ctx, source)
+	lock := sp.mirrorLock(mirror)
+	lock.Lock()
+	return mirror, lock.Unlock
+}
+
+// syncMirror makes sure the locked mirror of the source exists and is up to
+// date. A mirror that already contains the pinned commit is not fetched
+// again. Other mirrors are evicted afterwards if the cache is too large.
+func (sp *StorageProvider) syncMirror(ctx context.Context, source Source, mirror string) error {
+	if err := sp.fetchMirror(ctx, source, mirror); err != nil {
+		return err
+	}
+	// mark the mirror as recently used
+	now := time.Now()
+	if err := os.Chtimes(mirror, now, now); err != nil {
+		return err
+	}
+	sp.evict(ctx, mirror)
+	return nil
+}
+
+func (sp *StorageProvider) fetchMirror(ctx context.Context, source Source, mirror string) error {
+	env, err := sp.remoteEnv(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(mirror); errors.Is(err, fs.ErrNotExist) {
+		// clone next to the final location and rename, so an interrupted
+		// clone never leaves a partial mirror behind
+		tmp, err := os.MkdirTemp(sp.cacheDir, "clone-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp) //nolint:errcheck
+		if err = sp.runBounded(ctx, "", tmp, env, "clone", "--quiet", "--mirror", "--", source.Repo, tmp); err != nil {
+			return fmt.Errorf("failed to clone %s: %w", redact(source.Repo), err)
+		}
+		// allow checkouts to fetch pinned commits that are not a ref tip
+		if _, err = runGit(ctx, tmp, nil, "config", "uploadpack.allowAnySHA1InWant", "true"); err != nil {
+			return err
+		}
+		return os.Rename(tmp, mirror)
+	} else if err != nil {
+		return err
+	}
+
+	if source.Commit != "" && hasCommit(ctx, mirror, source.Commit) {
+		return nil
+	}
+	if err = sp.runBounded(ctx, mirror, mirror, env, "fetch", "--quiet", "--prune", "origin"); err != nil {
+		if errors.Is(err, errTooLarge) {
+			_ = os.RemoveAll(mirror)
+		}
+		return fmt.Errorf("failed to update %s: %w", redact(source.Repo), err)
+	}
+	return nil
+}
+
+var errTooLarge = errors.New("repository is too large")
+
+// runBounded runs a git command that fetches into repo, stopping it if it
+// takes longer than the fetch timeout or repo grows larger than the largest
+// repository size.
+func (sp *StorageProvider) runBounded(parent context.Context, dir, repo string, env []string, args ...string) error {
+	ctx, cancel := context.WithTimeout(parent, sp.fetchTimeout)
+	defer cancel()
+
+	var tooLarge atomic.Bool
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(sizeCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if size, err := dirSize(repo); err == nil && size > sp.maxRepoSize {
+					tooLarge.Store(true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	_, err := runGit(ctx, dir, env, args...)
+	if err == nil {
+		// the repository can grow beyond the limit between checks
+		if size, sizeErr := dirSize(repo); sizeErr != nil {
+			return sizeErr
+		} else if size > sp.maxRepoSize {
+			tooLarge.Store(true)
+		}
+	}
+	if tooLarge.Load() {
+		return fmt.Errorf("%w: larger than %d bytes", errTooLarge, sp.maxRepoSize)
+	}
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", sp.fetchTimeout, err)
+	}
+	return err
+}
+
+// evict removes the least recently used mirrors until the cache is no larger
+// than its maximum size. Mirrors that are in use, including keep, are never
+// removed.
+func (sp *StorageProvider) evict(ctx context.Context, keep string) {
+	paths, err := filepath.Glob(filepath.Join(sp.cacheDir, "*.git"))
+	if err != nil {
+		return
+	}
+	type cached struct {
+		path    string
+		size    uint64
+		lastUse time.Time
+	}
+	mirrors := make([]cached, 0, len(paths))
+	var total uint64
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			continue
+		}
+		total += size
+		mirrors = append(mirrors, cached{path: path, size: size, lastUse: info.ModTime()})
+	}
+	sort.Slice(mirrors, func(i, j int) bool { return mirrors[i].lastUse.Before(mirrors[j].lastUse) })
+
+	for _, mirror := range mirrors {
+		if total <= sp.maxCacheSize {
+			return
+		}
+		if mirror.path == keep {
+			continue
+		}
+		lock := sp.mirrorLock(mirror.path)
+		if !lock.TryLock() {
+			continue
+		}
+		err := os.RemoveAll(mirror.path)
+		lock.Unlock()
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("mirror", mirror.path).Msg("Failed to evict git mirror")
+			continue
+		}
+		total -= mirror.size
+		log.Ctx(ctx).Debug().Str("mirror", mirror.path).Uint64("size", mirror.size).Msg("Evicted git mirror")
+	}
+}
+
+// remoteEnv returns the environment needed to talk to the source's remote.
+// ssh only uses the configured key. Credentials are passed as an HTTP header through git's environment
+// configuration so they never appear in the command line or in the mirror's
+// stored configuration. They are resolved in the job's namespace, and only
+// for repositories the secret can be sent to. Redirects are not followed
+// when credentials are sent, so they cannot be forwarded to another host.
+func (sp *StorageProvider) remoteEnv(ctx context.Context, source Source) ([]string, error) {
+	env := []string{
+		"GIT_ALLOW_PROTOCOL=" + strings.Join(supportedSchemes, ":"),
+		"GIT_SSH_COMMAND=" + sp.sshCommand,
+		"GIT_SSH_VARIANT=ssh",
+	}
+	if source.Secret == "" {
+		return env, nil
+	}
+	credentials, err := secrets.ForNamespace(sp.secrets, sp.policy, secrets.NamespaceFromContext(ctx)).
+		ResolveFor(ctx, source.Secret, source.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(credentials, ":") {
+		credentials = "x-access-token:" + credentials
+	}
+	header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	return append(env,
+		"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0="+header,
+		"GIT_CONFIG_KEY_1=http.followRedirects",
+		"GIT_CONFIG_VALUE_1=false",
+	), nil
+}
+
+// resolveCommit returns the commit to check out for the source, making sure
+// a pinned commit exists and is reachable from the requested ref.
+func resolveCommit(ctx context.Context, mirror string, source Source) (string, error) {
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	tip, err := runGit(ctx, mirror, nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("ref %q not found in %s", ref, redact(source.Repo))
+	}
+	if source.Commit == "" {
+		return tip, nil
+	}
+
+	commit, err := runGit(ctx, mirror, nil, "rev-parse", "--verify", "--quiet", source.Commit+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("commit %s not found in %s", source.Commit, redact(source.Repo))
+	}
+	if source.Ref != "" {
+		if _, err = runGit(ctx, mirror, nil, "merge-base", "--is-ancestor", commit, tip); err != nil {
+			return "", fmt.Errorf("commit %s is not reachable from ref %q", source.Commit, source.Ref)
+		}
+	}
+	return commit, nil
+}
+
+// checkout creates a working tree of the commit at outputPath, fetching only
+// from the local mirror.
+func checkout(ctx context.Context, mirror, outputPath, commit string, source Source) error {
+	if _, err := runGit(ctx, "", nil, "init", "--quiet", outputPath); err != nil {
+		return err
+	}
+
+	fetchArgs := []string{"fetch", "--quiet", "--no-tags"}
+	if source.Depth > 0 {
+		fetchArgs = append(fetchArgs, "--depth", strconv.Itoa(source.Depth))
+	}
+	fetchArgs = append(fetchArgs, "file://"+mirror, commit)
+	if _, err := runGit(ctx, outputPath, nil, fetchArgs...); err != nil {
+		return fmt.Errorf("failed to fetch commit %s: %w", commit, err)
+	}
+
+	if len(source.SparsePaths) > 0 {
+		if _, err := runGit(ctx, outputPath, nil, "config", "core.sparseCheckout", "true"); err != nil {
+			return err
+		}
+		patterns := strings.Join(source.SparsePaths, "\n") + "\n"
+		sparseFile := filepath.Join(outputPath, ".git", "info", "sparse-checkout")
+		if err := os.MkdirAll(filepath.Dir(sparseFile), util.OS_USER_RWX); err != nil {
+			return err
+		}
+		if err := os.WriteFile(sparseFile, []byte(patterns), util.OS_USER_RW); err != nil {
+			return err
+		}
+	}
+
+	if _, err := runGit(ctx, outputPath, nil, "checkout", "--quiet", "--detach", commit); err != nil {
+		return fmt.Errorf("failed to check out commit %s: %w", commit, err)
+	}
+	// point origin at the real repository rather than the node's mirror
+	_, err := runGit(ctx, outputPath, nil, "remote", "add", "origin", redact(source.Repo))
+	return err
+}
+
+func hasCommit(ctx context.Context, mirror, commit string) bool {
+	_, err := runGit(ctx, mirror, nil, "cat-file", "-e", commit+"^{commit}")
+	return err == nil
+}
+
+// runGit runs git with a minimal environment that leaves out the system and
+// global git configuration of the node, plus the given variables.
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, gitBinary, args...)
+	cmd.Dir = dir
+	cmd.Env = []string{"GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null", "GIT_TERMINAL_PROMPT=0"}
+	for _, key := range inheritedEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	cmd.Env = append(cmd.Env, env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// redact removes any credentials embedded in a repository URL.
+func redact(repo string) string {
+	u, err := url.Parse(repo)
+	if err != nil || u.User == nil {
+		return repo
+	}
+	return u.Redacted()
+}
+
+func dirSize(path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
+
+var _ storage.Storage = (*StorageProvider)(nil)
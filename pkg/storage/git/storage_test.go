@@ -0,0 +1,334 @@
+//go:build unit || !integration
+
+package git
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bacalhau-project/bacalhau/pkg/config/types"
+	"github.com/bacalhau-project/bacalhau/pkg/lib/secrets"
+	"github.com/bacalhau-project/bacalhau/pkg/logger"
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+)
+
+type GitStorageSuite struct {
+	suite.Suite
+	ctx      context.Context
+	repo     string
+	repoURL  string
+	first    string
+	second   string
+	provider *StorageProvider
+
+	mu             sync.Mutex
+	authorizations []string
+}
+
+func TestGitStorageSuite(t *testing.T) {
+	if _, err := exec.LookPath(gitBinary); err != nil {
+		t.Skip("git is not installed")
+	}
+	suite.Run(t, new(GitStorageSuite))
+}
+
+func (s *GitStorageSuite) SetupSuite() {
+	logger.ConfigureTestLogging(s.T())
+}
+
+func (s *GitStorageSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.repo = s.T().TempDir()
+	s.git("init", "--quiet", "--initial-branch", "main")
+	s.commitFile("README.md", "first")
+	s.first = s.git("rev-parse", "HEAD")
+	s.commitFile("data/values.txt", "second")
+	s.second = s.git("rev-parse", "HEAD")
+	s.git("checkout", "--quiet", "-b", "feature")
+	s.commitFile("feature.txt", "feature")
+	s.git("checkout", "--quiet", "main")
+	s.serve()
+
+	var err error
+	s.provider, err = NewStorageProvider(StorageProviderParams{CacheDir: s.T().TempDir()})
+	s.Require().NoError(err)
+}
+
+// serve serves the repository over http with git's http backend, recording
+// the credentials sent with each request.
+func (s *GitStorageSuite) serve() {
+	gitPath, err := exec.LookPath(gitBinary)
+	s.Require().NoError(err)
+	backend := &cgi.Handler{
+		Path: gitPath,
+		Root: "/git",
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + s.repo, "GIT_HTTP_EXPORT_ALL=1"},
+	}
+	s.mu.Lock()
+	s.authorizations = nil
+	s.mu.Unlock()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.authorizations = append(s.authorizations, r.Header.Get("Authorization"))
+		s.mu.Unlock()
+		backend.ServeHTTP(w, r)
+	}))
+	s.T().Cleanup(server.Close)
+	s.repoURL = server.URL + "/git/.git"
+}
+
+func (s *GitStorageSuite) sentAuthorizations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.authorizations...)
+}
+
+func (s *GitStorageSuite) git(args ...string) string {
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	out, err := runGit(s.ctx, s.repo, nil, args...)
+	s.Require().NoError(err)
+	return out
+}
+
+func (s *GitStorageSuite) commitFile(name, content string) {
+	path := filepath.Join(s.repo, name)
+	s.Require().NoError(os.MkdirAll(filepath.Dir(path), 0o755))
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o600))
+	s.git("add", name)
+	s.git("commit", "--quiet", "-m", name)
+}
+
+func (s *GitStorageSuite) input(source Source) models.InputSource {
+	if source.Repo == "" {
+		source.Repo = s.repoURL
+	}
+	return models.InputSource{
+		Source: &models.SpecConfig{Type: models.StorageSourceGit, Params: source.ToMap()},
+		Target: "/inputs/repo",
+	}
+}
+
+func (s *GitStorageSuite) prepare(source Source) string {
+	volume, err := s.provider.PrepareStorage(s.ctx, s.T().TempDir(), s.input(source))
+	s.Require().NoError(err)
+	s.Equal("/inputs/repo", volume.Target)
+	return volume.Source
+}
+
+func (s *GitStorageSuite) head(dir string) string {
+	out, err := runGit(s.ctx, dir, nil, "rev-parse", "HEAD")
+	s.Require().NoError(err)
+	return out
+}
+
+func (s *GitStorageSuite) TestDefaultBranch() {
+	dir := s.prepare(Source{})
+	s.Equal(s.second, s.head(dir))
+	s.FileExists(filepath.Join(dir, "data", "values.txt"))
+	s.NoFileExists(filepath.Join(dir, "feature.txt"))
+}
+
+func (s *GitStorageSuite) TestRef() {
+	dir := s.prepare(Source{Ref: "feature"})
+	s.FileExists(filepath.Join(dir, "feature.txt"))
+}
+
+func (s *GitStorageSuite) TestPinnedCommit() {
+	dir := s.prepare(Source{Ref: "main", Commit: s.first})
+	s.Equal(s.first, s.head(dir))
+	s.NoFileExists(filepath.Join(dir, "data", "values.txt"))
+}
+
+func (s *GitStorageSuite) TestCommitNotReachableFromRef() {
+	feature := s.git("rev-parse", "feature")
+	_, err := s.provider.PrepareStorage(s.ctx, s.T().TempDir(), s.input(Source{Ref: "main", Commit: feature}))
+	s.ErrorContains(err, "not reachable")
+}
+
+func (s *GitStorageSuite) TestShallow() {
+	dir := s.prepare(Source{Depth: 1})
+	count, err := runGit(s.ctx, dir, nil, "rev-list", "--count", "HEAD")
+	s.Require().NoError(err)
+	s.Equal("1", count)
+}
+
+func (s *GitStorageSuite) TestSparsePaths() {
+	dir := s.prepare(Source{SparsePaths: []string{"data/"}})
+	s.FileExists(filepath.Join(dir, "data", "values.txt"))
+	s.NoFileExists(filepath.Join(dir, "README.md"))
+}
+
+func (s *GitStorageSuite) TestMirrorIsReusedAndUpdated() {
+	input := s.input(Source{})
+	local, err := s.provider.HasStorageLocally(s.ctx, input)
+	s.Require().NoError(err)
+	s.False(local)
+
+	size, err := s.provider.GetVolumeSize(s.ctx, input)
+	s.Require().NoError(err)
+	s.Greater(size, uint64(0))
+
+	local, err = s.provider.HasStorageLocally(s.ctx, input)
+	s.Require().NoError(err)
+	s.True(local)
+
+	s.commitFile("third.txt", "third")
+	third := s.git("rev-parse", "HEAD")
+
+	local, err = s.provider.HasStorageLocally(s.ctx, s.input(Source{Commit: third}))
+	s.Require().NoError(err)
+	s.False(local, "mirror does not contain the new commit yet")
+
+	dir := s.prepare(Source{})
+	s.Equal(third, s.head(dir))
+}
+
+func (s *GitStorageSuite) TestCleanup() {
+	input := s.input(Source{})
+	volume, err := s.provider.PrepareStorage(s.ctx, s.T().TempDir(), input)
+	s.Require().NoError(err)
+	s.Require().NoError(s.provider.CleanupStorage(s.ctx, input, volume))
+	s.NoDirExists(volume.Source)
+}
+
+func (s *GitStorageSuite) TestMirrorKeyedBySecret() {
+	source := Source{Repo: "https://example.com/repo.git", Secret: "a"}
+	withSecret := s.provider.mirrorPath(s.ctx, source)
+	withOther := s.provider.mirrorPath(s.ctx, Source{Repo: source.Repo, Secret: "b"})
+	without := s.provider.mirrorPath(s.ctx, Source{Repo: source.Repo})
+	inNamespace := s.provider.mirrorPath(secrets.ContextWithNamespace(s.ctx, "team-a"), source)
+	s.NotEqual(withSecret, withOther)
+	s.NotEqual(withSecret, without)
+	s.NotEqual(withSecret, inNamespace)
+}
+
+func (s *GitStorageSuite) TestSecret() {
+	policy, err := secrets.NewPolicy([]types.SecretConfig{
+		{Name: "team-a/token", URLs: []string{s.repoURL}},
+		{Name: "other", Shared: true, URLs: []string{"https://github.com/org/"}},
+	})
+	s.Require().NoError(err)
+	s.provider, err = NewStorageProvider(StorageProviderParams{
+		CacheDir:      s.T().TempDir(),
+		Secrets:       secrets.StaticResolver{"team-a/token": "user:password", "other": "other-token"},
+		SecretsPolicy: policy,
+	})
+	s.Require().NoError(err)
+
+	ctx := secrets.ContextWithNamespace(s.ctx, "team-a")
+	_, err = s.provider.PrepareStorage(ctx, s.T().TempDir(), s.input(Source{Secret: "token"}))
+	s.Require().NoError(err)
+	s.Contains(s.sentAuthorizations(), "Basic "+base64.StdEncoding.EncodeToString([]byte("user:password")))
+
+	// secrets are not sent to repositories they are not configured for, or to other namespaces
+	s.serve()
+	_, err = s.provider.PrepareStorage(ctx, s.T().TempDir(), s.input(Source{Secret: "other"}))
+	s.ErrorContains(err, "cannot be sent to")
+	_, err = s.provider.PrepareStorage(s.ctx, s.T().TempDir(), s.input(Source{Secret: "token"}))
+	s.ErrorContains(err, "not available to jobs")
+	s.Empty(s.sentAuthorizations())
+}
+
+func (s *GitStorageSuite) TestRemoteEnv() {
+	env, err := s.provider.remoteEnv(s.ctx, Source{Repo: s.repoURL})
+	s.Require().NoError(err)
+	s.Contains(env, "GIT_ALLOW_PROTOCOL=https:http:ssh")
+	s.Contains(env, "GIT_SSH_COMMAND=ssh -F /dev/null -o IdentitiesOnly=yes -o IdentityAgent=none "+
+		"-o BatchMode=yes -o StrictHostKeyChecking=yes -o IdentityFile=none")
+
+	key := filepath.Join(s.T().TempDir(), "it's a key")
+	s.Require().NoError(os.WriteFile(key, nil, 0o600))
+	s.provider, err = NewStorageProvider(StorageProviderParams{CacheDir: s.T().TempDir(), SSHKeyFile: key})
+	s.Require().NoError(err)
+	s.Contains(s.provider.sshCommand, `-o IdentitiesOnly=yes`)
+	s.Contains(s.provider.sshCommand, `-i '`+strings.ReplaceAll(key, "'", `'\''`)+`'`)
+
+	_, err = NewStorageProvider(StorageProviderParams{CacheDir: s.T().TempDir(), SSHKeyFile: key + ".missing"})
+	s.Error(err)
+}
+
+func (s *GitStorageSuite) TestMinimalEnv() {
+	home := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\tname = node\n"), 0o600))
+	s.T().Setenv("HOME", home)
+	s.T().Setenv("SSH_AUTH_SOCK", filepath.Join(home, "agent.sock"))
+
+	_, err := runGit(s.ctx, home, nil, "config", "user.name")
+	s.Error(err, "the global git configuration is not used")
+	env, err := runGit(s.ctx, "", nil, "-c", "alias.printenv=!env", "printenv")
+	s.Require().NoError(err)
+	s.Contains(env, "GIT_CONFIG_NOSYSTEM=1")
+	s.NotContains(env, "SSH_AUTH_SOCK")
+}
+
+func (s *GitStorageSuite) TestMaxRepositorySize() {
+	cacheDir := s.T().TempDir()
+	var err error
+	s.provider, err = NewStorageProvider(StorageProviderParams{CacheDir: cacheDir, MaxRepositorySize: 1})
+	s.Require().NoError(err)
+
+	_, err = s.provider.PrepareStorage(s.ctx, s.T().TempDir(), s.input(Source{}))
+	s.ErrorContains(err, "too large")
+	entries, err := os.ReadDir(cacheDir)
+	s.Require().NoError(err)
+	s.Empty(entries, "no partial mirror is left behind")
+}
+
+func (s *GitStorageSuite) TestEviction() {
+	cacheDir := s.T().TempDir()
+	var err error
+	s.provider, err = NewStorageProvider(StorageProviderParams{CacheDir: cacheDir, MaxCacheSize: 1})
+	s.Require().NoError(err)
+
+	s.prepare(Source{})
+	first := s.provider.mirrorPath(s.ctx, Source{Repo: s.repoURL})
+	s.DirExists(first, "the mirror in use is never evicted")
+
+	// a different url is mirrored separately
+	s.serve()
+	s.prepare(Source{})
+	s.NoDirExists(first)
+	s.DirExists(s.provider.mirrorPath(s.ctx, Source{Repo: s.repoURL}))
+}
+
+func (s *GitStorageSuite) TestValidate() {
+	for _, tc := range []struct {
+		name   string
+		source Source
+		valid  bool
+	}{
+		{name: "https", source: Source{Repo: "https://github.com/org/repo.git"}, valid: true},
+		{name: "https with secret", source: Source{Repo: "https://github.com/org/repo.git", Secret: "token"}, valid: true},
+		{name: "empty", source: Source{}},
+		{name: "unsupported scheme", source: Source{Repo: "ftp://example.com/repo"}},
+		{name: "file", source: Source{Repo: "file:///etc"}},
+		{name: "git protocol", source: Source{Repo: "git://example.com/repo.git"}},
+		{name: "local path", source: Source{Repo: "/var/lib/repo"}},
+		{name: "secret with ssh", source: Source{Repo: "ssh://git@example.com/repo.git", Secret: "token"}},
+		{name: "bad commit", source: Source{Repo: "https://example.com/r.git", Commit: "main"}},
+		{name: "option as ref", source: Source{Repo: "https://example.com/r.git", Ref: "--upload-pack=x"}},
+		{name: "negative depth", source: Source{Repo: "https://example.com/r.git", Depth: -1}},
+		{name: "absolute sparse path", source: Source{Repo: "https://example.com/r.git", SparsePaths: []string{"/etc"}}},
+	} {
+		s.Run(tc.name, func() {
+			err := tc.source.Validate()
+			if tc.valid {
+				s.NoError(err)
+			} else {
+				s.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+)
+
+var commitPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+
+// Source describes a git repository to be checked out as a job input.
+type Source struct {
+	// Repo is the clone URL of the repository. http(s) and ssh URLs are
+	// supported.
+	Repo string
+	// Ref is a branch or tag to check out. Defaults to the remote HEAD.
+	Ref string
+	// Commit pins the checkout to an exact commit. When set together with
+	// Ref, the commit must be reachable from the ref.
+	Commit string
+	// Depth limits the history fetched into the job's checkout. Zero means
+	// full history.
+	Depth int
+	// SparsePaths restricts the checkout to the given paths.
+	SparsePaths []string
+	// Secret is the name of a node secret holding credentials for private
+	// repositories, either as a token or as "user:password".
+	Secret string
+}
+
+func (s Source) Validate() error {
+	var errs error
+	if s.Repo == "" {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. repo cannot be empty"))
+	} else if u, err := url.Parse(s.Repo); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. invalid repo url: %w", err))
+	} else if !isSupportedScheme(u.Scheme) {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. unsupported repo url scheme %q", u.Scheme))
+	} else if s.Secret != "" && u.Scheme != "http" && u.Scheme != "https" {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. secrets are only supported for http(s) repositories"))
+	}
+	if strings.HasPrefix(s.Ref, "-") {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. invalid ref %q", s.Ref))
+	}
+	if s.Commit != "" && !commitPattern.MatchString(s.Commit) {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. commit must be a hex object id, got %q", s.Commit))
+	}
+	if s.Depth < 0 {
+		errs = errors.Join(errs, fmt.Errorf("invalid git params. depth cannot be negative"))
+	}
+	for _, p := range s.SparsePaths {
+		if p == "" || strings.HasPrefix(p, "-") || path.IsAbs(p) {
+			errs = errors.Join(errs, fmt.Errorf("invalid git params. invalid sparse path %q", p))
+		}
+	}
+	return errs
+}
+
+func (s Source) ToMap() map[string]interface{} {
+	return structs.Map(s)
+}
+
+func DecodeSpec(spec *models.SpecConfig) (Source, error) {
+	if !spec.IsType(models.StorageSourceGit) {
+		return Source{}, fmt.Errorf("invalid storage source type. expected %s, but received: %s",
+			models.StorageSourceGit, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return Source{}, fmt.Errorf("invalid storage source params. cannot be nil")
+	}
+
+	var c Source
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// supportedSchemes are the protocols git can use to fetch repositories. Local
+// repositories on the node cannot be checked out.
+var supportedSchemes = []string{"https", "http", "ssh"}
+
+func isSupportedScheme(scheme string) bool {
+	return slices.Contains(supportedSchemes, scheme)
+}
@@ -135,6 +135,11 @@ func (s *StorageProvider) getFileFromIPFS(ctx context.Context, cid, storageDirec
 		return storage.StorageVolume{}, err
 	}
 	if !ok {
+		// the CID may be followed by a path within its content, in which case
+		// the parent folders of the output path have to be created first
+		if err = os.MkdirAll(filepath.Dir(outputPath), models.DownloadFolderPerm); err != nil {
+			return storage.StorageVolume{}, err
+		}
 		err = s.ipfsClient.Get(ctx, cid, outputPath)
 		if err != nil {
 			return storage.StorageVolume{}, err
@@ -0,0 +1,67 @@
+package jobresult
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bacalhau-project/bacalhau/pkg/models"
+	"github.com/fatih/structs"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Source references the published results of another job as a job input.
+// It is resolved by the orchestrator when the job is submitted to the
+// storage source the results were published to, so compute nodes never see
+// it.
+type Source struct {
+	// JobID is the ID of the job whose results are used. Short IDs are
+	// accepted.
+	JobID string
+	// ExecutionID selects the results of one of the job's executions. It is
+	// required if the job has more than one completed execution. Short IDs are
+	// accepted.
+	ExecutionID string
+	// Publisher selects the results published by the given publisher type,
+	// if the job published its results with more than one publisher.
+	// Defaults to the first publisher of the job.
+	Publisher string
+	// Path selects a file or directory within the results. Only results
+	// published to IPFS can be narrowed down to a path, as other publishers
+	// publish archives.
+	Path string
+}
+
+func (s Source) Validate() error {
+	var errs error
+	if s.JobID == "" {
+		errs = errors.Join(errs, fmt.Errorf("invalid job result params. job id cannot be empty"))
+	}
+	if s.Path != "" && (path.IsAbs(s.Path) || strings.HasPrefix(path.Clean(s.Path), "..")) {
+		errs = errors.Join(errs, fmt.Errorf("invalid job result params. path must be relative to the results, got %q", s.Path))
+	}
+	return errs
+}
+
+func (s Source) ToMap() map[string]interface{} {
+	return structs.Map(s)
+}
+
+func DecodeSpec(spec *models.SpecConfig) (Source, error) {
+	if !spec.IsType(models.StorageSourceJobResult) {
+		return Source{}, fmt.Errorf("invalid storage source type. expected %s, but received: %s",
+			models.StorageSourceJobResult, spec.Type)
+	}
+	inputParams := spec.Params
+	if inputParams == nil {
+		return Source{}, fmt.Errorf("invalid storage source params. cannot be nil")
+	}
+
+	var c Source
+	if err := mapstructure.Decode(spec.Params, &c); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// TrimKeyPrefix returns the local path of an object key relative to the
+// directory part of the key prefix the user requested, given the prefix
+// split by "/". For example, objects listed with the prefix "dir/file-" are
+// written as "file-001.txt" rather than "dir/file-001.txt".
+func TrimKeyPrefix(key string, prefixTokens []string) string {
+	objectTokens := strings.Split(key, "/")
+	startingIndex := 0
+	for i := 0; i < len(prefixTokens)-1 && i < len(objectTokens); i++ {
+		if prefixTokens[i] == objectTokens[i] {
+			startingIndex++
+		} else {
+			break
+		}
+	}
+	return filepath.Join(objectTokens[startingIndex:]...)
+}
@@ -138,19 +138,8 @@ func (s *StorageProvider) downloadObject(ctx context.Context,
 	object s3ObjectSummary,
 	parentDir string,
 	prefixTokens []string) error {
-	// trim the user supplied prefix from the object local path
-	objectTokens := strings.Split(*object.key, "/")
-	startingIndex := 0
-	for i := 0; i < len(prefixTokens)-1; i++ {
-		if prefixTokens[i] == objectTokens[i] {
-			startingIndex++
-		} else {
-			break
-		}
-	}
-
 	// relative output path to the supplied prefix
-	outputPath := filepath.Join(parentDir, filepath.Join(objectTokens[startingIndex:]...))
+	outputPath := filepath.Join(parentDir, storage.TrimKeyPrefix(*object.key, prefixTokens))
 
 	if object.isDir {
 		return os.MkdirAll(outputPath, models.DownloadFolderPerm)
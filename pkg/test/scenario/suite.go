@@ -207,7 +207,18 @@ func (s *ScenarioRunner) RunScenario(scenario Scenario) string {
 			models.StorageSourceIPFS: ipfsDownloader,
 		})
 
-		err = downloader.DownloadResults(s.Ctx, results.Results, downloaderProvider, downloaderSettings)
+		nodeKeys := make(map[string]string)
+		for _, manifest := range results.Manifests {
+			if manifest == nil {
+				continue
+			}
+			node, err := apiClientV2.Nodes().Get(s.Ctx, &apimodels.GetNodeRequest{NodeID: manifest.NodeID})
+			s.Require().NoError(err)
+			nodeKeys[manifest.NodeID] = node.Node.PublicKey
+		}
+
+		err = downloader.DownloadResults(
+			s.Ctx, results.Results, results.Manifests, nodeKeys, downloaderProvider, downloaderSettings)
 		s.Require().NoError(err)
 
 		err = scenario.ResultsChecker(resultsDir)
@@ -35,6 +35,10 @@ func NewCallbackHandler(params CallbackHandlerParams) *CallbackHandler {
 	host := handler.host
 	host.SetStreamHandler(OnBidComplete, handleCallback(host, handler.callback.OnBidComplete))
 	host.SetStreamHandler(OnRunComplete, handleCallback(host, handler.callback.OnRunComplete))
+	host.SetStreamHandler(OnSnapshotComplete, handleCallback(host, handler.callback.OnSnapshotComplete))
+	host.SetStreamHandler(OnCheckpointComplete, handleCallback(host, handler.callback.OnCheckpointComplete))
+	host.SetStreamHandler(OnHealthUpdate, handleCallback(host, handler.callback.OnHealthUpdate))
+	host.SetStreamHandler(OnEndpointsAllocated, handleCallback(host, handler.callback.OnEndpointsAllocated))
 	host.SetStreamHandler(OnCancelComplete, handleCallback(host, handler.callback.OnCancelComplete))
 	host.SetStreamHandler(OnComputeFailure, handleCallback(host, handler.callback.OnComputeFailure))
 	return handler
@@ -52,6 +52,30 @@ func (p *CallbackProxy) OnRunComplete(ctx context.Context, result compute.RunRes
 	})
 }
 
+func (p *CallbackProxy) OnSnapshotComplete(ctx context.Context, result compute.SnapshotResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnSnapshotComplete, result, func(ctx2 context.Context) {
+		p.localCallback.OnSnapshotComplete(ctx2, result)
+	})
+}
+
+func (p *CallbackProxy) OnHealthUpdate(ctx context.Context, result compute.HealthResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnHealthUpdate, result, func(ctx2 context.Context) {
+		p.localCallback.OnHealthUpdate(ctx2, result)
+	})
+}
+
+func (p *CallbackProxy) OnEndpointsAllocated(ctx context.Context, result compute.EndpointsResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnEndpointsAllocated, result, func(ctx2 context.Context) {
+		p.localCallback.OnEndpointsAllocated(ctx2, result)
+	})
+}
+
+func (p *CallbackProxy) OnCheckpointComplete(ctx context.Context, result compute.CheckpointResult) {
+	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnCheckpointComplete, result, func(ctx2 context.Context) {
+		p.localCallback.OnCheckpointComplete(ctx2, result)
+	})
+}
+
 func (p *CallbackProxy) OnCancelComplete(ctx context.Context, result compute.CancelResult) {
 	proxyCallbackRequest(ctx, p, result.RoutingMetadata, OnCancelComplete, result, func(ctx2 context.Context) {
 		p.localCallback.OnCancelComplete(ctx2, result)
@@ -8,9 +8,15 @@ const (
 	CancelProtocolID      = "/bacalhau/compute/cancel/1.0.0"
 	ExecutionLogsID       = "/bacalhau/compute/execution_logs/1.0.0"
 
-	CallbackServiceName = "bacalhau.callback"
-	OnBidComplete       = "/bacalhau/callback/on_bid_complete/1.0.0"
-	OnRunComplete       = "/bacalhau/callback/on_run_complete/1.0.0"
-	OnCancelComplete    = "/bacalhau/callback/on_cancel_complete/1.0.0"
-	OnComputeFailure    = "/bacalhau/callback/on_compute_failure/1.0.0"
+	CallbackServiceName  = "bacalhau.callback"
+	OnBidComplete        = "/bacalhau/callback/on_bid_complete/1.0.0"
+	OnRunComplete        = "/bacalhau/callback/on_run_complete/1.0.0"
+	OnSnapshotComplete   = "/bacalhau/callback/on_snapshot_complete/1.0.0"
+	OnCheckpointComplete = "/bacalhau/callback/on_checkpoint_complete/1.0.0"
+	OnHealthUpdate       = "/bacalhau/callback/on_health_update/1.0.0"
+	OnEndpointsAllocated = "/bacalhau/callback/on_endpoints_allocated/1.0.0"
+	OnCancelComplete     = "/bacalhau/callback/on_cancel_complete/1.0.0"
+	OnComputeFailure     = "/bacalhau/callback/on_compute_failure/1.0.0"
+
+	NodeJoinProtocolID = "/bacalhau/node/join/1.0.0"
 )
@@ -0,0 +1,61 @@
+package bprotocol
+
+import (
+	"context"
+	"errors"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/rs/zerolog/log"
+
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
+)
+
+type JoinProxyParams struct {
+	Host host.Host
+}
+
+// JoinProxy presents the join token of a compute node to the orchestrators
+// it is connected to, which are the peers that handle the join protocol.
+type JoinProxy struct {
+	host host.Host
+}
+
+func NewJoinProxy(params JoinProxyParams) *JoinProxy {
+	return &JoinProxy{host: params.Host}
+}
+
+func (p *JoinProxy) Join(ctx context.Context, request routing.JoinRequest) (routing.JoinResponse, error) {
+	var errs error
+	for _, peerID := range p.host.Network().Peers() {
+		protocols, err := p.host.Peerstore().SupportsProtocols(peerID, NodeJoinProtocolID)
+		if err != nil || len(protocols) == 0 {
+			continue
+		}
+		response, err := proxyRequest[routing.JoinRequest, routing.JoinResponse](
+			ctx, p.host, peerID.String(), NodeJoinProtocolID, request)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		return response, nil
+	}
+	if errs == nil {
+		errs = errors.New("not connected to any orchestrator")
+	}
+	return routing.JoinResponse{}, errs
+}
+
+type JoinHandlerParams struct {
+	Host   host.Host
+	Joiner routing.NodeJoiner
+}
+
+// NewJoinHandler handles the join requests of compute nodes with the
+// orchestrator's node joiner.
+func NewJoinHandler(params JoinHandlerParams) {
+	params.Host.SetStreamHandler(NodeJoinProtocolID, handleWith(params.Host, params.Joiner.Join))
+	log.Debug().Msgf("JoinHandler started on host %s", params.Host.ID().String())
+}
+
+// compile-time interface check
+var _ routing.NodeJoiner = (*JoinProxy)(nil)
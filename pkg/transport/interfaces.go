@@ -7,6 +7,7 @@ import (
 	"github.com/bacalhau-project/bacalhau/pkg/model"
 	"github.com/bacalhau-project/bacalhau/pkg/models"
 	"github.com/bacalhau-project/bacalhau/pkg/pubsub"
+	"github.com/bacalhau-project/bacalhau/pkg/routing"
 )
 
 // TransportLayer is the interface for the transport layer.
@@ -18,6 +19,9 @@ type TransportLayer interface {
 	// NodeInfoPubSub enables compute nodes to publish their info and capabilities
 	// to orchestrator nodes for job matching and discovery.
 	NodeInfoPubSub() pubsub.PubSub[models.NodeInfo]
+	// NodeJoiner enables compute nodes to present their join token directly
+	// to orchestrator nodes, rather than with their published node info.
+	NodeJoiner() routing.NodeJoiner
 	// NodeInfoDecorator enables transport layer to enrich node info with data
 	// required for request routing
 	NodeInfoDecorator() models.NodeInfoDecorator
@@ -29,6 +33,9 @@ type TransportLayer interface {
 	// RegisterComputeEndpoint registers a compute endpoint with the transport layer
 	// so that incoming orchestrator requests are forwarded to the handler
 	RegisterComputeEndpoint(endpoint compute.Endpoint) error
+	// RegisterNodeJoiner registers the node joiner of an orchestrator with the
+	// transport layer so that join requests of compute nodes are forwarded to it
+	RegisterNodeJoiner(joiner routing.NodeJoiner) error
 	// Close closes the transport layer.
 	Close(ctx context.Context) error
 }
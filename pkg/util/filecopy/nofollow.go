@@ -0,0 +1,109 @@
+package filecopy
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CopyFileNoFollow copies the regular file at the slash separated path rel
+// within root to dst, preserving file mode. Unlike CopyFile, it does not
+// follow symbolic links in any part of rel, so that files outside of root
+// are never read even if rel is replaced with a link while it is copied.
+func CopyFileNoFollow(root, rel, dst string) error {
+	sourceFile, err := openNoFollow(root, rel, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to open source file")
+	}
+	defer sourceFile.Close()
+
+	srcinfo, err := sourceFile.Stat()
+	if err != nil {
+		return errors.Wrap(err, "failed to get file mode")
+	}
+	if !srcinfo.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", sourceFile.Name())
+	}
+
+	destinationFile, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "failed to open target file")
+	}
+	defer destinationFile.Close()
+
+	if _, err = io.Copy(destinationFile, sourceFile); err != nil {
+		return errors.Wrap(err, "failed to copy file to target")
+	}
+
+	err = os.Chmod(dst, srcinfo.Mode())
+	if err != nil {
+		return errors.Wrap(err, "failed to set file mode")
+	}
+
+	return nil
+}
+
+// CopyDirNoFollow copies the directories and regular files of source to
+// destination. Symbolic links and other special files are skipped, and
+// symbolic links are never followed.
+func CopyDirNoFollow(source string, destination string) error {
+	return copyDirNoFollow(source, ".", destination)
+}
+
+func copyDirNoFollow(root, rel, destination string) error {
+	dir, err := openNoFollow(root, rel, true)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	info, err := dir.Stat()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(destination, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	entries, err := dir.ReadDir(-1)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		src := path.Join(rel, entry.Name())
+		dst := filepath.Join(destination, entry.Name())
+
+		switch {
+		case entry.IsDir():
+			if err := copyDirNoFollow(root, src, dst); err != nil {
+				return errors.Wrap(err, "failed to copy directory")
+			}
+		case entry.Type().IsRegular():
+			if err := CopyFileNoFollow(root, src, dst); err != nil {
+				return errors.Wrap(err, "failed to copy file")
+			}
+		}
+	}
+	return nil
+}
+
+// pathNames splits a slash separated relative path into its names.
+func pathNames(rel string) ([]string, error) {
+	rel = path.Clean(filepath.ToSlash(rel))
+	if rel == "." {
+		return nil, nil
+	}
+	names := strings.Split(rel, "/")
+	for _, name := range names {
+		if name == ".." || name == "" {
+			return nil, fmt.Errorf("invalid relative path %q", rel)
+		}
+	}
+	return names, nil
+}
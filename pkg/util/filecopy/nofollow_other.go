@@ -0,0 +1,42 @@
+//go:build !unix
+
+package filecopy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// openNoFollow opens rel within root, refusing to open it if any part of rel
+// is a symbolic link, or if it was replaced after being checked.
+func openNoFollow(root, rel string, dir bool) (*os.File, error) {
+	names, err := pathNames(rel)
+	if err != nil {
+		return nil, err
+	}
+	name := root
+	var info os.FileInfo
+	for _, next := range names {
+		name = filepath.Join(name, next)
+		if info, err = os.Lstat(name); err != nil {
+			return nil, err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil, fmt.Errorf("%s is a symbolic link", name)
+		}
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil {
+		opened, err := f.Stat()
+		if err != nil || !os.SameFile(info, opened) {
+			f.Close()
+			return nil, fmt.Errorf("%s changed while being opened", name)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,62 @@
+//go:build unit || !integration
+
+package filecopy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NoFollowSuite struct {
+	suite.Suite
+	root    string
+	outside string
+}
+
+func TestNoFollowSuite(t *testing.T) {
+	suite.Run(t, new(NoFollowSuite))
+}
+
+func (s *NoFollowSuite) SetupTest() {
+	s.root = s.T().TempDir()
+	s.outside = filepath.Join(s.T().TempDir(), "secret")
+	s.Require().NoError(os.WriteFile(s.outside, []byte("secret"), 0o600))
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.root, "dir"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.root, "dir", "file"), []byte("data"), 0o600))
+}
+
+func (s *NoFollowSuite) TestCopyFile() {
+	dst := filepath.Join(s.T().TempDir(), "file")
+	s.Require().NoError(CopyFileNoFollow(s.root, "dir/file", dst))
+	data, err := os.ReadFile(dst)
+	s.Require().NoError(err)
+	s.Equal("data", string(data))
+}
+
+func (s *NoFollowSuite) TestCopyFileLink() {
+	s.Require().NoError(os.Symlink(s.outside, filepath.Join(s.root, "dir", "link")))
+	s.Error(CopyFileNoFollow(s.root, "dir/link", filepath.Join(s.T().TempDir(), "link")))
+}
+
+func (s *NoFollowSuite) TestCopyFileLinkedDir() {
+	s.Require().NoError(os.Symlink(filepath.Dir(s.outside), filepath.Join(s.root, "linked")))
+	s.Error(CopyFileNoFollow(s.root, "linked/secret", filepath.Join(s.T().TempDir(), "secret")))
+}
+
+func (s *NoFollowSuite) TestCopyFileOutsideRoot() {
+	s.Error(CopyFileNoFollow(s.root, "../secret", filepath.Join(s.T().TempDir(), "secret")))
+}
+
+func (s *NoFollowSuite) TestCopyDirSkipsLinks() {
+	s.Require().NoError(os.Symlink(s.outside, filepath.Join(s.root, "dir", "link")))
+	s.Require().NoError(os.Symlink(filepath.Dir(s.outside), filepath.Join(s.root, "linked")))
+
+	dst := filepath.Join(s.T().TempDir(), "copy")
+	s.Require().NoError(CopyDirNoFollow(s.root, dst))
+	s.FileExists(filepath.Join(dst, "dir", "file"))
+	s.NoFileExists(filepath.Join(dst, "dir", "link"))
+	s.NoDirExists(filepath.Join(dst, "linked"))
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package filecopy
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// openNoFollow opens rel within root one name at a time, refusing to follow
+// symbolic links. Files are opened without blocking, so that opening a named
+// pipe does not wait for a writer.
+func openNoFollow(root, rel string, dir bool) (*os.File, error) {
+	names, err := pathNames(rel)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Join(root, filepath.FromSlash(rel))
+
+	fd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: root, Err: err}
+	}
+	for i, next := range names {
+		flags := unix.O_RDONLY | unix.O_NOFOLLOW | unix.O_CLOEXEC
+		if dir || i < len(names)-1 {
+			flags |= unix.O_DIRECTORY
+		} else {
+			flags |= unix.O_NONBLOCK
+		}
+		nextFd, err := unix.Openat(fd, next, flags, 0)
+		unix.Close(fd)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		fd = nextFd
+	}
+	return os.NewFile(uintptr(fd), name), nil
+}